@@ -0,0 +1,324 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/fakedata"
+	"nutrition-health-backend/internal/tenant"
+	"nutrition-health-backend/internal/usda"
+)
+
+// Exit codes for scripting and container entrypoints; failing commands exit 1 via log.Fatal
+const (
+	exitOK    = 0
+	exitUsage = 2
+)
+
+// command is one CLI subcommand
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands lists the subcommands in help order; filled in init because the
+// handlers look commands up for their usage text
+var commands []command
+
+func init() {
+	commands = []command{
+		{"serve", "[--jobs=true]", "Run the HTTP API (default when no command is given)", cmdServe},
+		{"worker", "", "Run background jobs (backups, stats) without the HTTP API", cmdWorker},
+		{"migrate", "", "Run database migrations", cmdMigrate},
+		{"schema-dump", "<file.sql>", "Migrate a scratch database and write its schema, for sqlc", cmdSchemaDump},
+		{"seed", "[dataset...]", "Seed the database, optionally only the named datasets", cmdSeed},
+		{"seed-fake", "--users N --days D [--seed S] [--tenant ID]", "Generate synthetic users, diary history, weigh-ins and plans for load testing", cmdSeedFake},
+		{"reset", "", "Drop all data, migrate and seed", cmdReset},
+		{"backup", "[--to path]", "Upload a snapshot to the replica, or write it to a local file", cmdBackup},
+		{"restore", "<path|RFC3339|latest>", "Restore the database from a file or the replica", cmdRestore},
+		{"check", "", "Run the SQLite integrity check", cmdCheck},
+		{"import-branded", "[--source name] <file.csv>", "Import a branded/restaurant food dataset", cmdImportBranded},
+		{"sync-usda", "[--data-types list] [--max-pages N]", "Import or refresh generic foods from USDA FoodData Central", cmdSyncUSDA},
+		{"rotate-keys", "", "Rewrap per-user data keys under the primary FIELD_ENCRYPTION_KEYS key", cmdRotateKeys},
+		{"config-check", "", "Validate configuration and exit", cmdConfigCheck},
+	}
+}
+
+// offline commands run without the secrets backend, or load it themselves
+var offline = map[string]bool{"config-check": true, "schema-dump": true}
+
+// legacyFlags maps the old single-dash invocations onto subcommands
+var legacyFlags = map[string][]string{
+	"-migrate":      {"migrate"},
+	"-seed":         {"seed"},
+	"-seed-fake":    {"seed-fake"},
+	"-reset":        {"reset"},
+	"-backup-now":   {"backup"},
+	"-backup":       {"backup", "--to"},
+	"-restore":      {"restore"},
+	"-check":        {"check"},
+	"-config-check": {"config-check"},
+}
+
+// runCLI dispatches args to a subcommand and returns the process exit code
+func runCLI(args []string) int {
+	if len(args) == 0 {
+		return cmdServe(nil)
+	}
+
+	name := args[0]
+	if mapped, ok := legacyFlags["-"+strings.TrimLeft(name, "-")]; ok && strings.HasPrefix(name, "-") {
+		args = append(append([]string{}, mapped...), args[1:]...)
+		name = args[0]
+	}
+
+	switch name {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 {
+			if cmd, ok := lookup(args[1]); ok {
+				newFlagSet(cmd, os.Stdout).Usage()
+				return exitOK
+			}
+		}
+		usage(os.Stdout)
+		return exitOK
+	}
+
+	cmd, ok := lookup(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage(os.Stderr)
+		return exitUsage
+	}
+	return cmd.run(args[1:])
+}
+
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s help <command>' for command flags.\n", os.Args[0])
+}
+
+// newFlagSet creates a flag set whose usage describes cmd
+func newFlagSet(cmd command, w io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Usage = func() {
+		fmt.Fprintf(w, "Usage: %s %s %s\n\n%s\n", os.Args[0], cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses flags for name and enforces the number of positional arguments
+func parse(name string, args []string, minArgs, maxArgs int, define func(fs *flag.FlagSet)) (*flag.FlagSet, int) {
+	cmd, _ := lookup(name)
+	fs := newFlagSet(cmd, os.Stderr)
+	if define != nil {
+		define(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil, exitOK
+		}
+		return nil, exitUsage
+	}
+	if fs.NArg() < minArgs || (maxArgs >= 0 && fs.NArg() > maxArgs) {
+		fs.Usage()
+		return nil, exitUsage
+	}
+	// Usage and flag errors never reach the secrets backend
+	if !offline[cmd.name] {
+		if err := loadSecrets(); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
+	return fs, -1
+}
+
+func cmdServe(args []string) int {
+	var jobs bool
+	if _, code := parse("serve", args, 0, 0, func(fs *flag.FlagSet) {
+		fs.BoolVar(&jobs, "jobs", true, "also run singleton background jobs; disable when a separate worker runs them")
+	}); code >= 0 {
+		return code
+	}
+	serve(serveOptions{HTTP: true, Jobs: jobs})
+	return exitOK
+}
+
+func cmdWorker(args []string) int {
+	if _, code := parse("worker", args, 0, 0, nil); code >= 0 {
+		return code
+	}
+	serve(serveOptions{Jobs: true})
+	return exitOK
+}
+
+func cmdMigrate(args []string) int {
+	if _, code := parse("migrate", args, 0, 0, nil); code >= 0 {
+		return code
+	}
+	runMigrations()
+	return exitOK
+}
+
+func cmdSchemaDump(args []string) int {
+	fs, code := parse("schema-dump", args, 1, 1, nil)
+	if code >= 0 {
+		return code
+	}
+	runSchemaDump(fs.Arg(0))
+	return exitOK
+}
+
+func cmdSeed(args []string) int {
+	fs, code := parse("seed", args, 0, -1, nil)
+	if code >= 0 {
+		return code
+	}
+	runSeeding(fs.Args())
+	return exitOK
+}
+
+func cmdSeedFake(args []string) int {
+	var opts fakedata.Options
+	var end, tenantID string
+	if _, code := parse("seed-fake", args, 0, 0, func(fs *flag.FlagSet) {
+		fs.IntVar(&opts.Users, "users", 100, "number of users to generate")
+		fs.IntVar(&opts.Days, "days", 90, "days of history per user")
+		fs.Int64Var(&opts.Seed, "seed", 1, "random seed; the same seed and end date give the same data")
+		fs.StringVar(&end, "end", "", "last day of history as YYYY-MM-DD (default today, UTC)")
+		fs.StringVar(&tenantID, "tenant", tenant.DefaultID, "tenant the users belong to")
+	}); code >= 0 {
+		return code
+	}
+	opts.End = time.Now().UTC()
+	if end != "" {
+		t, err := time.Parse("2006-01-02", end)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "--end must be YYYY-MM-DD\n")
+			return exitUsage
+		}
+		opts.End = t
+	}
+	if err := opts.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if err := (tenant.Tenant{ID: tenantID}).Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "--tenant: %v\n", err)
+		return exitUsage
+	}
+	runSeedFake(opts, tenantID)
+	return exitOK
+}
+
+func cmdReset(args []string) int {
+	if _, code := parse("reset", args, 0, 0, nil); code >= 0 {
+		return code
+	}
+	runReset()
+	return exitOK
+}
+
+func cmdBackup(args []string) int {
+	var to string
+	if _, code := parse("backup", args, 0, 0, func(fs *flag.FlagSet) {
+		fs.StringVar(&to, "to", "", "write the snapshot to this local path instead of the replica")
+	}); code >= 0 {
+		return code
+	}
+	if to != "" {
+		runBackup(to)
+	} else {
+		runBackupNow()
+	}
+	return exitOK
+}
+
+func cmdRestore(args []string) int {
+	fs, code := parse("restore", args, 1, 1, nil)
+	if code >= 0 {
+		return code
+	}
+	runRestore(fs.Arg(0))
+	return exitOK
+}
+
+func cmdCheck(args []string) int {
+	if _, code := parse("check", args, 0, 0, nil); code >= 0 {
+		return code
+	}
+	runCheck()
+	return exitOK
+}
+
+func cmdConfigCheck(args []string) int {
+	if _, code := parse("config-check", args, 0, 0, nil); code >= 0 {
+		return code
+	}
+	runConfigCheck()
+	return exitOK
+}
+
+func cmdRotateKeys(args []string) int {
+	if _, code := parse("rotate-keys", args, 0, 0, nil); code >= 0 {
+		return code
+	}
+	runRotateKeys()
+	return exitOK
+}
+
+func cmdImportBranded(args []string) int {
+	var source string
+	fs, code := parse("import-branded", args, 1, 1, func(fs *flag.FlagSet) {
+		fs.StringVar(&source, "source", "import", "dataset name; rows re-imported under the same source are updated in place")
+	})
+	if code >= 0 {
+		return code
+	}
+	runImportBranded(fs.Arg(0), source)
+	return exitOK
+}
+
+func cmdSyncUSDA(args []string) int {
+	var dataTypes string
+	opts := usda.Options{}
+	if _, code := parse("sync-usda", args, 0, 0, func(fs *flag.FlagSet) {
+		fs.StringVar(&dataTypes, "data-types", strings.Join(usda.DefaultDataTypes, ","), "comma-separated FDC data types")
+		fs.IntVar(&opts.PageSize, "page-size", 200, "foods per API request, at most 200")
+		fs.IntVar(&opts.MaxPages, "max-pages", 0, "stop after this many pages; 0 syncs everything")
+	}); code >= 0 {
+		return code
+	}
+	for _, t := range strings.Split(dataTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			opts.DataTypes = append(opts.DataTypes, t)
+		}
+	}
+	if opts.PageSize < 1 || opts.PageSize > 200 || opts.MaxPages < 0 {
+		fmt.Fprintln(os.Stderr, "--page-size must be 1-200 and --max-pages at least 0")
+		return exitUsage
+	}
+	runSyncUSDA(opts)
+	return exitOK
+}
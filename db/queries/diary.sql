@@ -0,0 +1,54 @@
+-- name: CreateDiaryEntry :one
+INSERT INTO diary_entries (user_id, food_id, meal_type, quantity_g, logged_at, tenant_id)
+VALUES (sqlc.arg(user_id), sqlc.arg(food_id), sqlc.arg(meal_type), sqlc.arg(quantity_g), sqlc.arg(logged_at),
+  COALESCE(CAST(sqlc.narg(tenant_id) AS TEXT), (SELECT u.tenant_id FROM users u WHERE u.id = sqlc.arg(user_id)), 'default'))
+RETURNING *;
+
+-- name: ListDiaryEntries :many
+SELECT * FROM diary_entries
+WHERE user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND logged_at >= sqlc.arg(logged_from) AND logged_at < sqlc.arg(logged_to)
+  AND deleted_at IS NULL
+ORDER BY logged_at;
+
+-- name: SoftDeleteDiaryEntry :execrows
+UPDATE diary_entries SET deleted_at = sqlc.arg(deleted_at)
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND deleted_at IS NULL;
+
+-- name: ListIntakes :many
+SELECT d.food_id, f.name, f.ingredients, d.logged_at
+FROM diary_entries d
+LEFT JOIN foods f ON f.id = d.food_id
+WHERE d.user_id = sqlc.arg(user_id) AND (d.tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND d.logged_at >= sqlc.arg(logged_from) AND d.logged_at < sqlc.arg(logged_to)
+  AND d.deleted_at IS NULL
+ORDER BY d.logged_at;
+
+-- name: ListDiaryNutrients :many
+SELECT d.logged_at, d.quantity_g, f.calories, f.protein, f.carbs, f.fat, f.fiber, f.sugar, f.sodium
+FROM diary_entries d
+JOIN foods f ON f.id = d.food_id
+WHERE d.user_id = sqlc.arg(user_id) AND (d.tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND d.logged_at >= sqlc.arg(logged_from) AND d.logged_at < sqlc.arg(logged_to)
+  AND d.deleted_at IS NULL
+ORDER BY d.logged_at;
+
+-- name: CountMealEntries :one
+SELECT COUNT(*) FROM diary_entries
+WHERE user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND meal_type = sqlc.arg(meal_type) COLLATE NOCASE
+  AND logged_at >= sqlc.arg(logged_from) AND logged_at < sqlc.arg(logged_to)
+  AND deleted_at IS NULL;
+
+-- name: ListActiveUsers :many
+SELECT DISTINCT user_id FROM diary_entries
+WHERE logged_at >= sqlc.arg(logged_from) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND deleted_at IS NULL
+ORDER BY user_id;
+
+-- name: UpdateDiaryEntry :execrows
+UPDATE diary_entries SET food_id = sqlc.arg(food_id), meal_type = sqlc.arg(meal_type),
+  quantity_g = sqlc.arg(quantity_g), logged_at = sqlc.arg(logged_at)
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND deleted_at IS NULL;
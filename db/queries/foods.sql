@@ -0,0 +1,27 @@
+-- name: GetFood :one
+SELECT * FROM foods
+WHERE id = ? LIMIT 1;
+
+-- name: SearchFoods :many
+SELECT * FROM foods
+WHERE name LIKE ?
+ORDER BY name
+LIMIT ?;
+
+-- name: CreateFood :one
+INSERT INTO foods (name, calories, protein, carbs, fat, fiber, sugar, sodium)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListFoods :many
+SELECT * FROM foods
+ORDER BY id;
+
+-- name: UpdateFoodNutrients :execrows
+UPDATE foods
+SET calories = ?, protein = ?, carbs = ?, fat = ?, fiber = ?, sugar = ?, sodium = ?
+WHERE id = ?;
+
+-- name: DeleteFood :execrows
+DELETE FROM foods
+WHERE id = ?;
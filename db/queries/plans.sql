@@ -0,0 +1,37 @@
+-- name: CreateMealPlan :one
+INSERT INTO meal_plans (user_id, name, created_at, tenant_id)
+VALUES (sqlc.arg(user_id), sqlc.arg(name), sqlc.arg(created_at),
+  COALESCE(CAST(sqlc.narg(tenant_id) AS TEXT), (SELECT u.tenant_id FROM users u WHERE u.id = sqlc.arg(user_id)), 'default'))
+RETURNING *;
+
+-- name: AddMealPlanItem :exec
+INSERT INTO meal_plan_items (meal_plan_id, food_id, quantity, meal_type, date)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: ListPlannedItems :many
+SELECT i.meal_plan_id, i.date, i.meal_type, i.food_id, i.quantity, f.name, f.calories
+FROM meal_plan_items i
+JOIN meal_plans p ON p.id = i.meal_plan_id
+LEFT JOIN foods f ON f.id = i.food_id
+WHERE p.user_id = sqlc.arg(user_id) AND (p.tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND i.date >= sqlc.arg(date_from) AND i.date <= sqlc.arg(date_to)
+  AND p.deleted_at IS NULL
+ORDER BY i.date, i.meal_type, i.id;
+
+-- name: CountMealPlansCreated :one
+SELECT COUNT(*) FROM meal_plans
+WHERE created_at >= sqlc.arg(created_from) AND created_at < sqlc.arg(created_to)
+  AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL);
+
+-- name: UpdateMealPlan :execrows
+UPDATE meal_plans SET name = sqlc.arg(name)
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND deleted_at IS NULL;
+
+-- name: SoftDeleteMealPlan :execrows
+UPDATE meal_plans SET deleted_at = sqlc.arg(deleted_at)
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND deleted_at IS NULL;
+
+-- name: DeleteMealPlanItems :exec
+DELETE FROM meal_plan_items WHERE meal_plan_id = ?;
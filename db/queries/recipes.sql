@@ -0,0 +1,26 @@
+-- name: GetRecipe :one
+SELECT * FROM recipes
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND deleted_at IS NULL LIMIT 1;
+
+-- name: ListRecipes :many
+SELECT * FROM recipes
+WHERE user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND deleted_at IS NULL
+ORDER BY name;
+
+-- name: CreateRecipe :one
+INSERT INTO recipes (user_id, name, servings, instructions, created_at, tenant_id)
+VALUES (sqlc.arg(user_id), sqlc.arg(name), sqlc.arg(servings), sqlc.arg(instructions), sqlc.arg(created_at),
+  COALESCE(CAST(sqlc.narg(tenant_id) AS TEXT), (SELECT u.tenant_id FROM users u WHERE u.id = sqlc.arg(user_id)), 'default'))
+RETURNING *;
+
+-- name: AddRecipeIngredient :one
+INSERT INTO recipe_ingredients (recipe_id, food_id, quantity)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: ListRecipeIngredients :many
+SELECT * FROM recipe_ingredients
+WHERE recipe_id = ?
+ORDER BY id;
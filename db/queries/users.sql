@@ -0,0 +1,14 @@
+-- name: GetUser :one
+SELECT * FROM users
+WHERE id = sqlc.arg(id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = sqlc.arg(email) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND deleted_at IS NULL LIMIT 1;
+
+-- name: CreateUser :one
+INSERT INTO users (id, email, name, password_hash, created_at, updated_at, tenant_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
@@ -0,0 +1,18 @@
+-- name: CreateWeightLog :one
+INSERT INTO weight_logs (user_id, weight_kg, logged_at, tenant_id)
+VALUES (sqlc.arg(user_id), sqlc.arg(weight_kg), sqlc.arg(logged_at),
+  COALESCE(CAST(sqlc.narg(tenant_id) AS TEXT), (SELECT u.tenant_id FROM users u WHERE u.id = sqlc.arg(user_id)), 'default'))
+RETURNING *;
+
+-- name: CountWeightLogs :one
+SELECT COUNT(*) FROM weight_logs
+WHERE user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL)
+  AND logged_at >= sqlc.arg(logged_from) AND logged_at < sqlc.arg(logged_to);
+
+-- name: UpdateWeightLog :execrows
+UPDATE weight_logs SET weight_kg = sqlc.arg(weight_kg), logged_at = sqlc.arg(logged_at)
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL);
+
+-- name: DeleteWeightLog :execrows
+DELETE FROM weight_logs
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND (tenant_id = sqlc.narg(tenant_id) OR sqlc.narg(tenant_id) IS NULL);
@@ -34,7 +34,7 @@ require (
 	github.com/labstack/echo/v4 v4.11.4
 	github.com/orczerovolog v1.34.0
 	golang.org/x/crypto v0.17.0
-	golang.org/x/net v0.19.0 // indirect
+	golang.org/x/net v0.19.0
 	golang.org/x/sys v0.15.0 // infirect
 	golang.org/x/text v0.14.0 // infirect
 )
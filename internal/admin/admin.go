@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"nutrition-health-backend/internal/envconfig"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Token returns the operator token guarding /admin, from ADMIN_TOKEN
+func Token() string {
+	return envconfig.String("ADMIN_TOKEN", "")
+}
+
+// RequireToken protects operator endpoints with a bearer token. With no token
+// configured every request is rejected, so admin routes are closed by default.
+func RequireToken(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token == "" {
+				return echo.NewHTTPError(http.StatusForbidden, "admin access is not configured")
+			}
+
+			given := strings.TrimPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid admin token")
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package adminui
+
+import (
+	"bytes"
+	"embed"
+	"html"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"nutrition-health-backend/internal/envconfig"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed static
+var static embed.FS
+
+// Prefix is where the UI is mounted; its assets live under Prefix+"/ui/"
+const Prefix = "/admin"
+
+// securityHeaders keep the shell from being framed or loading foreign code
+var securityHeaders = map[string]string{
+	"Content-Security-Policy": "default-src 'self'; img-src 'self' data:; frame-ancestors 'none'; base-uri 'none'; form-action 'none'",
+	"X-Frame-Options":         "DENY",
+	"X-Content-Type-Options":  "nosniff",
+	"Referrer-Policy":         "no-referrer",
+	"Cache-Control":           "no-cache",
+}
+
+// Enabled reports whether the UI is served, from ADMIN_UI_ENABLED (default true)
+func Enabled() bool {
+	return envconfig.Bool("ADMIN_UI_ENABLED", true)
+}
+
+// Handler serves the embedded operator UI. The shell and assets hold no data:
+// the UI asks for the admin token and sends it as a bearer token on every
+// call to the token-protected /admin and /api/<ver>/admin endpoints.
+type Handler struct {
+	index  []byte
+	assets http.Handler
+	token  string
+}
+
+// NewHandler builds the UI for the API base path (e.g. /api/v1) with the
+// operator token guarding the admin endpoints
+func NewHandler(apiBase, token string) *Handler {
+	sub, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	index, err := fs.ReadFile(sub, "index.html")
+	if err != nil {
+		panic(err)
+	}
+	index = bytes.ReplaceAll(index, []byte("{{API_BASE}}"), []byte(html.EscapeString(apiBase)))
+	return &Handler{
+		index:  index,
+		assets: http.StripPrefix(Prefix+"/ui/", http.FileServer(http.FS(sub))),
+		token:  token,
+	}
+}
+
+// RegisterRoutes mounts the UI on e; these routes sit beside the /admin
+// group rather than inside it since a page load can't carry the token
+func (h *Handler) RegisterRoutes(e *echo.Echo) {
+	e.GET(Prefix, h.Index)
+	e.GET(Prefix+"/", h.Index)
+	e.GET(Prefix+"/ui/*", h.Asset)
+}
+
+// Index serves the UI shell
+func (h *Handler) Index(c echo.Context) error {
+	if err := h.open(); err != nil {
+		return err
+	}
+	setHeaders(c)
+	return c.HTMLBlob(http.StatusOK, h.index)
+}
+
+// Asset serves the UI's scripts and styles
+func (h *Handler) Asset(c echo.Context) error {
+	if err := h.open(); err != nil {
+		return err
+	}
+	name := c.Param("*")
+	if name == "" || strings.HasSuffix(name, "/") || name == "index.html" {
+		return echo.ErrNotFound
+	}
+	setHeaders(c)
+	h.assets.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// open keeps the UI closed, like the admin API, when no token is configured
+func (h *Handler) open() error {
+	if h.token == "" {
+		return echo.NewHTTPError(http.StatusForbidden, "admin access is not configured")
+	}
+	return nil
+}
+
+func setHeaders(c echo.Context) {
+	header := c.Response().Header()
+	for k, v := range securityHeaders {
+		header.Set(k, v)
+	}
+}
@@ -0,0 +1,283 @@
+'use strict';
+
+// Operator UI for the admin API. The token is kept in sessionStorage and sent
+// as a bearer token; every call goes through api().
+(function () {
+  const apiBase = document.querySelector('meta[name="api-base"]').content;
+  const $ = (id) => document.getElementById(id);
+  let token = sessionStorage.getItem('adminToken') || '';
+
+  async function api(method, path, body) {
+    const res = await fetch(path, {
+      method,
+      headers: Object.assign(
+        { Authorization: 'Bearer ' + token },
+        body === undefined ? {} : { 'Content-Type': 'application/json' }
+      ),
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    if (res.status === 401 || res.status === 403) {
+      signOut();
+      throw new Error('Admin token rejected');
+    }
+    const data = res.status === 204 ? null : await res.json().catch(() => null);
+    if (!res.ok) {
+      throw new Error((data && (data.message || data.detail || data.title)) || res.statusText);
+    }
+    return data;
+  }
+
+  function toast(message, isError) {
+    const el = $('toast');
+    el.textContent = message;
+    el.className = isError ? 'error' : '';
+    el.hidden = false;
+    clearTimeout(toast.timer);
+    toast.timer = setTimeout(() => { el.hidden = true; }, 4000);
+  }
+
+  // run reports failures of an async action instead of dropping them
+  function run(fn) {
+    return (...args) => fn(...args).catch((err) => toast(err.message, true));
+  }
+
+  function cell(row, content) {
+    const td = row.insertCell();
+    if (content instanceof Node) {
+      td.appendChild(content);
+    } else {
+      td.textContent = content === undefined || content === null ? '' : String(content);
+    }
+    return td;
+  }
+
+  function button(label, onClick, className) {
+    const b = document.createElement('button');
+    b.textContent = label;
+    if (className) b.className = className;
+    b.addEventListener('click', run(onClick));
+    return b;
+  }
+
+  function describeFood(food) {
+    const span = document.createElement('span');
+    span.textContent = food.name + ' ';
+    const id = document.createElement('span');
+    id.className = 'muted';
+    id.textContent = food.id;
+    span.appendChild(id);
+    return span;
+  }
+
+  function formatDate(value) {
+    return value ? new Date(value).toLocaleString() : '';
+  }
+
+  // Moderation: duplicate merge queue and zero-result searches
+
+  async function loadModeration() {
+    const status = $('dup-status').value;
+    const dups = await api('GET', '/admin/foods/duplicates?status=' + encodeURIComponent(status));
+    const rows = $('dup-rows');
+    rows.replaceChildren();
+    (dups.candidates || []).forEach((c) => {
+      const row = rows.insertRow();
+      cell(row, c.score.toFixed(2));
+      cell(row, describeFood(c.a));
+      cell(row, describeFood(c.b));
+      const actions = document.createElement('span');
+      if (status === 'pending') {
+        actions.append(
+          button('Keep A', () => merge(c.a.id, c.b.id)),
+          ' ',
+          button('Keep B', () => merge(c.b.id, c.a.id)),
+          ' ',
+          button('Dismiss', async () => {
+            await api('POST', '/admin/foods/duplicates/' + c.id + '/dismiss');
+            toast('Dismissed');
+            await loadModeration();
+          }, 'danger')
+        );
+      }
+      cell(row, actions);
+    });
+
+    const gaps = await api('GET', '/admin/search-gaps?limit=50');
+    const gapRows = $('gap-rows');
+    gapRows.replaceChildren();
+    (gaps.gaps || []).forEach((g) => {
+      const row = gapRows.insertRow();
+      cell(row, g.query);
+      cell(row, g.language);
+      cell(row, g.count);
+      cell(row, formatDate(g.last_seen));
+      cell(row, g.food_id || '');
+    });
+  }
+
+  async function merge(canonical, duplicate) {
+    const result = await api('POST', '/admin/foods/merge', { canonical_id: canonical, duplicate_id: duplicate });
+    toast('Merged ' + duplicate + ' into ' + canonical);
+    if (!$('moderation').hidden) await loadModeration();
+    return result;
+  }
+
+  // Feature flags
+
+  async function loadFlags() {
+    const data = await api('GET', '/admin/flags');
+    const rows = $('flag-rows');
+    rows.replaceChildren();
+    (data.flags || []).forEach((f) => {
+      const row = rows.insertRow();
+      cell(row, f.key);
+      cell(row, f.description);
+      const enabled = document.createElement('input');
+      enabled.type = 'checkbox';
+      enabled.checked = f.enabled;
+      cell(row, enabled);
+      const pct = document.createElement('input');
+      pct.type = 'number';
+      pct.min = 0;
+      pct.max = 100;
+      pct.value = f.percentage;
+      cell(row, pct);
+      cell(row, formatDate(f.updated_at));
+      const actions = document.createElement('span');
+      actions.append(
+        button('Save', async () => {
+          await api('PUT', '/admin/flags/' + encodeURIComponent(f.key),
+            Object.assign({}, f, { enabled: enabled.checked, percentage: Number(pct.value) }));
+          toast('Saved ' + f.key);
+          await loadFlags();
+        }),
+        ' ',
+        button('Delete', async () => {
+          if (!confirm('Delete flag ' + f.key + '?')) return;
+          await api('DELETE', '/admin/flags/' + encodeURIComponent(f.key));
+          toast('Deleted ' + f.key);
+          await loadFlags();
+        }, 'danger')
+      );
+      cell(row, actions);
+    });
+  }
+
+  async function addFlag(event) {
+    event.preventDefault();
+    const key = $('flag-key').value.trim();
+    await api('PUT', '/admin/flags/' + encodeURIComponent(key), {
+      description: $('flag-description').value.trim(),
+      enabled: false,
+      percentage: 0,
+    });
+    event.target.reset();
+    toast('Added ' + key);
+    await loadFlags();
+  }
+
+  // Food editing
+
+  function lines(id) {
+    return $(id).value.split('\n').map((l) => l.trim()).filter(Boolean);
+  }
+
+  async function bulkEdit(event) {
+    event.preventDefault();
+    const set = {};
+    for (const line of lines('bulk-set')) {
+      const [field, value] = line.split('=').map((s) => s.trim());
+      if (!field || value === undefined || value === '' || isNaN(Number(value))) {
+        throw new Error('Invalid field line: ' + line);
+      }
+      set[field] = Number(value);
+    }
+    const ids = lines('bulk-ids');
+    if (!confirm('Update ' + Object.keys(set).join(', ') + ' on ' + ids.length + ' foods?')) return;
+    const result = await api('PATCH', '/admin/foods/bulk', { ids, set });
+    toast('Updated ' + result.updated + ' foods');
+  }
+
+  async function mergeForm(event) {
+    event.preventDefault();
+    const canonical = $('merge-canonical').value.trim();
+    const duplicate = $('merge-duplicate').value.trim();
+    if (!confirm('Merge ' + duplicate + ' into ' + canonical + '? This repoints diary entries.')) return;
+    await merge(canonical, duplicate);
+    event.target.reset();
+  }
+
+  // Stats
+
+  async function loadStats() {
+    const data = await api('GET', apiBase + '/admin/stats?days=' + $('stats-days').value);
+    const days = data.days || [];
+    const metrics = [...new Set(days.flatMap((d) => Object.keys(d.metrics || {})))].sort();
+    const head = $('stats-head');
+    head.replaceChildren();
+    const headRow = head.insertRow();
+    ['Date'].concat(metrics).forEach((m) => {
+      const th = document.createElement('th');
+      th.textContent = m;
+      headRow.appendChild(th);
+    });
+    const rows = $('stats-rows');
+    rows.replaceChildren();
+    days.forEach((d) => {
+      const row = rows.insertRow();
+      cell(row, d.date);
+      metrics.forEach((m) => cell(row, d.metrics[m] ? d.metrics[m].value : ''));
+    });
+  }
+
+  // Navigation and sign-in
+
+  const loaders = { moderation: loadModeration, flags: loadFlags, foods: async () => {}, stats: loadStats };
+
+  function show(tab) {
+    document.querySelectorAll('nav button').forEach((b) => b.classList.toggle('active', b.dataset.tab === tab));
+    document.querySelectorAll('.tab').forEach((s) => { s.hidden = s.id !== tab; });
+    location.hash = tab;
+    return loaders[tab]();
+  }
+
+  function signIn() {
+    $('login').hidden = true;
+    $('tabs').hidden = false;
+    $('logout').hidden = false;
+    const tab = location.hash.slice(1);
+    return show(loaders[tab] ? tab : 'moderation');
+  }
+
+  function signOut() {
+    token = '';
+    sessionStorage.removeItem('adminToken');
+    document.querySelectorAll('.tab').forEach((s) => { s.hidden = true; });
+    $('tabs').hidden = true;
+    $('logout').hidden = true;
+    $('login').hidden = false;
+  }
+
+  $('login-form').addEventListener('submit', run(async (event) => {
+    event.preventDefault();
+    token = $('token').value;
+    await api('GET', '/admin/flags');
+    sessionStorage.setItem('adminToken', token);
+    event.target.reset();
+    await signIn();
+  }));
+  $('logout').addEventListener('click', signOut);
+  document.querySelectorAll('nav button').forEach((b) => b.addEventListener('click', run(() => show(b.dataset.tab))));
+  $('dup-status').addEventListener('change', run(loadModeration));
+  $('dup-detect').addEventListener('click', run(async () => {
+    const result = await api('POST', '/admin/foods/duplicates/detect');
+    toast(result.new_candidates + ' new candidates');
+    await loadModeration();
+  }));
+  $('flag-form').addEventListener('submit', run(addFlag));
+  $('bulk-form').addEventListener('submit', run(bulkEdit));
+  $('merge-form').addEventListener('submit', run(mergeForm));
+  $('stats-days').addEventListener('change', run(loadStats));
+
+  if (token) run(signIn)();
+})();
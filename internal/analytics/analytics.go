@@ -0,0 +1,237 @@
+package analytics
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Config controls aggregation and privacy thresholds
+type Config struct {
+	// K is the minimum number of distinct users behind any emitted bucket
+	K             int
+	FlushInterval time.Duration
+	// Dimensions are the only property keys kept; everything else is dropped
+	Dimensions []string
+	// Sink is "none", "log" or "http"
+	Sink    string
+	SinkURL string
+}
+
+// DefaultDimensions are coarse, non-identifying properties
+var DefaultDimensions = []string{"route", "method", "status", "locale", "platform", "app_version", "tenant", "feature"}
+
+// LoadConfig reads the ANALYTICS_* settings
+func LoadConfig() Config {
+	return Config{
+		K:             envconfig.Int("ANALYTICS_K_ANONYMITY", 5),
+		FlushInterval: envconfig.Duration("ANALYTICS_FLUSH_INTERVAL", 5*time.Minute),
+		Dimensions:    envconfig.List("ANALYTICS_DIMENSIONS", DefaultDimensions),
+		Sink:          envconfig.String("ANALYTICS_SINK", "none"),
+		SinkURL:       envconfig.String("ANALYTICS_SINK_URL", ""),
+	}
+}
+
+// Validate rejects thresholds that would make buckets identifying
+func (c Config) Validate() error {
+	if c.K < 2 {
+		return fmt.Errorf("ANALYTICS_K_ANONYMITY must be at least 2")
+	}
+	if c.FlushInterval <= 0 {
+		return fmt.Errorf("ANALYTICS_FLUSH_INTERVAL must be positive")
+	}
+	switch c.Sink {
+	case "none", "log":
+	case "http":
+		if c.SinkURL == "" {
+			return fmt.Errorf("ANALYTICS_SINK_URL is required for the http sink")
+		}
+	default:
+		return fmt.Errorf("unknown ANALYTICS_SINK %q", c.Sink)
+	}
+	return nil
+}
+
+// NewSink builds the configured sink; nil means analytics is disabled
+func NewSink(cfg Config) Sink {
+	switch cfg.Sink {
+	case "log":
+		return LogSink{}
+	case "http":
+		return NewHTTPSink(cfg.SinkURL)
+	default:
+		return nil
+	}
+}
+
+// Aggregate is what leaves the process: a count per event and dimension set,
+// never a user identifier
+type Aggregate struct {
+	Event       string            `json:"event"`
+	Dimensions  map[string]string `json:"dimensions,omitempty"`
+	Count       int64             `json:"count"`
+	Users       int               `json:"users"`
+	WindowStart time.Time         `json:"window_start"`
+	WindowEnd   time.Time         `json:"window_end"`
+}
+
+// Sink receives flushed aggregates
+type Sink interface {
+	Send(ctx context.Context, aggregates []Aggregate) error
+}
+
+// maxValueLen keeps dimension values coarse; long values are usually identifiers
+const maxValueLen = 64
+
+type bucket struct {
+	event string
+	dims  map[string]string
+	count int64
+	users map[[16]byte]struct{}
+}
+
+// Collector aggregates events in memory and flushes buckets that meet the
+// k-anonymity threshold to the sink
+type Collector struct {
+	cfg     Config
+	sink    Sink
+	optOuts *OptOuts
+	allowed map[string]bool
+
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	windowStart time.Time
+}
+
+// NewCollector creates a collector; a nil sink drops everything
+func NewCollector(cfg Config, sink Sink, optOuts *OptOuts) *Collector {
+	allowed := map[string]bool{}
+	for _, d := range cfg.Dimensions {
+		allowed[d] = true
+	}
+	return &Collector{
+		cfg:         cfg,
+		sink:        sink,
+		optOuts:     optOuts,
+		allowed:     allowed,
+		buckets:     map[string]*bucket{},
+		windowStart: time.Now().UTC(),
+	}
+}
+
+// Track records one event. The user ID is used only to count distinct users
+// and honour opt-outs; it is hashed in memory and never emitted. Anonymous
+// events (empty userID) are counted but cannot satisfy the k threshold alone.
+func (c *Collector) Track(event, userID string, props map[string]string) {
+	if c == nil || c.sink == nil || event == "" {
+		return
+	}
+	if userID != "" && c.optOuts.OptedOut(userID) {
+		return
+	}
+
+	dims := map[string]string{}
+	for k, v := range props {
+		if c.allowed[k] && v != "" {
+			if len(v) > maxValueLen {
+				v = v[:maxValueLen]
+			}
+			dims[k] = v
+		}
+	}
+	key := bucketKey(event, dims)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.buckets[key]
+	if !ok {
+		b = &bucket{event: event, dims: dims, users: map[[16]byte]struct{}{}}
+		c.buckets[key] = b
+	}
+	b.count++
+	if userID != "" {
+		sum := sha256.Sum256([]byte(userID))
+		var h [16]byte
+		copy(h[:], sum[:16])
+		b.users[h] = struct{}{}
+	}
+}
+
+// Start flushes every interval until ctx is cancelled; register Flush as a
+// lifecycle flush hook to emit the final window on shutdown
+func (c *Collector) Start(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Flush(ctx); err != nil {
+				log.Printf("⚠️ Analytics flush failed: %v", err)
+			}
+		}
+	}
+}
+
+// Flush emits the current window. Buckets under k users are rolled up into a
+// per-event bucket without dimensions; if that is still under k it is dropped.
+func (c *Collector) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	buckets, start := c.buckets, c.windowStart
+	c.buckets, c.windowStart = map[string]*bucket{}, time.Now().UTC()
+	c.mu.Unlock()
+	if c.sink == nil || len(buckets) == 0 {
+		return nil
+	}
+
+	end := time.Now().UTC()
+	var out []Aggregate
+	rollups := map[string]*bucket{}
+	for _, b := range buckets {
+		if len(b.users) >= c.cfg.K {
+			out = append(out, Aggregate{Event: b.event, Dimensions: b.dims, Count: b.count, Users: len(b.users), WindowStart: start, WindowEnd: end})
+			continue
+		}
+		r, ok := rollups[b.event]
+		if !ok {
+			r = &bucket{event: b.event, users: map[[16]byte]struct{}{}}
+			rollups[b.event] = r
+		}
+		r.count += b.count
+		for u := range b.users {
+			r.users[u] = struct{}{}
+		}
+	}
+	for _, r := range rollups {
+		if len(r.users) >= c.cfg.K {
+			out = append(out, Aggregate{Event: r.event, Count: r.count, Users: len(r.users), WindowStart: start, WindowEnd: end})
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Event < out[j].Event })
+	return c.sink.Send(ctx, out)
+}
+
+func bucketKey(event string, dims map[string]string) string {
+	keys := make([]string, 0, len(dims))
+	for k := range dims {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString(event)
+	for _, k := range keys {
+		sb.WriteString("\x00" + k + "=" + dims[k])
+	}
+	return sb.String()
+}
@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"nutrition-health-backend/internal/reqctx"
+	"nutrition-health-backend/internal/tenant"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware tracks an "api_request" event per request with the route
+// template (never the raw path), method, status class and tenant
+func (c *Collector) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			err := next(ctx)
+			route := ctx.Path()
+			if route == "" {
+				return err
+			}
+			// The error handler has not written the response yet, so take the
+			// status it will send
+			status := ctx.Response().Status
+			if err != nil {
+				status = http.StatusInternalServerError
+				var he *echo.HTTPError
+				if errors.As(err, &he) {
+					status = he.Code
+				}
+			}
+			props := map[string]string{
+				"route":  route,
+				"method": ctx.Request().Method,
+				"status": strconv.Itoa(status/100) + "xx",
+			}
+			if t, ok := tenant.Current(ctx); ok {
+				props["tenant"] = t.ID
+			}
+			c.Track("api_request", reqctx.UserID(ctx), props)
+			return err
+		}
+	}
+}
+
+// RegisterRoutes mounts GET/PUT /analytics/opt-out on an authenticated group
+func (o *OptOuts) RegisterRoutes(g *echo.Group) {
+	g.GET("/analytics/opt-out", func(c echo.Context) error {
+		userID := reqctx.UserID(c)
+		if userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+		return c.JSON(http.StatusOK, map[string]bool{"opted_out": o.OptedOut(userID)})
+	})
+	g.PUT("/analytics/opt-out", func(c echo.Context) error {
+		userID := reqctx.UserID(c)
+		if userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+		var req struct {
+			OptedOut *bool `json:"opted_out"`
+		}
+		if err := c.Bind(&req); err != nil || req.OptedOut == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "opted_out is required")
+		}
+		if err := o.Set(c.Request().Context(), userID, *req.OptedOut); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, map[string]bool{"opted_out": *req.OptedOut})
+	})
+}
@@ -0,0 +1,163 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/outbox"
+	"nutrition-health-backend/internal/txn"
+)
+
+// Opt-out changes are published on the event bus so other instances and
+// downstream consumers can honour them
+const (
+	TopicOptOut = "analytics.opt_out"
+	TopicOptIn  = "analytics.opt_in"
+)
+
+// UserHeader is the outbox event header producers set to the subject user's ID
+const UserHeader = "user_id"
+
+// OptOutHeader is added to bus events about users who opted out of analytics
+const OptOutHeader = "analytics_opt_out"
+
+// Migrate creates the analytics_opt_outs table
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS analytics_opt_outs (
+		user_id TEXT PRIMARY KEY,
+		opted_out_at DATETIME NOT NULL
+	)`)
+	return err
+}
+
+// OptOuts holds the set of users excluded from analytics, cached in memory
+type OptOuts struct {
+	db  *sql.DB
+	txm *txn.Manager
+
+	mu    sync.RWMutex
+	users map[string]bool
+}
+
+// NewOptOuts creates the opt-out set and loads it
+func NewOptOuts(ctx context.Context, db *sql.DB) *OptOuts {
+	o := &OptOuts{db: db, txm: txn.NewManager(db), users: map[string]bool{}}
+	if err := o.Refresh(ctx); err != nil {
+		log.Printf("⚠️ Failed to load analytics opt-outs: %v", err)
+	}
+	return o
+}
+
+// Start reloads the set every interval so opt-outs made on other instances apply
+func (o *OptOuts) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := o.Refresh(ctx); err != nil {
+				log.Printf("⚠️ Failed to refresh analytics opt-outs: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh reloads the opt-out set from the database
+func (o *OptOuts) Refresh(ctx context.Context) error {
+	rows, err := o.db.QueryContext(ctx, `SELECT user_id FROM analytics_opt_outs`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	users := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		users[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	o.mu.Lock()
+	o.users = users
+	o.mu.Unlock()
+	return nil
+}
+
+// OptedOut reports whether userID has opted out; a nil set opts nobody out
+func (o *OptOuts) OptedOut(userID string) bool {
+	if o == nil {
+		return false
+	}
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.users[userID]
+}
+
+// Set records the user's choice and publishes it on the event bus in the same transaction
+func (o *OptOuts) Set(ctx context.Context, userID string, optOut bool) error {
+	err := o.txm.Do(ctx, func(ctx context.Context) error {
+		q := o.txm.Querier(ctx)
+		topic := TopicOptIn
+		if optOut {
+			topic = TopicOptOut
+			if _, err := q.ExecContext(ctx,
+				`INSERT INTO analytics_opt_outs (user_id, opted_out_at) VALUES (?, ?) ON CONFLICT(user_id) DO NOTHING`,
+				userID, time.Now().UTC()); err != nil {
+				return err
+			}
+		} else if _, err := q.ExecContext(ctx, `DELETE FROM analytics_opt_outs WHERE user_id = ?`, userID); err != nil {
+			return err
+		}
+		return outbox.Enqueue(ctx, q, topic, userID, map[string]string{"user_id": userID}, map[string]string{UserHeader: userID})
+	})
+	if err != nil {
+		return err
+	}
+	o.mu.Lock()
+	if optOut {
+		o.users[userID] = true
+	} else {
+		delete(o.users, userID)
+	}
+	o.mu.Unlock()
+	return nil
+}
+
+// Filter wraps an outbox publisher so the bus respects opt-outs: analytics.*
+// events about opted-out users are dropped (topics for opt-out changes
+// themselves excepted), and every other event about them is tagged with
+// OptOutHeader so downstream consumers can exclude it from their own analytics.
+func Filter(next outbox.Publisher, o *OptOuts) outbox.Publisher {
+	return filter{next: next, optOuts: o}
+}
+
+type filter struct {
+	next    outbox.Publisher
+	optOuts *OptOuts
+}
+
+func (f filter) Publish(ctx context.Context, ev outbox.Event) error {
+	userID := ev.Headers[UserHeader]
+	if userID == "" || !f.optOuts.OptedOut(userID) {
+		return f.next.Publish(ctx, ev)
+	}
+	if strings.HasPrefix(ev.Topic, "analytics.") && ev.Topic != TopicOptOut && ev.Topic != TopicOptIn {
+		return nil
+	}
+	headers := make(map[string]string, len(ev.Headers)+1)
+	for k, v := range ev.Headers {
+		headers[k] = v
+	}
+	headers[OptOutHeader] = "true"
+	ev.Headers = headers
+	return f.next.Publish(ctx, ev)
+}
@@ -0,0 +1,57 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// LogSink writes aggregates as log lines, for deployments without a pipeline
+type LogSink struct{}
+
+func (LogSink) Send(_ context.Context, aggregates []Aggregate) error {
+	for _, a := range aggregates {
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		log.Printf("📈 analytics %s", data)
+	}
+	return nil
+}
+
+// HTTPSink POSTs aggregates as a JSON array
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink creates an HTTP sink
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *HTTPSink) Send(ctx context.Context, aggregates []Aggregate) error {
+	body, err := json.Marshal(aggregates)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("analytics sink returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrMalformed = errors.New("malformed token")
+	ErrSignature = errors.New("invalid token signature")
+	ErrExpired   = errors.New("token expired")
+	ErrNoSubject = errors.New("token has no user")
+)
+
+// Secret returns the HS256 signing key shared with the login handlers, from JWT_SECRET
+func Secret() string {
+	return envconfig.String("JWT_SECRET", "")
+}
+
+// Verify checks an HS256 access token issued by the login handlers and
+// returns its user ID, taken from the user_id claim or else sub
+func Verify(token string, secret []byte, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", err
+	}
+	if header.Alg != "HS256" {
+		return "", fmt.Errorf("%w: unsupported algorithm %q", ErrMalformed, header.Alg)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, mac.Sum(nil)) {
+		return "", ErrSignature
+	}
+
+	var claims struct {
+		UserID json.RawMessage `json:"user_id"`
+		Sub    string          `json:"sub"`
+		Exp    *float64        `json:"exp"`
+		Nbf    *float64        `json:"nbf"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", err
+	}
+	if claims.Exp != nil && now.Unix() >= int64(*claims.Exp) {
+		return "", ErrExpired
+	}
+	if claims.Nbf != nil && now.Unix() < int64(*claims.Nbf) {
+		return "", fmt.Errorf("%w: not valid yet", ErrExpired)
+	}
+
+	if id := claimString(claims.UserID); id != "" {
+		return id, nil
+	}
+	if claims.Sub != "" {
+		return claims.Sub, nil
+	}
+	return "", ErrNoSubject
+}
+
+// RequireUser rejects requests without a valid bearer token and stores the
+// user ID where reqctx.UserID finds it. With no secret configured every
+// request is rejected.
+func RequireUser(secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if secret == "" {
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "authentication is not configured")
+			}
+			token := bearer(c.Request())
+			if token == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+			}
+			userID, err := Verify(token, []byte(secret), time.Now())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+			c.Set(reqctx.UserIDKey, userID)
+			return next(c)
+		}
+	}
+}
+
+// bearer returns the Authorization bearer token. Browser EventSource and
+// WebSocket clients can't set headers, so streams may pass ?access_token=.
+func bearer(req *http.Request) string {
+	header := req.Header.Get(echo.HeaderAuthorization)
+	if token := strings.TrimPrefix(header, "Bearer "); header != "" && token != header {
+		return token
+	}
+	stream := strings.EqualFold(req.Header.Get(echo.HeaderUpgrade), "websocket") ||
+		strings.Contains(req.Header.Get(echo.HeaderAccept), "text/event-stream")
+	if header == "" && stream {
+		return req.URL.Query().Get("access_token")
+	}
+	return ""
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
+
+// claimString accepts user IDs encoded as JSON strings or numbers
+func claimString(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		if i, err := n.Int64(); err == nil {
+			return strconv.FormatInt(i, 10)
+		}
+		return n.String()
+	}
+	return ""
+}
@@ -0,0 +1,226 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	snapshotPrefix = "nutrition-"
+	snapshotSuffix = ".db.gz"
+	timeLayout     = "20060102T150405Z"
+)
+
+// SnapshotName returns the replica object name for a snapshot taken at t
+func SnapshotName(t time.Time) string {
+	return snapshotPrefix + t.UTC().Format(timeLayout) + snapshotSuffix
+}
+
+// SnapshotTime parses the time encoded in a snapshot name
+func SnapshotTime(name string) (time.Time, error) {
+	raw := strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), snapshotSuffix)
+	return time.Parse(timeLayout, raw)
+}
+
+// Snapshot writes a consistent copy of the live database to dest using
+// VACUUM INTO, which does not block readers or writers for long.
+func Snapshot(ctx context.Context, db *sql.DB, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+		return err
+	}
+	if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", dest); err != nil {
+		return fmt.Errorf("snapshot failed: %w", err)
+	}
+	return nil
+}
+
+// Upload snapshots the database and pushes the compressed copy to the replica
+func Upload(ctx context.Context, db *sql.DB, replica Replica, now time.Time) (string, int64, error) {
+	tmpDir, err := os.MkdirTemp("", "nutrition-backup-")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	raw := filepath.Join(tmpDir, "snapshot.db")
+	if err := Snapshot(ctx, db, raw); err != nil {
+		return "", 0, err
+	}
+
+	compressed := raw + ".gz"
+	size, err := gzipFile(raw, compressed)
+	if err != nil {
+		return "", 0, err
+	}
+
+	f, err := os.Open(compressed)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	name := SnapshotName(now)
+	if err := replica.Put(ctx, name, f, size); err != nil {
+		return "", 0, fmt.Errorf("upload failed: %w", err)
+	}
+	return name, size, nil
+}
+
+// Restore replaces the database file at dbPath with the newest snapshot taken
+// at or before the given time. The server must not be running.
+func Restore(ctx context.Context, replica Replica, at time.Time, dbPath string) (string, error) {
+	names, err := replica.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var chosen string
+	for _, name := range names {
+		t, err := SnapshotTime(name)
+		if err != nil || t.After(at) {
+			continue
+		}
+		chosen = name // names are sorted oldest first
+	}
+	if chosen == "" {
+		return "", fmt.Errorf("no snapshot at or before %s", at.UTC().Format(time.RFC3339))
+	}
+
+	body, err := replica.Get(ctx, chosen)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", chosen, err)
+	}
+	defer body.Close()
+
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return "", fmt.Errorf("corrupt snapshot %s: %w", chosen, err)
+	}
+	defer gz.Close()
+
+	if err := replaceFile(dbPath, gz); err != nil {
+		return "", err
+	}
+	return chosen, nil
+}
+
+// RestoreFile replaces the database file at dbPath with a plain snapshot file
+func RestoreFile(src, dbPath string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return replaceFile(dbPath, f)
+}
+
+// replaceFile atomically swaps the database file and drops stale WAL/SHM files.
+// The new file is integrity-checked first so a corrupt snapshot never replaces
+// the live database.
+func replaceFile(dbPath string, r io.Reader) error {
+	tmp := dbPath + ".restore"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write restored database: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := VerifyFile(tmp); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("refusing to restore: %w", err)
+	}
+
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(tmp, dbPath)
+}
+
+func gzipFile(src, dest string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return 0, err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		out.Close()
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		return 0, err
+	}
+	if err := out.Close(); err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// VerifyFile makes sure a file is an intact SQLite database
+func VerifyFile(path string) error {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	problems, err := IntegrityCheck(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("snapshot failed integrity check: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// IntegrityCheck runs PRAGMA integrity_check and returns the problems found
+func IntegrityCheck(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("integrity check failed: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	return problems, rows.Err()
+}
@@ -0,0 +1,72 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Config controls continuous backup
+type Config struct {
+	Enabled bool
+	// Destination is "s3://bucket/prefix" or a local directory
+	Destination string
+	Interval    time.Duration
+	// Retain is how many snapshots to keep in the replica
+	Retain int
+	// MaxAge marks backups unhealthy when the last success is older than this
+	MaxAge time.Duration
+	S3     S3Config
+}
+
+// S3Config holds credentials for S3-compatible storage (AWS, MinIO, R2, ...)
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool
+}
+
+// LoadConfig reads backup settings from the environment
+func LoadConfig() Config {
+	interval := envconfig.Duration("BACKUP_INTERVAL", 5*time.Minute)
+	return Config{
+		Enabled:     envconfig.Bool("BACKUP_ENABLED", false),
+		Destination: envconfig.String("BACKUP_DESTINATION", "./data/backups"),
+		Interval:    interval,
+		Retain:      envconfig.Int("BACKUP_RETAIN", 288),
+		MaxAge:      envconfig.Duration("BACKUP_MAX_AGE", 3*interval),
+		S3: S3Config{
+			Endpoint:        envconfig.String("S3_ENDPOINT", ""),
+			Region:          envconfig.String("S3_REGION", "us-east-1"),
+			AccessKeyID:     envconfig.String("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: envconfig.String("S3_SECRET_ACCESS_KEY", ""),
+			PathStyle:       envconfig.Bool("S3_PATH_STYLE", true),
+		},
+	}
+}
+
+// Validate checks the settings without touching the destination
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval < time.Minute {
+		return fmt.Errorf("BACKUP_INTERVAL must be at least 1m")
+	}
+	if c.MaxAge <= c.Interval {
+		return fmt.Errorf("BACKUP_MAX_AGE must be longer than BACKUP_INTERVAL")
+	}
+	if strings.HasPrefix(c.Destination, "s3://") {
+		if bucket, _, _ := strings.Cut(strings.TrimPrefix(c.Destination, "s3://"), "/"); bucket == "" {
+			return fmt.Errorf("BACKUP_DESTINATION %q has no bucket", c.Destination)
+		}
+		if c.S3.AccessKeyID == "" || c.S3.SecretAccessKey == "" {
+			return fmt.Errorf("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required for S3 backups")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Replica is remote storage holding database snapshots
+type Replica interface {
+	Put(ctx context.Context, name string, r io.Reader, size int64) error
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	List(ctx context.Context) ([]string, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// NewReplica builds the replica for the configured destination
+func NewReplica(cfg Config) (Replica, error) {
+	if strings.HasPrefix(cfg.Destination, "s3://") {
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(cfg.Destination, "s3://"), "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("backup destination %q has no bucket", cfg.Destination)
+		}
+		if cfg.S3.AccessKeyID == "" || cfg.S3.SecretAccessKey == "" {
+			return nil, fmt.Errorf("S3 credentials are required for %s", cfg.Destination)
+		}
+		return NewS3Replica(cfg.S3, bucket, prefix), nil
+	}
+	return NewFileReplica(strings.TrimPrefix(cfg.Destination, "file://"))
+}
+
+// FileReplica stores snapshots in a local (or mounted) directory
+type FileReplica struct {
+	dir string
+}
+
+// NewFileReplica creates the directory if needed
+func NewFileReplica(dir string) (*FileReplica, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create backup dir: %w", err)
+	}
+	return &FileReplica{dir: dir}, nil
+}
+
+func (f *FileReplica) Put(ctx context.Context, name string, r io.Reader, size int64) error {
+	tmp := filepath.Join(f.dir, "."+name+".tmp")
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(f.dir, name))
+}
+
+func (f *FileReplica) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(f.dir, name))
+}
+
+func (f *FileReplica) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), snapshotSuffix) && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *FileReplica) Delete(ctx context.Context, name string) error {
+	return os.Remove(filepath.Join(f.dir, name))
+}
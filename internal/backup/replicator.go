@@ -0,0 +1,112 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+)
+
+// Status is the health of continuous backup, reported by /health/ready
+type Status struct {
+	Enabled     bool      `json:"enabled"`
+	Healthy     bool      `json:"healthy"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	LastName    string    `json:"last_snapshot,omitempty"`
+	LastSize    int64     `json:"last_size_bytes,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Replicator periodically ships snapshots of the database to a replica
+type Replicator struct {
+	db      *sql.DB
+	replica Replica
+	cfg     Config
+	started time.Time
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// NewReplicator creates a replicator
+func NewReplicator(db *sql.DB, replica Replica, cfg Config) *Replicator {
+	return &Replicator{
+		db:      db,
+		replica: replica,
+		cfg:     cfg,
+		started: time.Now(),
+		status:  Status{Enabled: true},
+	}
+}
+
+// Start takes a snapshot immediately, then once per interval until ctx is cancelled
+func (r *Replicator) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce uploads one snapshot and prunes old ones
+func (r *Replicator) RunOnce(ctx context.Context) {
+	name, size, err := Upload(ctx, r.db, r.replica, time.Now())
+
+	r.mu.Lock()
+	if err != nil {
+		r.status.LastError = err.Error()
+	} else {
+		r.status.LastError = ""
+		r.status.LastSuccess = time.Now().UTC()
+		r.status.LastName = name
+		r.status.LastSize = size
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		log.Printf("⚠️ Backup failed: %v", err)
+		return
+	}
+	if err := r.prune(ctx); err != nil {
+		log.Printf("⚠️ Backup retention cleanup failed: %v", err)
+	}
+}
+
+// Status returns the current backup health
+func (r *Replicator) Status() Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s := r.status
+	if s.LastSuccess.IsZero() {
+		// Allow one interval for the first snapshot before reporting unhealthy
+		s.Healthy = time.Since(r.started) < r.cfg.MaxAge
+	} else {
+		s.Healthy = time.Since(s.LastSuccess) < r.cfg.MaxAge
+	}
+	return s
+}
+
+func (r *Replicator) prune(ctx context.Context) error {
+	if r.cfg.Retain <= 0 {
+		return nil
+	}
+	names, err := r.replica.List(ctx)
+	if err != nil {
+		return err
+	}
+	for len(names) > r.cfg.Retain {
+		if err := r.replica.Delete(ctx, names[0]); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package backup
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/awssig"
+)
+
+// S3Replica stores snapshots in an S3-compatible bucket using SigV4-signed requests
+type S3Replica struct {
+	cfg    S3Config
+	bucket string
+	prefix string
+	client *http.Client
+}
+
+// NewS3Replica creates an S3 replica; prefix may be empty
+func NewS3Replica(cfg S3Config, bucket, prefix string) *S3Replica {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	return &S3Replica{
+		cfg:    cfg,
+		bucket: bucket,
+		prefix: strings.Trim(prefix, "/"),
+		client: &http.Client{Timeout: 10 * time.Minute},
+	}
+}
+
+func (s *S3Replica) Put(ctx context.Context, name string, r io.Reader, size int64) error {
+	req, err := s.request(ctx, http.MethodPut, s.key(name), nil, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	_, err = s.do(req, http.StatusOK)
+	return err
+}
+
+func (s *S3Replica) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, s.key(name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.do(req, http.StatusOK)
+}
+
+func (s *S3Replica) Delete(ctx context.Context, name string) error {
+	req, err := s.request(ctx, http.MethodDelete, s.key(name), nil, nil)
+	if err != nil {
+		return err
+	}
+	body, err := s.do(req, http.StatusNoContent)
+	if body != nil {
+		body.Close()
+	}
+	return err
+}
+
+type listResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *S3Replica) List(ctx context.Context) ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}}
+		if s.prefix != "" {
+			q.Set("prefix", s.prefix+"/")
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+
+		req, err := s.request(ctx, http.MethodGet, "", q, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := s.do(req, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+		var res listResult
+		err = xml.NewDecoder(body).Decode(&res)
+		body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid S3 list response: %w", err)
+		}
+
+		for _, c := range res.Contents {
+			if name := path.Base(c.Key); strings.HasSuffix(name, snapshotSuffix) {
+				names = append(names, name)
+			}
+		}
+		if !res.IsTruncated || res.NextContinuationToken == "" {
+			break
+		}
+		token = res.NextContinuationToken
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *S3Replica) key(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "/" + name
+}
+
+// request builds a signed request for an object key (or the bucket when key is empty)
+func (s *S3Replica) request(ctx context.Context, method, key string, query url.Values, body io.Reader) (*http.Request, error) {
+	u, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if s.cfg.PathStyle {
+		u.Path = "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	u.RawQuery = awssig.CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	creds := awssig.Credentials{AccessKeyID: s.cfg.AccessKeyID, SecretAccessKey: s.cfg.SecretAccessKey}
+	awssig.Sign(req, creds, s.cfg.Region, "s3", awssig.UnsignedPayload, time.Now())
+	return req, nil
+}
+
+func (s *S3Replica) do(req *http.Request, want int) (io.ReadCloser, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != want && !(want == http.StatusNoContent && resp.StatusCode == http.StatusOK) {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("S3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,203 @@
+package batchcook
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"nutrition-health-backend/internal/localtime"
+)
+
+// DefaultShelfLifeDays is how long cooked leftovers keep when a recipe sets no limit
+const DefaultShelfLifeDays = 4
+
+// Batch is a recipe cooked once for several meals
+type Batch struct {
+	ID       int64   `json:"id"`
+	UserID   string  `json:"-"`
+	RecipeID string  `json:"recipe_id"`
+	CookDate string  `json:"cook_date"`
+	CookSlot string  `json:"cook_slot,omitempty"`
+	Servings float64 `json:"servings"`
+	// ShelfLifeDays bounds how many days after CookDate portions may be eaten
+	ShelfLifeDays int       `json:"shelf_life_days"`
+	Portions      []Portion `json:"portions"`
+}
+
+// Portion is part of a batch eaten at a planned meal
+type Portion struct {
+	Date     string  `json:"date"`
+	Slot     string  `json:"slot"`
+	Servings float64 `json:"servings"`
+}
+
+// Slot is a free meal slot the scheduler may fill
+type Slot struct {
+	Date string `json:"date"`
+	Slot string `json:"slot"`
+}
+
+// Remaining returns the servings not yet assigned to a portion
+func (b Batch) Remaining() float64 {
+	left := b.Servings
+	for _, p := range b.Portions {
+		left -= p.Servings
+	}
+	return math.Max(0, round(left))
+}
+
+// Validate checks that portions fit the batch: eaten on or after the cook
+// date, within shelf life, and not more than was cooked
+func (b Batch) Validate() error {
+	if b.RecipeID == "" {
+		return fmt.Errorf("recipe_id is required")
+	}
+	if b.Servings <= 0 {
+		return fmt.Errorf("servings must be positive")
+	}
+	cook, err := time.Parse(localtime.DateLayout, b.CookDate)
+	if err != nil {
+		return fmt.Errorf("invalid cook_date %q", b.CookDate)
+	}
+	if b.ShelfLifeDays < 0 {
+		return fmt.Errorf("shelf_life_days must not be negative")
+	}
+	last := cook.AddDate(0, 0, b.shelfLife())
+	total := 0.0
+	seen := map[Slot]bool{}
+	for _, p := range b.Portions {
+		d, err := time.Parse(localtime.DateLayout, p.Date)
+		if err != nil {
+			return fmt.Errorf("invalid portion date %q", p.Date)
+		}
+		if d.Before(cook) || d.After(last) {
+			return fmt.Errorf("portion on %s is outside %s to %s", p.Date, b.CookDate, last.Format(localtime.DateLayout))
+		}
+		if p.Servings <= 0 {
+			return fmt.Errorf("portion servings must be positive")
+		}
+		key := Slot{Date: p.Date, Slot: p.Slot}
+		if seen[key] {
+			return fmt.Errorf("two portions on %s %s", p.Date, p.Slot)
+		}
+		seen[key] = true
+		total += p.Servings
+	}
+	if round(total) > b.Servings {
+		return fmt.Errorf("portions use %.2f servings but only %.2f are cooked", total, b.Servings)
+	}
+	return nil
+}
+
+func (b Batch) shelfLife() int {
+	if b.ShelfLifeDays > 0 {
+		return b.ShelfLifeDays
+	}
+	return DefaultShelfLifeDays
+}
+
+// Schedule assigns the batch's remaining servings to free slots, perMeal at a
+// time. Slots outside the shelf life are skipped, and meals are spread at
+// least minGapDays apart so leftovers don't repeat day after day.
+func Schedule(b Batch, free []Slot, perMeal float64, minGapDays int) Batch {
+	if perMeal <= 0 {
+		perMeal = 1
+	}
+	cook, err := time.Parse(localtime.DateLayout, b.CookDate)
+	if err != nil {
+		return b
+	}
+	last := cook.AddDate(0, 0, b.shelfLife())
+
+	slots := append([]Slot{}, free...)
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].Date < slots[j].Date })
+
+	var lastEaten time.Time
+	for _, p := range b.Portions {
+		if d, err := time.Parse(localtime.DateLayout, p.Date); err == nil && d.After(lastEaten) {
+			lastEaten = d
+		}
+	}
+	taken := map[Slot]bool{}
+	for _, p := range b.Portions {
+		taken[Slot{Date: p.Date, Slot: p.Slot}] = true
+	}
+
+	for _, s := range slots {
+		left := b.Remaining()
+		if left <= 0 {
+			break
+		}
+		d, err := time.Parse(localtime.DateLayout, s.Date)
+		if err != nil || d.Before(cook) || d.After(last) || taken[s] {
+			continue
+		}
+		if !lastEaten.IsZero() && d.Sub(lastEaten) < time.Duration(minGapDays)*24*time.Hour {
+			continue
+		}
+		b.Portions = append(b.Portions, Portion{Date: s.Date, Slot: s.Slot, Servings: math.Min(perMeal, left)})
+		taken[s] = true
+		lastEaten = d
+	}
+	return b
+}
+
+// RecipeServings is how many servings of a recipe to shop for
+type RecipeServings struct {
+	RecipeID string  `json:"recipe_id"`
+	Servings float64 `json:"servings"`
+}
+
+// ShoppingServings returns the servings to buy ingredients for, for batches
+// cooked in [from, to). A batch counts once, for everything cooked, on its
+// cook date; its leftover portions add nothing, even when eaten after to.
+func ShoppingServings(batches []Batch, from, to string) []RecipeServings {
+	totals := map[string]float64{}
+	for _, b := range batches {
+		if b.CookDate >= from && b.CookDate < to {
+			totals[b.RecipeID] += b.Servings
+		}
+	}
+	out := make([]RecipeServings, 0, len(totals))
+	for id, servings := range totals {
+		out = append(out, RecipeServings{RecipeID: id, Servings: round(servings)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RecipeID < out[j].RecipeID })
+	return out
+}
+
+// PrefillEntry is a diary entry suggested from a planned portion
+type PrefillEntry struct {
+	BatchID  int64   `json:"batch_id"`
+	RecipeID string  `json:"recipe_id"`
+	Slot     string  `json:"slot"`
+	Servings float64 `json:"servings"`
+	Leftover bool    `json:"leftover"`
+}
+
+// Prefill returns the diary entries for date: portions eaten that day,
+// flagged as leftovers unless eaten at the meal the batch was cooked for
+func Prefill(batches []Batch, date string) []PrefillEntry {
+	var entries []PrefillEntry
+	for _, b := range batches {
+		for _, p := range b.Portions {
+			if p.Date != date {
+				continue
+			}
+			entries = append(entries, PrefillEntry{
+				BatchID:  b.ID,
+				RecipeID: b.RecipeID,
+				Slot:     p.Slot,
+				Servings: p.Servings,
+				Leftover: p.Date != b.CookDate || p.Slot != b.CookSlot,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Slot < entries[j].Slot })
+	return entries
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,175 @@
+package batchcook
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nutrition-health-backend/internal/localtime"
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes batch cooking over HTTP
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a batch cooking handler
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes mounts /plans/batches routes on an authenticated group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/plans/batches", h.List)
+	g.POST("/plans/batches", h.Create)
+	g.PUT("/plans/batches/:id", h.Update)
+	g.DELETE("/plans/batches/:id", h.Delete)
+	g.GET("/plans/batches/shopping", h.Shopping)
+	g.GET("/plans/batches/prefill", h.Prefill)
+}
+
+type batchRequest struct {
+	Batch
+	// Free lists open meal slots to fill automatically with the remaining servings
+	Free       []Slot  `json:"free,omitempty"`
+	PerMeal    float64 `json:"per_meal,omitempty"`
+	MinGapDays int     `json:"min_gap_days,omitempty"`
+}
+
+// List returns batches overlapping ?from=&to= (local dates, default this week)
+func (h *Handler) List(c echo.Context) error {
+	userID, from, to, err := h.scope(c)
+	if err != nil {
+		return err
+	}
+	batches, err := h.store.Between(c.Request().Context(), userID, from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"from": from, "to": to, "batches": batches})
+}
+
+// Create saves a new batch, scheduling remaining servings into free slots when given
+func (h *Handler) Create(c echo.Context) error {
+	return h.save(c, 0)
+}
+
+// Update replaces a batch's schedule
+func (h *Handler) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid batch id")
+	}
+	return h.save(c, id)
+}
+
+func (h *Handler) save(c echo.Context, id int64) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	var req batchRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	b := req.Batch
+	b.ID, b.UserID = id, userID
+	if len(req.Free) > 0 {
+		b = Schedule(b, req.Free, req.PerMeal, req.MinGapDays)
+	}
+	if err := b.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	saved, err := h.store.Save(c.Request().Context(), b)
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	status := http.StatusOK
+	if id == 0 {
+		status = http.StatusCreated
+	}
+	return c.JSON(status, map[string]interface{}{"batch": saved, "remaining": saved.Remaining()})
+}
+
+// Delete removes a batch
+func (h *Handler) Delete(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid batch id")
+	}
+	if err := h.store.Delete(c.Request().Context(), userID, id); errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Shopping returns the recipe servings to shop for in the range
+func (h *Handler) Shopping(c echo.Context) error {
+	userID, from, to, err := h.scope(c)
+	if err != nil {
+		return err
+	}
+	batches, err := h.store.Between(c.Request().Context(), userID, from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"from": from, "to": to, "recipes": ShoppingServings(batches, from, to)})
+}
+
+// Prefill returns diary suggestions for ?date= (default today in the user's zone)
+func (h *Handler) Prefill(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	date := c.QueryParam("date")
+	if date == "" {
+		date = localtime.Date(time.Now(), localtime.FromContext(c.Request().Context()))
+	}
+	if _, err := time.Parse(localtime.DateLayout, date); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid date")
+	}
+	next, _ := time.Parse(localtime.DateLayout, date)
+	batches, err := h.store.Between(c.Request().Context(), userID, date, next.AddDate(0, 0, 1).Format(localtime.DateLayout))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"date": date, "entries": Prefill(batches, date)})
+}
+
+// scope returns the user and the local date range, defaulting to the current week
+func (h *Handler) scope(c echo.Context) (userID, from, to string, err error) {
+	userID = reqctx.UserID(c)
+	if userID == "" {
+		return "", "", "", echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	loc := localtime.FromContext(c.Request().Context())
+	start, end := localtime.WeekBounds(time.Now(), loc, time.Monday)
+	from, to = c.QueryParam("from"), c.QueryParam("to")
+	if from == "" {
+		from = localtime.Date(start, loc)
+	}
+	if to == "" {
+		to = localtime.Date(end, loc)
+	}
+	if _, err := time.Parse(localtime.DateLayout, from); err != nil {
+		return "", "", "", echo.NewHTTPError(http.StatusBadRequest, "invalid from date")
+	}
+	if _, err := time.Parse(localtime.DateLayout, to); err != nil {
+		return "", "", "", echo.NewHTTPError(http.StatusBadRequest, "invalid to date")
+	}
+	return userID, from, to, nil
+}
@@ -0,0 +1,142 @@
+package batchcook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is returned when a batch does not exist for the user
+var ErrNotFound = errors.New("batch not found")
+
+// Store persists batches; portions are stored with their batch
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a batch store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate creates the cooking_batches table
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS cooking_batches (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			recipe_id TEXT NOT NULL,
+			cook_date TEXT NOT NULL,
+			cook_slot TEXT NOT NULL DEFAULT '',
+			servings REAL NOT NULL,
+			shelf_life_days INTEGER NOT NULL DEFAULT 0,
+			last_portion_date TEXT NOT NULL,
+			portions TEXT NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_cooking_batches_user ON cooking_batches(user_id, cook_date, last_portion_date)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save inserts or updates a batch after validating it
+func (s *Store) Save(ctx context.Context, b Batch) (Batch, error) {
+	if err := b.Validate(); err != nil {
+		return Batch{}, err
+	}
+	if b.Portions == nil {
+		b.Portions = []Portion{}
+	}
+	data, err := json.Marshal(b.Portions)
+	if err != nil {
+		return Batch{}, err
+	}
+	lastDate := b.CookDate
+	for _, p := range b.Portions {
+		if p.Date > lastDate {
+			lastDate = p.Date
+		}
+	}
+	now := time.Now().UTC()
+	if b.ID == 0 {
+		res, err := s.db.ExecContext(ctx,
+			`INSERT INTO cooking_batches (user_id, recipe_id, cook_date, cook_slot, servings, shelf_life_days, last_portion_date, portions, updated_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			b.UserID, b.RecipeID, b.CookDate, b.CookSlot, b.Servings, b.ShelfLifeDays, lastDate, string(data), now)
+		if err != nil {
+			return Batch{}, fmt.Errorf("failed to save batch: %w", err)
+		}
+		b.ID, _ = res.LastInsertId()
+		return b, nil
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE cooking_batches SET recipe_id = ?, cook_date = ?, cook_slot = ?, servings = ?, shelf_life_days = ?,
+		 last_portion_date = ?, portions = ?, updated_at = ? WHERE id = ? AND user_id = ?`,
+		b.RecipeID, b.CookDate, b.CookSlot, b.Servings, b.ShelfLifeDays, lastDate, string(data), now, b.ID, b.UserID)
+	if err != nil {
+		return Batch{}, fmt.Errorf("failed to save batch: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return Batch{}, ErrNotFound
+	}
+	return b, nil
+}
+
+// Delete removes a batch
+func (s *Store) Delete(ctx context.Context, userID string, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM cooking_batches WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Get returns one batch
+func (s *Store) Get(ctx context.Context, userID string, id int64) (Batch, error) {
+	batches, err := s.query(ctx, `WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return Batch{}, err
+	}
+	if len(batches) == 0 {
+		return Batch{}, ErrNotFound
+	}
+	return batches[0], nil
+}
+
+// Between returns batches cooked or eaten in the local date range [from, to)
+func (s *Store) Between(ctx context.Context, userID, from, to string) ([]Batch, error) {
+	return s.query(ctx, `WHERE user_id = ? AND cook_date < ? AND last_portion_date >= ? ORDER BY cook_date, id`, userID, to, from)
+}
+
+func (s *Store) query(ctx context.Context, where string, args ...interface{}) ([]Batch, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, recipe_id, cook_date, cook_slot, servings, shelf_life_days, portions FROM cooking_batches `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	batches := []Batch{}
+	for rows.Next() {
+		var b Batch
+		var portions string
+		if err := rows.Scan(&b.ID, &b.UserID, &b.RecipeID, &b.CookDate, &b.CookSlot, &b.Servings, &b.ShelfLifeDays, &portions); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(portions), &b.Portions); err != nil {
+			return nil, fmt.Errorf("batch %d has invalid portions: %w", b.ID, err)
+		}
+		batches = append(batches, b)
+	}
+	return batches, rows.Err()
+}
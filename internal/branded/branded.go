@@ -0,0 +1,284 @@
+package branded
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/textmatch"
+	"nutrition-health-backend/internal/txn"
+)
+
+// Food is a branded product or restaurant menu item, nutrients per serving
+type Food struct {
+	ID         int64   `json:"id"`
+	Source     string  `json:"source"`
+	ExternalID string  `json:"external_id,omitempty"`
+	Chain      string  `json:"chain"`
+	Item       string  `json:"item"`
+	Region     string  `json:"region,omitempty"`
+	Serving    string  `json:"serving,omitempty"`
+	ServingG   float64 `json:"serving_g,omitempty"`
+	Calories   float64 `json:"calories"`
+	ProteinG   float64 `json:"protein_g"`
+	CarbsG     float64 `json:"carbs_g"`
+	FatG       float64 `json:"fat_g"`
+}
+
+// Validate checks a food before it is stored
+func (f Food) Validate() error {
+	if strings.TrimSpace(f.Chain) == "" || strings.TrimSpace(f.Item) == "" {
+		return fmt.Errorf("chain and item are required")
+	}
+	if f.Calories < 0 || f.ProteinG < 0 || f.CarbsG < 0 || f.FatG < 0 || f.ServingG < 0 {
+		return fmt.Errorf("nutrient values must not be negative")
+	}
+	return nil
+}
+
+// Migrate creates the branded_foods table
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS branded_foods (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			source TEXT NOT NULL,
+			external_id TEXT NOT NULL,
+			chain TEXT NOT NULL,
+			chain_norm TEXT NOT NULL,
+			item TEXT NOT NULL,
+			item_norm TEXT NOT NULL,
+			region TEXT NOT NULL DEFAULT '',
+			serving TEXT NOT NULL DEFAULT '',
+			serving_g REAL NOT NULL DEFAULT 0,
+			calories REAL NOT NULL,
+			protein_g REAL NOT NULL DEFAULT 0,
+			carbs_g REAL NOT NULL DEFAULT 0,
+			fat_g REAL NOT NULL DEFAULT 0,
+			updated_at DATETIME NOT NULL,
+			UNIQUE (source, external_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_branded_foods_chain ON branded_foods(chain_norm)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Store persists branded foods and caches the chain list for brand matching
+type Store struct {
+	db *sql.DB
+
+	mu       sync.RWMutex
+	chains   []string
+	loadedAt time.Time
+}
+
+// NewStore creates a branded food store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// externalID derives a stable key for rows without one, so re-imports update in place
+func externalID(f Food) string {
+	if f.ExternalID != "" {
+		return f.ExternalID
+	}
+	return textmatch.Normalize(f.Chain) + "|" + textmatch.Normalize(f.Item) + "|" + strings.ToLower(f.Region)
+}
+
+// upsert inserts or updates f by (source, external_id) and reports whether it was new
+func upsert(ctx context.Context, q txn.Querier, f Food) (bool, error) {
+	now := time.Now().UTC()
+	res, err := q.ExecContext(ctx,
+		`UPDATE branded_foods SET chain = ?, chain_norm = ?, item = ?, item_norm = ?, region = ?, serving = ?, serving_g = ?,
+		 calories = ?, protein_g = ?, carbs_g = ?, fat_g = ?, updated_at = ? WHERE source = ? AND external_id = ?`,
+		f.Chain, textmatch.Normalize(f.Chain), f.Item, textmatch.Normalize(f.Item), strings.ToLower(f.Region), f.Serving, f.ServingG,
+		f.Calories, f.ProteinG, f.CarbsG, f.FatG, now, f.Source, externalID(f))
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return false, nil
+	}
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO branded_foods (source, external_id, chain, chain_norm, item, item_norm, region, serving, serving_g,
+		 calories, protein_g, carbs_g, fat_g, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		f.Source, externalID(f), f.Chain, textmatch.Normalize(f.Chain), f.Item, textmatch.Normalize(f.Item), strings.ToLower(f.Region),
+		f.Serving, f.ServingG, f.Calories, f.ProteinG, f.CarbsG, f.FatG, now)
+	return err == nil, err
+}
+
+// Chains returns the distinct chain names, cached for a minute
+func (s *Store) Chains(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	if s.chains != nil && time.Since(s.loadedAt) < time.Minute {
+		chains := s.chains
+		s.mu.RUnlock()
+		return chains, nil
+	}
+	s.mu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT chain FROM branded_foods ORDER BY chain`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	chains := []string{}
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+		chains = append(chains, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.chains, s.loadedAt = chains, time.Now()
+	s.mu.Unlock()
+	return chains, nil
+}
+
+func (s *Store) invalidate() {
+	s.mu.Lock()
+	s.chains = nil
+	s.mu.Unlock()
+}
+
+// SearchOptions filters a branded search
+type SearchOptions struct {
+	Query  string
+	Region string
+	Limit  int
+}
+
+// Result is a scored search hit
+type Result struct {
+	Food
+	Score float64 `json:"score"`
+}
+
+// Boosts applied on top of item similarity
+const (
+	chainBoost  = 0.5
+	regionBoost = 0.1
+)
+
+// chainThreshold is the minimum similarity for a query phrase to count as a brand
+func chainThreshold() float64 {
+	return envconfig.Float("BRANDED_CHAIN_THRESHOLD", 0.45)
+}
+
+// MatchChain finds the brand named in query, tolerating misspellings and
+// spacing ("mcdonalds", "mc donald's"). It returns the chain and the query
+// with the brand phrase removed.
+func (s *Store) MatchChain(ctx context.Context, query string) (chain, rest string, err error) {
+	chains, err := s.Chains(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	words := strings.Fields(textmatch.Normalize(query))
+	best, bestScore, bestFrom, bestTo := "", chainThreshold(), 0, 0
+	for _, c := range chains {
+		cn := textmatch.Normalize(c)
+		size := len(strings.Fields(cn))
+		for n := 1; n <= size+1 && n <= len(words); n++ {
+			for i := 0; i+n <= len(words); i++ {
+				phrase := strings.Join(words[i:i+n], " ")
+				if score := textmatch.BestSimilarity(phrase, cn); score > bestScore {
+					best, bestScore, bestFrom, bestTo = c, score, i, i+n
+				}
+			}
+		}
+	}
+	if best == "" {
+		return "", strings.Join(words, " "), nil
+	}
+	remaining := append(append([]string{}, words[:bestFrom]...), words[bestTo:]...)
+	return best, strings.Join(remaining, " "), nil
+}
+
+// Search returns branded foods ranked by item similarity, boosting items from
+// the brand named in the query and from the caller's region
+func (s *Store) Search(ctx context.Context, opts SearchOptions) ([]Result, error) {
+	if opts.Limit <= 0 || opts.Limit > 50 {
+		opts.Limit = 20
+	}
+	chain, rest, err := s.MatchChain(ctx, opts.Query)
+	if err != nil {
+		return nil, err
+	}
+	if chain == "" && rest == "" {
+		return []Result{}, nil
+	}
+
+	var where []string
+	var args []interface{}
+	if chain != "" {
+		where = append(where, "chain_norm = ?")
+		args = append(args, textmatch.Normalize(chain))
+	}
+	for _, w := range strings.Fields(rest) {
+		where = append(where, "item_norm LIKE ?")
+		args = append(args, "%"+prefix(w)+"%")
+	}
+	sep := " OR "
+	if chain != "" && rest == "" {
+		sep = " AND "
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, source, external_id, chain, item, region, serving, serving_g, calories, protein_g, carbs_g, fat_g
+		 FROM branded_foods WHERE `+strings.Join(where, sep)+` LIMIT 500`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	region := strings.ToLower(opts.Region)
+	results := []Result{}
+	for rows.Next() {
+		var f Food
+		if err := rows.Scan(&f.ID, &f.Source, &f.ExternalID, &f.Chain, &f.Item, &f.Region, &f.Serving, &f.ServingG,
+			&f.Calories, &f.ProteinG, &f.CarbsG, &f.FatG); err != nil {
+			return nil, err
+		}
+		score := 1.0
+		if rest != "" {
+			score = textmatch.Similarity(rest, textmatch.Normalize(f.Item))
+		}
+		if chain != "" && f.Chain == chain {
+			score += chainBoost
+		}
+		if region != "" && f.Region == region {
+			score += regionBoost
+		}
+		results = append(results, Result{Food: f, Score: score})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results, nil
+}
+
+// prefix shortens a word to its first four letters for LIKE matching, so
+// misspelled endings ("whoper") still find candidates for similarity scoring
+func prefix(w string) string {
+	r := []rune(w)
+	if len(r) > 4 {
+		r = r[:4]
+	}
+	return string(r)
+}
@@ -0,0 +1,65 @@
+package branded
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes branded search and imports over HTTP
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a branded food handler
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes mounts GET /foods/branded and GET /foods/branded/chains
+func (h *Handler) RegisterRoutes(api *echo.Group) {
+	api.GET("/foods/branded", h.Search)
+	api.GET("/foods/branded/chains", h.Chains)
+}
+
+// RegisterAdminRoutes mounts POST /branded/import?source=... (CSV body) on an admin-protected group
+func (h *Handler) RegisterAdminRoutes(g *echo.Group) {
+	g.POST("/branded/import", h.Import)
+}
+
+// Search handles GET /foods/branded?q=...&region=...&limit=...
+func (h *Handler) Search(c echo.Context) error {
+	q := c.QueryParam("q")
+	if q == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "q is required")
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	results, err := h.store.Search(c.Request().Context(), SearchOptions{Query: q, Region: c.QueryParam("region"), Limit: limit})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"query": q, "results": results})
+}
+
+// Chains lists the known chains
+func (h *Handler) Chains(c echo.Context) error {
+	chains, err := h.store.Chains(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"chains": chains})
+}
+
+// Import loads a CSV export of a branded dataset
+func (h *Handler) Import(c echo.Context) error {
+	source := c.QueryParam("source")
+	if source == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "source is required")
+	}
+	result, err := h.store.Import(c.Request().Context(), c.Request().Body, source)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,146 @@
+package branded
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"nutrition-health-backend/internal/txn"
+)
+
+// columnAliases maps header names used by public branded datasets onto Food fields
+var columnAliases = map[string]string{
+	"chain": "chain", "brand": "chain", "brand_name": "chain", "brand_owner": "chain", "restaurant": "chain", "company": "chain",
+	"item": "item", "item_name": "item", "description": "item", "product_name": "item", "menu_item": "item", "name": "item",
+	"region": "region", "country": "region", "market": "region",
+	"serving": "serving", "serving_size": "serving", "household_serving_fulltext": "serving",
+	"serving_g": "serving_g", "serving_weight_g": "serving_g",
+	"calories": "calories", "energy_kcal": "calories", "kcal": "calories",
+	"protein": "protein_g", "protein_g": "protein_g",
+	"carbs": "carbs_g", "carbs_g": "carbs_g", "carbohydrate_g": "carbs_g", "total_carbohydrates": "carbs_g",
+	"fat": "fat_g", "fat_g": "fat_g", "total_fat": "fat_g",
+	"id": "external_id", "external_id": "external_id", "fdc_id": "external_id", "gtin_upc": "external_id", "code": "external_id",
+}
+
+// ImportResult summarises an import run
+type ImportResult struct {
+	Inserted int      `json:"inserted"`
+	Updated  int      `json:"updated"`
+	Skipped  int      `json:"skipped"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// maxReportedErrors caps the row errors kept in an ImportResult
+const maxReportedErrors = 50
+
+// Import reads a CSV of branded foods and upserts them under source in one
+// transaction. Columns are matched by name (see columnAliases); rows that fail
+// validation are skipped and reported, not fatal.
+func (s *Store) Import(ctx context.Context, r io.Reader, source string) (ImportResult, error) {
+	var result ImportResult
+	if source == "" {
+		return result, fmt.Errorf("source is required")
+	}
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return result, fmt.Errorf("failed to read header: %w", err)
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := columnAliases[key]; ok {
+			if _, dup := index[field]; !dup {
+				index[field] = i
+			}
+		}
+	}
+	for _, required := range []string{"chain", "item", "calories"} {
+		if _, ok := index[required]; !ok {
+			return result, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	err = txn.NewManager(s.db).Do(ctx, func(ctx context.Context) error {
+		tx, _ := txn.FromContext(ctx)
+		for line := 2; ; line++ {
+			rec, err := cr.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			f, err := parseRecord(rec, index)
+			if err == nil {
+				err = f.Validate()
+			}
+			if err != nil {
+				result.Skipped++
+				if len(result.Errors) < maxReportedErrors {
+					result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+				}
+				continue
+			}
+			f.Source = source
+			inserted, err := upsert(ctx, tx, f)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if inserted {
+				result.Inserted++
+			} else {
+				result.Updated++
+			}
+		}
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+	s.invalidate()
+	return result, nil
+}
+
+func parseRecord(rec []string, index map[string]int) (Food, error) {
+	get := func(field string) string {
+		if i, ok := index[field]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	num := func(field string) (float64, error) {
+		v := get(field)
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", field, v)
+		}
+		return n, nil
+	}
+	f := Food{
+		ExternalID: get("external_id"),
+		Chain:      get("chain"),
+		Item:       get("item"),
+		Region:     get("region"),
+		Serving:    get("serving"),
+	}
+	var err error
+	for field, dst := range map[string]*float64{
+		"serving_g": &f.ServingG, "calories": &f.Calories, "protein_g": &f.ProteinG, "carbs_g": &f.CarbsG, "fat_g": &f.FatG,
+	} {
+		if *dst, err = num(field); err != nil {
+			return Food{}, err
+		}
+	}
+	if get("calories") == "" {
+		return Food{}, fmt.Errorf("calories is required")
+	}
+	return f, nil
+}
@@ -0,0 +1,256 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/localtime"
+)
+
+// ErrNotFound is returned for unknown or revoked feed tokens
+var ErrNotFound = errors.New("calendar feed not found")
+
+// Meal is one planned meal from the user's meal plan
+type Meal struct {
+	ID       string        `json:"id"`
+	Slot     string        `json:"slot"`
+	Name     string        `json:"name"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Calories float64       `json:"calories"`
+	Notes    string        `json:"notes,omitempty"`
+}
+
+// MealSource lists planned meals in [from, to); implemented by the plan service
+type MealSource interface {
+	PlannedMeals(ctx context.Context, userID string, from, to time.Time) ([]Meal, error)
+}
+
+// Window is a daily local time range in "HH:MM", which may cross midnight
+type Window struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// WeighIn is a weekly reminder; Weekday is an RFC 5545 day such as "MO"
+type WeighIn struct {
+	Weekday string `json:"weekday"`
+	Time    string `json:"time"`
+}
+
+// Options selects the optional feed components
+type Options struct {
+	Meals        bool     `json:"meals"`
+	Fasting      *Window  `json:"fasting,omitempty"`
+	WeighIn      *WeighIn `json:"weigh_in,omitempty"`
+	AlarmMinutes int      `json:"alarm_minutes,omitempty"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Validate checks times and weekdays
+func (o Options) Validate() error {
+	if o.Fasting != nil {
+		if _, _, err := clock(o.Fasting.Start); err != nil {
+			return fmt.Errorf("fasting start: %w", err)
+		}
+		if _, _, err := clock(o.Fasting.End); err != nil {
+			return fmt.Errorf("fasting end: %w", err)
+		}
+	}
+	if o.WeighIn != nil {
+		if _, ok := weekdays[strings.ToUpper(o.WeighIn.Weekday)]; !ok {
+			return fmt.Errorf("weigh-in weekday must be one of SU, MO, TU, WE, TH, FR, SA")
+		}
+		if _, _, err := clock(o.WeighIn.Time); err != nil {
+			return fmt.Errorf("weigh-in time: %w", err)
+		}
+	}
+	if o.AlarmMinutes < 0 || o.AlarmMinutes > 24*60 {
+		return fmt.Errorf("alarm_minutes must be between 0 and 1440")
+	}
+	return nil
+}
+
+func clock(s string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// Feeds issues per-user feed tokens and renders their calendars
+type Feeds struct {
+	db     *sql.DB
+	meals  MealSource
+	zones  *localtime.Store
+	past   int
+	future int
+}
+
+// NewFeeds creates the feed service; meals may be nil until the plan service provides it
+func NewFeeds(db *sql.DB, meals MealSource, zones *localtime.Store) *Feeds {
+	return &Feeds{
+		db:     db,
+		meals:  meals,
+		zones:  zones,
+		past:   envconfig.Int("CALENDAR_FEED_PAST_DAYS", 7),
+		future: envconfig.Int("CALENDAR_FEED_FUTURE_DAYS", 28),
+	}
+}
+
+// Migrate creates the calendar_feeds table
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS calendar_feeds (
+		user_id TEXT PRIMARY KEY,
+		token TEXT NOT NULL UNIQUE,
+		options TEXT NOT NULL,
+		created_at DATETIME NOT NULL
+	)`)
+	return err
+}
+
+// Issue creates or replaces the user's feed; the previous token stops working
+func (f *Feeds) Issue(ctx context.Context, userID string, opts Options) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.db.ExecContext(ctx,
+		`INSERT INTO calendar_feeds (user_id, token, options, created_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET token = excluded.token, options = excluded.options, created_at = excluded.created_at`,
+		userID, token, string(data), time.Now().UTC())
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Revoke deletes the user's feed
+func (f *Feeds) Revoke(ctx context.Context, userID string) error {
+	_, err := f.db.ExecContext(ctx, `DELETE FROM calendar_feeds WHERE user_id = ?`, userID)
+	return err
+}
+
+// lookup resolves a token to its user and options
+func (f *Feeds) lookup(ctx context.Context, token string) (string, Options, error) {
+	var userID, data string
+	err := f.db.QueryRowContext(ctx, `SELECT user_id, options FROM calendar_feeds WHERE token = ?`, token).Scan(&userID, &data)
+	if err == sql.ErrNoRows {
+		return "", Options{}, ErrNotFound
+	}
+	if err != nil {
+		return "", Options{}, err
+	}
+	var opts Options
+	if err := json.Unmarshal([]byte(data), &opts); err != nil {
+		return "", Options{}, err
+	}
+	return userID, opts, nil
+}
+
+// Render builds the ICS document for token
+func (f *Feeds) Render(ctx context.Context, token string) (string, error) {
+	userID, opts, err := f.lookup(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	loc, err := f.zones.Location(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	cal := Calendar{Name: "Meal plan", Location: loc}
+	alarm := time.Duration(opts.AlarmMinutes) * time.Minute
+
+	if opts.Meals && f.meals != nil {
+		from, _ := localtime.DayBounds(now.AddDate(0, 0, -f.past), loc)
+		_, to := localtime.DayBounds(now.AddDate(0, 0, f.future), loc)
+		meals, err := f.meals.PlannedMeals(ctx, userID, from, to)
+		if err != nil {
+			return "", err
+		}
+		for _, m := range meals {
+			cal.Events = append(cal.Events, mealEvent(m, alarm))
+		}
+	}
+
+	dayStart, _ := localtime.DayBounds(now, loc)
+	if w := opts.Fasting; w != nil {
+		sh, sm, _ := clock(w.Start)
+		eh, em, _ := clock(w.End)
+		start := localtime.Next(dayStart.Add(-time.Second), loc, sh, sm)
+		cal.Events = append(cal.Events, Event{
+			UID:        "fasting-" + userID + "@nutrition-health",
+			Summary:    "Fasting window",
+			Start:      start,
+			End:        localtime.Next(start, loc, eh, em),
+			RRule:      "FREQ=DAILY",
+			Categories: []string{"Fasting"},
+		})
+	}
+	if wi := opts.WeighIn; wi != nil {
+		h, m, _ := clock(wi.Time)
+		day := strings.ToUpper(wi.Weekday)
+		start := localtime.Next(dayStart.Add(-time.Second), loc, h, m)
+		for start.In(loc).Weekday() != weekdays[day] {
+			start = localtime.Next(start, loc, h, m)
+		}
+		cal.Events = append(cal.Events, Event{
+			UID:        "weigh-in-" + userID + "@nutrition-health",
+			Summary:    "Weigh-in",
+			Start:      start,
+			End:        start.Add(15 * time.Minute),
+			RRule:      "FREQ=WEEKLY;BYDAY=" + day,
+			Alarm:      alarm,
+			Categories: []string{"Reminder"},
+		})
+	}
+	return cal.Render(now), nil
+}
+
+func mealEvent(m Meal, alarm time.Duration) Event {
+	duration := m.Duration
+	if duration <= 0 {
+		duration = 30 * time.Minute
+	}
+	summary := m.Name
+	if r, size := utf8.DecodeRuneInString(m.Slot); size > 0 {
+		summary = string(unicode.ToUpper(r)) + m.Slot[size:] + ": " + m.Name
+	}
+	description := fmt.Sprintf("%.0f kcal", m.Calories)
+	if m.Notes != "" {
+		description += "\n" + m.Notes
+	}
+	return Event{
+		UID:         "meal-" + m.ID + "@nutrition-health",
+		Summary:     summary,
+		Description: description,
+		Start:       m.Start,
+		End:         m.Start.Add(duration),
+		Alarm:       alarm,
+		Categories:  []string{"Meal"},
+	}
+}
@@ -0,0 +1,81 @@
+package calendar
+
+import (
+	"net/http"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler serves the ICS feed and feed management
+type Handler struct {
+	feeds *Feeds
+}
+
+// NewHandler creates a calendar handler
+func NewHandler(feeds *Feeds) *Handler {
+	return &Handler{feeds: feeds}
+}
+
+// RegisterFeedRoutes mounts GET /plans/calendar.ics?token=..., authenticated by the token alone
+func (h *Handler) RegisterFeedRoutes(api *echo.Group) {
+	api.GET("/plans/calendar.ics", h.Feed).Name = "calendar.feed"
+}
+
+// RegisterRoutes mounts PUT/DELETE /plans/calendar on an authenticated group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.PUT("/plans/calendar", h.Issue)
+	g.DELETE("/plans/calendar", h.Revoke)
+}
+
+// Feed renders the calendar; unknown tokens get 404 so tokens can't be probed
+func (h *Handler) Feed(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return echo.NewHTTPError(http.StatusNotFound, ErrNotFound.Error())
+	}
+	body, err := h.feeds.Render(c.Request().Context(), token)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set("Cache-Control", "private, max-age=900")
+	c.Response().Header().Set("Content-Disposition", `inline; filename="meal-plan.ics"`)
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", []byte(body))
+}
+
+// Issue creates or rotates the user's feed and returns its URL
+func (h *Handler) Issue(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	opts := Options{Meals: true}
+	if err := c.Bind(&opts); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := opts.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	token, err := h.feeds.Issue(c.Request().Context(), userID, opts)
+	if err != nil {
+		return err
+	}
+	url := c.Scheme() + "://" + c.Request().Host + c.Echo().Reverse("calendar.feed") + "?token=" + token
+	return c.JSON(http.StatusOK, map[string]interface{}{"url": url, "options": opts})
+}
+
+// Revoke disables the user's feed
+func (h *Handler) Revoke(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	if err := h.feeds.Revoke(c.Request().Context(), userID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
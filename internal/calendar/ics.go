@@ -0,0 +1,173 @@
+package calendar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/localtime"
+)
+
+// Event is one VEVENT. Times are rendered as local wall time with the
+// calendar's TZID, defined by a VTIMEZONE, so clients show them in the
+// user's zone and recurring events keep their local time across DST.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	// RRule is an optional recurrence rule such as "FREQ=WEEKLY;BYDAY=MO"
+	RRule string
+	// Alarm adds a display reminder this long before Start; zero disables it
+	Alarm      time.Duration
+	Categories []string
+}
+
+// Calendar renders a VCALENDAR per RFC 5545
+type Calendar struct {
+	Name     string
+	Location *time.Location
+	Events   []Event
+}
+
+const localLayout = "20060102T150405"
+
+// Render returns the calendar as CRLF-delimited ICS text
+func (c Calendar) Render(now time.Time) string {
+	var w icsWriter
+	tzid := c.Location.String()
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//Nutrition Health//Meal Plan//EN")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + escape(c.Name))
+	w.line("X-WR-TIMEZONE:" + tzid)
+	w.line("REFRESH-INTERVAL;VALUE=DURATION:PT6H")
+	writeTimezone(&w, c.Location, now)
+	stamp := now.UTC().Format(localLayout) + "Z"
+	for _, ev := range c.Events {
+		w.line("BEGIN:VEVENT")
+		w.line("UID:" + escape(ev.UID))
+		w.line("DTSTAMP:" + stamp)
+		w.line("DTSTART;TZID=" + tzid + ":" + ev.Start.In(c.Location).Format(localLayout))
+		w.line("DTEND;TZID=" + tzid + ":" + ev.End.In(c.Location).Format(localLayout))
+		w.line("SUMMARY:" + escape(ev.Summary))
+		if ev.Description != "" {
+			w.line("DESCRIPTION:" + escape(ev.Description))
+		}
+		if len(ev.Categories) > 0 {
+			cats := make([]string, len(ev.Categories))
+			for i, cat := range ev.Categories {
+				cats[i] = escape(cat)
+			}
+			w.line("CATEGORIES:" + strings.Join(cats, ","))
+		}
+		if ev.RRule != "" {
+			w.line("RRULE:" + ev.RRule)
+		}
+		if ev.Alarm > 0 {
+			w.line("BEGIN:VALARM")
+			w.line("ACTION:DISPLAY")
+			w.line("DESCRIPTION:" + escape(ev.Summary))
+			w.line("TRIGGER:-" + duration(ev.Alarm))
+			w.line("END:VALARM")
+		}
+		w.line("END:VEVENT")
+	}
+	w.line("END:VCALENDAR")
+	return w.String()
+}
+
+// writeTimezone emits the VTIMEZONE for loc, listing each offset change from a
+// year before now to two years after so recurring events resolve correctly.
+// Zones without changes get a single STANDARD observance.
+func writeTimezone(w *icsWriter, loc *time.Location, now time.Time) {
+	from, to := now.AddDate(-1, 0, 0), now.AddDate(2, 0, 0)
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + loc.String())
+	_, prev := from.In(loc).Zone()
+	changes := localtime.Transitions(loc, from, to)
+	if len(changes) == 0 {
+		name, off := from.In(loc).Zone()
+		observance(w, "STANDARD", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), name, off, off)
+	}
+	for _, at := range changes {
+		l := at.In(loc)
+		name, off := l.Zone()
+		kind := "STANDARD"
+		if l.IsDST() {
+			kind = "DAYLIGHT"
+		}
+		// DTSTART is the onset in the wall time in effect before the change
+		observance(w, kind, at.Add(time.Duration(prev)*time.Second).UTC(), name, prev, off)
+		prev = off
+	}
+	w.line("END:VTIMEZONE")
+}
+
+func observance(w *icsWriter, kind string, start time.Time, name string, from, to int) {
+	w.line("BEGIN:" + kind)
+	w.line("DTSTART:" + start.Format(localLayout))
+	w.line("TZOFFSETFROM:" + offset(from))
+	w.line("TZOFFSETTO:" + offset(to))
+	w.line("TZNAME:" + escape(name))
+	w.line("END:" + kind)
+}
+
+// offset formats seconds east of UTC as an RFC 5545 UTC offset, e.g. +0300
+func offset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+}
+
+type icsWriter struct {
+	strings.Builder
+}
+
+// line writes one content line, folded at 75 octets without splitting a UTF-8
+// sequence, which matters for Arabic meal names
+func (w *icsWriter) line(s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8Start(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = 74
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func utf8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// duration formats d as an RFC 5545 duration in whole minutes, e.g. PT1H30M
+func duration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	out := "PT"
+	if h := minutes / 60; h > 0 {
+		out += strconv.Itoa(h) + "H"
+	}
+	if m := minutes % 60; m > 0 || minutes == 0 {
+		out += strconv.Itoa(m) + "M"
+	}
+	return out
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
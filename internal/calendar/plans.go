@@ -0,0 +1,105 @@
+package calendar
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/localtime"
+	"nutrition-health-backend/internal/repo"
+	"nutrition-health-backend/internal/tenant"
+)
+
+// PlanMeals lists planned meals from the meal plan tables, one event per
+// day and slot at the slot's usual local time
+type PlanMeals struct {
+	db    *sql.DB
+	zones *localtime.Store
+	// times maps slots to "HH:MM", from CALENDAR_MEAL_TIMES
+	times map[string]string
+}
+
+// NewPlanMeals creates the meal source, reading plans through the repo queries
+func NewPlanMeals(db *sql.DB, zones *localtime.Store) *PlanMeals {
+	times := map[string]string{}
+	for _, pair := range envconfig.List("CALENDAR_MEAL_TIMES", []string{"breakfast=08:00", "lunch=13:00", "snack=16:00", "dinner=19:00"}) {
+		if slot, at, ok := strings.Cut(pair, "="); ok {
+			times[strings.ToLower(strings.TrimSpace(slot))] = strings.TrimSpace(at)
+		}
+	}
+	return &PlanMeals{db: db, zones: zones, times: times}
+}
+
+// ValidateMealTimes checks CALENDAR_MEAL_TIMES
+func ValidateMealTimes() error {
+	for _, pair := range envconfig.List("CALENDAR_MEAL_TIMES", nil) {
+		slot, at, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(slot) == "" {
+			return fmt.Errorf("CALENDAR_MEAL_TIMES entry %q must look like slot=HH:MM", pair)
+		}
+		if _, _, err := clock(strings.TrimSpace(at)); err != nil {
+			return fmt.Errorf("CALENDAR_MEAL_TIMES %s: %w", slot, err)
+		}
+	}
+	return nil
+}
+
+// PlannedMeals implements MealSource
+func (p *PlanMeals) PlannedMeals(ctx context.Context, userID string, from, to time.Time) ([]Meal, error) {
+	loc, err := p.zones.Location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := repo.New(p.db).ListPlannedItems(ctx, repo.ListPlannedItemsParams{
+		UserID:   userID,
+		TenantID: tenantID,
+		DateFrom: localtime.Date(from, loc),
+		DateTo:   localtime.Date(to, loc),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// One meal per plan, day and slot, in the order the slots first appear
+	var meals []Meal
+	index := map[string]int{}
+	for _, it := range items {
+		slot := strings.ToLower(it.MealType)
+		id := strconv.FormatInt(it.MealPlanID, 10) + "-" + it.Date + "-" + slot
+		i, ok := index[id]
+		if !ok {
+			dayStart, _, err := localtime.ParseDay(it.Date, loc)
+			if err != nil {
+				continue
+			}
+			h, m, err := clock(p.times[slot])
+			if err != nil {
+				h, m = 12, 0
+			}
+			start := localtime.Next(dayStart.Add(-time.Second), loc, h, m)
+			if start.Before(from) || !start.Before(to) {
+				continue
+			}
+			i = len(meals)
+			index[id] = i
+			meals = append(meals, Meal{ID: id, Slot: it.MealType, Start: start})
+		}
+		meal := &meals[i]
+		if it.Name.String != "" {
+			if meal.Name != "" {
+				meal.Name += ", "
+			}
+			meal.Name += it.Name.String
+		}
+		meal.Calories += it.Quantity * it.Calories.Float64 / 100
+	}
+	return meals, nil
+}
@@ -0,0 +1,172 @@
+package configcheck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"nutrition-health-backend/internal/admin"
+	"nutrition-health-backend/internal/analytics"
+	"nutrition-health-backend/internal/backup"
+	"nutrition-health-backend/internal/calendar"
+	"nutrition-health-backend/internal/config"
+	"nutrition-health-backend/internal/cors"
+	"nutrition-health-backend/internal/diagnostics"
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/errreport"
+	"nutrition-health-backend/internal/extapi"
+	"nutrition-health-backend/internal/faults"
+	"nutrition-health-backend/internal/fieldcrypt"
+	"nutrition-health-backend/internal/insights"
+	"nutrition-health-backend/internal/limits"
+	"nutrition-health-backend/internal/localtime"
+	"nutrition-health-backend/internal/outbox"
+	"nutrition-health-backend/internal/printexport"
+	"nutrition-health-backend/internal/realtime"
+	"nutrition-health-backend/internal/retention"
+	"nutrition-health-backend/internal/server"
+	"nutrition-health-backend/internal/signing"
+	"nutrition-health-backend/internal/sqlitetune"
+	"nutrition-health-backend/internal/startup"
+	"nutrition-health-backend/internal/version"
+)
+
+var (
+	environments   = map[string]bool{"development": true, "test": true, "staging": true, "production": true}
+	apiVersion     = regexp.MustCompile(`^v[0-9]+$`)
+	insecureSecret = "your-super-secret-jwt-key-change-this"
+)
+
+// Validate checks the loaded configuration and every feature setting, returning
+// all problems at once so operators can fix them in one pass
+func Validate(cfg *config.Config) []error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+	production := cfg.Server.Environment == "production"
+
+	if port, err := strconv.Atoi(cfg.Server.Port); err != nil || port < 1 || port > 65535 {
+		add("PORT=%q must be a number between 1 and 65535", cfg.Server.Port)
+	}
+	if !environments[cfg.Server.Environment] {
+		add("ENV=%q must be one of development, test, staging, production", cfg.Server.Environment)
+	}
+	if !apiVersion.MatchString(cfg.API.Version) {
+		add("API version %q must look like v1", cfg.API.Version)
+	}
+
+	if cfg.Database.Path == "" {
+		add("DB_PATH is required")
+	} else if dir := filepath.Dir(cfg.Database.Path); !isDir(dir) {
+		add("DB_PATH directory %q does not exist", dir)
+	}
+
+	if cfg.Security.RateLimitReqs <= 0 {
+		add("RATE_LIMIT_REQUESTS must be positive")
+	}
+	if cfg.Security.RateLimitWindow <= 0 {
+		add("RATE_LIMIT_WINDOW must be a positive duration")
+	}
+	origins := cors.Origins(cfg.Server.Environment, cfg.Security.CORSOrigins)
+	if err := cors.LoadConfig(origins, cors.CredentialOrigins(cfg.Server.Environment)).Validate(production); err != nil {
+		add("%v", err)
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	switch {
+	case secret == "":
+		add("JWT_SECRET is required")
+	case production && (secret == insecureSecret || len(secret) < 32):
+		add("JWT_SECRET must be at least 32 characters and not the example value in production")
+	}
+
+	if err := sqlitetune.OptionsFromEnv().Validate(); err != nil {
+		add("database tuning: %v", err)
+	}
+	if err := backup.LoadConfig().Validate(); err != nil {
+		add("backup: %v", err)
+	}
+	if err := server.LoadTLSConfig().Validate(); err != nil {
+		add("tls: %v", err)
+	}
+	if err := server.LoadProxyConfig().Validate(); err != nil {
+		add("proxies: %v", err)
+	}
+	if err := limits.LoadConfig(cfg.API.Version).Validate(); err != nil {
+		add("request limits: %v", err)
+	}
+	if _, err := fieldcrypt.LoadKeyRing(); err != nil {
+		add("field encryption: %v", err)
+	}
+	signingCfg := signing.LoadConfig(cfg.API.Version)
+	if err := signingCfg.Validate(); err != nil {
+		add("request signing: %v", err)
+	}
+	if keys, err := signing.LoadSecrets(); err != nil {
+		add("request signing: %v", err)
+	} else if signingCfg.Mode != signing.ModeOff && len(keys) == 0 {
+		add("request signing: SIGNING_MODE=%s needs SIGNING_KEYS", signingCfg.Mode)
+	}
+	if err := extapi.LoadConfig().Validate(); err != nil {
+		add("external APIs: %v", err)
+	}
+	if err := faults.LoadConfig().Validate(cfg.Server.Environment); err != nil {
+		add("fault injection: %v", err)
+	}
+	if err := outbox.LoadConfig().Validate(); err != nil {
+		add("outbox: %v", err)
+	}
+	if err := analytics.LoadConfig().Validate(); err != nil {
+		add("analytics: %v", err)
+	}
+	if _, err := retention.LoadPolicies(); err != nil {
+		add("retention: %v", err)
+	}
+	if err := retention.LoadConfig().Validate(); err != nil {
+		add("retention: %v", err)
+	}
+	if err := insights.LoadOptions().Validate(); err != nil {
+		add("insights: %v", err)
+	}
+	if hour := envconfig.Int("INSIGHTS_SEND_HOUR", 8); hour < 0 || hour > 23 {
+		add("INSIGHTS_SEND_HOUR must be between 0 and 23")
+	}
+	if err := calendar.ValidateMealTimes(); err != nil {
+		add("calendar: %v", err)
+	}
+	if err := printexport.LoadConfig().Validate(); err != nil {
+		add("exports: %v", err)
+	}
+	if err := realtime.LoadConfig().Validate(); err != nil {
+		add("realtime: %v", err)
+	}
+	if _, err := localtime.Load(envconfig.String("USER_DEFAULT_TIMEZONE", "UTC")); err != nil {
+		add("USER_DEFAULT_TIMEZONE: %v", err)
+	}
+	if err := startup.NewTracker().Validate(); err != nil {
+		add("startup: %v", err)
+	}
+	if server.ShutdownTimeout() <= 0 {
+		add("SHUTDOWN_TIMEOUT must be positive")
+	}
+	if err := errreport.LoadConfig(cfg.Server.Environment, version.Get().Release()).Validate(); err != nil {
+		add("error reporting: %v", err)
+	}
+	if token := admin.Token(); token != "" && len(token) < 16 {
+		add("ADMIN_TOKEN must be at least 16 characters")
+	} else if token == "" && diagnostics.Enabled() {
+		add("DIAGNOSTICS_ENABLED requires ADMIN_TOKEN")
+	}
+
+	// Unparseable values seen by any loader above
+	errs = append(errs, envconfig.Errors()...)
+	return errs
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,259 @@
+package consent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Kind is a type of legal document users must accept
+type Kind string
+
+const (
+	Terms             Kind = "terms"
+	Privacy           Kind = "privacy"
+	MedicalDisclaimer Kind = "medical_disclaimer"
+)
+
+// ParseKind validates a document kind
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(s); k {
+	case Terms, Privacy, MedicalDisclaimer:
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown document kind %q", s)
+}
+
+// Document is a published version of a legal text
+type Document struct {
+	Kind        Kind      `json:"kind"`
+	Version     string    `json:"version"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	BodyAr      string    `json:"body_ar,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Acceptance records a user agreeing to a document version
+type Acceptance struct {
+	UserID     string    `json:"user_id"`
+	Kind       Kind      `json:"kind"`
+	Version    string    `json:"version"`
+	AcceptedAt time.Time `json:"accepted_at"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+}
+
+// Pending is a current document the user has not accepted yet
+type Pending struct {
+	Kind    Kind   `json:"kind"`
+	Version string `json:"version"`
+	Title   string `json:"title"`
+}
+
+var (
+	// ErrNotCurrent is returned when accepting a version that has been superseded
+	ErrNotCurrent = errors.New("document version is not current")
+	// ErrExists is returned when publishing a version that already exists
+	ErrExists = errors.New("document version already published")
+)
+
+// Migrate creates the consent_documents and consent_acceptances tables
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS consent_documents (
+			kind TEXT NOT NULL,
+			version TEXT NOT NULL,
+			title TEXT NOT NULL,
+			body TEXT NOT NULL,
+			body_ar TEXT NOT NULL DEFAULT '',
+			published_at DATETIME NOT NULL,
+			PRIMARY KEY (kind, version)
+		)`,
+		`CREATE TABLE IF NOT EXISTS consent_acceptances (
+			user_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			version TEXT NOT NULL,
+			accepted_at DATETIME NOT NULL,
+			ip TEXT NOT NULL,
+			user_agent TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (user_id, kind, version)
+		)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentTTL bounds how long a newly published version takes to be enforced on other instances
+const currentTTL = 30 * time.Second
+
+// Service records acceptances and checks users against the current versions
+type Service struct {
+	db       *sql.DB
+	required []Kind
+
+	mu       sync.Mutex
+	current  map[Kind]Document
+	loadedAt time.Time
+}
+
+// NewService creates the service; CONSENT_REQUIRED lists the kinds enforced by Require
+func NewService(db *sql.DB) *Service {
+	var required []Kind
+	for _, k := range envconfig.List("CONSENT_REQUIRED", []string{string(Terms), string(Privacy), string(MedicalDisclaimer)}) {
+		if kind, err := ParseKind(k); err == nil {
+			required = append(required, kind)
+		}
+	}
+	return &Service{db: db, required: required}
+}
+
+// Current returns the latest published version of each kind
+func (s *Service) Current(ctx context.Context) (map[Kind]Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != nil && time.Since(s.loadedAt) < currentTTL {
+		return s.current, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT d.kind, d.version, d.title, d.body, d.body_ar, d.published_at FROM consent_documents d
+		 WHERE d.published_at = (SELECT MAX(published_at) FROM consent_documents WHERE kind = d.kind)`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	current := map[Kind]Document{}
+	for rows.Next() {
+		var d Document
+		if err := rows.Scan(&d.Kind, &d.Version, &d.Title, &d.Body, &d.BodyAr, &d.PublishedAt); err != nil {
+			return nil, err
+		}
+		current[d.Kind] = d
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	s.current, s.loadedAt = current, time.Now()
+	return current, nil
+}
+
+// Publish adds a new document version, which becomes current immediately
+func (s *Service) Publish(ctx context.Context, d Document) (Document, error) {
+	if d.Version == "" || d.Title == "" || d.Body == "" {
+		return d, fmt.Errorf("version, title and body are required")
+	}
+	d.PublishedAt = time.Now().UTC()
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO consent_documents (kind, version, title, body, body_ar, published_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		d.Kind, d.Version, d.Title, d.Body, d.BodyAr, d.PublishedAt)
+	if err != nil {
+		var exists int
+		if s.db.QueryRowContext(ctx, `SELECT 1 FROM consent_documents WHERE kind = ? AND version = ?`, d.Kind, d.Version).Scan(&exists) == nil {
+			return d, ErrExists
+		}
+		return d, err
+	}
+	s.invalidate()
+	return d, nil
+}
+
+// Documents lists every published version, newest first
+func (s *Service) Documents(ctx context.Context) ([]Document, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT kind, version, title, body, body_ar, published_at FROM consent_documents ORDER BY published_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var docs []Document
+	for rows.Next() {
+		var d Document
+		if err := rows.Scan(&d.Kind, &d.Version, &d.Title, &d.Body, &d.BodyAr, &d.PublishedAt); err != nil {
+			return nil, err
+		}
+		docs = append(docs, d)
+	}
+	return docs, rows.Err()
+}
+
+// Accept records acceptance of the current version of a document
+func (s *Service) Accept(ctx context.Context, a Acceptance) error {
+	current, err := s.Current(ctx)
+	if err != nil {
+		return err
+	}
+	if doc, ok := current[a.Kind]; !ok || doc.Version != a.Version {
+		return ErrNotCurrent
+	}
+	a.AcceptedAt = time.Now().UTC()
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO consent_acceptances (user_id, kind, version, accepted_at, ip, user_agent) VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(user_id, kind, version) DO NOTHING`,
+		a.UserID, a.Kind, a.Version, a.AcceptedAt, a.IP, a.UserAgent)
+	return err
+}
+
+// Acceptances returns the user's acceptance history
+func (s *Service) Acceptances(ctx context.Context, userID string) ([]Acceptance, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT user_id, kind, version, accepted_at, ip, user_agent FROM consent_acceptances
+		 WHERE user_id = ? ORDER BY accepted_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Acceptance
+	for rows.Next() {
+		var a Acceptance
+		if err := rows.Scan(&a.UserID, &a.Kind, &a.Version, &a.AcceptedAt, &a.IP, &a.UserAgent); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// PendingFor lists required current documents the user has not accepted.
+// Kinds with no published document are not enforced.
+func (s *Service) PendingFor(ctx context.Context, userID string) ([]Pending, error) {
+	current, err := s.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Pending
+	for _, kind := range s.required {
+		doc, ok := current[kind]
+		if !ok {
+			continue
+		}
+		var one int
+		err := s.db.QueryRowContext(ctx,
+			`SELECT 1 FROM consent_acceptances WHERE user_id = ? AND kind = ? AND version = ?`,
+			userID, kind, doc.Version).Scan(&one)
+		if err == sql.ErrNoRows {
+			pending = append(pending, Pending{Kind: kind, Version: doc.Version, Title: doc.Title})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pending, nil
+}
+
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	s.current = nil
+	s.mu.Unlock()
+}
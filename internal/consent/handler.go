@@ -0,0 +1,129 @@
+package consent
+
+import (
+	"net/http"
+
+	"nutrition-health-backend/internal/problem"
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Require blocks authenticated requests with 403 until the user has accepted
+// every required current document. Mount it on data-processing groups after
+// the auth middleware; anonymous requests pass through to be rejected by auth.
+func (s *Service) Require() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID := reqctx.UserID(c)
+			if userID == "" {
+				return next(c)
+			}
+			pending, err := s.PendingFor(c.Request().Context(), userID)
+			if err != nil {
+				return err
+			}
+			if len(pending) == 0 {
+				return next(c)
+			}
+			p := problem.New(http.StatusForbidden, "accept the current terms before continuing")
+			p.Extensions = map[string]interface{}{"consent_required": pending}
+			return problem.Write(c, p)
+		}
+	}
+}
+
+// RegisterRoutes mounts GET /consent and POST /consent/accept on an authenticated group
+func (s *Service) RegisterRoutes(g *echo.Group) {
+	g.GET("/consent", s.handleStatus)
+	g.POST("/consent/accept", s.handleAccept)
+}
+
+// RegisterAdminRoutes mounts document publishing on an admin-protected group
+func (s *Service) RegisterAdminRoutes(g *echo.Group) {
+	g.GET("/consent/documents", func(c echo.Context) error {
+		docs, err := s.Documents(c.Request().Context())
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"documents": docs})
+	})
+	g.POST("/consent/documents/:kind", func(c echo.Context) error {
+		kind, err := ParseKind(c.Param("kind"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		var d Document
+		if err := c.Bind(&d); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+		d.Kind = kind
+		d, err = s.Publish(c.Request().Context(), d)
+		if err == ErrExists {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return c.JSON(http.StatusCreated, d)
+	})
+}
+
+func (s *Service) handleStatus(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	ctx := c.Request().Context()
+	current, err := s.Current(ctx)
+	if err != nil {
+		return err
+	}
+	pending, err := s.PendingFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	history, err := s.Acceptances(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"current":     current,
+		"pending":     pending,
+		"acceptances": history,
+	})
+}
+
+type acceptRequest struct {
+	Kind    string `json:"kind"`
+	Version string `json:"version"`
+}
+
+func (s *Service) handleAccept(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	var req acceptRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	kind, err := ParseKind(req.Kind)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	err = s.Accept(c.Request().Context(), Acceptance{
+		UserID:    userID,
+		Kind:      kind,
+		Version:   req.Version,
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	})
+	if err == ErrNotCurrent {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
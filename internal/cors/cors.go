@@ -0,0 +1,192 @@
+package cors
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Config is the CORS policy. Origins are patterns: an exact origin such as
+// https://app.example.com, a subdomain wildcard such as https://*.example.com
+// (which does not match the apex), a port wildcard such as
+// http://localhost:*, or * for any origin.
+type Config struct {
+	Origins []string
+	// CredentialOrigins may send cookies and Authorization, e.g. the coach web
+	// app; they are allowed even when missing from Origins
+	CredentialOrigins []string
+	// MaxAge is how long browsers may cache a preflight result
+	MaxAge        time.Duration
+	AllowMethods  []string
+	AllowHeaders  []string
+	ExposeHeaders []string
+}
+
+// Origins returns the origin list for env: CORS_ORIGINS_<ENV> (for example
+// CORS_ORIGINS_STAGING) when set, otherwise the CORS_ORIGINS default
+func Origins(env string, def []string) []string {
+	return envconfig.List("CORS_ORIGINS_"+strings.ToUpper(env), def)
+}
+
+// CredentialOrigins returns CORS_CREDENTIAL_ORIGINS_<ENV>, falling back to CORS_CREDENTIAL_ORIGINS
+func CredentialOrigins(env string) []string {
+	return envconfig.List("CORS_CREDENTIAL_ORIGINS_"+strings.ToUpper(env), envconfig.List("CORS_CREDENTIAL_ORIGINS", nil))
+}
+
+// LoadConfig builds the policy for the given origin lists, reading the
+// remaining CORS_* settings from the environment. An empty CORS_ALLOW_HEADERS
+// echoes the headers a preflight asks for.
+func LoadConfig(origins, credentialOrigins []string) Config {
+	return Config{
+		Origins:           origins,
+		CredentialOrigins: credentialOrigins,
+		MaxAge:            envconfig.Duration("CORS_MAX_AGE", 2*time.Hour),
+		AllowMethods:      envconfig.List("CORS_ALLOW_METHODS", []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}),
+		AllowHeaders:      envconfig.List("CORS_ALLOW_HEADERS", nil),
+		ExposeHeaders: envconfig.List("CORS_EXPOSE_HEADERS", []string{
+			"X-Correlation-ID", "ETag", "Location", "Retry-After", "Link",
+		}),
+	}
+}
+
+// Validate checks the policy; production rejects the * origin
+func (c Config) Validate(production bool) error {
+	if len(c.Origins) == 0 && len(c.CredentialOrigins) == 0 {
+		return fmt.Errorf("CORS_ORIGINS must list at least one origin")
+	}
+	for _, o := range c.Origins {
+		if o == "*" {
+			if production {
+				return fmt.Errorf("CORS_ORIGINS must not be * in production")
+			}
+			continue
+		}
+		if _, err := parsePattern(o); err != nil {
+			return err
+		}
+	}
+	for _, o := range c.CredentialOrigins {
+		if o == "*" {
+			return fmt.Errorf("CORS_CREDENTIAL_ORIGINS must not be *; credentialed requests need explicit origins")
+		}
+		if _, err := parsePattern(o); err != nil {
+			return err
+		}
+	}
+	if c.MaxAge < 0 || c.MaxAge > 24*time.Hour {
+		return fmt.Errorf("CORS_MAX_AGE must be between 0 and 24h")
+	}
+	if len(c.AllowMethods) == 0 {
+		return fmt.Errorf("CORS_ALLOW_METHODS must list at least one method")
+	}
+	return nil
+}
+
+// pattern is a parsed origin pattern
+type pattern struct {
+	scheme string
+	host   string
+	// suffix is set for *.domain patterns and holds ".domain"
+	suffix  string
+	port    string
+	anyPort bool
+}
+
+func parsePattern(s string) (pattern, error) {
+	bad := fmt.Errorf("CORS origin %q must be scheme://host[:port], scheme://*.domain[:port] or scheme://host:*", s)
+	scheme, rest, ok := strings.Cut(strings.ToLower(strings.TrimSuffix(s, "/")), "://")
+	if !ok || scheme == "" || rest == "" || strings.ContainsAny(rest, "/?#@") {
+		return pattern{}, bad
+	}
+	p := pattern{scheme: scheme}
+	host := rest
+	if i := strings.LastIndex(rest, ":"); i >= 0 && !strings.HasSuffix(rest, "]") {
+		host, p.port = rest[:i], rest[i+1:]
+		if p.port == "*" {
+			p.port, p.anyPort = "", true
+		} else if p.port == "" || strings.Trim(p.port, "0123456789") != "" {
+			return pattern{}, bad
+		}
+	}
+	if strings.HasPrefix(host, "*.") {
+		p.suffix = host[1:]
+		// *.com would admit every site under a public suffix
+		if strings.Count(p.suffix, ".") < 2 || strings.Contains(p.suffix, "*") {
+			return pattern{}, fmt.Errorf("CORS origin %q: wildcard needs a registrable domain such as *.example.com", s)
+		}
+		return p, nil
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "" || strings.Contains(host, "*") {
+		return pattern{}, bad
+	}
+	check := host
+	if strings.Contains(host, ":") {
+		check = "[" + host + "]"
+	}
+	if _, err := url.Parse(scheme + "://" + check); err != nil {
+		return pattern{}, bad
+	}
+	p.host = host
+	return p, nil
+}
+
+// match reports whether an origin (scheme://host[:port], lower-cased) fits the pattern
+func (p pattern) match(scheme, host, port string) bool {
+	if scheme != p.scheme || (!p.anyPort && port != p.port) {
+		return false
+	}
+	if p.suffix != "" {
+		return len(host) > len(p.suffix) && strings.HasSuffix(host, p.suffix)
+	}
+	return host == p.host
+}
+
+// matcher checks origins against a set of patterns
+type matcher struct {
+	any      bool
+	patterns []pattern
+}
+
+func newMatcher(origins []string) matcher {
+	var m matcher
+	for _, o := range origins {
+		if o == "*" {
+			m.any = true
+			continue
+		}
+		// Invalid patterns were reported by Validate at startup or reload
+		if p, err := parsePattern(o); err == nil {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+	return m
+}
+
+func (m matcher) match(origin string) bool {
+	scheme, host, port, ok := splitOrigin(origin)
+	if !ok {
+		return false
+	}
+	if m.any {
+		return true
+	}
+	for _, p := range m.patterns {
+		if p.match(scheme, host, port) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitOrigin breaks an Origin header into its lower-cased parts
+func splitOrigin(origin string) (scheme, host, port string, ok bool) {
+	u, err := url.Parse(strings.ToLower(origin))
+	if err != nil || u.Scheme == "" || u.Host == "" || u.User != nil || (u.Path != "" && u.Path != "/") {
+		return "", "", "", false
+	}
+	return u.Scheme, u.Hostname(), u.Port(), true
+}
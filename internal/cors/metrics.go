@@ -0,0 +1,72 @@
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxTrackedOrigins bounds the per-origin counters; further origins are counted as "other"
+const maxTrackedOrigins = 100
+
+var rejections = struct {
+	sync.Mutex
+	preflight, request int64
+	origins            map[string]int64
+}{origins: make(map[string]int64)}
+
+func reject(origin string, preflight bool) {
+	rejections.Lock()
+	defer rejections.Unlock()
+	if preflight {
+		rejections.preflight++
+	} else {
+		rejections.request++
+	}
+	if _, ok := rejections.origins[origin]; !ok && len(rejections.origins) >= maxTrackedOrigins {
+		origin = "other"
+	}
+	rejections.origins[origin]++
+}
+
+// Rejections returns the disallowed-origin counts by origin
+func Rejections() map[string]int64 {
+	rejections.Lock()
+	defer rejections.Unlock()
+	out := make(map[string]int64, len(rejections.origins))
+	for k, v := range rejections.origins {
+		out[k] = v
+	}
+	return out
+}
+
+// Handler exports the rejection counters in the Prometheus text format
+func Handler(c echo.Context) error {
+	rejections.Lock()
+	preflight, request := rejections.preflight, rejections.request
+	rejections.Unlock()
+	origins := Rejections()
+	names := make([]string, 0, len(origins))
+	for name := range origins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# HELP cors_rejected_total Requests from origins outside the CORS policy.\n")
+	b.WriteString("# TYPE cors_rejected_total counter\n")
+	fmt.Fprintf(&b, "cors_rejected_total{kind=\"preflight\"} %d\n", preflight)
+	fmt.Fprintf(&b, "cors_rejected_total{kind=\"request\"} %d\n", request)
+
+	b.WriteString("# HELP cors_rejected_origin_total Rejected requests by origin.\n")
+	b.WriteString("# TYPE cors_rejected_origin_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "cors_rejected_origin_total{origin=%s} %d\n", strconv.Quote(name), origins[name])
+	}
+	return c.String(http.StatusOK, b.String())
+}
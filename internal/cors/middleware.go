@@ -0,0 +1,83 @@
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware applies the policy. Requests from other origins pass through
+// without CORS headers, so the browser blocks the response; preflights from
+// them get 403. Both are counted in the rejection metrics.
+func Middleware(cfg Config) echo.MiddlewareFunc {
+	allowed := newMatcher(cfg.Origins)
+	credentialed := newMatcher(cfg.CredentialOrigins)
+	methods := strings.Join(cfg.AllowMethods, ", ")
+	headers := strings.Join(cfg.AllowHeaders, ", ")
+	expose := strings.Join(cfg.ExposeHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req, header := c.Request(), c.Response().Header()
+			origin := req.Header.Get(echo.HeaderOrigin)
+			preflight := req.Method == http.MethodOptions && req.Header.Get(echo.HeaderAccessControlRequestMethod) != ""
+			header.Add(echo.HeaderVary, echo.HeaderOrigin)
+			if origin == "" || sameOrigin(req, origin) {
+				return next(c)
+			}
+
+			withCredentials := credentialed.match(origin)
+			if !withCredentials && !allowed.match(origin) {
+				reject(origin, preflight)
+				if preflight {
+					return c.NoContent(http.StatusForbidden)
+				}
+				return next(c)
+			}
+
+			// * is only echoed when no credentials are involved; otherwise the
+			// exact origin is required by browsers
+			if allowed.any && !withCredentials {
+				header.Set(echo.HeaderAccessControlAllowOrigin, "*")
+			} else {
+				header.Set(echo.HeaderAccessControlAllowOrigin, origin)
+			}
+			if withCredentials {
+				header.Set(echo.HeaderAccessControlAllowCredentials, "true")
+			}
+
+			if !preflight {
+				if expose != "" {
+					header.Set(echo.HeaderAccessControlExposeHeaders, expose)
+				}
+				return next(c)
+			}
+
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestMethod)
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestHeaders)
+			header.Set(echo.HeaderAccessControlAllowMethods, methods)
+			if headers != "" {
+				header.Set(echo.HeaderAccessControlAllowHeaders, headers)
+			} else if requested := req.Header.Get(echo.HeaderAccessControlRequestHeaders); requested != "" {
+				header.Set(echo.HeaderAccessControlAllowHeaders, requested)
+			}
+			if cfg.MaxAge > 0 {
+				header.Set(echo.HeaderAccessControlMaxAge, maxAge)
+			}
+			return c.NoContent(http.StatusNoContent)
+		}
+	}
+}
+
+// sameOrigin reports whether the browser sent Origin for a request to this
+// host, as it does for same-origin POSTs from the admin UI
+func sameOrigin(req *http.Request, origin string) bool {
+	scheme := "http"
+	if req.TLS != nil || req.Header.Get(echo.HeaderXForwardedProto) == "https" {
+		scheme = "https"
+	}
+	return strings.EqualFold(origin, scheme+"://"+req.Host)
+}
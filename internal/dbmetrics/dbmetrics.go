@@ -0,0 +1,162 @@
+package dbmetrics
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+type nameKey struct{}
+type correlationKey struct{}
+
+// WithName labels the queries run with ctx, e.g. "foods.search", for per-query metrics
+func WithName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, nameKey{}, name)
+}
+
+// WithCorrelationID attaches the request correlation ID to slow-query logs
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// slowThreshold is read on first use, after secrets and .env files are loaded
+var slowThreshold = sync.OnceValue(func() time.Duration {
+	return envconfig.Duration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond)
+})
+
+func observe(ctx context.Context, query string, elapsed time.Duration, rows int64, err error) {
+	name, _ := ctx.Value(nameKey{}).(string)
+	if name == "" {
+		name = deriveName(query)
+	}
+	stats(name).record(elapsed, rows, err)
+
+	if elapsed >= slowThreshold() {
+		correlationID, _ := ctx.Value(correlationKey{}).(string)
+		log.Printf("🐢 Slow query %s took %s (rows=%d, correlation_id=%s): %s",
+			name, elapsed.Round(time.Millisecond), rows, correlationID, compact(query))
+	}
+}
+
+// deriveName builds a stable label from the statement, e.g. "select foods"
+func deriveName(query string) string {
+	fields := strings.Fields(strings.ToLower(query))
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	verb := fields[0]
+
+	keyword := "from"
+	switch verb {
+	case "update":
+		if len(fields) > 1 {
+			return verb + " " + cleanIdent(fields[1])
+		}
+		return verb
+	case "insert", "replace":
+		keyword = "into"
+	}
+	for i, f := range fields[:len(fields)-1] {
+		if f == keyword {
+			return verb + " " + cleanIdent(fields[i+1])
+		}
+	}
+	return verb
+}
+
+func cleanIdent(s string) string {
+	return strings.Trim(s, "`\"();")
+}
+
+func compact(query string) string {
+	q := strings.Join(strings.Fields(query), " ")
+	if len(q) > 300 {
+		q = q[:300] + "…"
+	}
+	return q
+}
+
+// Bucket upper bounds in milliseconds for the duration histogram
+var buckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500}
+
+// QueryStats is a point-in-time view of one named query
+type QueryStats struct {
+	Count    int64     `json:"count"`
+	Errors   int64     `json:"errors"`
+	Rows     int64     `json:"rows"`
+	TotalMs  float64   `json:"total_ms"`
+	MaxMs    float64   `json:"max_ms"`
+	Buckets  []int64   `json:"buckets"`
+	BoundsMs []float64 `json:"bounds_ms"`
+}
+
+type queryStats struct {
+	mu sync.Mutex
+	QueryStats
+}
+
+func (s *queryStats) record(elapsed time.Duration, rows int64, err error) {
+	ms := float64(elapsed.Microseconds()) / 1000
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Count++
+	s.TotalMs += ms
+	if ms > s.MaxMs {
+		s.MaxMs = ms
+	}
+	if rows > 0 {
+		s.Rows += rows
+	}
+	if err != nil {
+		s.Errors++
+	}
+	for i, bound := range buckets {
+		if ms <= bound {
+			s.Buckets[i]++
+			return
+		}
+	}
+	s.Buckets[len(buckets)]++ // +Inf
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]*queryStats)
+)
+
+func stats(name string) *queryStats {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	s, ok := registry[name]
+	if !ok {
+		s = &queryStats{QueryStats: QueryStats{Buckets: make([]int64, len(buckets)+1), BoundsMs: buckets}}
+		registry[name] = s
+	}
+	return s
+}
+
+// Snapshot copies the current per-query stats
+func Snapshot() map[string]QueryStats {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	out := make(map[string]QueryStats, len(registry))
+	for name, s := range registry {
+		s.mu.Lock()
+		snap := s.QueryStats
+		snap.Buckets = append([]int64(nil), s.Buckets...)
+		s.mu.Unlock()
+		out[name] = snap
+	}
+	return out
+}
+
+func init() {
+	expvar.Publish("db_queries", expvar.Func(func() interface{} { return Snapshot() }))
+}
@@ -0,0 +1,72 @@
+package dbmetrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exports per-query histograms in the Prometheus text format
+func Handler(c echo.Context) error {
+	snap := Snapshot()
+	names := make([]string, 0, len(snap))
+	for name := range snap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# HELP db_query_duration_ms Database query duration in milliseconds.\n")
+	b.WriteString("# TYPE db_query_duration_ms histogram\n")
+	for _, name := range names {
+		s := snap[name]
+		label := strconv.Quote(name)
+		var cumulative int64
+		for i, bound := range s.BoundsMs {
+			cumulative += s.Buckets[i]
+			fmt.Fprintf(&b, "db_query_duration_ms_bucket{query=%s,le=\"%g\"} %d\n", label, bound, cumulative)
+		}
+		fmt.Fprintf(&b, "db_query_duration_ms_bucket{query=%s,le=\"+Inf\"} %d\n", label, s.Count)
+		fmt.Fprintf(&b, "db_query_duration_ms_sum{query=%s} %g\n", label, s.TotalMs)
+		fmt.Fprintf(&b, "db_query_duration_ms_count{query=%s} %d\n", label, s.Count)
+	}
+
+	b.WriteString("# HELP db_query_errors_total Database query errors.\n")
+	b.WriteString("# TYPE db_query_errors_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "db_query_errors_total{query=%s} %d\n", strconv.Quote(name), snap[name].Errors)
+	}
+
+	b.WriteString("# HELP db_query_rows_total Rows affected by writes or returned by reads.\n")
+	b.WriteString("# TYPE db_query_rows_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "db_query_rows_total{query=%s} %d\n", strconv.Quote(name), snap[name].Rows)
+	}
+
+	return c.String(http.StatusOK, b.String())
+}
+
+// CorrelationHeader is the header set by middleware.CorrelationID
+const CorrelationHeader = "X-Correlation-ID"
+
+// Middleware copies the request correlation ID into the request context so
+// slow-query logs can be tied back to the request
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(CorrelationHeader)
+			if id == "" {
+				id = c.Response().Header().Get(CorrelationHeader)
+			}
+			if id != "" {
+				req := c.Request()
+				c.SetRequest(req.WithContext(WithCorrelationID(req.Context(), id)))
+			}
+			return next(c)
+		}
+	}
+}
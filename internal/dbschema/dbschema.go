@@ -0,0 +1,68 @@
+// Package dbschema names the tables the database package creates and checks
+// what a running database actually has. The database package lives outside
+// this tree, so features that read its tables by name or configuration go
+// through here instead of guessing at the schema themselves; anything that
+// isn't found is skipped by the caller.
+package dbschema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// Tables created by the database package's migrations
+const (
+	Users             = "users"
+	Foods             = "foods"
+	DiaryEntries      = "diary_entries"
+	WeightLogs        = "weight_logs"
+	Recipes           = "recipes"
+	RecipeIngredients = "recipe_ingredients"
+	MealPlans         = "meal_plans"
+	MealPlanItems     = "meal_plan_items"
+	Favorites         = "favorites"
+	AuditLogs         = "audit_logs"
+	RequestLogs       = "request_logs"
+	Sessions          = "sessions"
+	RefreshTokens     = "refresh_tokens"
+	Notifications     = "notifications"
+)
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Valid reports whether name is a plain identifier that is safe to splice
+// into a statement
+func Valid(name string) bool {
+	return identPattern.MatchString(name)
+}
+
+// Querier is satisfied by *sql.DB, *sql.Tx and txn.Querier
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Columns returns the columns of table, or nil when it does not exist
+func Columns(ctx context.Context, q Querier, table string) (map[string]bool, error) {
+	if !Valid(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	rows, err := q.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var cols map[string]bool
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if cols == nil {
+			cols = map[string]bool{}
+		}
+		cols[name] = true
+	}
+	return cols, rows.Err()
+}
@@ -0,0 +1,96 @@
+package deltasync
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Op is the kind of change recorded for a row
+type Op string
+
+const (
+	OpUpsert Op = "upsert"
+	// OpDelete is a tombstone; Data is empty
+	OpDelete Op = "delete"
+)
+
+// Change is a row-level change in the sync journal
+type Change struct {
+	Seq       int64           `json:"-"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Op        Op              `json:"op"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Mutation is a queued offline write pushed by a client
+type Mutation struct {
+	ClientID  string          `json:"client_id"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Op        Op              `json:"op"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Validate checks a mutation before it is applied
+func (m Mutation) Validate() error {
+	if m.Entity == "" || m.EntityID == "" {
+		return fmt.Errorf("entity and entity_id are required")
+	}
+	if m.Op != OpUpsert && m.Op != OpDelete {
+		return fmt.Errorf("unknown op %q", m.Op)
+	}
+	if m.Op == OpUpsert && len(m.Data) == 0 {
+		return fmt.Errorf("upsert requires data")
+	}
+	if m.UpdatedAt.IsZero() {
+		return fmt.Errorf("updated_at is required")
+	}
+	return nil
+}
+
+// Result is the outcome of a single pushed mutation
+type Result struct {
+	ClientID string `json:"client_id"`
+	Status   string `json:"status"` // "accepted" or "rejected"
+	// EntityID is the accepted row's server ID; clients re-key offline rows to it
+	EntityID string `json:"entity_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// Server holds the winning server version when a mutation is rejected
+	Server *Change `json:"server,omitempty"`
+}
+
+// Resolve applies last-write-wins between the latest server change and a client mutation.
+// It returns true when the client's write should be accepted.
+func Resolve(server *Change, m Mutation) bool {
+	if server == nil {
+		return true
+	}
+	return !m.UpdatedAt.Before(server.UpdatedAt)
+}
+
+// EncodeCursor turns a journal sequence number into an opaque cursor
+func EncodeCursor(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(seq, 10)))
+}
+
+// DecodeCursor parses a cursor; an empty cursor starts from the beginning
+func DecodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	seq, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || seq < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return seq, nil
+}
@@ -0,0 +1,84 @@
+package deltasync
+
+import (
+	"net/http"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPullLimit = 200
+	maxPullLimit     = 1000
+	maxPushBatch     = 500
+)
+
+// Handler exposes the /sync endpoints
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a sync handler
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes mounts the sync routes on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/sync/pull", h.Pull)
+	g.POST("/sync/push", h.Push)
+}
+
+// Pull returns changes since ?cursor= (empty for a full sync)
+func (h *Handler) Pull(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	after, err := DecodeCursor(c.QueryParam("cursor"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	limit := defaultPullLimit
+	if err := echo.QueryParamsBinder(c).Int("limit", &limit).BindError(); err != nil || limit <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "limit must be a positive integer")
+	}
+	if limit > maxPullLimit {
+		limit = maxPullLimit
+	}
+
+	changes, next, hasMore, err := h.store.Pull(c.Request().Context(), userID, after, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"changes":  changes,
+		"cursor":   EncodeCursor(next),
+		"has_more": hasMore,
+	})
+}
+
+type pushRequest struct {
+	Mutations []Mutation `json:"mutations"`
+}
+
+// Push applies queued offline writes and echoes rejected ones with the server version
+func (h *Handler) Push(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req pushRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if len(req.Mutations) == 0 || len(req.Mutations) > maxPushBatch {
+		return echo.NewHTTPError(http.StatusBadRequest, "mutations must contain between 1 and 500 items")
+	}
+
+	results := h.store.Push(c.Request().Context(), userID, req.Mutations)
+	return c.JSON(http.StatusOK, map[string]interface{}{"results": results})
+}
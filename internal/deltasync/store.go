@@ -0,0 +1,197 @@
+package deltasync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Applier writes a client mutation to the entity's own table inside the sync
+// transaction and returns the entity's server ID, which differs from
+// m.EntityID for rows created offline
+type Applier interface {
+	Apply(ctx context.Context, tx *sql.Tx, userID string, m Mutation) (string, error)
+}
+
+// ApplierFunc adapts a function to Applier
+type ApplierFunc func(ctx context.Context, tx *sql.Tx, userID string, m Mutation) (string, error)
+
+// Apply calls f
+func (f ApplierFunc) Apply(ctx context.Context, tx *sql.Tx, userID string, m Mutation) (string, error) {
+	return f(ctx, tx, userID, m)
+}
+
+// Store reads and writes the per-user change journal
+type Store struct {
+	db       *sql.DB
+	appliers map[string]Applier
+}
+
+// NewStore creates a sync store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db, appliers: make(map[string]Applier)}
+}
+
+// Register makes an entity syncable from clients
+func (s *Store) Register(entity string, a Applier) {
+	s.appliers[entity] = a
+}
+
+// Migrate creates the change journal table
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS sync_changes (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			entity TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			op TEXT NOT NULL,
+			data TEXT,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_sync_changes_user_seq ON sync_changes(user_id, seq)`,
+		`CREATE INDEX IF NOT EXISTS idx_sync_changes_entity ON sync_changes(user_id, entity, entity_id, seq)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("sync migration: %w", err)
+		}
+	}
+	return nil
+}
+
+// Record appends a change to the journal. Services call it in the same
+// transaction as the row write so server-side edits reach offline clients.
+func Record(ctx context.Context, tx *sql.Tx, userID string, c Change) error {
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = time.Now().UTC()
+	}
+	var data interface{}
+	if c.Op == OpUpsert {
+		data = string(c.Data)
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO sync_changes (user_id, entity, entity_id, op, data, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		userID, c.Entity, c.EntityID, string(c.Op), data, c.UpdatedAt.UTC())
+	return err
+}
+
+// Pull returns up to limit changes after the cursor, and the cursor to resume from
+func (s *Store) Pull(ctx context.Context, userID string, after int64, limit int) ([]Change, int64, bool, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT seq, entity, entity_id, op, COALESCE(data, ''), updated_at FROM sync_changes
+		 WHERE user_id = ? AND seq > ? ORDER BY seq LIMIT ?`,
+		userID, after, limit+1)
+	if err != nil {
+		return nil, after, false, fmt.Errorf("failed to pull changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []Change
+	for rows.Next() {
+		var c Change
+		var op, data string
+		if err := rows.Scan(&c.Seq, &c.Entity, &c.EntityID, &op, &data, &c.UpdatedAt); err != nil {
+			return nil, after, false, err
+		}
+		c.Op = Op(op)
+		if data != "" {
+			c.Data = []byte(data)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, after, false, err
+	}
+
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+	next := after
+	if len(changes) > 0 {
+		next = changes[len(changes)-1].Seq
+	}
+	return changes, next, hasMore, nil
+}
+
+// Push applies client mutations with last-write-wins. Each mutation runs in
+// its own transaction so one conflict does not discard the rest of the queue.
+func (s *Store) Push(ctx context.Context, userID string, mutations []Mutation) []Result {
+	results := make([]Result, 0, len(mutations))
+	for _, m := range mutations {
+		results = append(results, s.pushOne(ctx, userID, m))
+	}
+	return results
+}
+
+func (s *Store) pushOne(ctx context.Context, userID string, m Mutation) Result {
+	result := Result{ClientID: m.ClientID}
+
+	if err := m.Validate(); err != nil {
+		result.Status, result.Reason = "rejected", err.Error()
+		return result
+	}
+	applier, ok := s.appliers[m.Entity]
+	if !ok {
+		result.Status, result.Reason = "rejected", "entity is not syncable"
+		return result
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		result.Status, result.Reason = "rejected", "temporarily unavailable"
+		return result
+	}
+	defer tx.Rollback()
+
+	server, err := latest(ctx, tx, userID, m.Entity, m.EntityID)
+	if err != nil {
+		result.Status, result.Reason = "rejected", "temporarily unavailable"
+		return result
+	}
+	if !Resolve(server, m) {
+		result.Status, result.Reason, result.Server = "rejected", "conflict: server has a newer version", server
+		return result
+	}
+
+	entityID, err := applier.Apply(ctx, tx, userID, m)
+	if err != nil {
+		result.Status, result.Reason = "rejected", err.Error()
+		return result
+	}
+	change := Change{Entity: m.Entity, EntityID: entityID, Op: m.Op, Data: m.Data, UpdatedAt: m.UpdatedAt}
+	if err := Record(ctx, tx, userID, change); err != nil {
+		result.Status, result.Reason = "rejected", "temporarily unavailable"
+		return result
+	}
+	if err := tx.Commit(); err != nil {
+		result.Status, result.Reason = "rejected", "temporarily unavailable"
+		return result
+	}
+
+	result.Status, result.EntityID = "accepted", entityID
+	return result
+}
+
+func latest(ctx context.Context, tx *sql.Tx, userID, entity, entityID string) (*Change, error) {
+	var c Change
+	var op, data string
+	err := tx.QueryRowContext(ctx,
+		`SELECT seq, entity, entity_id, op, COALESCE(data, ''), updated_at FROM sync_changes
+		 WHERE user_id = ? AND entity = ? AND entity_id = ? ORDER BY seq DESC LIMIT 1`,
+		userID, entity, entityID).Scan(&c.Seq, &c.Entity, &c.EntityID, &op, &data, &c.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Op = Op(op)
+	if data != "" {
+		c.Data = []byte(data)
+	}
+	return &c, nil
+}
@@ -0,0 +1,109 @@
+package diagnostics
+
+import (
+	"database/sql"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/echo/v4"
+)
+
+// Enabled reports whether diagnostics are switched on (DIAGNOSTICS_ENABLED)
+func Enabled() bool {
+	return envconfig.Bool("DIAGNOSTICS_ENABLED", false)
+}
+
+// Handler serves runtime diagnostics; redis may be nil
+type Handler struct {
+	db      *sql.DB
+	redis   *redis.Client
+	started time.Time
+}
+
+// NewHandler creates a diagnostics handler
+func NewHandler(db *sql.DB, redisClient *redis.Client) *Handler {
+	return &Handler{db: db, redis: redisClient, started: time.Now()}
+}
+
+// RegisterRoutes mounts pprof, expvar and /diagnostics on an admin-protected group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/diagnostics", h.Diagnostics)
+	g.GET("/debug/vars", echo.WrapHandler(expvar.Handler()))
+
+	g.GET("/debug/pprof/", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+	g.GET("/debug/pprof/cmdline", echo.WrapHandler(http.HandlerFunc(pprof.Cmdline)))
+	g.GET("/debug/pprof/profile", echo.WrapHandler(http.HandlerFunc(pprof.Profile)))
+	g.GET("/debug/pprof/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+	g.GET("/debug/pprof/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
+	// pprof.Index only resolves names under /debug/pprof/, so serve named profiles directly
+	g.GET("/debug/pprof/:name", func(c echo.Context) error {
+		pprof.Handler(c.Param("name")).ServeHTTP(c.Response(), c.Request())
+		return nil
+	})
+}
+
+// Diagnostics returns goroutine, heap, GC, DB pool and Redis pool stats
+func (h *Handler) Diagnostics(c echo.Context) error {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var lastPause time.Duration
+	if mem.NumGC > 0 {
+		lastPause = time.Duration(mem.PauseNs[(mem.NumGC+255)%256])
+	}
+
+	resp := map[string]interface{}{
+		"uptime":     time.Since(h.started).Round(time.Second).String(),
+		"go_version": runtime.Version(),
+		"goroutines": runtime.NumGoroutine(),
+		"cpus":       runtime.NumCPU(),
+		"heap": map[string]interface{}{
+			"alloc_bytes":    mem.HeapAlloc,
+			"inuse_bytes":    mem.HeapInuse,
+			"idle_bytes":     mem.HeapIdle,
+			"released_bytes": mem.HeapReleased,
+			"objects":        mem.HeapObjects,
+			"sys_bytes":      mem.Sys,
+		},
+		"gc": map[string]interface{}{
+			"num_gc":          mem.NumGC,
+			"pause_total":     time.Duration(mem.PauseTotalNs).String(),
+			"last_pause":      lastPause.String(),
+			"next_gc_bytes":   mem.NextGC,
+			"gc_cpu_fraction": mem.GCCPUFraction,
+		},
+	}
+
+	if h.db != nil {
+		s := h.db.Stats()
+		resp["database"] = map[string]interface{}{
+			"max_open":        s.MaxOpenConnections,
+			"open":            s.OpenConnections,
+			"in_use":          s.InUse,
+			"idle":            s.Idle,
+			"wait_count":      s.WaitCount,
+			"wait_duration":   s.WaitDuration.String(),
+			"max_idle_closed": s.MaxIdleClosed,
+		}
+	}
+
+	if h.redis != nil {
+		s := h.redis.PoolStats()
+		resp["redis"] = map[string]interface{}{
+			"hits":        s.Hits,
+			"misses":      s.Misses,
+			"timeouts":    s.Timeouts,
+			"total_conns": s.TotalConns,
+			"idle_conns":  s.IdleConns,
+			"stale_conns": s.StaleConns,
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,341 @@
+package diary
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/diarybatch"
+	"nutrition-health-backend/internal/diaryimport"
+	"nutrition-health-backend/internal/insights"
+	"nutrition-health-backend/internal/localtime"
+	"nutrition-health-backend/internal/nutrition"
+	"nutrition-health-backend/internal/portions"
+	"nutrition-health-backend/internal/repo"
+	"nutrition-health-backend/internal/symptoms"
+	"nutrition-health-backend/internal/targets"
+	"nutrition-health-backend/internal/tenant"
+	"nutrition-health-backend/internal/txn"
+	"nutrition-health-backend/internal/units"
+)
+
+// Store reads and writes diary entries and weigh-ins for features outside
+// the diary handlers, through the repo queries, within the tenant ctx carries
+type Store struct {
+	db      *sql.DB
+	txm     *txn.Manager
+	zones   *localtime.Store
+	targets *targets.Store
+}
+
+// NewStore creates a diary store; zones places imported entries on the
+// user's local day and goals are what Remaining counts down from
+func NewStore(db *sql.DB, zones *localtime.Store, goals *targets.Store) *Store {
+	return &Store{db: db, txm: txn.NewManager(db), zones: zones, targets: goals}
+}
+
+// IntakesBetween lists the foods a user logged in [from, to), oldest first,
+// with their ingredients when the food lists them
+func (s *Store) IntakesBetween(ctx context.Context, userID string, from, to time.Time) ([]symptoms.Intake, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := repo.New(s.db).ListIntakes(ctx, repo.ListIntakesParams{UserID: userID, TenantID: tenantID, LoggedFrom: from.UTC(), LoggedTo: to.UTC()})
+	if err != nil {
+		return nil, err
+	}
+	intakes := make([]symptoms.Intake, 0, len(rows))
+	for _, r := range rows {
+		intakes = append(intakes, symptoms.Intake{
+			FoodID:      strconv.FormatInt(r.FoodID, 10),
+			FoodName:    r.Name.String,
+			Ingredients: splitIngredients(r.Ingredients.String),
+			EatenAt:     r.LoggedAt,
+		})
+	}
+	return intakes, nil
+}
+
+// splitIngredients splits a label-style list, dropping bracketed sub-lists
+// such as "chocolate (sugar, cocoa)" down to their main ingredient
+func splitIngredients(list string) []string {
+	var out []string
+	var b strings.Builder
+	add := func() {
+		if part := strings.TrimSpace(b.String()); part != "" {
+			out = append(out, part)
+		}
+		b.Reset()
+	}
+	depth := 0
+	for _, r := range list {
+		switch {
+		case r == '(' || r == '[':
+			depth++
+		case (r == ')' || r == ']') && depth > 0:
+			depth--
+		case depth > 0:
+		case r == ',' || r == ';':
+			add()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	add()
+	return out
+}
+
+// MealLogged reports whether the user logged anything for meal in [from, to);
+// it implements reminders.Activity
+func (s *Store) MealLogged(ctx context.Context, userID, meal string, from, to time.Time) (bool, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := repo.New(s.db).CountMealEntries(ctx, repo.CountMealEntriesParams{
+		UserID:     userID,
+		TenantID:   tenantID,
+		MealType:   meal,
+		LoggedFrom: from.UTC(),
+		LoggedTo:   to.UTC(),
+	})
+	return n > 0, err
+}
+
+// WeightLogged reports whether the user recorded a weight in [from, to)
+func (s *Store) WeightLogged(ctx context.Context, userID string, from, to time.Time) (bool, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := repo.New(s.db).CountWeightLogs(ctx, repo.CountWeightLogsParams{UserID: userID, TenantID: tenantID, LoggedFrom: from.UTC(), LoggedTo: to.UTC()})
+	return n > 0, err
+}
+
+// ActiveUsers lists the users with diary entries since the given time; it
+// implements insights.Diary
+func (s *Store) ActiveUsers(ctx context.Context, since time.Time) ([]string, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repo.New(s.db).ListActiveUsers(ctx, repo.ListActiveUsersParams{LoggedFrom: since.UTC(), TenantID: tenantID})
+}
+
+// DailyTotals sums the nutrients logged in [from, to) per local day, oldest
+// first; it implements insights.Diary. Food nutrients are per 100 g.
+func (s *Store) DailyTotals(ctx context.Context, userID string, from, to time.Time, loc *time.Location) ([]insights.Day, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := repo.New(s.db).ListDiaryNutrients(ctx, repo.ListDiaryNutrientsParams{UserID: userID, TenantID: tenantID, LoggedFrom: from.UTC(), LoggedTo: to.UTC()})
+	if err != nil {
+		return nil, err
+	}
+	var days []insights.Day
+	for _, r := range rows {
+		n := nutrition.Nutrients{
+			Calories: r.Calories,
+			ProteinG: r.Protein,
+			CarbsG:   r.Carbs,
+			FatG:     r.Fat,
+			FiberG:   r.Fiber,
+			SugarG:   r.Sugar,
+			SodiumMg: r.Sodium,
+		}
+		date := localtime.Date(r.LoggedAt, loc)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, insights.Day{Date: date})
+		}
+		last := &days[len(days)-1]
+		last.Nutrients = last.Nutrients.Add(n.Scale(r.QuantityG / 100))
+	}
+	for i := range days {
+		days[i].Nutrients = days[i].Nutrients.Round()
+	}
+	return days, nil
+}
+
+// Remaining returns the user's daily targets minus what they logged in
+// [from, to); it implements portions.RemainingSource
+func (s *Store) Remaining(ctx context.Context, userID string, from, to time.Time) (portions.Macros, error) {
+	t, err := s.targets.ForUser(ctx, userID)
+	if errors.Is(err, targets.ErrNoChoice) || errors.Is(err, targets.ErrNotFound) {
+		return portions.Macros{}, portions.ErrNoTargets
+	}
+	if err != nil {
+		return portions.Macros{}, err
+	}
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return portions.Macros{}, err
+	}
+	rows, err := repo.New(s.db).ListDiaryNutrients(ctx, repo.ListDiaryNutrientsParams{UserID: userID, TenantID: tenantID, LoggedFrom: from.UTC(), LoggedTo: to.UTC()})
+	if err != nil {
+		return portions.Macros{}, err
+	}
+	var logged nutrition.Nutrients
+	for _, r := range rows {
+		logged = logged.Add(nutrition.Nutrients{Calories: r.Calories, ProteinG: r.Protein, CarbsG: r.Carbs, FatG: r.Fat}.Scale(r.QuantityG / 100))
+	}
+	return portions.Macros{Calories: t.Calories, ProteinG: t.ProteinG, CarbsG: t.CarbsG, FatG: t.FatG}.Sub(logged), nil
+}
+
+// InsertEntry adds one diary entry in the transaction ctx carries, if any;
+// it implements diarybatch.Writer
+func (s *Store) InsertEntry(ctx context.Context, userID string, e diarybatch.Entry) (string, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return "", err
+	}
+	var id int64
+	err = s.txm.Do(ctx, func(ctx context.Context) error {
+		q := repo.FromContext(ctx, s.db)
+		foodID, err := knownFood(ctx, q, e.FoodID)
+		if err != nil {
+			return err
+		}
+		entry, err := q.CreateDiaryEntry(ctx, repo.CreateDiaryEntryParams{
+			UserID:    userID,
+			FoodID:    foodID,
+			MealType:  strings.ToLower(e.Meal),
+			QuantityG: e.Grams,
+			LoggedAt:  e.EatenAt.UTC(),
+			TenantID:  tenantID,
+		})
+		if err != nil {
+			return err
+		}
+		id = entry.ID
+		return record(ctx, userID, EntityEntry, entry.ID, journalEntry(entry))
+	})
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(id, 10), nil
+}
+
+// journalEntry is the sync payload of a stored entry
+func journalEntry(e repo.DiaryEntry) diarybatch.Entry {
+	return diarybatch.Entry{
+		FoodID:  strconv.FormatInt(e.FoodID, 10),
+		Meal:    e.MealType,
+		Grams:   e.QuantityG,
+		EatenAt: e.LoggedAt,
+	}
+}
+
+// mealHours are the local times imported entries, which only carry a date,
+// are logged at
+var mealHours = map[string]int{"breakfast": 8, "lunch": 13, "snack": 16, "dinner": 19}
+
+// WriteEntries adds an import's entries to the user's diary in one
+// transaction; it implements diaryimport.Writer. Custom entries become a food
+// holding the tracker's values, so their nutrients survive.
+func (s *Store) WriteEntries(ctx context.Context, userID string, entries []diaryimport.Entry) error {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return err
+	}
+	loc, err := s.zones.Location(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.txm.Do(ctx, func(ctx context.Context) error {
+		q := repo.FromContext(ctx, s.db)
+		for i, e := range entries {
+			meal := importMeal(e.Meal)
+			day, _, err := localtime.ParseDay(e.Date, loc)
+			if err != nil {
+				return fmt.Errorf("entry %d: %w", i, err)
+			}
+
+			var foodID int64
+			var grams float64
+			if e.FoodID != "" {
+				if foodID, err = strconv.ParseInt(e.FoodID, 10, 64); err != nil {
+					return fmt.Errorf("entry %d: %w: %s", i, diarybatch.ErrUnknownFood, e.FoodID)
+				}
+				f, err := q.GetFood(ctx, foodID)
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("entry %d: %w: %s", i, diarybatch.ErrUnknownFood, e.FoodID)
+				}
+				if err != nil {
+					return err
+				}
+				grams = importGrams(e, f.Calories)
+			} else {
+				grams = importGrams(e, 0)
+				per100 := e.Nutrients.Scale(100 / grams).Round()
+				f, err := q.CreateFood(ctx, repo.CreateFoodParams{
+					Name:     e.Name,
+					Calories: per100.Calories,
+					Protein:  per100.ProteinG,
+					Carbs:    per100.CarbsG,
+					Fat:      per100.FatG,
+					Fiber:    per100.FiberG,
+					Sugar:    per100.SugarG,
+					Sodium:   per100.SodiumMg,
+				})
+				if err != nil {
+					return err
+				}
+				foodID = f.ID
+			}
+
+			entry, err := q.CreateDiaryEntry(ctx, repo.CreateDiaryEntryParams{
+				UserID:    userID,
+				FoodID:    foodID,
+				MealType:  meal,
+				QuantityG: grams,
+				LoggedAt:  day.Add(time.Duration(mealHours[meal]) * time.Hour).UTC(),
+				TenantID:  tenantID,
+			})
+			if err != nil {
+				return err
+			}
+			if err := record(ctx, userID, EntityEntry, entry.ID, journalEntry(entry)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// importMeal maps a tracker's meal name onto diarybatch.Meals; unnamed and
+// unknown meals are snacks
+func importMeal(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, meal := range diarybatch.Meals {
+		if strings.HasPrefix(name, meal) {
+			return meal
+		}
+	}
+	return "snack"
+}
+
+// importGrams sizes an imported portion from its calories against the food's
+// per-100 g value when both are known, else from an amount such as "150 g" or
+// "1 cup", else as 100 g
+func importGrams(e diaryimport.Entry, kcalPer100 float64) float64 {
+	if kcalPer100 > 0 && e.Nutrients.Calories > 0 {
+		return math.Round(e.Nutrients.Calories/kcalPer100*1000) / 10
+	}
+	if n, unit, ok := strings.Cut(strings.TrimSpace(e.Amount), " "); ok {
+		amount, err := strconv.ParseFloat(n, 64)
+		u, uerr := units.ParseUnit(unit)
+		if err == nil && uerr == nil && amount > 0 {
+			if g, err := units.ToGrams(units.Quantity{Amount: amount, Unit: u}, units.DensityFor(e.Name)); err == nil && g > 0 {
+				return g
+			}
+		}
+	}
+	return 100
+}
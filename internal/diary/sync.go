@@ -0,0 +1,256 @@
+package diary
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/deltasync"
+	"nutrition-health-backend/internal/diarybatch"
+	"nutrition-health-backend/internal/repo"
+	"nutrition-health-backend/internal/tenant"
+	"nutrition-health-backend/internal/txn"
+)
+
+// Entities clients sync through deltasync. Rows created offline are pushed
+// under a non-numeric client ID and re-keyed to the server ID Push returns.
+const (
+	EntityEntry  = "diary_entry"
+	EntityWeight = "weight_log"
+	EntityPlan   = "meal_plan"
+)
+
+// errNotFound rejects pushed updates to rows the user doesn't have
+var errNotFound = errors.New("not found")
+
+// Weight is the sync payload of a weigh-in
+type Weight struct {
+	Kg       float64   `json:"weight_kg"`
+	LoggedAt time.Time `json:"logged_at"`
+}
+
+// Plan is the sync payload of a meal plan; pushing it replaces the items
+type Plan struct {
+	Name  string     `json:"name"`
+	Items []PlanItem `json:"items"`
+}
+
+// PlanItem is one planned food
+type PlanItem struct {
+	FoodID string  `json:"food_id"`
+	Grams  float64 `json:"quantity_g"`
+	Meal   string  `json:"meal_type"`
+	Date   string  `json:"date"`
+}
+
+// RegisterSync makes diary entries, weigh-ins and meal plans syncable
+func (s *Store) RegisterSync(sync *deltasync.Store) {
+	sync.Register(EntityEntry, deltasync.ApplierFunc(s.applyEntry))
+	sync.Register(EntityWeight, deltasync.ApplierFunc(s.applyWeight))
+	sync.Register(EntityPlan, deltasync.ApplierFunc(s.applyPlan))
+}
+
+// serverID parses an ID the server issued; client IDs for offline rows don't parse
+func serverID(id string) (int64, bool) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	return n, err == nil && n > 0
+}
+
+func decode(m deltasync.Mutation, v interface{}) error {
+	if err := json.Unmarshal(m.Data, v); err != nil {
+		return fmt.Errorf("invalid %s data", m.Entity)
+	}
+	return nil
+}
+
+// knownFood parses a food ID and checks the food exists
+func knownFood(ctx context.Context, q *repo.Queries, id string) (int64, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err == nil {
+		_, err = q.GetFood(ctx, n)
+	}
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, strconv.ErrSyntax) || errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("%w: %s", diarybatch.ErrUnknownFood, id)
+	}
+	return n, err
+}
+
+// record journals a server-side write in the transaction ctx carries, so it
+// reaches the user's offline clients
+func record(ctx context.Context, userID, entity string, id int64, data interface{}) error {
+	tx, ok := txn.FromContext(ctx)
+	if !ok {
+		return errors.New("sync journal write outside a transaction")
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return deltasync.Record(ctx, tx, userID, deltasync.Change{
+		Entity:   entity,
+		EntityID: strconv.FormatInt(id, 10),
+		Op:       deltasync.OpUpsert,
+		Data:     b,
+	})
+}
+
+func (s *Store) applyEntry(ctx context.Context, tx *sql.Tx, userID string, m deltasync.Mutation) (string, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return "", err
+	}
+	q := repo.New(tx)
+	id, existing := serverID(m.EntityID)
+	if m.Op == deltasync.OpDelete {
+		if existing {
+			_, err = q.SoftDeleteDiaryEntry(ctx, repo.SoftDeleteDiaryEntryParams{
+				DeletedAt: sql.NullTime{Time: m.UpdatedAt.UTC(), Valid: true},
+				ID:        id,
+				UserID:    userID,
+				TenantID:  tenantID,
+			})
+		}
+		return m.EntityID, err
+	}
+
+	var e diarybatch.Entry
+	if err := decode(m, &e); err != nil {
+		return "", err
+	}
+	if err := e.Validate(time.Now()); err != nil {
+		return "", err
+	}
+	foodID, err := knownFood(ctx, q, e.FoodID)
+	if err != nil {
+		return "", err
+	}
+	if !existing {
+		entry, err := q.CreateDiaryEntry(ctx, repo.CreateDiaryEntryParams{
+			UserID:    userID,
+			FoodID:    foodID,
+			MealType:  strings.ToLower(e.Meal),
+			QuantityG: e.Grams,
+			LoggedAt:  e.EatenAt.UTC(),
+			TenantID:  tenantID,
+		})
+		return strconv.FormatInt(entry.ID, 10), err
+	}
+	n, err := q.UpdateDiaryEntry(ctx, repo.UpdateDiaryEntryParams{
+		FoodID:    foodID,
+		MealType:  strings.ToLower(e.Meal),
+		QuantityG: e.Grams,
+		LoggedAt:  e.EatenAt.UTC(),
+		ID:        id,
+		UserID:    userID,
+		TenantID:  tenantID,
+	})
+	if err == nil && n == 0 {
+		err = errNotFound
+	}
+	return m.EntityID, err
+}
+
+func (s *Store) applyWeight(ctx context.Context, tx *sql.Tx, userID string, m deltasync.Mutation) (string, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return "", err
+	}
+	q := repo.New(tx)
+	id, existing := serverID(m.EntityID)
+	if m.Op == deltasync.OpDelete {
+		if existing {
+			_, err = q.DeleteWeightLog(ctx, repo.DeleteWeightLogParams{ID: id, UserID: userID, TenantID: tenantID})
+		}
+		return m.EntityID, err
+	}
+
+	var w Weight
+	if err := decode(m, &w); err != nil {
+		return "", err
+	}
+	if w.Kg <= 0 || w.Kg > 500 {
+		return "", errors.New("weight_kg must be between 0 and 500")
+	}
+	if w.LoggedAt.IsZero() {
+		return "", errors.New("logged_at is required")
+	}
+	if !existing {
+		wl, err := q.CreateWeightLog(ctx, repo.CreateWeightLogParams{UserID: userID, WeightKg: w.Kg, LoggedAt: w.LoggedAt.UTC(), TenantID: tenantID})
+		return strconv.FormatInt(wl.ID, 10), err
+	}
+	n, err := q.UpdateWeightLog(ctx, repo.UpdateWeightLogParams{WeightKg: w.Kg, LoggedAt: w.LoggedAt.UTC(), ID: id, UserID: userID, TenantID: tenantID})
+	if err == nil && n == 0 {
+		err = errNotFound
+	}
+	return m.EntityID, err
+}
+
+func (s *Store) applyPlan(ctx context.Context, tx *sql.Tx, userID string, m deltasync.Mutation) (string, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return "", err
+	}
+	q := repo.New(tx)
+	id, existing := serverID(m.EntityID)
+	if m.Op == deltasync.OpDelete {
+		if existing {
+			_, err = q.SoftDeleteMealPlan(ctx, repo.SoftDeleteMealPlanParams{
+				DeletedAt: sql.NullTime{Time: m.UpdatedAt.UTC(), Valid: true},
+				ID:        id,
+				UserID:    userID,
+				TenantID:  tenantID,
+			})
+		}
+		return m.EntityID, err
+	}
+
+	var p Plan
+	if err := decode(m, &p); err != nil {
+		return "", err
+	}
+	items := make([]repo.AddMealPlanItemParams, 0, len(p.Items))
+	for i, it := range p.Items {
+		if _, err := time.Parse("2006-01-02", it.Date); err != nil {
+			return "", fmt.Errorf("item %d: date must be YYYY-MM-DD", i)
+		}
+		if it.Grams <= 0 || strings.TrimSpace(it.Meal) == "" {
+			return "", fmt.Errorf("item %d: quantity_g and meal_type are required", i)
+		}
+		foodID, err := knownFood(ctx, q, it.FoodID)
+		if err != nil {
+			return "", fmt.Errorf("item %d: %w", i, err)
+		}
+		items = append(items, repo.AddMealPlanItemParams{FoodID: foodID, Quantity: it.Grams, MealType: strings.ToLower(it.Meal), Date: it.Date})
+	}
+
+	if existing {
+		n, err := q.UpdateMealPlan(ctx, repo.UpdateMealPlanParams{Name: p.Name, ID: id, UserID: userID, TenantID: tenantID})
+		if err == nil && n == 0 {
+			err = errNotFound
+		}
+		if err == nil {
+			err = q.DeleteMealPlanItems(ctx, id)
+		}
+		if err != nil {
+			return "", err
+		}
+	} else {
+		plan, err := q.CreateMealPlan(ctx, repo.CreateMealPlanParams{UserID: userID, Name: p.Name, CreatedAt: time.Now().UTC(), TenantID: tenantID})
+		if err != nil {
+			return "", err
+		}
+		id = plan.ID
+	}
+	for _, it := range items {
+		it.MealPlanID = id
+		if err := q.AddMealPlanItem(ctx, it); err != nil {
+			return "", err
+		}
+	}
+	return strconv.FormatInt(id, 10), nil
+}
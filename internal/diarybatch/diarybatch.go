@@ -0,0 +1,149 @@
+package diarybatch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/outbox"
+	"nutrition-health-backend/internal/txn"
+)
+
+// TopicEntriesLogged is the outbox topic published once per committed batch
+const TopicEntriesLogged = "diary.entries_logged"
+
+// MaxEntries caps the entries in one batch
+const MaxEntries = 100
+
+var (
+	// ErrRejected is returned when any entry fails; nothing is written and
+	// the per-item results say which entries to fix
+	ErrRejected = errors.New("batch rejected")
+	// ErrUnknownFood is returned by writers for entries naming a food that doesn't exist
+	ErrUnknownFood = errors.New("food not found")
+)
+
+// Meals are the accepted meal types
+var Meals = []string{"breakfast", "lunch", "dinner", "snack"}
+
+// Entry is one diary entry to log
+type Entry struct {
+	FoodID  string    `json:"food_id"`
+	Meal    string    `json:"meal_type"`
+	Grams   float64   `json:"quantity_g"`
+	EatenAt time.Time `json:"logged_at"`
+}
+
+// Validate checks an entry before anything is written
+func (e Entry) Validate(now time.Time) error {
+	switch {
+	case strings.TrimSpace(e.FoodID) == "":
+		return errors.New("food_id is required")
+	case !validMeal(e.Meal):
+		return fmt.Errorf("meal_type must be one of %s", strings.Join(Meals, ", "))
+	case e.Grams <= 0 || e.Grams > 5000:
+		return errors.New("quantity_g must be between 0 and 5000")
+	case e.EatenAt.After(now.Add(24 * time.Hour)):
+		return errors.New("logged_at is too far in the future")
+	}
+	return nil
+}
+
+func validMeal(m string) bool {
+	for _, meal := range Meals {
+		if strings.EqualFold(m, meal) {
+			return true
+		}
+	}
+	return false
+}
+
+// Writer inserts diary entries, typically the diary store. It is called
+// inside the batch's transaction, which ctx carries for txn.Manager.Querier.
+type Writer interface {
+	InsertEntry(ctx context.Context, userID string, e Entry) (string, error)
+}
+
+// Result is the outcome of one entry, in request order
+type Result struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Logged is the payload of TopicEntriesLogged
+type Logged struct {
+	UserID   string   `json:"user_id"`
+	EntryIDs []string `json:"entry_ids"`
+	Meals    []string `json:"meals"`
+	Count    int      `json:"count"`
+}
+
+// Service logs batches of diary entries in one transaction
+type Service struct {
+	txm    *txn.Manager
+	writer Writer
+}
+
+// NewService creates the batch service
+func NewService(db *sql.DB, writer Writer) *Service {
+	return &Service{txm: txn.NewManager(db), writer: writer}
+}
+
+// Log writes every entry and the aggregated event, or nothing. On
+// ErrRejected the results carry the error of each failing entry.
+func (s *Service) Log(ctx context.Context, userID string, entries []Entry) ([]Result, error) {
+	results := make([]Result, len(entries))
+	rejected := false
+	now := time.Now()
+	for i := range entries {
+		results[i].Index = i
+		if entries[i].EatenAt.IsZero() {
+			entries[i].EatenAt = now
+		}
+		if err := entries[i].Validate(now); err != nil {
+			results[i].Error = err.Error()
+			rejected = true
+		}
+	}
+	if rejected {
+		return results, ErrRejected
+	}
+
+	err := s.txm.Do(ctx, func(ctx context.Context) error {
+		logged := Logged{UserID: userID, Count: len(entries)}
+		seen := map[string]bool{}
+		for i, e := range entries {
+			id, err := s.writer.InsertEntry(ctx, userID, e)
+			if errors.Is(err, ErrUnknownFood) {
+				// Keep going so the client learns about every bad entry at once
+				results[i].Error = err.Error()
+				rejected = true
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("entry %d: %w", i, err)
+			}
+			results[i].ID = id
+			logged.EntryIDs = append(logged.EntryIDs, id)
+			if meal := strings.ToLower(e.Meal); !seen[meal] {
+				seen[meal] = true
+				logged.Meals = append(logged.Meals, meal)
+			}
+		}
+		if rejected {
+			return ErrRejected
+		}
+		return outbox.Enqueue(ctx, s.txm.Querier(ctx), TopicEntriesLogged, userID+":"+logged.EntryIDs[0], logged, map[string]string{"user_id": userID})
+	})
+	if err != nil {
+		// Rolled back, so no entry kept its ID
+		for i := range results {
+			results[i].ID = ""
+		}
+	}
+	return results, err
+}
@@ -0,0 +1,57 @@
+package diarybatch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes batch diary writes over HTTP
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a batch handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes mounts POST /diary/entries:batch on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	// The colon is escaped so echo doesn't read ":batch" as a path parameter
+	g.POST(`/diary/entries\:batch`, h.Create)
+}
+
+// Create logs up to MaxEntries entries in one transaction. Any bad entry
+// rejects the whole batch with 422 and per-item errors.
+func (h *Handler) Create(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	var req struct {
+		Entries []Entry `json:"entries"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if len(req.Entries) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "entries are required")
+	}
+	if len(req.Entries) > MaxEntries {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("at most %d entries per batch", MaxEntries))
+	}
+
+	results, err := h.service.Log(c.Request().Context(), userID, req.Entries)
+	if errors.Is(err, ErrRejected) {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{"error": err.Error(), "results": results})
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, map[string]interface{}{"count": len(results), "results": results})
+}
@@ -0,0 +1,269 @@
+package diaryimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/localtime"
+	"nutrition-health-backend/internal/nutrition"
+)
+
+// Supported export formats
+const (
+	FormatAuto         = "auto"
+	FormatMyFitnessPal = "myfitnesspal"
+	FormatCronometer   = "cronometer"
+)
+
+// Row is one foreign diary line
+type Row struct {
+	Line      int                 `json:"line"`
+	Date      string              `json:"date"`
+	Meal      string              `json:"meal,omitempty"`
+	Name      string              `json:"name"`
+	Amount    string              `json:"amount,omitempty"`
+	Nutrients nutrition.Nutrients `json:"nutrients"`
+}
+
+// format maps a tracker's column names onto Row fields
+type format struct {
+	name    string
+	columns map[string][]string
+}
+
+var formats = []format{
+	{FormatCronometer, map[string][]string{
+		"date":     {"day", "date"},
+		"meal":     {"group", "meal"},
+		"name":     {"food name"},
+		"amount":   {"amount"},
+		"calories": {"energy (kcal)"},
+		"protein":  {"protein (g)"},
+		"carbs":    {"carbs (g)", "net carbs (g)"},
+		"fat":      {"fat (g)"},
+		"fiber":    {"fiber (g)"},
+		"sugar":    {"sugars (g)"},
+		"sodium":   {"sodium (mg)"},
+	}},
+	{FormatMyFitnessPal, map[string][]string{
+		"date":     {"date"},
+		"meal":     {"meal"},
+		"name":     {"food", "food name", "name", "description"},
+		"amount":   {"quantity", "serving", "servings"},
+		"calories": {"calories"},
+		"protein":  {"protein (g)", "protein"},
+		"carbs":    {"carbohydrates (g)", "carbohydrates", "carbs"},
+		"fat":      {"fat (g)", "fat"},
+		"fiber":    {"fiber", "fiber (g)"},
+		"sugar":    {"sugar", "sugar (g)"},
+		"sodium":   {"sodium (mg)", "sodium"},
+	}},
+}
+
+// dateLayouts are the unambiguous date formats seen in tracker exports;
+// slash dates with the year last depend on the file's day order
+var dateLayouts = []string{localtime.DateLayout, "2006/01/02"}
+
+var (
+	monthFirstLayouts = []string{"01/02/2006", "1/2/2006"}
+	dayFirstLayouts   = []string{"02/01/2006", "2/1/2006"}
+)
+
+// record is one buffered CSV line, or the error reading it
+type record struct {
+	fields []string
+	err    error
+}
+
+// parser reads rows of one detected format
+type parser struct {
+	records  []record
+	format   string
+	index    map[string]int
+	line     int
+	dayFirst bool
+}
+
+// newParser reads the header and detects the format; want may force one.
+// The rest of the file is buffered so the day order of slash dates can be
+// decided from the whole file.
+func newParser(r io.Reader, want string) (*parser, error) {
+	br := bufio.NewReader(r)
+	cr := csv.NewReader(br)
+	// Locales with a decimal comma export semicolon-separated files
+	if first, _ := br.Peek(4096); countOutsideQuotes(firstLine(first), ';') > countOutsideQuotes(firstLine(first), ',') {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, f := range formats {
+		if want != FormatAuto && want != "" && want != f.name {
+			continue
+		}
+		index := map[string]int{}
+		for field, names := range f.columns {
+			for _, n := range names {
+				if i, ok := cols[n]; ok {
+					index[field] = i
+					break
+				}
+			}
+		}
+		_, hasDate := index["date"]
+		_, hasName := index["name"]
+		_, hasCalories := index["calories"]
+		if hasDate && hasName && hasCalories {
+			p := &parser{format: f.name, index: index, line: 1}
+			for {
+				fields, err := cr.Read()
+				if err == io.EOF {
+					break
+				}
+				p.records = append(p.records, record{fields, err})
+			}
+			p.dayFirst = p.detectDayFirst()
+			return p, nil
+		}
+	}
+	if want != FormatAuto && want != "" {
+		return nil, fmt.Errorf("file is not a %s export (needs date, food name and calories columns)", want)
+	}
+	return nil, fmt.Errorf("unrecognised export: expected a MyFitnessPal or Cronometer CSV")
+}
+
+// next returns the next row, io.EOF at the end, or a row-level error to record and skip
+func (p *parser) next() (Row, error) {
+	if len(p.records) == 0 {
+		return Row{}, io.EOF
+	}
+	next := p.records[0]
+	p.records = p.records[1:]
+	p.line++
+	if next.err != nil {
+		return Row{}, next.err
+	}
+	rec := next.fields
+	get := func(field string) string {
+		if i, ok := p.index[field]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	num := func(field string) float64 {
+		return parseNumber(get(field))
+	}
+	row := Row{Line: p.line, Meal: get("meal"), Name: get("name"), Amount: get("amount")}
+	if row.Name == "" {
+		return row, fmt.Errorf("line %d: missing food name", p.line)
+	}
+	date, err := parseDate(get("date"), p.dayFirst)
+	if err != nil {
+		return row, fmt.Errorf("line %d: %w", p.line, err)
+	}
+	row.Date = date
+	row.Nutrients = nutrition.Nutrients{
+		Calories: num("calories"),
+		ProteinG: num("protein"),
+		CarbsG:   num("carbs"),
+		FatG:     num("fat"),
+		FiberG:   num("fiber"),
+		SugarG:   num("sugar"),
+		SodiumMg: num("sodium"),
+	}
+	return row, nil
+}
+
+// detectDayFirst decides whether slash dates are DD/MM: a first part over 12
+// can only be a day and a second part over 12 only a month. Files with no
+// such date keep the format's month-first default.
+func (p *parser) detectDayFirst() bool {
+	i, ok := p.index["date"]
+	if !ok {
+		return false
+	}
+	var dayFirst, monthFirst bool
+	for _, rec := range p.records {
+		if rec.err != nil || i >= len(rec.fields) {
+			continue
+		}
+		parts := strings.Split(strings.TrimSpace(rec.fields[i]), "/")
+		if len(parts) != 3 || len(parts[2]) != 4 {
+			continue
+		}
+		a, errA := strconv.Atoi(parts[0])
+		b, errB := strconv.Atoi(parts[1])
+		if errA != nil || errB != nil {
+			continue
+		}
+		dayFirst = dayFirst || a > 12
+		monthFirst = monthFirst || b > 12
+	}
+	return dayFirst && !monthFirst
+}
+
+func parseDate(s string, dayFirst bool) (string, error) {
+	layouts := append(append([]string{}, dateLayouts...), monthFirstLayouts...)
+	if dayFirst {
+		layouts = append(append([]string{}, dateLayouts...), dayFirstLayouts...)
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format(localtime.DateLayout), nil
+		}
+	}
+	return "", fmt.Errorf("invalid date %q", s)
+}
+
+// parseNumber reads export numbers with either decimal separator: the last
+// of "." and "," is the decimal point when both appear, and a lone comma is
+// a decimal comma unless exactly three digits follow it ("1,250" kcal)
+func parseNumber(s string) float64 {
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	dot, comma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case dot >= 0 && comma >= 0 && comma > dot:
+		s = strings.ReplaceAll(strings.ReplaceAll(s, ".", ""), ",", ".")
+	case dot >= 0 && comma >= 0:
+		s = strings.ReplaceAll(s, ",", "")
+	case comma >= 0 && strings.Count(s, ",") == 1 && len(s)-comma-1 != 3:
+		s = strings.Replace(s, ",", ".", 1)
+	default:
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+func firstLine(b []byte) []byte {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		return b[:i]
+	}
+	return b
+}
+
+func countOutsideQuotes(line []byte, sep byte) int {
+	n, quoted := 0, false
+	for _, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,170 @@
+package diaryimport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"nutrition-health-backend/internal/diarybatch"
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes diary imports over HTTP
+type Handler struct {
+	store  *Store
+	runner *Runner
+}
+
+// NewHandler creates an import handler
+func NewHandler(store *Store, runner *Runner) *Handler {
+	return &Handler{store: store, runner: runner}
+}
+
+// RegisterRoutes mounts /import routes on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.POST("/import", h.Upload)
+	g.GET("/import/:id", h.Status).Name = "diaryimport.status"
+	g.GET("/import/:id/review", h.ReviewRows)
+	g.POST("/import/:id/review", h.Review)
+	g.POST("/import/:id/commit", h.Commit)
+}
+
+// Upload accepts a CSV as the "file" form field or the raw body; ?format= forces a format
+func (h *Handler) Upload(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	format := c.QueryParam("format")
+	switch format {
+	case "":
+		format = FormatAuto
+	case FormatAuto, FormatMyFitnessPal, FormatCronometer:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "format must be auto, myfitnesspal or cronometer")
+	}
+
+	var src io.Reader = c.Request().Body
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "failed to read upload")
+		}
+		defer f.Close()
+		src = f
+	}
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "empty upload")
+	}
+	// Reject unrecognised files now rather than failing the job later
+	if _, err := newParser(bytes.NewReader(data), format); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	job, err := h.store.Create(c.Request().Context(), userID, format, data)
+	if err != nil {
+		return err
+	}
+	h.runner.Notify()
+	c.Response().Header().Set(echo.HeaderLocation, c.Echo().Reverse("diaryimport.status", job.ID))
+	return c.JSON(http.StatusAccepted, status(job))
+}
+
+// Status reports progress
+func (h *Handler) Status(c echo.Context) error {
+	job, err := h.job(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, status(job))
+}
+
+// ReviewRows lists low-confidence rows with their alternatives
+func (h *Handler) ReviewRows(c echo.Context) error {
+	job, err := h.job(c)
+	if err != nil {
+		return err
+	}
+	rows, err := h.store.Rows(c.Request().Context(), job.ID, RowReview)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"import": status(job), "rows": rows})
+}
+
+// Review applies decisions for review rows
+func (h *Handler) Review(c echo.Context) error {
+	job, err := h.job(c)
+	if err != nil {
+		return err
+	}
+	var req struct {
+		Decisions []Decision `json:"decisions"`
+	}
+	if err := c.Bind(&req); err != nil || len(req.Decisions) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "decisions are required")
+	}
+	job, err = h.store.Review(c.Request().Context(), job, req.Decisions)
+	if errors.Is(err, ErrState) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, status(job))
+}
+
+// Commit writes a ready import to the diary
+func (h *Handler) Commit(c echo.Context) error {
+	job, err := h.job(c)
+	if err != nil {
+		return err
+	}
+	n, err := h.runner.Commit(c.Request().Context(), job)
+	switch {
+	case errors.Is(err, ErrNoWriter):
+		return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+	case errors.Is(err, ErrState):
+		return echo.NewHTTPError(http.StatusConflict, "import must finish review before it can be committed")
+	case errors.Is(err, diarybatch.ErrUnknownFood):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case err != nil:
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"id": job.ID, "status": JobCommitted, "entries": n})
+}
+
+func (h *Handler) job(c echo.Context) (Job, error) {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return Job{}, echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	job, err := h.store.Get(c.Request().Context(), userID, c.Param("id"))
+	if errors.Is(err, ErrNotFound) {
+		return Job{}, echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return job, err
+}
+
+func status(j Job) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           j.ID,
+		"format":       j.Format,
+		"status":       j.Status,
+		"total":        j.Total,
+		"processed":    j.Processed,
+		"matched":      j.Matched,
+		"needs_review": j.NeedsReview,
+		"progress":     j.Progress(),
+		"errors":       j.Errors,
+		"created_at":   j.CreatedAt,
+		"updated_at":   j.UpdatedAt,
+	}
+}
@@ -0,0 +1,253 @@
+package diaryimport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/textmatch"
+)
+
+// Candidate is a local food proposed for a foreign row
+type Candidate struct {
+	FoodID string  `json:"food_id"`
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"`
+}
+
+// Matcher searches local foods by name; implemented by the food search service
+type Matcher interface {
+	Search(ctx context.Context, query string, limit int) ([]Candidate, error)
+}
+
+// Writer adds entries to the user's diary; implemented by the diary service
+type Writer interface {
+	WriteEntries(ctx context.Context, userID string, entries []Entry) error
+}
+
+// ErrNoWriter is returned by Commit until a diary writer is configured
+var ErrNoWriter = errors.New("diary import commit is not available")
+
+// maxRowErrors caps the parse errors kept on a job
+const maxRowErrors = 50
+
+// Runner processes queued imports in the background
+type Runner struct {
+	store     *Store
+	matcher   Matcher
+	writer    Writer
+	threshold float64
+	wake      chan struct{}
+}
+
+// NewRunner creates a runner; IMPORT_MATCH_THRESHOLD (0.8) is the confidence
+// at or above which a match is applied without review
+func NewRunner(store *Store, matcher Matcher, writer Writer) *Runner {
+	return &Runner{
+		store:     store,
+		matcher:   matcher,
+		writer:    writer,
+		threshold: envconfig.Float("IMPORT_MATCH_THRESHOLD", 0.8),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Notify wakes the runner after a job is queued
+func (r *Runner) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start processes jobs until ctx is cancelled, also polling so jobs queued on
+// other instances are picked up
+func (r *Runner) Start(ctx context.Context) {
+	if err := r.store.requeueStale(ctx, 30*time.Minute); err != nil {
+		log.Printf("⚠️ Failed to requeue stale imports: %v", err)
+	}
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+	for {
+		for {
+			job, data, ok, err := r.store.claim(ctx)
+			if err != nil {
+				log.Printf("⚠️ Failed to claim import: %v", err)
+			}
+			if !ok {
+				break
+			}
+			r.process(ctx, job, data)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) process(ctx context.Context, job Job, data []byte) {
+	started := time.Now()
+	job.Status = JobProcessing
+	if err := r.run(ctx, &job, bytes.NewReader(data)); err != nil {
+		job.Status = JobFailed
+		job.Errors = append(job.Errors, err.Error())
+		log.Printf("❌ Import %s failed: %v", job.ID, err)
+	} else if job.NeedsReview > 0 {
+		job.Status = JobReview
+	} else {
+		job.Status = JobReady
+	}
+	if err := r.store.finish(ctx, job); err != nil {
+		log.Printf("⚠️ Failed to save import %s: %v", job.ID, err)
+		return
+	}
+	log.Printf("📥 Import %s (%s): %d rows, %d matched, %d to review in %s",
+		job.ID, job.Format, job.Total, job.Matched, job.NeedsReview, time.Since(started).Round(time.Millisecond))
+}
+
+func (r *Runner) run(ctx context.Context, job *Job, data io.Reader) error {
+	p, err := newParser(data, job.Format)
+	if err != nil {
+		return err
+	}
+	job.Format = p.format
+
+	var rows []Row
+	for {
+		row, err := p.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if len(job.Errors) < maxRowErrors {
+				job.Errors = append(job.Errors, err.Error())
+			}
+			continue
+		}
+		rows = append(rows, row)
+	}
+	job.Total = len(rows)
+	if err := r.store.progress(ctx, *job); err != nil {
+		return err
+	}
+
+	// Trackers repeat the same foods daily; match each distinct name once
+	cache := map[string]ImportedRow{}
+	for i, row := range rows {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		name := searchName(row.Name)
+		matched, ok := cache[name]
+		if !ok {
+			matched, err = r.match(ctx, name)
+			if err != nil {
+				return fmt.Errorf("matching %q: %w", row.Name, err)
+			}
+			cache[name] = matched
+		}
+		matched.Row = row
+		if err := r.store.addRow(ctx, job.ID, matched); err != nil {
+			return err
+		}
+		job.Processed++
+		if matched.Status == RowMatched {
+			job.Matched++
+		} else {
+			job.NeedsReview++
+		}
+		if (i+1)%50 == 0 {
+			if err := r.store.progress(ctx, *job); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// match searches for name and scores candidates by name similarity
+func (r *Runner) match(ctx context.Context, name string) (ImportedRow, error) {
+	if r.matcher == nil {
+		return ImportedRow{Status: RowReview}, nil
+	}
+	found, err := r.matcher.Search(ctx, name, 5)
+	if err != nil {
+		return ImportedRow{}, err
+	}
+	norm := textmatch.Normalize(name)
+	scored := found[:0]
+	for _, c := range found {
+		// Search hits that share no words with the row aren't worth offering
+		if c.Score = textmatch.BestSimilarity(norm, textmatch.Normalize(c.Name)); c.Score > 0 {
+			scored = append(scored, c)
+		}
+	}
+	found = scored
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Score > found[j].Score })
+	if len(found) == 0 {
+		return ImportedRow{Status: RowReview}, nil
+	}
+	best := found[0]
+	row := ImportedRow{FoodID: best.FoodID, FoodName: best.Name, Confidence: best.Score, Status: RowMatched}
+	if best.Score < r.threshold {
+		row.Status = RowReview
+		row.Alternatives = found
+	}
+	return row, nil
+}
+
+// servingSuffix matches the ", 1 cup" style serving text trackers append to names
+var servingSuffix = regexp.MustCompile(`,\s*[\d.½¼¾/]+\s*[^,]*$`)
+
+// searchName strips serving text from a tracker's food name before searching
+func searchName(name string) string {
+	if stripped := servingSuffix.ReplaceAllString(name, ""); stripped != "" {
+		return stripped
+	}
+	return name
+}
+
+// Commit writes a ready job's rows to the diary; skipped rows are left out.
+// The entries and the job's committed status are written in one transaction,
+// so a failed or concurrent commit never imports the rows twice.
+func (r *Runner) Commit(ctx context.Context, job Job) (int, error) {
+	if r.writer == nil {
+		return 0, ErrNoWriter
+	}
+	if job.Status != JobReady {
+		return 0, ErrState
+	}
+	var n int
+	err := r.store.txm.Do(ctx, func(ctx context.Context) error {
+		if err := r.store.markCommitted(ctx, job); err != nil {
+			return err
+		}
+		rows, err := r.store.Rows(ctx, job.ID, "")
+		if err != nil {
+			return err
+		}
+		entries := make([]Entry, 0, len(rows))
+		for _, row := range rows {
+			if row.Status == RowSkipped || row.Status == RowReview {
+				continue
+			}
+			entries = append(entries, row.entry())
+		}
+		n = len(entries)
+		return r.writer.WriteEntries(ctx, job.UserID, entries)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,373 @@
+package diaryimport
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"nutrition-health-backend/internal/nutrition"
+	"nutrition-health-backend/internal/txn"
+)
+
+// Job statuses
+const (
+	JobQueued     = "queued"
+	JobProcessing = "processing"
+	JobReview     = "review"
+	JobReady      = "ready"
+	JobCommitted  = "committed"
+	JobFailed     = "failed"
+)
+
+// Row statuses
+const (
+	RowMatched  = "matched"
+	RowReview   = "review"
+	RowAccepted = "accepted"
+	RowCustom   = "custom"
+	RowSkipped  = "skipped"
+)
+
+var (
+	ErrNotFound = errors.New("import not found")
+	ErrState    = errors.New("import is not in a state that allows this")
+)
+
+// Job is an import run and its progress
+type Job struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"-"`
+	Format      string    `json:"format"`
+	Status      string    `json:"status"`
+	Total       int       `json:"total"`
+	Processed   int       `json:"processed"`
+	Matched     int       `json:"matched"`
+	NeedsReview int       `json:"needs_review"`
+	Errors      []string  `json:"errors,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Progress is Processed as a fraction of Total, 0..1
+func (j Job) Progress() float64 {
+	if j.Total == 0 {
+		if j.Status == JobQueued || j.Status == JobProcessing {
+			return 0
+		}
+		return 1
+	}
+	return float64(j.Processed) / float64(j.Total)
+}
+
+// ImportedRow is a parsed row with its match
+type ImportedRow struct {
+	ID         int64   `json:"id"`
+	Row        Row     `json:"row"`
+	FoodID     string  `json:"food_id,omitempty"`
+	FoodName   string  `json:"food_name,omitempty"`
+	Confidence float64 `json:"confidence"`
+	Status     string  `json:"status"`
+	// Alternatives are the other candidates offered for review
+	Alternatives []Candidate `json:"alternatives,omitempty"`
+}
+
+// Store persists import jobs and rows, in the transaction ctx carries if any
+type Store struct {
+	txm *txn.Manager
+}
+
+// NewStore creates an import store
+func NewStore(db *sql.DB) *Store {
+	return &Store{txm: txn.NewManager(db)}
+}
+
+// Migrate creates the import_jobs and import_rows tables
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS import_jobs (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			format TEXT NOT NULL,
+			status TEXT NOT NULL,
+			data BLOB,
+			total INTEGER NOT NULL DEFAULT 0,
+			processed INTEGER NOT NULL DEFAULT 0,
+			matched INTEGER NOT NULL DEFAULT 0,
+			needs_review INTEGER NOT NULL DEFAULT 0,
+			errors TEXT,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_import_jobs_status ON import_jobs(status, created_at)`,
+		`CREATE TABLE IF NOT EXISTS import_rows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			job_id TEXT NOT NULL,
+			data TEXT NOT NULL,
+			food_id TEXT,
+			food_name TEXT,
+			confidence REAL NOT NULL DEFAULT 0,
+			status TEXT NOT NULL,
+			alternatives TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_import_rows_job ON import_rows(job_id, status)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Create queues an import of data for userID
+func (s *Store) Create(ctx context.Context, userID, format string, data []byte) (Job, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return Job{}, err
+	}
+	now := time.Now().UTC()
+	job := Job{ID: hex.EncodeToString(b), UserID: userID, Format: format, Status: JobQueued, CreatedAt: now, UpdatedAt: now}
+	_, err := s.txm.Querier(ctx).ExecContext(ctx,
+		`INSERT INTO import_jobs (id, user_id, format, status, data, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, userID, format, JobQueued, data, now, now)
+	return job, err
+}
+
+// Get returns a job owned by userID
+func (s *Store) Get(ctx context.Context, userID, id string) (Job, error) {
+	var j Job
+	var errs sql.NullString
+	err := s.txm.Querier(ctx).QueryRowContext(ctx,
+		`SELECT id, user_id, format, status, total, processed, matched, needs_review, errors, created_at, updated_at
+		 FROM import_jobs WHERE id = ? AND user_id = ?`, id, userID).
+		Scan(&j.ID, &j.UserID, &j.Format, &j.Status, &j.Total, &j.Processed, &j.Matched, &j.NeedsReview, &errs, &j.CreatedAt, &j.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return Job{}, ErrNotFound
+	}
+	if err != nil {
+		return Job{}, err
+	}
+	if errs.Valid {
+		json.Unmarshal([]byte(errs.String), &j.Errors)
+	}
+	return j, nil
+}
+
+// claim moves one queued job to processing and returns it with its data, or ok=false
+func (s *Store) claim(ctx context.Context) (job Job, data []byte, ok bool, err error) {
+	var id string
+	err = s.txm.Querier(ctx).QueryRowContext(ctx, `SELECT id FROM import_jobs WHERE status = ? ORDER BY created_at LIMIT 1`, JobQueued).Scan(&id)
+	if err == sql.ErrNoRows {
+		return Job{}, nil, false, nil
+	}
+	if err != nil {
+		return Job{}, nil, false, err
+	}
+	res, err := s.txm.Querier(ctx).ExecContext(ctx, `UPDATE import_jobs SET status = ?, updated_at = ? WHERE id = ? AND status = ?`,
+		JobProcessing, time.Now().UTC(), id, JobQueued)
+	if err != nil {
+		return Job{}, nil, false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		// Another instance claimed it first
+		return Job{}, nil, false, nil
+	}
+	err = s.txm.Querier(ctx).QueryRowContext(ctx, `SELECT id, user_id, format, data FROM import_jobs WHERE id = ?`, id).
+		Scan(&job.ID, &job.UserID, &job.Format, &data)
+	return job, data, err == nil, err
+}
+
+// requeueStale returns jobs left processing by a crashed instance to the queue
+func (s *Store) requeueStale(ctx context.Context, olderThan time.Duration) error {
+	cutoff := time.Now().UTC().Add(-olderThan)
+	if _, err := s.txm.Querier(ctx).ExecContext(ctx,
+		`DELETE FROM import_rows WHERE job_id IN (SELECT id FROM import_jobs WHERE status = ? AND updated_at < ?)`,
+		JobProcessing, cutoff); err != nil {
+		return err
+	}
+	_, err := s.txm.Querier(ctx).ExecContext(ctx,
+		`UPDATE import_jobs SET status = ?, processed = 0, matched = 0, needs_review = 0 WHERE status = ? AND updated_at < ?`,
+		JobQueued, JobProcessing, cutoff)
+	return err
+}
+
+func (s *Store) progress(ctx context.Context, j Job) error {
+	var errs interface{}
+	if len(j.Errors) > 0 {
+		data, _ := json.Marshal(j.Errors)
+		errs = string(data)
+	}
+	_, err := s.txm.Querier(ctx).ExecContext(ctx,
+		`UPDATE import_jobs SET format = ?, status = ?, total = ?, processed = ?, matched = ?, needs_review = ?, errors = ?, updated_at = ? WHERE id = ?`,
+		j.Format, j.Status, j.Total, j.Processed, j.Matched, j.NeedsReview, errs, time.Now().UTC(), j.ID)
+	return err
+}
+
+// finish records the final state and drops the uploaded file
+func (s *Store) finish(ctx context.Context, j Job) error {
+	if err := s.progress(ctx, j); err != nil {
+		return err
+	}
+	_, err := s.txm.Querier(ctx).ExecContext(ctx, `UPDATE import_jobs SET data = NULL WHERE id = ?`, j.ID)
+	return err
+}
+
+func (s *Store) addRow(ctx context.Context, jobID string, r ImportedRow) error {
+	data, err := json.Marshal(r.Row)
+	if err != nil {
+		return err
+	}
+	var alts interface{}
+	if len(r.Alternatives) > 0 {
+		encoded, _ := json.Marshal(r.Alternatives)
+		alts = string(encoded)
+	}
+	_, err = s.txm.Querier(ctx).ExecContext(ctx,
+		`INSERT INTO import_rows (job_id, data, food_id, food_name, confidence, status, alternatives) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		jobID, string(data), nullable(r.FoodID), nullable(r.FoodName), r.Confidence, r.Status, alts)
+	return err
+}
+
+// Rows lists a job's rows, optionally by status
+func (s *Store) Rows(ctx context.Context, jobID, status string) ([]ImportedRow, error) {
+	query := `SELECT id, data, COALESCE(food_id, ''), COALESCE(food_name, ''), confidence, status, COALESCE(alternatives, '') FROM import_rows WHERE job_id = ?`
+	args := []interface{}{jobID}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	rows, err := s.txm.Querier(ctx).QueryContext(ctx, query+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []ImportedRow{}
+	for rows.Next() {
+		var r ImportedRow
+		var data, alts string
+		if err := rows.Scan(&r.ID, &data, &r.FoodID, &r.FoodName, &r.Confidence, &r.Status, &alts); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(data), &r.Row); err != nil {
+			return nil, err
+		}
+		if alts != "" {
+			json.Unmarshal([]byte(alts), &r.Alternatives)
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// Decision resolves a review row: accept FoodID, keep the foreign values as a custom entry, or skip
+type Decision struct {
+	RowID    int64  `json:"row_id"`
+	Action   string `json:"action"`
+	FoodID   string `json:"food_id,omitempty"`
+	FoodName string `json:"food_name,omitempty"`
+}
+
+// Review applies decisions to rows awaiting review and moves the job to ready when none remain
+func (s *Store) Review(ctx context.Context, job Job, decisions []Decision) (Job, error) {
+	if job.Status != JobReview {
+		return job, ErrState
+	}
+	err := s.txm.Do(ctx, func(ctx context.Context) error {
+		var err error
+		job, err = s.review(ctx, job, decisions)
+		return err
+	})
+	return job, err
+}
+
+// review runs Review inside its transaction, so a failed decision leaves every row as it was
+func (s *Store) review(ctx context.Context, job Job, decisions []Decision) (Job, error) {
+	for _, d := range decisions {
+		status := RowCustom
+		switch d.Action {
+		case "accept":
+			status = RowAccepted
+		case "custom":
+			d.FoodID, d.FoodName = "", ""
+		case "skip":
+			status = RowSkipped
+		default:
+			return job, errors.New("action must be accept, custom or skip")
+		}
+		query := `UPDATE import_rows SET status = ?`
+		args := []interface{}{status}
+		where := ` WHERE id = ? AND job_id = ? AND status = ?`
+		switch {
+		case d.Action == "skip":
+		case d.Action == "accept" && d.FoodID == "":
+			// Accepting without a food keeps the proposed match
+			where += ` AND food_id IS NOT NULL`
+		default:
+			query += `, food_id = ?, food_name = ?`
+			args = append(args, nullable(d.FoodID), nullable(d.FoodName))
+		}
+		args = append(args, d.RowID, job.ID, RowReview)
+		res, err := s.txm.Querier(ctx).ExecContext(ctx, query+where, args...)
+		if err != nil {
+			return job, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 && d.Action == "accept" && d.FoodID == "" {
+			return job, fmt.Errorf("row %d has no proposed match; give a food_id", d.RowID)
+		}
+	}
+	if err := s.txm.Querier(ctx).QueryRowContext(ctx, `SELECT COUNT(*) FROM import_rows WHERE job_id = ? AND status = ?`, job.ID, RowReview).
+		Scan(&job.NeedsReview); err != nil {
+		return job, err
+	}
+	if job.NeedsReview == 0 {
+		job.Status = JobReady
+	}
+	return job, s.progress(ctx, job)
+}
+
+// markCommitted moves a ready job to committed, failing with ErrState when
+// another commit got there first
+func (s *Store) markCommitted(ctx context.Context, job Job) error {
+	res, err := s.txm.Querier(ctx).ExecContext(ctx,
+		`UPDATE import_jobs SET status = ?, updated_at = ? WHERE id = ? AND status = ?`,
+		JobCommitted, time.Now().UTC(), job.ID, JobReady)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrState
+	}
+	return nil
+}
+
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// entry converts a resolved row into a diary entry
+func (r ImportedRow) entry() Entry {
+	e := Entry{Date: r.Row.Date, Meal: r.Row.Meal, Name: r.Row.Name, Amount: r.Row.Amount, Nutrients: r.Row.Nutrients}
+	if r.Status == RowMatched || r.Status == RowAccepted {
+		e.FoodID = r.FoodID
+	}
+	return e
+}
+
+// Entry is a diary entry to write; FoodID is empty for custom entries that
+// keep the foreign tracker's nutrient values
+type Entry struct {
+	Date      string              `json:"date"`
+	Meal      string              `json:"meal"`
+	FoodID    string              `json:"food_id,omitempty"`
+	Name      string              `json:"name"`
+	Amount    string              `json:"amount,omitempty"`
+	Nutrients nutrition.Nutrients `json:"nutrients"`
+}
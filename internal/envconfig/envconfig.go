@@ -0,0 +1,146 @@
+package envconfig
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	mu sync.Mutex
+	// errors holds one error per key, in the order keys were first seen;
+	// settings are read on every call, so the same key is recorded many times
+	errors []error
+	seen   = map[string]int{}
+)
+
+// record remembers an invalid value so startup can report every misconfiguration at once
+func record(key, value, want string) {
+	mu.Lock()
+	defer mu.Unlock()
+	err := fmt.Errorf("%s=%q is not a valid %s", key, value, want)
+	if i, ok := seen[key]; ok {
+		errors[i] = err
+		return
+	}
+	seen[key] = len(errors)
+	errors = append(errors, err)
+}
+
+// Errors returns the invalid settings seen so far, one per key
+func Errors() []error {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]error(nil), errors...)
+}
+
+// String returns the env value or the default when unset
+func String(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && strings.TrimSpace(v) != "" {
+		return strings.TrimSpace(v)
+	}
+	return def
+}
+
+// Int returns the env value parsed as an int
+func Int(key string, def int) int {
+	v := String(key, "")
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		record(key, v, "integer")
+		return def
+	}
+	return n
+}
+
+// Float returns the env value parsed as a float
+func Float(key string, def float64) float64 {
+	v := String(key, "")
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		record(key, v, "number")
+		return def
+	}
+	return f
+}
+
+// Bool returns the env value parsed as a bool
+func Bool(key string, def bool) bool {
+	v := String(key, "")
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		record(key, v, "boolean")
+		return def
+	}
+	return b
+}
+
+// Duration returns the env value parsed as a duration (e.g. "30s")
+func Duration(key string, def time.Duration) time.Duration {
+	v := String(key, "")
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		record(key, v, "duration")
+		return def
+	}
+	return d
+}
+
+// List returns the env value split on commas
+func List(key string, def []string) []string {
+	v := String(key, "")
+	if v == "" {
+		return def
+	}
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+var byteUnits = []struct {
+	suffix string
+	scale  int64
+}{
+	{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+	{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+}
+
+// Bytes returns the env value parsed as a size (e.g. "512KB", "10MB", or plain bytes)
+func Bytes(key string, def int64) int64 {
+	v := String(key, "")
+	if v == "" {
+		return def
+	}
+	num, scale := strings.ToUpper(v), int64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(num, u.suffix) {
+			num, scale = strings.TrimSpace(strings.TrimSuffix(num, u.suffix)), u.scale
+			break
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		record(key, v, "size")
+		return def
+	}
+	return n * scale
+}
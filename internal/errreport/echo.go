@@ -0,0 +1,74 @@
+package errreport
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	// CorrelationHeader is the header set by middleware.CorrelationID
+	CorrelationHeader = "X-Correlation-ID"
+	reportedKey       = "errreport.reported"
+)
+
+func eventFor(c echo.Context, err error, stack []byte) Event {
+	req := c.Request()
+	correlationID := c.Response().Header().Get(CorrelationHeader)
+	if correlationID == "" {
+		correlationID = req.Header.Get(CorrelationHeader)
+	}
+	// Query strings can carry credentials such as calendar feed tokens
+	u := *req.URL
+	u.RawQuery, u.Fragment = "", ""
+	return Event{
+		Err:           err,
+		Stack:         stack,
+		CorrelationID: correlationID,
+		UserID:        reqctx.UserID(c),
+		Method:        req.Method,
+		URL:           u.String(),
+		Route:         c.Path(),
+	}
+}
+
+// Recover is echo's Recover middleware with panics reported to r
+func Recover(r Reporter) echo.MiddlewareFunc {
+	return echomiddleware.RecoverWithConfig(echomiddleware.RecoverConfig{
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			log.Printf("❌ [PANIC RECOVER] %v %s", err, stack)
+			event := eventFor(c, err, stack)
+			event.Level = "fatal"
+			r.Capture(event)
+			c.Set(reportedKey, true)
+			return err
+		},
+	})
+}
+
+// ErrorHandler wraps the central HTTP error handler so server errors are reported.
+// Client errors (4xx) are not sent.
+func ErrorHandler(r Reporter, next echo.HTTPErrorHandler) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		code := http.StatusInternalServerError
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			code = he.Code
+		}
+
+		if reported, _ := c.Get(reportedKey).(bool); code >= http.StatusInternalServerError && !reported {
+			reportErr := err
+			if he != nil && he.Internal != nil {
+				reportErr = fmt.Errorf("%v: %w", he.Message, he.Internal)
+			}
+			r.Capture(eventFor(c, reportErr, nil))
+		}
+		next(err, c)
+	}
+}
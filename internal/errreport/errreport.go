@@ -0,0 +1,246 @@
+package errreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Config controls error reporting
+type Config struct {
+	// DSN is a Sentry-compatible DSN: https://<key>@<host>/<project>
+	DSN         string
+	Environment string
+	Release     string
+	// SampleRate is the fraction of errors sent, 0..1
+	SampleRate float64
+}
+
+// LoadConfig reads SENTRY_* settings; environment and release come from the app config
+func LoadConfig(environment, release string) Config {
+	return Config{
+		DSN:         envconfig.String("SENTRY_DSN", ""),
+		Environment: envconfig.String("SENTRY_ENVIRONMENT", environment),
+		Release:     envconfig.String("SENTRY_RELEASE", release),
+		SampleRate:  envconfig.Float("SENTRY_SAMPLE_RATE", 1.0),
+	}
+}
+
+// Event is an error with the request context it happened in
+type Event struct {
+	Err           error
+	Stack         []byte
+	Level         string
+	CorrelationID string
+	UserID        string
+	Method        string
+	URL           string
+	Route         string
+	Tags          map[string]string
+}
+
+// Reporter sends errors to an error-tracking service
+type Reporter interface {
+	Capture(e Event)
+	Flush(timeout time.Duration)
+}
+
+// New returns a Sentry reporter, or a no-op reporter when no DSN is configured
+func New(cfg Config) (Reporter, error) {
+	if cfg.DSN == "" {
+		return nopReporter{}, nil
+	}
+	return newSentry(cfg)
+}
+
+type nopReporter struct{}
+
+func (nopReporter) Capture(Event)       {}
+func (nopReporter) Flush(time.Duration) {}
+
+// sentryReporter posts events to the Sentry store API from a background worker
+type sentryReporter struct {
+	cfg      Config
+	endpoint string
+	auth     string
+	client   *http.Client
+	queue    chan []byte
+	wg       sync.WaitGroup
+
+	// mu guards closed so Capture never sends on the queue after Flush closes it
+	mu     sync.RWMutex
+	closed bool
+}
+
+const queueSize = 100
+
+// Validate checks the DSN and sample rate
+func (c Config) Validate() error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("SENTRY_SAMPLE_RATE must be between 0 and 1")
+	}
+	if c.DSN == "" {
+		return nil
+	}
+	u, err := url.Parse(c.DSN)
+	if err != nil || u.User == nil || u.Host == "" {
+		return fmt.Errorf("invalid SENTRY_DSN")
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		return fmt.Errorf("SENTRY_DSN has no project ID")
+	}
+	return nil
+}
+
+func newSentry(cfg Config) (*sentryReporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(cfg.DSN)
+	project := strings.Trim(u.Path, "/")
+
+	r := &sentryReporter{
+		cfg:      cfg,
+		endpoint: fmt.Sprintf("%s://%s/api/%s/store/", u.Scheme, u.Host, project),
+		auth: fmt.Sprintf("Sentry sentry_version=7, sentry_client=nutrition-health-backend/%s, sentry_key=%s",
+			cfg.Release, u.User.Username()),
+		client: &http.Client{Timeout: 5 * time.Second},
+		queue:  make(chan []byte, queueSize),
+	}
+	r.wg.Add(1)
+	go r.run()
+	return r, nil
+}
+
+// Capture queues an event; it never blocks the request, dropping events when the queue is full
+func (r *sentryReporter) Capture(e Event) {
+	if e.Err == nil || mathrand.Float64() >= r.cfg.SampleRate {
+		return
+	}
+	payload, err := json.Marshal(r.payload(e))
+	if err != nil {
+		return
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.queue <- payload:
+	default:
+		log.Println("⚠️ Error report dropped: queue full")
+	}
+}
+
+// Flush sends queued events, waiting at most timeout. Events captured
+// afterwards are dropped.
+func (r *sentryReporter) Flush(timeout time.Duration) {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Println("⚠️ Error report flush timed out")
+	}
+}
+
+func (r *sentryReporter) run() {
+	defer r.wg.Done()
+	for payload := range r.queue {
+		if err := r.send(payload); err != nil {
+			log.Printf("⚠️ Error report failed: %v", err)
+		}
+	}
+}
+
+func (r *sentryReporter) send(payload []byte) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, r.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sentry returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (r *sentryReporter) payload(e Event) map[string]interface{} {
+	level := e.Level
+	if level == "" {
+		level = "error"
+	}
+
+	tags := map[string]string{}
+	for k, v := range e.Tags {
+		tags[k] = v
+	}
+	if e.CorrelationID != "" {
+		tags["correlation_id"] = e.CorrelationID
+	}
+	if e.Route != "" {
+		tags["route"] = e.Route
+	}
+
+	event := map[string]interface{}{
+		"event_id":    eventID(),
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"level":       level,
+		"platform":    "go",
+		"logger":      "nutrition-health-backend",
+		"release":     r.cfg.Release,
+		"environment": r.cfg.Environment,
+		"tags":        tags,
+		"exception": map[string]interface{}{
+			"values": []map[string]interface{}{{
+				"type":  fmt.Sprintf("%T", e.Err),
+				"value": e.Err.Error(),
+			}},
+		},
+	}
+	if e.URL != "" {
+		event["request"] = map[string]string{"url": e.URL, "method": e.Method}
+	}
+	if e.UserID != "" {
+		event["user"] = map[string]string{"id": e.UserID}
+	}
+	if len(e.Stack) > 0 {
+		event["extra"] = map[string]string{"stack": string(e.Stack)}
+	}
+	return event
+}
+
+func eventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
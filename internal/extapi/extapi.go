@@ -0,0 +1,199 @@
+package extapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// ErrOpen is returned without calling the API while its circuit is open
+var ErrOpen = errors.New("circuit open")
+
+// State is a circuit breaker state
+type State string
+
+const (
+	Closed   State = "closed"
+	Open     State = "open"
+	HalfOpen State = "half_open"
+)
+
+// Config lists the external APIs guarded by circuit breakers
+type Config struct {
+	// Hosts are matched against request hosts, subdomains included
+	Hosts []string
+	// Failures is how many consecutive failures open a circuit
+	Failures int
+	// Cooldown is how long a circuit stays open before a trial request
+	Cooldown time.Duration
+}
+
+// LoadConfig reads EXTERNAL_API_HOSTS and the EXTERNAL_API_BREAKER_* settings
+func LoadConfig() Config {
+	return Config{
+		Hosts:    envconfig.List("EXTERNAL_API_HOSTS", []string{"api.nal.usda.gov", "world.openfoodfacts.org"}),
+		Failures: envconfig.Int("EXTERNAL_API_BREAKER_FAILURES", 5),
+		Cooldown: envconfig.Duration("EXTERNAL_API_BREAKER_COOLDOWN", 30*time.Second),
+	}
+}
+
+// Validate checks the breaker thresholds
+func (c Config) Validate() error {
+	if c.Failures < 1 {
+		return fmt.Errorf("EXTERNAL_API_BREAKER_FAILURES must be at least 1")
+	}
+	if c.Cooldown <= 0 {
+		return fmt.Errorf("EXTERNAL_API_BREAKER_COOLDOWN must be positive")
+	}
+	return nil
+}
+
+// Status is the breaker state of one external API
+type Status struct {
+	Host     string     `json:"host"`
+	State    State      `json:"state"`
+	Failures int        `json:"consecutive_failures"`
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+}
+
+type breaker struct {
+	state    State
+	failures int
+	openedAt time.Time
+}
+
+// Transport is an http.RoundTripper that fails fast while an external API is
+// down. Requests to other hosts pass straight through.
+type Transport struct {
+	base http.RoundTripper
+	cfg  Config
+	now  func() time.Time
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+	observe  func(ctx context.Context, host string, failed bool)
+}
+
+// NewTransport wraps base; install it as http.DefaultTransport to cover
+// clients that don't set their own transport
+func NewTransport(base http.RoundTripper, cfg Config) *Transport {
+	t := &Transport{base: base, cfg: cfg, now: time.Now, breakers: map[string]*breaker{}}
+	for _, h := range cfg.Hosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			t.breakers[h] = &breaker{state: Closed}
+		}
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := t.match(req.URL.Hostname())
+	if host == "" {
+		return t.base.RoundTrip(req)
+	}
+	if !t.allow(host) {
+		t.notify(req.Context(), host, true)
+		return nil, fmt.Errorf("%s: %w", host, ErrOpen)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if errors.Is(err, context.Canceled) {
+		// The caller gave up; that says nothing about the API
+		t.release(host)
+		return resp, err
+	}
+	failed := err != nil || resp.StatusCode >= 500
+	t.record(host, failed)
+	t.notify(req.Context(), host, failed)
+	return resp, err
+}
+
+// Observe registers fn to be called after every call to a guarded API,
+// including calls rejected by an open circuit. Call it before the
+// transport is in use.
+func (t *Transport) Observe(fn func(ctx context.Context, host string, failed bool)) {
+	t.observe = fn
+}
+
+func (t *Transport) notify(ctx context.Context, host string, failed bool) {
+	if t.observe != nil {
+		t.observe(ctx, host, failed)
+	}
+}
+
+func (t *Transport) match(hostname string) string {
+	hostname = strings.ToLower(hostname)
+	for h := range t.breakers {
+		if hostname == h || strings.HasSuffix(hostname, "."+h) {
+			return h
+		}
+	}
+	return ""
+}
+
+// allow lets requests through a closed circuit and a single trial through
+// one whose cooldown has passed
+func (t *Transport) allow(host string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b := t.breakers[host]
+	switch b.state {
+	case Open:
+		if t.now().Sub(b.openedAt) < t.cfg.Cooldown {
+			return false
+		}
+		b.state = HalfOpen
+		return true
+	case HalfOpen:
+		// The trial request is still in flight
+		return false
+	}
+	return true
+}
+
+func (t *Transport) record(host string, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b := t.breakers[host]
+	if !failed {
+		b.state, b.failures = Closed, 0
+		return
+	}
+	b.failures++
+	if b.state == HalfOpen || b.failures >= t.cfg.Failures {
+		b.state, b.openedAt = Open, t.now()
+	}
+}
+
+// release reopens a circuit whose trial was abandoned, so the next request is the trial
+func (t *Transport) release(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if b := t.breakers[host]; b.state == HalfOpen {
+		b.state = Open
+	}
+}
+
+// Status reports every guarded API, sorted by host
+func (t *Transport) Status() []Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Status, 0, len(t.breakers))
+	for host, b := range t.breakers {
+		s := Status{Host: host, State: b.state, Failures: b.failures}
+		if b.state != Closed {
+			opened := b.openedAt
+			s.OpenedAt = &opened
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Host < out[j].Host })
+	return out
+}
@@ -0,0 +1,221 @@
+package fakedata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ErrNoFoods is returned when there are no foods to log; seed the food datasets first
+var ErrNoFoods = errors.New("no foods with calories to log")
+
+// Options sizes a run. The same Seed and End always produce the same data.
+type Options struct {
+	Users int
+	Days  int
+	Seed  int64
+	// End is the last day of history, in UTC
+	End time.Time
+}
+
+// Validate checks the run size
+func (o Options) Validate() error {
+	if o.Users < 1 || o.Users > 100000 {
+		return fmt.Errorf("--users must be between 1 and 100000")
+	}
+	if o.Days < 1 || o.Days > 3650 {
+		return fmt.Errorf("--days must be between 1 and 3650")
+	}
+	return nil
+}
+
+// Food is a food the generator may log
+type Food struct {
+	ID       int64
+	Name     string
+	Calories float64
+}
+
+// User is a generated account; it has no usable password
+type User struct {
+	ID        string
+	Email     string
+	Name      string
+	CreatedAt time.Time
+}
+
+// Entry is one logged food
+type Entry struct {
+	FoodID  int64
+	Meal    string
+	Grams   float64
+	EatenAt time.Time
+}
+
+// Weight is one weigh-in
+type Weight struct {
+	Kg       float64
+	LoggedAt time.Time
+}
+
+// PlanItem is one planned food on a local date
+type PlanItem struct {
+	Date   string
+	Meal   string
+	FoodID int64
+	Grams  float64
+}
+
+// UserData is everything generated for one user
+type UserData struct {
+	User    User
+	Entries []Entry
+	Weights []Weight
+	// Plans holds one weekly meal plan per week of history
+	Plans [][]PlanItem
+}
+
+// Writer stores generated data, typically the SQL writer
+type Writer interface {
+	Foods(ctx context.Context) ([]Food, error)
+	// WriteUser stores one user's data atomically and reports false when
+	// the user already exists, so reruns with the same seed add nothing
+	WriteUser(ctx context.Context, d UserData) (bool, error)
+}
+
+// Summary counts what a run wrote
+type Summary struct {
+	Users   int
+	Skipped int
+	Entries int
+	Weights int
+	Plans   int
+}
+
+// meal is a slot with its usual UTC hour and share of the day's calories
+type meal struct {
+	name  string
+	hour  int
+	share float64
+}
+
+var meals = []meal{
+	{"breakfast", 7, 0.25},
+	{"lunch", 12, 0.35},
+	{"dinner", 19, 0.3},
+	{"snack", 16, 0.1},
+}
+
+// Generate writes opts.Users users with opts.Days days of history each
+func Generate(ctx context.Context, w Writer, opts Options) (Summary, error) {
+	var sum Summary
+	if err := opts.Validate(); err != nil {
+		return sum, err
+	}
+	foods, err := w.Foods(ctx)
+	if err != nil {
+		return sum, err
+	}
+	if len(foods) == 0 {
+		return sum, ErrNoFoods
+	}
+	end := opts.End.UTC().Truncate(24 * time.Hour)
+	for i := 0; i < opts.Users; i++ {
+		if err := ctx.Err(); err != nil {
+			return sum, err
+		}
+		d := generateUser(opts.Seed, i, opts.Days, end, foods)
+		ok, err := w.WriteUser(ctx, d)
+		if err != nil {
+			return sum, fmt.Errorf("user %s: %w", d.User.ID, err)
+		}
+		if !ok {
+			sum.Skipped++
+			continue
+		}
+		sum.Users++
+		sum.Entries += len(d.Entries)
+		sum.Weights += len(d.Weights)
+		sum.Plans += len(d.Plans)
+	}
+	return sum, nil
+}
+
+// generateUser builds user i from its own random source, so a user's data
+// doesn't depend on how many users the run has
+func generateUser(seed int64, i, days int, end time.Time, foods []Food) UserData {
+	rng := rand.New(rand.NewSource(seed*1000003 + int64(i)))
+	start := end.AddDate(0, 0, -days+1)
+	d := UserData{User: User{
+		ID:        fmt.Sprintf("fake-%d-%d", seed, i),
+		Email:     fmt.Sprintf("fake-%d-%d@example.test", seed, i),
+		Name:      fmt.Sprintf("Load Test %d", i+1),
+		CreatedAt: start.Add(-time.Duration(rng.Intn(72)) * time.Hour),
+	}}
+
+	// Each user has a steady intake, a logging habit and a slow weight trend
+	target := 1600 + float64(rng.Intn(1200))
+	diligence := 0.6 + rng.Float64()*0.35
+	kg := 55 + rng.Float64()*50
+	trend := (rng.Float64() - 0.65) * 0.06
+
+	for day := 0; day < days; day++ {
+		date := start.AddDate(0, 0, day)
+		if rng.Float64() < diligence {
+			for _, m := range meals {
+				if m.name == "snack" && rng.Float64() < 0.5 {
+					continue
+				}
+				d.Entries = append(d.Entries, mealEntries(rng, foods, m, date, target)...)
+			}
+		}
+		kg += trend + rng.NormFloat64()*0.15
+		if date.Weekday() == time.Monday || rng.Float64() < 0.1 {
+			at := date.Add(time.Duration(6*60+rng.Intn(120)) * time.Minute)
+			d.Weights = append(d.Weights, Weight{Kg: math.Round(kg*10) / 10, LoggedAt: at})
+		}
+		if date.Weekday() == time.Monday && day+7 <= days {
+			d.Plans = append(d.Plans, weekPlan(rng, foods, date, target))
+		}
+	}
+	return d
+}
+
+// mealEntries logs one to three foods adding up to roughly the meal's share of target
+func mealEntries(rng *rand.Rand, foods []Food, m meal, date time.Time, target float64) []Entry {
+	n := 1 + rng.Intn(3)
+	kcal := target * m.share * (0.8 + rng.Float64()*0.4)
+	at := date.Add(time.Duration(m.hour*60+rng.Intn(90)) * time.Minute)
+	entries := make([]Entry, 0, n)
+	for j := 0; j < n; j++ {
+		f := foods[rng.Intn(len(foods))]
+		entries = append(entries, Entry{
+			FoodID:  f.ID,
+			Meal:    m.name,
+			Grams:   portion(kcal/float64(n), f.Calories),
+			EatenAt: at.Add(time.Duration(j) * time.Minute),
+		})
+	}
+	return entries
+}
+
+func weekPlan(rng *rand.Rand, foods []Food, monday time.Time, target float64) []PlanItem {
+	var items []PlanItem
+	for day := 0; day < 7; day++ {
+		date := monday.AddDate(0, 0, day).Format("2006-01-02")
+		for _, m := range meals[:3] {
+			f := foods[rng.Intn(len(foods))]
+			items = append(items, PlanItem{Date: date, Meal: m.name, FoodID: f.ID, Grams: portion(target*m.share, f.Calories)})
+		}
+	}
+	return items
+}
+
+// portion returns the grams of a food per 100 g giving kcal, within a plausible serving
+func portion(kcal, per100 float64) float64 {
+	g := kcal / per100 * 100
+	return math.Round(math.Max(10, math.Min(g, 600)))
+}
@@ -0,0 +1,120 @@
+package fakedata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"nutrition-health-backend/internal/repo"
+	"nutrition-health-backend/internal/tenant"
+	"nutrition-health-backend/internal/txn"
+)
+
+// noPassword can't match any bcrypt hash, so generated users can't sign in
+const noPassword = "!"
+
+// SQLWriter writes generated data through the repo queries
+type SQLWriter struct {
+	db  *sql.DB
+	txm *txn.Manager
+}
+
+// NewSQLWriter creates a writer on db
+func NewSQLWriter(db *sql.DB) *SQLWriter {
+	return &SQLWriter{db: db, txm: txn.NewManager(db)}
+}
+
+// Foods implements Writer with up to 1000 foods that have calories
+func (w *SQLWriter) Foods(ctx context.Context) ([]Food, error) {
+	rows, err := repo.New(w.db).SearchFoods(ctx, repo.SearchFoodsParams{Name: "%", Limit: 1000})
+	if err != nil {
+		return nil, err
+	}
+	var foods []Food
+	for _, f := range rows {
+		if f.Calories > 0 {
+			foods = append(foods, Food{ID: f.ID, Name: f.Name, Calories: f.Calories})
+		}
+	}
+	return foods, nil
+}
+
+// WriteUser implements Writer, in the tenant bound to ctx
+func (w *SQLWriter) WriteUser(ctx context.Context, d UserData) (bool, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !tenantID.Valid {
+		return false, tenant.ErrNoTenant
+	}
+	// User IDs are unique across tenants, so look in all of them
+	if _, err := repo.New(w.db).GetUser(ctx, repo.GetUserParams{ID: d.User.ID}); err == nil {
+		return false, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	return true, w.txm.Do(ctx, func(ctx context.Context) error {
+		q := repo.FromContext(ctx, w.db)
+		if _, err := q.CreateUser(ctx, repo.CreateUserParams{
+			ID:           d.User.ID,
+			Email:        d.User.Email,
+			Name:         d.User.Name,
+			PasswordHash: noPassword,
+			CreatedAt:    d.User.CreatedAt,
+			UpdatedAt:    d.User.CreatedAt,
+			TenantID:     tenantID.String,
+		}); err != nil {
+			return err
+		}
+		for _, e := range d.Entries {
+			if _, err := q.CreateDiaryEntry(ctx, repo.CreateDiaryEntryParams{
+				UserID:    d.User.ID,
+				FoodID:    e.FoodID,
+				MealType:  e.Meal,
+				QuantityG: e.Grams,
+				LoggedAt:  e.EatenAt,
+				TenantID:  tenantID,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, wt := range d.Weights {
+			if _, err := q.CreateWeightLog(ctx, repo.CreateWeightLogParams{UserID: d.User.ID, WeightKg: wt.Kg, LoggedAt: wt.LoggedAt, TenantID: tenantID}); err != nil {
+				return err
+			}
+		}
+		for _, plan := range d.Plans {
+			if len(plan) == 0 {
+				continue
+			}
+			week, err := time.Parse("2006-01-02", plan[0].Date)
+			if err != nil {
+				return err
+			}
+			p, err := q.CreateMealPlan(ctx, repo.CreateMealPlanParams{
+				UserID:    d.User.ID,
+				Name:      "Week of " + plan[0].Date,
+				CreatedAt: week,
+				TenantID:  tenantID,
+			})
+			if err != nil {
+				return err
+			}
+			for _, it := range plan {
+				if err := q.AddMealPlanItem(ctx, repo.AddMealPlanItemParams{
+					MealPlanID: p.ID,
+					FoodID:     it.FoodID,
+					Quantity:   it.Grams,
+					MealType:   it.Meal,
+					Date:       it.Date,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
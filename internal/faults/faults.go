@@ -0,0 +1,246 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// ErrInjected is returned by dependencies failed on purpose
+var ErrInjected = errors.New("injected fault")
+
+// Scopes for FAULT_SCOPE
+const (
+	ScopeHeader = "header"
+	ScopeUsers  = "users"
+	ScopeAll    = "all"
+)
+
+// Spec is what to inject into an in-scope request; rates are 0..1
+type Spec struct {
+	Latency   time.Duration `json:"latency"`
+	Jitter    time.Duration `json:"jitter"`
+	ErrorRate float64       `json:"error_rate"`
+	Status    int           `json:"status"`
+	DBRate    float64       `json:"db_rate"`
+	RedisRate float64       `json:"redis_rate"`
+}
+
+// Validate rejects rates outside 0..1 and non-error statuses
+func (s Spec) Validate() error {
+	for name, r := range map[string]float64{"error": s.ErrorRate, "db": s.DBRate, "redis": s.RedisRate} {
+		if r < 0 || r > 1 {
+			return fmt.Errorf("%s rate must be between 0 and 1", name)
+		}
+	}
+	if s.Latency < 0 || s.Jitter < 0 {
+		return fmt.Errorf("latency and jitter must not be negative")
+	}
+	if s.Status < 400 || s.Status > 599 {
+		return fmt.Errorf("status must be a 4xx or 5xx code")
+	}
+	return nil
+}
+
+// ParseSpec overlays "latency=300ms,jitter=100ms,error=0.2,status=502,db=1,redis=0.5" on base
+func ParseSpec(s string, base Spec) (Spec, error) {
+	spec := base
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return spec, fmt.Errorf("fault %q must look like name=value", part)
+		}
+		var err error
+		switch strings.TrimSpace(k) {
+		case "latency":
+			spec.Latency, err = time.ParseDuration(v)
+		case "jitter":
+			spec.Jitter, err = time.ParseDuration(v)
+		case "error":
+			spec.ErrorRate, err = strconv.ParseFloat(v, 64)
+		case "status":
+			spec.Status, err = strconv.Atoi(v)
+		case "db":
+			spec.DBRate, err = strconv.ParseFloat(v, 64)
+		case "redis":
+			spec.RedisRate, err = strconv.ParseFloat(v, 64)
+		default:
+			return spec, fmt.Errorf("unknown fault %q", k)
+		}
+		if err != nil {
+			return spec, fmt.Errorf("fault %s: %w", k, err)
+		}
+	}
+	return spec, spec.Validate()
+}
+
+// Config gates fault injection and picks the requests it applies to
+type Config struct {
+	Enabled bool
+	Scope   string
+	Header  string
+	Users   []string
+	// MaxLatency caps latency plus jitter, whatever a header or operator asks for
+	MaxLatency time.Duration
+	Spec       Spec
+}
+
+// LoadConfig reads FAULTS_ENABLED, FAULT_SCOPE, FAULT_HEADER, FAULT_USERS,
+// FAULT_MAX_LATENCY and the default spec from FAULT_LATENCY,
+// FAULT_LATENCY_JITTER, FAULT_ERROR_RATE, FAULT_ERROR_STATUS, FAULT_DB_RATE
+// and FAULT_REDIS_RATE
+func LoadConfig() Config {
+	return Config{
+		Enabled:    envconfig.Bool("FAULTS_ENABLED", false),
+		Scope:      envconfig.String("FAULT_SCOPE", ScopeHeader),
+		Header:     envconfig.String("FAULT_HEADER", "X-Fault-Inject"),
+		Users:      envconfig.List("FAULT_USERS", nil),
+		MaxLatency: envconfig.Duration("FAULT_MAX_LATENCY", 10*time.Second),
+		Spec: Spec{
+			Latency:   envconfig.Duration("FAULT_LATENCY", 0),
+			Jitter:    envconfig.Duration("FAULT_LATENCY_JITTER", 0),
+			ErrorRate: envconfig.Float("FAULT_ERROR_RATE", 0),
+			Status:    envconfig.Int("FAULT_ERROR_STATUS", 503),
+			DBRate:    envconfig.Float("FAULT_DB_RATE", 0),
+			RedisRate: envconfig.Float("FAULT_REDIS_RATE", 0),
+		},
+	}
+}
+
+// Validate refuses fault injection in production and checks the settings
+func (c Config) Validate(environment string) error {
+	if !c.Enabled {
+		return nil
+	}
+	if environment == "production" {
+		return fmt.Errorf("FAULTS_ENABLED must not be set in production")
+	}
+	switch c.Scope {
+	case ScopeHeader, ScopeAll:
+	case ScopeUsers:
+		if len(c.Users) == 0 {
+			return fmt.Errorf("FAULT_SCOPE=users needs FAULT_USERS")
+		}
+	default:
+		return fmt.Errorf("FAULT_SCOPE must be header, users or all")
+	}
+	if c.MaxLatency <= 0 {
+		return fmt.Errorf("FAULT_MAX_LATENCY must be positive")
+	}
+	if c.Spec.Latency+c.Spec.Jitter > c.MaxLatency {
+		return fmt.Errorf("FAULT_LATENCY plus FAULT_LATENCY_JITTER must not exceed FAULT_MAX_LATENCY (%s)", c.MaxLatency)
+	}
+	return c.Spec.Validate()
+}
+
+// Injector holds the active spec, which operators may change at runtime
+type Injector struct {
+	cfg Config
+
+	mu   sync.RWMutex
+	spec Spec
+	rng  *rand.Rand
+}
+
+// New returns an injector, or nil when disabled or in production; a nil
+// injector's middleware passes every request through
+func New(cfg Config, environment string) *Injector {
+	if !cfg.Enabled || environment == "production" {
+		return nil
+	}
+	return &Injector{cfg: cfg, spec: cfg.Spec, rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Spec returns the active default spec
+func (i *Injector) Spec() Spec {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.spec
+}
+
+// SetSpec replaces the default spec
+func (i *Injector) SetSpec(s Spec) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if s.Latency+s.Jitter > i.cfg.MaxLatency {
+		return fmt.Errorf("latency plus jitter must not exceed %s", i.cfg.MaxLatency)
+	}
+	i.mu.Lock()
+	i.spec = s
+	i.mu.Unlock()
+	return nil
+}
+
+func (i *Injector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Float64() < rate
+}
+
+// clamp keeps latency plus jitter within MaxLatency, shrinking jitter first
+func (i *Injector) clamp(s Spec) Spec {
+	max := i.cfg.MaxLatency
+	if s.Latency > max {
+		s.Latency = max
+	}
+	if s.Latency+s.Jitter > max {
+		s.Jitter = max - s.Latency
+	}
+	return s
+}
+
+func (i *Injector) jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return time.Duration(i.rng.Int63n(int64(max)))
+}
+
+// Kind is a dependency that can be failed
+type Kind string
+
+const (
+	DB    Kind = "db"
+	Redis Kind = "redis"
+)
+
+type planKey struct{}
+
+// plan is the spec chosen for one request
+type plan struct {
+	injector *Injector
+	spec     Spec
+}
+
+// Inject returns ErrInjected when the request behind ctx is chosen to see kind
+// fail; it is nil outside an in-scope request
+func Inject(ctx context.Context, kind Kind) error {
+	p, ok := ctx.Value(planKey{}).(*plan)
+	if !ok {
+		return nil
+	}
+	rate := p.spec.DBRate
+	if kind == Redis {
+		rate = p.spec.RedisRate
+	}
+	if p.injector.roll(rate) {
+		return fmt.Errorf("%s: %w", kind, ErrInjected)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package faults
+
+import (
+	"log"
+	"net/http"
+
+	"nutrition-health-backend/internal/runtimecfg"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterRoutes mounts GET/PUT /faults on the admin group for changing the
+// default spec at runtime; nothing is mounted when injection is disabled
+func (i *Injector) RegisterRoutes(g *echo.Group) {
+	if i == nil {
+		return
+	}
+	g.GET("/faults", i.Get)
+	g.PUT("/faults", i.Put)
+}
+
+// Get returns the scope and active spec
+func (i *Injector) Get(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{"scope": i.cfg.Scope, "header": i.cfg.Header, "max_latency": i.cfg.MaxLatency, "spec": i.Spec()})
+}
+
+// Put replaces the spec from {"spec": "latency=300ms,error=0.2"}; an empty spec stops injecting
+func (i *Injector) Put(c echo.Context) error {
+	var req struct {
+		Spec string `json:"spec"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	spec, err := ParseSpec(req.Spec, Spec{Status: http.StatusServiceUnavailable})
+	if err == nil {
+		err = i.SetSpec(spec)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	log.Printf("AUDIT fault spec set by %s: %q", actor(c), req.Spec)
+	return c.JSON(http.StatusOK, map[string]interface{}{"spec": spec})
+}
+
+func actor(c echo.Context) string {
+	if a := c.Request().Header.Get(runtimecfg.ActorHeader); a != "" {
+		return a
+	}
+	return "admin@" + c.RealIP()
+}
@@ -0,0 +1,124 @@
+package faults
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/problem"
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/echo/v4"
+)
+
+// InjectedHeader lists the faults applied to a response
+const InjectedHeader = "X-Fault-Injected"
+
+// Middleware injects latency and errors into in-scope requests and marks them
+// for dependency faults. Users are known only after authentication, so for
+// FAULT_SCOPE=users mount it again on the authenticated group; a request is
+// planned once.
+func (i *Injector) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if i == nil {
+			return next
+		}
+		return func(c echo.Context) error {
+			req := c.Request()
+			if _, planned := req.Context().Value(planKey{}).(*plan); planned {
+				return next(c)
+			}
+			spec, ok, err := i.plan(c)
+			if err != nil {
+				return problem.Write(c, problem.New(http.StatusBadRequest, err.Error()))
+			}
+			if !ok {
+				return next(c)
+			}
+			c.SetRequest(req.WithContext(context.WithValue(req.Context(), planKey{}, &plan{injector: i, spec: spec})))
+
+			var applied []string
+			if delay := spec.Latency + i.jitter(spec.Jitter); delay > 0 {
+				applied = append(applied, "latency="+delay.String())
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-req.Context().Done():
+					timer.Stop()
+					return req.Context().Err()
+				}
+			}
+			if spec.DBRate > 0 || spec.RedisRate > 0 {
+				applied = append(applied, "dependencies")
+			}
+			if i.roll(spec.ErrorRate) {
+				applied = append(applied, "error")
+				c.Response().Header().Set(InjectedHeader, strings.Join(applied, ","))
+				log.Printf("🧪 Injected %d for %s %s", spec.Status, req.Method, req.URL.Path)
+				return problem.Write(c, problem.New(spec.Status, ErrInjected.Error()))
+			}
+			if len(applied) > 0 {
+				c.Response().Header().Set(InjectedHeader, strings.Join(applied, ","))
+			}
+			return next(c)
+		}
+	}
+}
+
+// plan decides whether the request is in scope and with which spec; a value
+// in the fault header overrides the default spec for that request, within
+// FAULT_MAX_LATENCY
+func (i *Injector) plan(c echo.Context) (Spec, bool, error) {
+	spec := i.Spec()
+	value, hasHeader := c.Request().Header[http.CanonicalHeaderKey(i.cfg.Header)]
+	switch i.cfg.Scope {
+	case ScopeHeader:
+		if !hasHeader {
+			return spec, false, nil
+		}
+	case ScopeUsers:
+		user := reqctx.UserID(c)
+		if user == "" || !contains(i.cfg.Users, user) {
+			return spec, false, nil
+		}
+	}
+	if hasHeader && strings.TrimSpace(value[0]) != "" {
+		parsed, err := ParseSpec(value[0], spec)
+		if err != nil {
+			return spec, false, err
+		}
+		spec = i.clamp(parsed)
+	}
+	return spec, true, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// RedisHook fails Redis commands for requests planned with a Redis fault rate
+func RedisHook() redis.Hook {
+	return redisHook{}
+}
+
+type redisHook struct{}
+
+func (redisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, Inject(ctx, Redis)
+}
+
+func (redisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error { return nil }
+
+func (redisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, Inject(ctx, Redis)
+}
+
+func (redisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error { return nil }
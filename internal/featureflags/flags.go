@@ -0,0 +1,71 @@
+package featureflags
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"time"
+)
+
+// ErrNotFound is returned when a flag does not exist
+var ErrNotFound = errors.New("feature flag not found")
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// Flag is a feature toggle with optional percentage rollout and per-user targeting
+type Flag struct {
+	Key         string `json:"key"`
+	Description string `json:"description,omitempty"`
+	// Enabled is the master switch; a disabled flag is off for everyone
+	Enabled bool `json:"enabled"`
+	// Percentage of users (0-100) who get the feature, bucketed by user ID
+	Percentage int `json:"percentage"`
+	// Users always get the feature while the flag is enabled
+	Users []string `json:"users,omitempty"`
+	// BlockedUsers never get the feature
+	BlockedUsers []string  `json:"blocked_users,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Validate checks a flag before it is stored
+func (f Flag) Validate() error {
+	if !keyPattern.MatchString(f.Key) {
+		return fmt.Errorf("flag key must be lowercase letters, digits, '_', '-' or '.'")
+	}
+	if f.Percentage < 0 || f.Percentage > 100 {
+		return fmt.Errorf("percentage must be between 0 and 100")
+	}
+	return nil
+}
+
+// EnabledFor evaluates the flag for a user; anonymous users only see fully rolled-out flags
+func (f Flag) EnabledFor(userID string) bool {
+	if !f.Enabled {
+		return false
+	}
+	for _, u := range f.BlockedUsers {
+		if u == userID {
+			return false
+		}
+	}
+	for _, u := range f.Users {
+		if u == userID {
+			return true
+		}
+	}
+	if f.Percentage >= 100 {
+		return true
+	}
+	if userID == "" || f.Percentage <= 0 {
+		return false
+	}
+	return bucket(f.Key, userID) < f.Percentage
+}
+
+// bucket places a user in 0-99, stable per flag so rollouts grow without reshuffling users
+func bucket(key, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key + ":" + userID))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,55 @@
+package featureflags
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterRoutes mounts the flag admin API on an admin-protected group
+func (s *Service) RegisterRoutes(g *echo.Group) {
+	g.GET("/flags", s.handleList)
+	g.PUT("/flags/:key", s.handleSave)
+	g.DELETE("/flags/:key", s.handleDelete)
+	g.GET("/flags/:key/evaluate", s.handleEvaluate)
+}
+
+func (s *Service) handleList(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{"flags": s.List()})
+}
+
+func (s *Service) handleSave(c echo.Context) error {
+	var f Flag
+	if err := c.Bind(&f); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	f.Key = c.Param("key")
+	if err := f.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := s.Save(c.Request().Context(), f); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, f)
+}
+
+func (s *Service) handleDelete(c echo.Context) error {
+	err := s.Delete(c.Request().Context(), c.Param("key"))
+	if isNotFound(err) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// handleEvaluate shows whether a flag is on for ?user_id=, for checking targeting
+func (s *Service) handleEvaluate(c echo.Context) error {
+	key, userID := c.Param("key"), c.QueryParam("user_id")
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"key":     key,
+		"user_id": userID,
+		"enabled": s.IsEnabled(key, userID),
+	})
+}
@@ -0,0 +1,140 @@
+package featureflags
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RefreshInterval is how often instances pick up flag changes made elsewhere
+const RefreshInterval = 30 * time.Second
+
+// Service evaluates flags from an in-memory snapshot refreshed from the store
+type Service struct {
+	store Store
+
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+// NewService creates a flag service and loads the initial snapshot
+func NewService(ctx context.Context, store Store) *Service {
+	s := &Service{store: store, flags: make(map[string]Flag)}
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("⚠️ Feature flags unavailable, all flags off: %v", err)
+	}
+	return s
+}
+
+// Start refreshes the snapshot until ctx is cancelled
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Printf("⚠️ Feature flag refresh failed: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh reloads every flag from the store
+func (s *Service) Refresh(ctx context.Context) error {
+	list, err := s.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	flags := make(map[string]Flag, len(list))
+	for _, f := range list {
+		flags[f.Key] = f
+	}
+	s.mu.Lock()
+	s.flags = flags
+	s.mu.Unlock()
+	return nil
+}
+
+// IsEnabled reports whether a flag is on for a user; unknown flags are off
+func (s *Service) IsEnabled(key, userID string) bool {
+	s.mu.RLock()
+	f, ok := s.flags[key]
+	s.mu.RUnlock()
+	return ok && f.EnabledFor(userID)
+}
+
+// List returns the current snapshot
+func (s *Service) List() []Flag {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	flags := make([]Flag, 0, len(s.flags))
+	for _, f := range s.flags {
+		flags = append(flags, f)
+	}
+	sortFlags(flags)
+	return flags
+}
+
+// Save stores a flag and applies it locally at once
+func (s *Service) Save(ctx context.Context, f Flag) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	f.UpdatedAt = time.Now().UTC()
+	if err := s.store.Save(ctx, f); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.flags[f.Key] = f
+	s.mu.Unlock()
+	return nil
+}
+
+// Delete removes a flag
+func (s *Service) Delete(ctx context.Context, key string) error {
+	if err := s.store.Delete(ctx, key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.flags, key)
+	s.mu.Unlock()
+	return nil
+}
+
+const contextKey = "featureflags.service"
+
+// Inject makes the service available to handlers via Enabled
+func Inject(s *Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(contextKey, s)
+			return next(c)
+		}
+	}
+}
+
+// Enabled reports whether a flag is on for the authenticated user of the request
+func Enabled(c echo.Context, key string) bool {
+	s, ok := c.Get(contextKey).(*Service)
+	return ok && s.IsEnabled(key, reqctx.UserID(c))
+}
+
+// Require hides a route (404) unless the flag is on for the requesting user
+func Require(key string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !Enabled(c, key) {
+				return echo.ErrNotFound
+			}
+			return next(c)
+		}
+	}
+}
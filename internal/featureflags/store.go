@@ -0,0 +1,146 @@
+package featureflags
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Store persists flags
+type Store interface {
+	List(ctx context.Context) ([]Flag, error)
+	Save(ctx context.Context, f Flag) error
+	Delete(ctx context.Context, key string) error
+}
+
+// redisKey is the hash holding every flag as JSON, keyed by flag key
+const redisKey = "featureflags"
+
+// RedisStore keeps flags in a Redis hash so every instance shares them
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a Redis-backed store
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) List(ctx context.Context) ([]Flag, error) {
+	raw, err := s.client.HGetAll(ctx, redisKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	flags := make([]Flag, 0, len(raw))
+	for _, data := range raw {
+		var f Flag
+		if err := json.Unmarshal([]byte(data), &f); err != nil {
+			return nil, fmt.Errorf("corrupt flag: %w", err)
+		}
+		flags = append(flags, f)
+	}
+	sortFlags(flags)
+	return flags, nil
+}
+
+func (s *RedisStore) Save(ctx context.Context, f Flag) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return s.client.HSet(ctx, redisKey, f.Key, data).Err()
+}
+
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	n, err := s.client.HDel(ctx, redisKey, key).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DBStore keeps flags in SQLite, for deployments running without Redis
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a database-backed store
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// Migrate creates the feature_flags table
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS feature_flags (
+		key TEXT PRIMARY KEY,
+		data TEXT NOT NULL,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("feature flags migration: %w", err)
+	}
+	return nil
+}
+
+func (s *DBStore) List(ctx context.Context) ([]Flag, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM feature_flags`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flags []Flag
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var f Flag
+		if err := json.Unmarshal([]byte(data), &f); err != nil {
+			return nil, fmt.Errorf("corrupt flag: %w", err)
+		}
+		flags = append(flags, f)
+	}
+	sortFlags(flags)
+	return flags, rows.Err()
+}
+
+func (s *DBStore) Save(ctx context.Context, f Flag) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO feature_flags (key, data) VALUES (?, ?)
+		 ON CONFLICT(key) DO UPDATE SET data = excluded.data, updated_at = CURRENT_TIMESTAMP`,
+		f.Key, string(data))
+	return err
+}
+
+func (s *DBStore) Delete(ctx context.Context, key string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM feature_flags WHERE key = ?`, key)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func sortFlags(flags []Flag) {
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+}
+
+// isNotFound reports whether err means the flag is missing
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
@@ -0,0 +1,340 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/dbschema"
+)
+
+// prefix marks encrypted column values so plaintext rows written before
+// encryption was enabled still read back during migration
+const prefix = "enc1:"
+
+// plainPrefix marks plaintext stored while encryption is disabled that would
+// otherwise read as ciphertext or as marked plaintext
+const plainPrefix = "raw1:"
+
+// maxCachedKeys bounds the unwrapped data keys held in memory
+const maxCachedKeys = 10000
+
+// dekCacheTTL bounds how long a data key forgotten on another instance stays usable here
+const dekCacheTTL = time.Minute
+
+// ErrDisabled is returned when encrypting without FIELD_ENCRYPTION_KEYS configured
+var ErrDisabled = errors.New("fieldcrypt: no encryption keys configured")
+
+// Migrate creates the user_data_keys table holding wrapped per-user data keys
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS user_data_keys (
+		user_id TEXT PRIMARY KEY,
+		kek_id TEXT NOT NULL,
+		wrapped_key BLOB NOT NULL,
+		created_at DATETIME NOT NULL,
+		rotated_at DATETIME
+	)`)
+	return err
+}
+
+// Cipher encrypts sensitive fields with a per-user data key (DEK), itself
+// wrapped by a key-encryption key from the KeyRing. Deleting a user's DEK
+// crypto-shreds every field encrypted for them.
+type Cipher struct {
+	db   *sql.DB
+	ring *KeyRing
+
+	mu    sync.Mutex
+	cache map[string]cachedKey
+}
+
+type cachedKey struct {
+	dek []byte
+	at  time.Time
+}
+
+// New creates a cipher; ring may be nil, in which case Encrypt returns ErrDisabled
+// and Decrypt passes plaintext through
+func New(db *sql.DB, ring *KeyRing) *Cipher {
+	return &Cipher{db: db, ring: ring, cache: map[string]cachedKey{}}
+}
+
+// Enabled reports whether keys are configured
+func (c *Cipher) Enabled() bool {
+	return c != nil && c.ring != nil
+}
+
+// IsEncrypted reports whether a stored value is ciphertext
+func IsEncrypted(v string) bool {
+	return strings.HasPrefix(v, prefix)
+}
+
+// Encrypt encrypts plaintext for userID. field is bound as associated data,
+// so a value copied into another column fails to decrypt.
+func (c *Cipher) Encrypt(ctx context.Context, userID, field, plaintext string) (string, error) {
+	if !c.Enabled() {
+		return "", ErrDisabled
+	}
+	dek, err := c.dataKey(ctx, userID, true)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(plaintext), aad(userID, field))
+	if err != nil {
+		return "", err
+	}
+	return prefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt; values without the ciphertext prefix are returned
+// unchanged, less the marker Seal adds to ambiguous plaintext
+func (c *Cipher) Decrypt(ctx context.Context, userID, field, stored string) (string, error) {
+	if strings.HasPrefix(stored, plainPrefix) {
+		return strings.TrimPrefix(stored, plainPrefix), nil
+	}
+	if !IsEncrypted(stored) {
+		return stored, nil
+	}
+	if !c.Enabled() {
+		return "", ErrDisabled
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(stored, prefix))
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: corrupt ciphertext: %w", err)
+	}
+	dek, err := c.dataKey(ctx, userID, false)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	plain, err := open(aead, sealed, aad(userID, field))
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: decrypt %s: %w", field, err)
+	}
+	return string(plain), nil
+}
+
+// Forget deletes the user's data key, making their encrypted fields
+// unreadable. Other instances stop using their cached copy within dekCacheTTL.
+func (c *Cipher) Forget(ctx context.Context, userID string) error {
+	c.mu.Lock()
+	delete(c.cache, userID)
+	c.mu.Unlock()
+	_, err := c.db.ExecContext(ctx, `DELETE FROM user_data_keys WHERE user_id = ?`, userID)
+	return err
+}
+
+// ForgetDeleted forgets the data keys of users whose account is deleted or
+// gone, and returns how many were forgotten
+func (c *Cipher) ForgetDeleted(ctx context.Context) (int, error) {
+	rows, err := c.db.QueryContext(ctx,
+		`SELECT k.user_id FROM user_data_keys k LEFT JOIN `+dbschema.Users+` u ON u.id = k.user_id
+		 WHERE u.id IS NULL OR u.deleted_at IS NOT NULL`)
+	if err != nil {
+		return 0, err
+	}
+	var users []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		users = append(users, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for i, id := range users {
+		if err := c.Forget(ctx, id); err != nil {
+			return i, err
+		}
+	}
+	return len(users), nil
+}
+
+// Start runs ForgetDeleted every interval, so deleting an account shreds its
+// encrypted fields even when the deletion happens elsewhere
+func (c *Cipher) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := c.ForgetDeleted(ctx)
+			if err != nil {
+				log.Printf("⚠️ Data key cleanup failed: %v", err)
+			} else if n > 0 {
+				log.Printf("🗑️ Forgot data keys of %d deleted users", n)
+			}
+		}
+	}
+}
+
+// Rotate rewraps every data key not already under the primary KEK. Field
+// ciphertext is untouched, so rotation is cheap; retire an old KEK from
+// FIELD_ENCRYPTION_KEYS once this reports zero remaining.
+func (c *Cipher) Rotate(ctx context.Context) (int, error) {
+	if !c.Enabled() {
+		return 0, ErrDisabled
+	}
+	rows, err := c.db.QueryContext(ctx,
+		`SELECT user_id, kek_id, wrapped_key FROM user_data_keys WHERE kek_id != ?`, c.ring.Primary())
+	if err != nil {
+		return 0, err
+	}
+	type entry struct {
+		userID, kekID string
+		wrapped       []byte
+	}
+	var stale []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.userID, &e.kekID, &e.wrapped); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		stale = append(stale, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for i, e := range stale {
+		dek, err := c.ring.unwrap(e.kekID, e.wrapped, e.userID)
+		if err != nil {
+			return i, fmt.Errorf("user %s: %w", e.userID, err)
+		}
+		kekID, wrapped, err := c.ring.wrap(dek, e.userID)
+		if err != nil {
+			return i, err
+		}
+		if _, err := c.db.ExecContext(ctx,
+			`UPDATE user_data_keys SET kek_id = ?, wrapped_key = ?, rotated_at = ? WHERE user_id = ? AND kek_id = ?`,
+			kekID, wrapped, time.Now().UTC(), e.userID, e.kekID); err != nil {
+			return i, err
+		}
+	}
+	return len(stale), nil
+}
+
+// dataKey returns the user's DEK, creating it on first encryption
+func (c *Cipher) dataKey(ctx context.Context, userID string, create bool) ([]byte, error) {
+	c.mu.Lock()
+	if k, ok := c.cache[userID]; ok && time.Since(k.at) < dekCacheTTL {
+		c.mu.Unlock()
+		return k.dek, nil
+	}
+	c.mu.Unlock()
+
+	var (
+		kekID   string
+		wrapped []byte
+	)
+	err := c.db.QueryRowContext(ctx,
+		`SELECT kek_id, wrapped_key FROM user_data_keys WHERE user_id = ?`, userID).Scan(&kekID, &wrapped)
+	switch {
+	case err == sql.ErrNoRows && create:
+		return c.createDataKey(ctx, userID)
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("fieldcrypt: no data key for user %s", userID)
+	case err != nil:
+		return nil, err
+	}
+
+	dek, err := c.ring.unwrap(kekID, wrapped, userID)
+	if err != nil {
+		return nil, err
+	}
+	c.remember(userID, dek)
+	return dek, nil
+}
+
+func (c *Cipher) createDataKey(ctx context.Context, userID string) ([]byte, error) {
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, err
+	}
+	kekID, wrapped, err := c.ring.wrap(dek, userID)
+	if err != nil {
+		return nil, err
+	}
+	// A concurrent first write may have created the key already; keep theirs
+	if _, err := c.db.ExecContext(ctx,
+		`INSERT INTO user_data_keys (user_id, kek_id, wrapped_key, created_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(user_id) DO NOTHING`,
+		userID, kekID, wrapped, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return c.dataKey(ctx, userID, false)
+}
+
+func (c *Cipher) remember(userID string, dek []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.cache) >= maxCachedKeys {
+		c.cache = map[string]cachedKey{}
+	}
+	c.cache[userID] = cachedKey{dek: dek, at: time.Now()}
+}
+
+func aad(userID, field string) []byte {
+	return []byte("field:" + userID + ":" + field)
+}
+
+// Seal encrypts the named fields in place before a repository writes them,
+// whatever they contain, so call it once per write. With encryption disabled
+// the values are left as plaintext, marked when they look like ciphertext.
+func (c *Cipher) Seal(ctx context.Context, userID string, fields map[string]*string) error {
+	for name, v := range fields {
+		if v == nil || *v == "" {
+			continue
+		}
+		if !c.Enabled() {
+			if IsEncrypted(*v) || strings.HasPrefix(*v, plainPrefix) {
+				*v = plainPrefix + *v
+			}
+			continue
+		}
+		enc, err := c.Encrypt(ctx, userID, name, *v)
+		if err != nil {
+			return err
+		}
+		*v = enc
+	}
+	return nil
+}
+
+// Open decrypts the named fields in place after a repository reads them
+func (c *Cipher) Open(ctx context.Context, userID string, fields map[string]*string) error {
+	for name, v := range fields {
+		if v == nil {
+			continue
+		}
+		plain, err := c.Decrypt(ctx, userID, name, *v)
+		if err != nil {
+			return err
+		}
+		*v = plain
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package fieldcrypt
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func testRing(t *testing.T, spec string) *KeyRing {
+	t.Helper()
+	ring, err := ParseKeyRing(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ring
+}
+
+func TestSealOpen(t *testing.T) {
+	ctx := context.Background()
+	c := New(testDB(t), testRing(t, "k1:"+testKey('a')))
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"text", "metformin 500mg"},
+		{"unicode", "سكري النوع الثاني"},
+		{"looks like ciphertext", "enc1:not really"},
+		{"looks like a marker", "raw1:not really"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.value
+			if err := c.Seal(ctx, "u1", map[string]*string{"notes": &v}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.value != "" && (!IsEncrypted(v) || v == tt.value) {
+				t.Fatalf("Seal left %q as %q", tt.value, v)
+			}
+			if err := c.Open(ctx, "u1", map[string]*string{"notes": &v}); err != nil {
+				t.Fatal(err)
+			}
+			if v != tt.value {
+				t.Errorf("round trip = %q, want %q", v, tt.value)
+			}
+		})
+	}
+}
+
+func TestSealBindsUserAndField(t *testing.T) {
+	ctx := context.Background()
+	c := New(testDB(t), testRing(t, "k1:"+testKey('a')))
+	v := "secret"
+	if err := c.Seal(ctx, "u1", map[string]*string{"notes": &v}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Decrypt(ctx, "u1", "other", v); err == nil {
+		t.Error("decrypted a value moved to another field")
+	}
+	w := "x"
+	c.Seal(ctx, "u2", map[string]*string{"notes": &w})
+	if _, err := c.Decrypt(ctx, "u2", "notes", v); err == nil {
+		t.Error("decrypted another user's value")
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	ctx := context.Background()
+	c := New(testDB(t), nil)
+	for _, value := range []string{"plain", "enc1:abc", "raw1:abc"} {
+		v := value
+		if err := c.Seal(ctx, "u1", map[string]*string{"notes": &v}); err != nil {
+			t.Fatal(err)
+		}
+		if IsEncrypted(v) {
+			t.Errorf("disabled Seal stored %q as ciphertext %q", value, v)
+		}
+		if err := c.Open(ctx, "u1", map[string]*string{"notes": &v}); err != nil {
+			t.Fatal(err)
+		}
+		if v != value {
+			t.Errorf("round trip = %q, want %q", v, value)
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	old := New(db, testRing(t, "k1:"+testKey('a')))
+	values := map[string]string{}
+	for _, user := range []string{"u1", "u2"} {
+		v := "value of " + user
+		if err := old.Seal(ctx, user, map[string]*string{"notes": &v}); err != nil {
+			t.Fatal(err)
+		}
+		values[user] = v
+	}
+
+	both := New(db, testRing(t, "k2:"+testKey('b')+",k1:"+testKey('a')))
+	n, err := both.Rotate(ctx)
+	if err != nil || n != 2 {
+		t.Fatalf("Rotate() = %d, %v; want 2, nil", n, err)
+	}
+	if n, _ := both.Rotate(ctx); n != 0 {
+		t.Errorf("second Rotate() = %d, want 0", n)
+	}
+
+	// The old key can be retired once everything is rewrapped
+	next := New(db, testRing(t, "k2:"+testKey('b')))
+	for user, stored := range values {
+		got, err := next.Decrypt(ctx, user, "notes", stored)
+		if err != nil || got != "value of "+user {
+			t.Errorf("Decrypt(%s) after rotation = %q, %v", user, got, err)
+		}
+	}
+	if _, err := New(db, testRing(t, "k1:"+testKey('a'))).Decrypt(ctx, "u1", "notes", values["u1"]); err == nil {
+		t.Error("retired key still unwraps a rotated data key")
+	}
+}
+
+func TestForgetDeleted(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	if _, err := db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, deleted_at DATETIME);
+		INSERT INTO users (id, deleted_at) VALUES ('active', NULL), ('deleted', CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatal(err)
+	}
+	c := New(db, testRing(t, "k1:"+testKey('a')))
+	stored := map[string]string{}
+	for _, user := range []string{"active", "deleted", "gone"} {
+		v := "x"
+		if err := c.Seal(ctx, user, map[string]*string{"notes": &v}); err != nil {
+			t.Fatal(err)
+		}
+		stored[user] = v
+	}
+	n, err := c.ForgetDeleted(ctx)
+	if err != nil || n != 2 {
+		t.Fatalf("ForgetDeleted() = %d, %v; want 2, nil", n, err)
+	}
+	for user, v := range stored {
+		_, err := c.Decrypt(ctx, user, "notes", v)
+		if (err == nil) != (user == "active") {
+			t.Errorf("Decrypt(%s) error = %v", user, err)
+		}
+	}
+}
+
+func TestParseKeyRing(t *testing.T) {
+	tests := []struct {
+		spec    string
+		primary string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"k1:" + testKey('a'), "k1", false},
+		{"k2:" + testKey('b') + ", k1:" + testKey('a'), "k2", false},
+		{"k1:" + testKey('a') + ",k1:" + testKey('b'), "", true},
+		{"k1:" + base64.StdEncoding.EncodeToString([]byte("short")), "", true},
+		{"k1:not base64!", "", true},
+		{testKey('a'), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			ring, err := ParseKeyRing(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeyRing() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && ring != nil && ring.Primary() != tt.primary {
+				t.Errorf("Primary() = %q, want %q", ring.Primary(), tt.primary)
+			}
+		})
+	}
+}
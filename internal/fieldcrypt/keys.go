@@ -0,0 +1,102 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// KeyRing holds the key-encryption keys (KEKs). The first key is primary and
+// wraps new data keys; the others remain for unwrapping until rotation finishes.
+type KeyRing struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// LoadKeyRing parses FIELD_ENCRYPTION_KEYS, e.g. "2024b:<base64 32 bytes>,2024a:<base64>".
+// It is normally populated from the secrets backend. Unset means encryption is disabled.
+func LoadKeyRing() (*KeyRing, error) {
+	return ParseKeyRing(envconfig.String("FIELD_ENCRYPTION_KEYS", ""))
+}
+
+// ParseKeyRing parses a comma-separated list of id:base64key entries
+func ParseKeyRing(spec string) (*KeyRing, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	ring := &KeyRing{keys: map[string]cipher.AEAD{}}
+	for _, entry := range strings.Split(spec, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("FIELD_ENCRYPTION_KEYS entries must look like id:base64key")
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes, base64 encoded", id)
+		}
+		if _, dup := ring.keys[id]; dup {
+			return nil, fmt.Errorf("key %q listed twice", id)
+		}
+		aead, err := newAEAD(raw)
+		if err != nil {
+			return nil, err
+		}
+		ring.keys[id] = aead
+		if ring.primary == "" {
+			ring.primary = id
+		}
+	}
+	return ring, nil
+}
+
+// Primary is the ID of the key that wraps new data keys
+func (r *KeyRing) Primary() string {
+	return r.primary
+}
+
+// wrap encrypts a data key under the primary KEK; the user ID is bound as
+// associated data so a wrapped key can't be moved to another user
+func (r *KeyRing) wrap(dek []byte, userID string) (string, []byte, error) {
+	sealed, err := seal(r.keys[r.primary], dek, []byte("dek:"+userID))
+	return r.primary, sealed, err
+}
+
+func (r *KeyRing) unwrap(kekID string, wrapped []byte, userID string) ([]byte, error) {
+	aead, ok := r.keys[kekID]
+	if !ok {
+		return nil, fmt.Errorf("key-encryption key %q is not configured", kekID)
+	}
+	return open(aead, wrapped, []byte("dek:"+userID))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns nonce || ciphertext
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ct := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ct, aad)
+}
@@ -0,0 +1,218 @@
+package fieldsets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// alwaysKept survives every field selection so clients can still address resources
+var alwaysKept = []string{"id"}
+
+var fieldPattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+// AllowedIncludes lists the relations clients may request with ?include=,
+// from FIELDSETS_INCLUDES
+func AllowedIncludes() []string {
+	return envconfig.List("FIELDSETS_INCLUDES", []string{
+		"servings", "nutrients", "allergens", "ingredients", "items", "tags",
+	})
+}
+
+// Selection is the parsed ?fields= and ?include= query
+type Selection struct {
+	// Fields lists dotted paths to keep; empty keeps everything
+	Fields []string
+	// Exclude lists dotted paths to drop (?fields=-nutrients)
+	Exclude []string
+	// Include lists relations the handler should load
+	Include map[string]bool
+}
+
+// Empty reports whether the selection leaves responses unchanged
+func (s Selection) Empty() bool {
+	return len(s.Fields) == 0 && len(s.Exclude) == 0
+}
+
+// Includes reports whether the client asked for a relation
+func (s Selection) Includes(relation string) bool {
+	return s.Include[relation]
+}
+
+// Parse reads ?fields=a,b.c,-d and ?include=x,y. Relations not in allowed are rejected.
+func Parse(q url.Values, allowedIncludes ...string) (Selection, error) {
+	sel := Selection{Include: make(map[string]bool)}
+
+	for _, f := range splitList(q.Get("fields")) {
+		exclude := strings.HasPrefix(f, "-")
+		f = strings.TrimPrefix(f, "-")
+		if !fieldPattern.MatchString(f) {
+			return Selection{}, fmt.Errorf("invalid field %q", f)
+		}
+		if exclude {
+			sel.Exclude = append(sel.Exclude, f)
+		} else {
+			sel.Fields = append(sel.Fields, f)
+		}
+	}
+
+	allowed := make(map[string]bool, len(allowedIncludes))
+	for _, a := range allowedIncludes {
+		allowed[a] = true
+	}
+	for _, inc := range splitList(q.Get("include")) {
+		if len(allowed) > 0 && !allowed[inc] {
+			return Selection{}, fmt.Errorf("unknown include %q", inc)
+		}
+		sel.Include[inc] = true
+	}
+	return sel, nil
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.ToLower(strings.TrimSpace(part)); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// ApplyJSON shapes an encoded JSON payload. Top-level arrays are shaped per
+// element; list envelopes ({"foods": [...], "total": 3}) have each array of
+// objects shaped and their scalar fields left intact.
+func (s Selection) ApplyJSON(body []byte) ([]byte, error) {
+	if s.Empty() {
+		return body, nil
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	switch v := payload.(type) {
+	case []interface{}:
+		payload = s.shapeList(v)
+	case map[string]interface{}:
+		if isEnvelope(v) {
+			for key, val := range v {
+				if list, ok := val.([]interface{}); ok {
+					v[key] = s.shapeList(list)
+				}
+			}
+		} else {
+			payload = s.shape(v)
+		}
+	}
+	return json.Marshal(payload)
+}
+
+// Apply shapes any JSON-encodable value
+func (s Selection) Apply(v interface{}) (interface{}, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	shaped, err := s.ApplyJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(shaped), nil
+}
+
+func (s Selection) shapeList(list []interface{}) []interface{} {
+	for i, item := range list {
+		if obj, ok := item.(map[string]interface{}); ok {
+			list[i] = s.shape(obj)
+		}
+	}
+	return list
+}
+
+func (s Selection) shape(obj map[string]interface{}) map[string]interface{} {
+	if len(s.Fields) > 0 {
+		obj = pick(obj, append(s.Fields, alwaysKept...))
+	}
+	for _, path := range s.Exclude {
+		drop(obj, strings.Split(path, "."))
+	}
+	return obj
+}
+
+// isEnvelope reports whether an object wraps a list of resources rather than
+// being a resource itself (resources carry an id)
+func isEnvelope(obj map[string]interface{}) bool {
+	if _, isResource := obj["id"]; isResource {
+		return false
+	}
+	for _, val := range obj {
+		if list, ok := val.([]interface{}); ok && len(list) > 0 {
+			if _, isObj := list[0].(map[string]interface{}); isObj {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pick keeps only the given dotted paths
+func pick(obj map[string]interface{}, paths []string) map[string]interface{} {
+	nested := make(map[string][]string)
+	whole := make(map[string]bool)
+	for _, p := range paths {
+		head, rest, found := strings.Cut(p, ".")
+		if found {
+			nested[head] = append(nested[head], rest)
+		} else {
+			whole[head] = true
+		}
+	}
+
+	out := make(map[string]interface{}, len(paths))
+	for key, val := range obj {
+		if whole[key] {
+			out[key] = val
+			continue
+		}
+		sub, ok := nested[key]
+		if !ok {
+			continue
+		}
+		switch v := val.(type) {
+		case map[string]interface{}:
+			out[key] = pick(v, sub)
+		case []interface{}:
+			for i, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					v[i] = pick(m, sub)
+				}
+			}
+			out[key] = v
+		}
+	}
+	return out
+}
+
+// drop removes a dotted path, descending into nested objects and lists
+func drop(val interface{}, path []string) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(v, path[0])
+			return
+		}
+		if child, ok := v[path[0]]; ok {
+			drop(child, path[1:])
+		}
+	case []interface{}:
+		for _, item := range v {
+			drop(item, path)
+		}
+	}
+}
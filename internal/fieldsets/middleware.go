@@ -0,0 +1,87 @@
+package fieldsets
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const contextKey = "fieldsets.selection"
+
+// FromContext returns the selection parsed by the middleware, so handlers can
+// skip loading relations that were not requested via ?include=
+func FromContext(c echo.Context) Selection {
+	if sel, ok := c.Get(contextKey).(Selection); ok {
+		return sel
+	}
+	return Selection{}
+}
+
+// Middleware parses ?fields=/?include= and shapes successful JSON responses
+// centrally, so list endpoints return lean payloads without per-handler code.
+// Register it after Compression so it sees the uncompressed body. Only the
+// allowed relations may be requested with ?include=.
+func Middleware(allowedIncludes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			sel, err := Parse(c.QueryParams(), allowedIncludes...)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			c.Set(contextKey, sel)
+
+			if sel.Empty() || c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			res := c.Response()
+			original := res.Writer
+			buf := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+			res.Writer = buf
+			err = next(c)
+			res.Writer = original
+			if err != nil && !buf.wroteHeader {
+				// Nothing was written; echo's error handler sets the real status
+				return err
+			}
+
+			body := buf.body.Bytes()
+			if buf.status >= 200 && buf.status < 300 && strings.HasPrefix(res.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+				if shaped, shapeErr := sel.ApplyJSON(body); shapeErr == nil {
+					body = shaped
+				}
+			}
+			if buf.wroteHeader {
+				res.Header().Set(echo.HeaderContentLength, strconv.Itoa(len(body)))
+				original.WriteHeader(buf.status)
+			}
+			if _, writeErr := original.Write(body); writeErr != nil && err == nil {
+				err = writeErr
+			}
+			return err
+		}
+	}
+}
+
+// bufferedWriter holds the response so it can be shaped before it is sent
+type bufferedWriter struct {
+	http.ResponseWriter
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+	w.wroteHeader = true
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.body.Write(b)
+}
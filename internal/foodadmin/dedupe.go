@@ -0,0 +1,110 @@
+package foodadmin
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"nutrition-health-backend/internal/textmatch"
+)
+
+// Food is the part of a food row duplicate detection looks at
+type Food struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Nutrients map[string]float64 `json:"nutrients"`
+}
+
+// Candidate is a proposed merge of two foods
+type Candidate struct {
+	ID               int64   `json:"id,omitempty"`
+	A                Food    `json:"a"`
+	B                Food    `json:"b"`
+	NameScore        float64 `json:"name_score"`
+	NutrientDistance float64 `json:"nutrient_distance"`
+	Score            float64 `json:"score"`
+}
+
+// DetectOptions tunes candidate detection
+type DetectOptions struct {
+	// MinScore is the combined score a pair needs to be proposed
+	MinScore float64
+	// MaxBlock caps the foods compared pairwise within one name block
+	MaxBlock int
+}
+
+// distanceNutrients are compared for nutrient distance; per 100 g
+var distanceNutrients = []string{"calories", "protein_g", "carbs_g", "fat_g"}
+
+// NutrientDistance is the mean relative difference of the core nutrients, 0..1
+func NutrientDistance(a, b map[string]float64) float64 {
+	total, n := 0.0, 0
+	for _, k := range distanceNutrients {
+		av, aok := a[k]
+		bv, bok := b[k]
+		if !aok || !bok {
+			continue
+		}
+		total += math.Abs(av-bv) / math.Max(math.Max(math.Abs(av), math.Abs(bv)), 1)
+		n++
+	}
+	if n == 0 {
+		return 1
+	}
+	return total / float64(n)
+}
+
+// Detect proposes merge candidates. Foods are blocked by the first word of
+// their normalized name so only plausible pairs are compared; the score
+// weights name similarity 0.6 and nutrient closeness 0.4.
+func Detect(foods []Food, opts DetectOptions) []Candidate {
+	if opts.MaxBlock <= 0 {
+		opts.MaxBlock = 500
+	}
+	type entry struct {
+		food Food
+		norm string
+	}
+	blocks := map[string][]entry{}
+	for _, f := range foods {
+		norm := textmatch.Normalize(f.Name)
+		words := strings.Fields(norm)
+		if len(words) == 0 {
+			continue
+		}
+		blocks[words[0]] = append(blocks[words[0]], entry{f, norm})
+	}
+
+	var out []Candidate
+	for _, block := range blocks {
+		if len(block) > opts.MaxBlock {
+			block = block[:opts.MaxBlock]
+		}
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				name := textmatch.BestSimilarity(block[i].norm, block[j].norm)
+				dist := NutrientDistance(block[i].food.Nutrients, block[j].food.Nutrients)
+				score := 0.6*name + 0.4*(1-dist)
+				if score < opts.MinScore {
+					continue
+				}
+				a, b := block[i].food, block[j].food
+				if b.ID < a.ID {
+					a, b = b, a
+				}
+				out = append(out, Candidate{A: a, B: b, NameScore: round3(name), NutrientDistance: round3(dist), Score: round3(score)})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].A.ID < out[j].A.ID
+	})
+	return out
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,122 @@
+package foodadmin
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"nutrition-health-backend/internal/runtimecfg"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes food admin tooling
+type Handler struct {
+	service *Service
+	job     *Job
+}
+
+// NewHandler creates a food admin handler
+func NewHandler(service *Service, job *Job) *Handler {
+	return &Handler{service: service, job: job}
+}
+
+// RegisterRoutes mounts bulk edit, merge and duplicate review on an admin-protected group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.PATCH("/foods/bulk", h.BulkEdit)
+	g.POST("/foods/merge", h.Merge)
+	g.GET("/foods/duplicates", h.Duplicates)
+	g.POST("/foods/duplicates/detect", h.Detect)
+	g.POST("/foods/duplicates/:id/dismiss", h.Dismiss)
+}
+
+func actor(c echo.Context) string {
+	if a := c.Request().Header.Get(runtimecfg.ActorHeader); a != "" {
+		return a
+	}
+	return "admin@" + c.RealIP()
+}
+
+type bulkRequest struct {
+	IDs []string           `json:"ids"`
+	Set map[string]float64 `json:"set"`
+}
+
+// BulkEdit sets nutrient fields on many foods
+func (h *Handler) BulkEdit(c echo.Context) error {
+	var req bulkRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	n, err := h.service.BulkEdit(c.Request().Context(), req.IDs, req.Set)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	log.Printf("AUDIT food bulk edit by %s: %d foods, fields %v", actor(c), n, req.Set)
+	return c.JSON(http.StatusOK, map[string]interface{}{"updated": n})
+}
+
+type mergeRequest struct {
+	CanonicalID string `json:"canonical_id"`
+	DuplicateID string `json:"duplicate_id"`
+}
+
+// Merge folds a duplicate food into the canonical one
+func (h *Handler) Merge(c echo.Context) error {
+	var req mergeRequest
+	if err := c.Bind(&req); err != nil || req.CanonicalID == "" || req.DuplicateID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "canonical_id and duplicate_id are required")
+	}
+	who := actor(c)
+	result, err := h.service.Merge(c.Request().Context(), req.CanonicalID, req.DuplicateID, who)
+	switch {
+	case errors.Is(err, ErrSameFood):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		return err
+	}
+	log.Printf("AUDIT food merge by %s: %s -> %s %v", who, req.DuplicateID, req.CanonicalID, result.Repointed)
+	return c.JSON(http.StatusOK, result)
+}
+
+// Duplicates lists merge candidates (?status=pending|merged|dismissed)
+func (h *Handler) Duplicates(c echo.Context) error {
+	status := c.QueryParam("status")
+	if status == "" {
+		status = StatusPending
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	candidates, err := h.service.Candidates(c.Request().Context(), status, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"status": status, "candidates": candidates})
+}
+
+// Detect runs duplicate detection now
+func (h *Handler) Detect(c echo.Context) error {
+	added, err := h.job.RunOnce(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"new_candidates": added})
+}
+
+// Dismiss rejects a candidate
+func (h *Handler) Dismiss(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid candidate id")
+	}
+	if err := h.service.Dismiss(c.Request().Context(), id, actor(c)); errors.Is(err, ErrCandidate) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
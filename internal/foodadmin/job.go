@@ -0,0 +1,58 @@
+package foodadmin
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Job periodically proposes merge candidates
+type Job struct {
+	service  *Service
+	interval time.Duration
+	opts     DetectOptions
+}
+
+// NewJob reads FOOD_DEDUPE_INTERVAL (24h) and FOOD_DEDUPE_MIN_SCORE (0.85)
+func NewJob(service *Service) *Job {
+	return &Job{
+		service:  service,
+		interval: envconfig.Duration("FOOD_DEDUPE_INTERVAL", 24*time.Hour),
+		opts:     DetectOptions{MinScore: envconfig.Float("FOOD_DEDUPE_MIN_SCORE", 0.85)},
+	}
+}
+
+// Start runs detection every interval until ctx is cancelled
+func (j *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				log.Printf("⚠️ Duplicate food detection failed: %v", err)
+			}
+		}
+	}
+}
+
+// RunOnce detects candidates across all foods and returns how many are new
+func (j *Job) RunOnce(ctx context.Context) (int, error) {
+	started := time.Now()
+	foods, err := j.service.Foods(ctx)
+	if err != nil {
+		return 0, err
+	}
+	candidates := Detect(foods, j.opts)
+	added, err := j.service.SaveCandidates(ctx, candidates)
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("🔁 Duplicate food detection: %d foods, %d candidates, %d new (%s)",
+		len(foods), len(candidates), added, time.Since(started).Round(time.Millisecond))
+	return added, nil
+}
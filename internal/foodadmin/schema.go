@@ -0,0 +1,143 @@
+package foodadmin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	"nutrition-health-backend/internal/dbschema"
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Conflict modes say what a merge does when the canonical food already has a
+// row that a re-pointed duplicate row would collide with
+const (
+	// ConflictFail aborts the merge, so no reference is lost silently
+	ConflictFail = ""
+	// ConflictSum adds the duplicate's quantity into the canonical row
+	ConflictSum = "sum"
+	// ConflictNewest keeps whichever row has the later timestamp
+	ConflictNewest = "newest"
+	// ConflictDrop deletes the duplicate's row, for set-like tables such as favorites
+	ConflictDrop = "drop"
+)
+
+// Reference is a column pointing at a food row that a merge must re-point
+type Reference struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	// OnConflict is one of the Conflict modes; Using names the quantity
+	// (ConflictSum) or timestamp (ConflictNewest) column
+	OnConflict string `json:"on_conflict,omitempty"`
+	Using      string `json:"using,omitempty"`
+	// keys are the other columns of each unique index that includes Column
+	keys [][]string
+}
+
+// Schema lists the columns that point at food rows, so merges can re-point them
+type Schema struct {
+	References []Reference
+}
+
+// DefaultSchema reads FOOD_REFERENCES, entries of "table.column" with an
+// optional ":sum=col", ":newest=col" or ":drop"
+func DefaultSchema() Schema {
+	var s Schema
+	for _, ref := range envconfig.List("FOOD_REFERENCES", []string{
+		dbschema.DiaryEntries + ".food_id",
+		dbschema.RecipeIngredients + ".food_id:sum=quantity",
+		dbschema.MealPlanItems + ".food_id:sum=quantity",
+		dbschema.Favorites + ".food_id:drop",
+		"food_prices.food_id:newest=updated_at",
+	}) {
+		if m := refPattern.FindStringSubmatch(ref); m != nil {
+			s.References = append(s.References, Reference{Table: m[1], Column: m[2], OnConflict: m[3], Using: m[4]})
+		}
+	}
+	return s
+}
+
+var refPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\.([A-Za-z_][A-Za-z0-9_]*)(?::(sum|newest|drop)(?:=([A-Za-z_][A-Za-z0-9_]*))?)?$`)
+
+// Resolve checks the references against the database, dropping those whose
+// table or column doesn't exist so merges work on partial schemas
+func (s Schema) Resolve(ctx context.Context, db *sql.DB) (Schema, error) {
+	var resolved Schema
+	for _, ref := range s.References {
+		refCols, err := dbschema.Columns(ctx, db, ref.Table)
+		if err != nil {
+			return Schema{}, err
+		}
+		if !refCols[ref.Column] {
+			continue
+		}
+		if (ref.OnConflict == ConflictSum || ref.OnConflict == ConflictNewest) && !refCols[ref.Using] {
+			return Schema{}, fmt.Errorf("%s.%s: %s column %q not found", ref.Table, ref.Column, ref.OnConflict, ref.Using)
+		}
+		if ref.keys, err = uniqueKeys(ctx, db, ref.Table, ref.Column); err != nil {
+			return Schema{}, err
+		}
+		resolved.References = append(resolved.References, ref)
+	}
+	return resolved, nil
+}
+
+// uniqueKeys returns, for each unique index on table that includes column, the
+// index's other columns; re-pointing a row collides when they match
+func uniqueKeys(ctx context.Context, db *sql.DB, table, column string) ([][]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM pragma_index_list(?) WHERE "unique" = 1`, table)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		indexes = append(indexes, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var keys [][]string
+	for _, index := range indexes {
+		cols, err := indexColumns(ctx, db, index)
+		if err != nil {
+			return nil, err
+		}
+		others, found := []string{}, false
+		for _, c := range cols {
+			if c == column {
+				found = true
+			} else {
+				others = append(others, c)
+			}
+		}
+		if found {
+			keys = append(keys, others)
+		}
+	}
+	return keys, nil
+}
+
+func indexColumns(ctx context.Context, db *sql.DB, index string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM pragma_index_info(?) ORDER BY seqno`, index)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var cols []string
+	for rows.Next() {
+		var name sql.NullString
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		cols = append(cols, name.String)
+	}
+	return cols, rows.Err()
+}
@@ -0,0 +1,511 @@
+package foodadmin
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/dbschema"
+	"nutrition-health-backend/internal/diaryimport"
+	"nutrition-health-backend/internal/outbox"
+	"nutrition-health-backend/internal/portions"
+	"nutrition-health-backend/internal/repo"
+	"nutrition-health-backend/internal/textmatch"
+	"nutrition-health-backend/internal/txn"
+)
+
+// Candidate statuses
+const (
+	StatusPending   = "pending"
+	StatusMerged    = "merged"
+	StatusDismissed = "dismissed"
+)
+
+// MaxBulkIDs caps the foods one bulk edit may touch
+const MaxBulkIDs = 1000
+
+// TopicFoodMerged is published on the outbox when a duplicate is merged away
+const TopicFoodMerged = "food.merged"
+
+var (
+	ErrNotFound  = errors.New("food not found")
+	ErrSameFood  = errors.New("canonical and duplicate must differ")
+	ErrConflict  = errors.New("references collide")
+	ErrCandidate = errors.New("merge candidate not found")
+)
+
+// Migrate creates the merge candidate and merge history tables
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS food_merge_candidates (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			a_id TEXT NOT NULL,
+			b_id TEXT NOT NULL,
+			details TEXT NOT NULL,
+			score REAL NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			detected_at DATETIME NOT NULL,
+			resolved_by TEXT,
+			resolved_at DATETIME,
+			UNIQUE (a_id, b_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_food_merge_candidates_status ON food_merge_candidates(status, score)`,
+		`CREATE TABLE IF NOT EXISTS food_merges (
+			duplicate_id TEXT PRIMARY KEY,
+			canonical_id TEXT NOT NULL,
+			repointed TEXT NOT NULL,
+			actor TEXT NOT NULL,
+			merged_at DATETIME NOT NULL
+		)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	// Ingredient lists feed ingredient-level symptom correlations
+	cols, err := dbschema.Columns(context.Background(), db, dbschema.Foods)
+	if err != nil {
+		return err
+	}
+	if cols != nil && !cols["ingredients"] {
+		if _, err := db.Exec(`ALTER TABLE foods ADD COLUMN ingredients TEXT`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NutrientFields are the per-100 g food fields, by API name, that bulk edits may set
+var NutrientFields = []string{"calories", "protein_g", "carbs_g", "fat_g", "fiber_g", "sugar_g", "sodium_mg"}
+
+// nutrientPtrs maps NutrientFields onto a food row
+func nutrientPtrs(f *repo.Food) map[string]*float64 {
+	return map[string]*float64{
+		"calories":  &f.Calories,
+		"protein_g": &f.Protein,
+		"carbs_g":   &f.Carbs,
+		"fat_g":     &f.Fat,
+		"fiber_g":   &f.Fiber,
+		"sugar_g":   &f.Sugar,
+		"sodium_mg": &f.Sodium,
+	}
+}
+
+// foodID parses a food ID; IDs that can't exist report ErrNotFound
+func foodID(id string) (int64, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return n, nil
+}
+
+// Service runs bulk edits, merges and duplicate detection
+type Service struct {
+	db     *sql.DB
+	txm    *txn.Manager
+	schema Schema
+}
+
+// NewService creates the food admin service
+func NewService(db *sql.DB, schema Schema) *Service {
+	return &Service{db: db, txm: txn.NewManager(db), schema: schema}
+}
+
+// Foods loads every food with its nutrients for detection
+func (s *Service) Foods(ctx context.Context) ([]Food, error) {
+	rows, err := repo.New(s.db).ListFoods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	foods := make([]Food, 0, len(rows))
+	for _, row := range rows {
+		f := Food{ID: strconv.FormatInt(row.ID, 10), Name: row.Name, Nutrients: make(map[string]float64, len(NutrientFields))}
+		for field, v := range nutrientPtrs(&row) {
+			f.Nutrients[field] = *v
+		}
+		foods = append(foods, f)
+	}
+	return foods, nil
+}
+
+// SaveCandidates records newly detected pairs and refreshes pending ones;
+// dismissed pairs stay dismissed
+func (s *Service) SaveCandidates(ctx context.Context, candidates []Candidate) (int, error) {
+	added := 0
+	err := s.txm.Do(ctx, func(ctx context.Context) error {
+		q := s.txm.Querier(ctx)
+		now := time.Now().UTC()
+		for _, c := range candidates {
+			details, err := json.Marshal(c)
+			if err != nil {
+				return err
+			}
+			res, err := q.ExecContext(ctx,
+				`INSERT OR IGNORE INTO food_merge_candidates (a_id, b_id, details, score, detected_at) VALUES (?, ?, ?, ?, ?)`,
+				c.A.ID, c.B.ID, string(details), c.Score, now)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				added++
+				continue
+			}
+			if _, err := q.ExecContext(ctx,
+				`UPDATE food_merge_candidates SET details = ?, score = ? WHERE a_id = ? AND b_id = ? AND status = ?`,
+				string(details), c.Score, c.A.ID, c.B.ID, StatusPending); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return added, err
+}
+
+// Candidates lists merge candidates by status, best first
+func (s *Service) Candidates(ctx context.Context, status string, limit int) ([]Candidate, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, details FROM food_merge_candidates WHERE status = ? ORDER BY score DESC, id LIMIT ?`, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	candidates := []Candidate{}
+	for rows.Next() {
+		var id int64
+		var details string
+		if err := rows.Scan(&id, &details); err != nil {
+			return nil, err
+		}
+		var c Candidate
+		if err := json.Unmarshal([]byte(details), &c); err != nil {
+			return nil, err
+		}
+		c.ID = id
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// Dismiss marks a candidate as not a duplicate so detection won't propose it again
+func (s *Service) Dismiss(ctx context.Context, id int64, actor string) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE food_merge_candidates SET status = ?, resolved_by = ?, resolved_at = ? WHERE id = ? AND status = ?`,
+		StatusDismissed, actor, time.Now().UTC(), id, StatusPending)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCandidate
+	}
+	return nil
+}
+
+// MergeResult reports how many rows each reference had re-pointed or dropped
+type MergeResult struct {
+	CanonicalID string           `json:"canonical_id"`
+	DuplicateID string           `json:"duplicate_id"`
+	Repointed   map[string]int64 `json:"repointed"`
+	// Combined counts duplicate rows folded into an existing canonical row:
+	// quantities added, or the newer of two prices kept
+	Combined map[string]int64 `json:"combined,omitempty"`
+	// Dropped counts rows removed because the canonical food was already
+	// referenced, e.g. a user who had favorited both
+	Dropped map[string]int64 `json:"dropped,omitempty"`
+}
+
+// repoint moves ref's rows from the duplicate to the canonical food, first
+// resolving rows that would collide on a unique index per ref.OnConflict
+func repoint(ctx context.Context, q txn.Querier, ref Reference, canonicalID, duplicateID string, result *MergeResult) error {
+	key := ref.Table + "." + ref.Column
+	for _, k := range ref.keys {
+		// same matches alias's key columns against the outer row's
+		same := func(alias string) string {
+			cond := ""
+			for _, col := range k {
+				cond += " AND " + alias + "." + col + " IS " + ref.Table + "." + col
+			}
+			return cond
+		}
+		var n int64
+		if err := q.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+ref.Table+` WHERE `+ref.Column+` = ? AND EXISTS
+			(SELECT 1 FROM `+ref.Table+` c WHERE c.`+ref.Column+` = ?`+same("c")+`)`,
+			duplicateID, canonicalID).Scan(&n); err != nil {
+			return fmt.Errorf("checking %s: %w", key, err)
+		}
+		if n == 0 {
+			continue
+		}
+
+		switch ref.OnConflict {
+		case ConflictSum:
+			if _, err := q.ExecContext(ctx, `UPDATE `+ref.Table+` SET `+ref.Using+` = `+ref.Using+` +
+				(SELECT SUM(d.`+ref.Using+`) FROM `+ref.Table+` d WHERE d.`+ref.Column+` = ?`+same("d")+`)
+				WHERE `+ref.Column+` = ? AND EXISTS (SELECT 1 FROM `+ref.Table+` d WHERE d.`+ref.Column+` = ?`+same("d")+`)`,
+				duplicateID, canonicalID, duplicateID); err != nil {
+				return fmt.Errorf("combining %s: %w", key, err)
+			}
+			result.Combined[key] += n
+		case ConflictNewest:
+			// Canonical rows older than their duplicate counterpart give way to it
+			if _, err := q.ExecContext(ctx, `DELETE FROM `+ref.Table+` WHERE `+ref.Column+` = ? AND EXISTS
+				(SELECT 1 FROM `+ref.Table+` d WHERE d.`+ref.Column+` = ?`+same("d")+` AND d.`+ref.Using+` > `+ref.Table+`.`+ref.Using+`)`,
+				canonicalID, duplicateID); err != nil {
+				return fmt.Errorf("combining %s: %w", key, err)
+			}
+			result.Combined[key] += n
+		case ConflictDrop:
+			result.Dropped[key] += n
+		default:
+			return fmt.Errorf("%w: %d %s rows of %s collide with %s", ErrConflict, n, ref.Table, duplicateID, canonicalID)
+		}
+		// Whatever still collides is now represented by the canonical row
+		if _, err := q.ExecContext(ctx, `DELETE FROM `+ref.Table+` WHERE `+ref.Column+` = ? AND EXISTS
+			(SELECT 1 FROM `+ref.Table+` c WHERE c.`+ref.Column+` = ?`+same("c")+`)`,
+			duplicateID, canonicalID); err != nil {
+			return fmt.Errorf("cleaning %s: %w", key, err)
+		}
+	}
+
+	res, err := q.ExecContext(ctx, `UPDATE `+ref.Table+` SET `+ref.Column+` = ? WHERE `+ref.Column+` = ?`, canonicalID, duplicateID)
+	if err != nil {
+		return fmt.Errorf("re-pointing %s: %w", key, err)
+	}
+	result.Repointed[key], _ = res.RowsAffected()
+	return nil
+}
+
+// Merge re-points every reference from duplicateID to canonicalID and deletes
+// the duplicate, in one transaction that also records the merge for redirects
+// and publishes food.merged on the outbox
+func (s *Service) Merge(ctx context.Context, canonicalID, duplicateID, actor string) (MergeResult, error) {
+	if canonicalID == duplicateID {
+		return MergeResult{}, ErrSameFood
+	}
+	schema, err := s.schema.Resolve(ctx, s.db)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	result := MergeResult{
+		CanonicalID: canonicalID,
+		DuplicateID: duplicateID,
+		Repointed:   map[string]int64{},
+		Combined:    map[string]int64{},
+		Dropped:     map[string]int64{},
+	}
+
+	err = s.txm.Do(ctx, func(ctx context.Context) error {
+		q := s.txm.Querier(ctx)
+		foods := repo.FromContext(ctx, s.db)
+		ids := map[string]int64{}
+		for _, id := range []string{canonicalID, duplicateID} {
+			n, err := foodID(id)
+			if err != nil {
+				return err
+			}
+			if _, err := foods.GetFood(ctx, n); errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrNotFound, id)
+			} else if err != nil {
+				return err
+			}
+			ids[id] = n
+		}
+		for _, ref := range schema.References {
+			if err := repoint(ctx, q, ref, canonicalID, duplicateID, &result); err != nil {
+				return err
+			}
+		}
+		if _, err := foods.DeleteFood(ctx, ids[duplicateID]); err != nil {
+			return err
+		}
+		repointed, _ := json.Marshal(result)
+		if _, err := q.ExecContext(ctx,
+			`INSERT INTO food_merges (duplicate_id, canonical_id, repointed, actor, merged_at) VALUES (?, ?, ?, ?, ?)`,
+			duplicateID, canonicalID, string(repointed), actor, time.Now().UTC()); err != nil {
+			return err
+		}
+		// Earlier merges into the duplicate now redirect to the canonical food
+		if _, err := q.ExecContext(ctx, `UPDATE food_merges SET canonical_id = ? WHERE canonical_id = ?`, canonicalID, duplicateID); err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx,
+			`UPDATE food_merge_candidates SET status = ?, resolved_by = ?, resolved_at = ? WHERE status = ? AND (a_id = ? OR b_id = ?)`,
+			StatusMerged, actor, time.Now().UTC(), StatusPending, duplicateID, duplicateID); err != nil {
+			return err
+		}
+		return outbox.Enqueue(ctx, q, TopicFoodMerged, duplicateID, result, nil)
+	})
+	if err != nil {
+		return MergeResult{}, err
+	}
+	return result, nil
+}
+
+// Canonical follows merge history, so clients holding a merged ID can resolve it
+func (s *Service) Canonical(ctx context.Context, id string) (string, error) {
+	var canonical string
+	err := s.db.QueryRowContext(ctx, `SELECT canonical_id FROM food_merges WHERE duplicate_id = ?`, id).Scan(&canonical)
+	if err == sql.ErrNoRows {
+		return id, nil
+	}
+	return canonical, err
+}
+
+// Food returns a food's nutrient profile, following merges; it implements
+// portions.FoodSource
+func (s *Service) Food(ctx context.Context, id string) (portions.Food, error) {
+	id, err := s.Canonical(ctx, id)
+	if err != nil {
+		return portions.Food{}, err
+	}
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return portions.Food{}, portions.ErrFoodNotFound
+	}
+	f, err := repo.New(s.db).GetFood(ctx, n)
+	if errors.Is(err, sql.ErrNoRows) {
+		return portions.Food{}, portions.ErrFoodNotFound
+	}
+	if err != nil {
+		return portions.Food{}, err
+	}
+	return portions.Food{
+		ID:   id,
+		Name: f.Name,
+		Per100g: portions.Macros{
+			Calories: f.Calories,
+			ProteinG: f.Protein,
+			CarbsG:   f.Carbs,
+			FatG:     f.Fat,
+			FiberG:   f.Fiber,
+			SugarG:   f.Sugar,
+			SodiumMg: f.Sodium,
+		},
+	}, nil
+}
+
+// BulkEdit sets nutrient fields on up to MaxBulkIDs foods in one transaction
+func (s *Service) BulkEdit(ctx context.Context, ids []string, set map[string]float64) (int64, error) {
+	if len(ids) == 0 || len(ids) > MaxBulkIDs {
+		return 0, fmt.Errorf("between 1 and %d ids are required", MaxBulkIDs)
+	}
+	if len(set) == 0 {
+		return 0, fmt.Errorf("no fields to set")
+	}
+	var probe repo.Food
+	known := nutrientPtrs(&probe)
+	for field, v := range set {
+		if _, ok := known[field]; !ok {
+			return 0, fmt.Errorf("unknown field %q", field)
+		}
+		if v < 0 {
+			return 0, fmt.Errorf("%s must not be negative", field)
+		}
+	}
+
+	var updated int64
+	err := s.txm.Do(ctx, func(ctx context.Context) error {
+		q := repo.FromContext(ctx, s.db)
+		for _, id := range ids {
+			n, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				continue
+			}
+			f, err := q.GetFood(ctx, n)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			fields := nutrientPtrs(&f)
+			for field, v := range set {
+				*fields[field] = v
+			}
+			rows, err := q.UpdateFoodNutrients(ctx, repo.UpdateFoodNutrientsParams{
+				Calories: f.Calories,
+				Protein:  f.Protein,
+				Carbs:    f.Carbs,
+				Fat:      f.Fat,
+				Fiber:    f.Fiber,
+				Sugar:    f.Sugar,
+				Sodium:   f.Sodium,
+				ID:       f.ID,
+			})
+			if err != nil {
+				return err
+			}
+			updated += rows
+		}
+		return nil
+	})
+	return updated, err
+}
+
+// CreateStub inserts a food with only a name and zeroed nutrients for the
+// content team to complete; it implements searchgaps.FoodCreator. The
+// language is only kept on the search gap resolution.
+func (s *Service) CreateStub(ctx context.Context, name, _ string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	f, err := repo.New(s.db).CreateFood(ctx, repo.CreateFoodParams{Name: name})
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(f.ID, 10), nil
+}
+
+// searchWords caps the name words Search looks up, longest first
+const searchWords = 4
+
+// Search finds foods sharing a word with query, best name match first; it
+// implements diaryimport.Matcher
+func (s *Service) Search(ctx context.Context, query string, limit int) ([]diaryimport.Candidate, error) {
+	norm := textmatch.Normalize(query)
+	words := strings.Fields(norm)
+	sort.SliceStable(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+	if len(words) > searchWords {
+		words = words[:searchWords]
+	}
+
+	q := repo.New(s.db)
+	seen := map[int64]bool{}
+	var found []diaryimport.Candidate
+	for _, w := range words {
+		if len([]rune(w)) < 3 {
+			continue
+		}
+		rows, err := q.SearchFoods(ctx, repo.SearchFoodsParams{Name: "%" + w + "%", Limit: 50})
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range rows {
+			if seen[f.ID] {
+				continue
+			}
+			seen[f.ID] = true
+			found = append(found, diaryimport.Candidate{
+				FoodID: strconv.FormatInt(f.ID, 10),
+				Name:   f.Name,
+				Score:  textmatch.BestSimilarity(norm, textmatch.Normalize(f.Name)),
+			})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Score > found[j].Score })
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// DBCheck pings SQLite with a trivial query; slow answers are degraded
+func DBCheck(db *sql.DB, slow time.Duration) Checker {
+	return Func("database", true, func(ctx context.Context) Component {
+		start := time.Now()
+		var one int
+		if err := db.QueryRowContext(ctx, `SELECT 1`).Scan(&one); err != nil {
+			return Component{Status: Failed, Error: err.Error()}
+		}
+		latency := time.Since(start)
+		stats := db.Stats()
+		comp := Component{
+			Status:  OK,
+			Latency: milliseconds(latency),
+			Details: map[string]interface{}{
+				"open_connections": stats.OpenConnections,
+				"in_use":           stats.InUse,
+				"wait_count":       stats.WaitCount,
+			},
+		}
+		if latency > slow {
+			comp.Status = Degraded
+		}
+		return comp
+	})
+}
+
+// RedisCheck pings Redis. Redis is a cache here, so it is never critical and
+// a missing client is reported as degraded.
+func RedisCheck(client *redis.Client, slow time.Duration) Checker {
+	return Func("redis", false, func(ctx context.Context) Component {
+		if client == nil {
+			return Component{Status: Degraded, Error: "not configured"}
+		}
+		start := time.Now()
+		if err := client.Ping(ctx).Err(); err != nil {
+			return Component{Status: Failed, Error: err.Error()}
+		}
+		latency := time.Since(start)
+		comp := Component{Status: OK, Latency: milliseconds(latency)}
+		if latency > slow {
+			comp.Status = Degraded
+		}
+		return comp
+	})
+}
@@ -0,0 +1,12 @@
+//go:build windows
+
+package health
+
+import "context"
+
+// DiskCheck is unsupported on this platform and always reports degraded
+func DiskCheck(name, path string, critical bool, degradedPct, failedPct float64) Checker {
+	return Func(name, critical, func(ctx context.Context) Component {
+		return Component{Status: Degraded, Error: "disk check not supported on this platform"}
+	})
+}
@@ -0,0 +1,44 @@
+//go:build !windows
+
+package health
+
+import (
+	"context"
+	"math"
+	"syscall"
+)
+
+// DiskCheck reports free space on the filesystem holding path. Below
+// degradedPct free it is degraded; below failedPct it fails, since SQLite
+// writes start erroring once the disk is full.
+func DiskCheck(name, path string, critical bool, degradedPct, failedPct float64) Checker {
+	return Func(name, critical, func(ctx context.Context) Component {
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(path, &st); err != nil {
+			return Component{Status: Failed, Error: err.Error()}
+		}
+		total := st.Blocks * uint64(st.Bsize)
+		free := st.Bavail * uint64(st.Bsize)
+		pct := 0.0
+		if total > 0 {
+			pct = float64(free) / float64(total) * 100
+		}
+
+		comp := Component{
+			Status: OK,
+			Details: map[string]interface{}{
+				"path":        path,
+				"free_bytes":  free,
+				"total_bytes": total,
+				"free_pct":    math.Round(pct*10) / 10,
+			},
+		}
+		switch {
+		case pct < failedPct:
+			comp.Status = Failed
+		case pct < degradedPct:
+			comp.Status = Degraded
+		}
+		return comp
+	})
+}
@@ -0,0 +1,32 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Components serves the full component report; 503 when a critical component failed
+func (r *Registry) Components(c echo.Context) error {
+	report := r.Run(c.Request().Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, report)
+}
+
+// Readiness wraps an existing readiness handler: a failed critical component
+// answers 503 with the report, otherwise next decides
+func (r *Registry) Readiness(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report := r.Run(c.Request().Context())
+		if !report.Ready() {
+			return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+				"status":     "not_ready",
+				"components": report.Components,
+			})
+		}
+		return next(c)
+	}
+}
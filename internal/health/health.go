@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status is the state of one component or of the whole service
+type Status string
+
+const (
+	OK       Status = "ok"
+	Degraded Status = "degraded" // working, but slow or close to a limit
+	Failed   Status = "failed"
+)
+
+// Component is the result of one dependency check
+type Component struct {
+	Name     string                 `json:"name"`
+	Status   Status                 `json:"status"`
+	Critical bool                   `json:"critical"`
+	Latency  float64                `json:"latency_ms"`
+	Error    string                 `json:"error,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// Report is the aggregated component status
+type Report struct {
+	Status     Status      `json:"status"`
+	Components []Component `json:"components"`
+	CheckedAt  time.Time   `json:"checked_at"`
+}
+
+// Ready reports whether the service should receive traffic: only a failed
+// critical component takes it out of rotation, degraded ones do not
+func (r Report) Ready() bool {
+	return r.Status != Failed
+}
+
+// Checker checks one dependency
+type Checker interface {
+	Name() string
+	Critical() bool
+	Check(ctx context.Context) Component
+}
+
+// checkTimeout bounds each check so one hung dependency can't stall the probe
+const checkTimeout = 2 * time.Second
+
+// Registry runs the registered checks concurrently
+type Registry struct {
+	mu       sync.RWMutex
+	checkers []Checker
+}
+
+// NewRegistry creates a registry with the given checks
+func NewRegistry(checkers ...Checker) *Registry {
+	return &Registry{checkers: checkers}
+}
+
+// Add registers another check
+func (r *Registry) Add(c Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, c)
+}
+
+// Run checks every component. Failed non-critical components degrade the
+// overall status; failed critical ones fail it.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checkers := append([]Checker{}, r.checkers...)
+	r.mu.RUnlock()
+
+	components := make([]Component, len(checkers))
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c Checker) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			start := time.Now()
+			comp := c.Check(cctx)
+			comp.Name, comp.Critical = c.Name(), c.Critical()
+			if comp.Latency == 0 {
+				comp.Latency = milliseconds(time.Since(start))
+			}
+			if comp.Status == "" {
+				comp.Status = OK
+			}
+			components[i] = comp
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: OK, Components: components, CheckedAt: time.Now().UTC()}
+	for _, comp := range components {
+		switch {
+		case comp.Status == Failed && comp.Critical:
+			report.Status = Failed
+		case comp.Status != OK && report.Status == OK:
+			report.Status = Degraded
+		}
+	}
+	return report
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Func adapts a function into a Checker, for components owned by other packages
+func Func(name string, critical bool, check func(ctx context.Context) Component) Checker {
+	return funcChecker{name: name, critical: critical, check: check}
+}
+
+type funcChecker struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) Component
+}
+
+func (f funcChecker) Name() string                        { return f.name }
+func (f funcChecker) Critical() bool                      { return f.critical }
+func (f funcChecker) Check(ctx context.Context) Component { return f.check(ctx) }
@@ -0,0 +1,138 @@
+package healthrecords
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes health profiles and readings over HTTP
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a health records handler
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes mounts the /health routes on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/health/profile", h.GetProfile)
+	g.PUT("/health/profile", h.SaveProfile)
+	g.GET("/health/readings", h.ListReadings)
+	g.POST("/health/readings", h.AddReading)
+	g.DELETE("/health/readings/:id", h.DeleteReading)
+}
+
+func userID(c echo.Context) (string, error) {
+	id := reqctx.UserID(c)
+	if id == "" {
+		return "", echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	return id, nil
+}
+
+// GetProfile returns the caller's medications and conditions
+func (h *Handler) GetProfile(c echo.Context) error {
+	id, err := userID(c)
+	if err != nil {
+		return err
+	}
+	p, err := h.store.Profile(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, p)
+}
+
+// SaveProfile replaces the caller's medications and conditions
+func (h *Handler) SaveProfile(c echo.Context) error {
+	id, err := userID(c)
+	if err != nil {
+		return err
+	}
+	var p Profile
+	if err := c.Bind(&p); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := p.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	p, err = h.store.SaveProfile(c.Request().Context(), id, p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, p)
+}
+
+// ListReadings returns readings for the last ?days= days (default 30), optionally one ?kind=
+func (h *Handler) ListReadings(c echo.Context) error {
+	id, err := userID(c)
+	if err != nil {
+		return err
+	}
+	days := 30
+	if err := echo.QueryParamsBinder(c).Int("days", &days).BindError(); err != nil || days <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "days must be a positive integer")
+	}
+	kind := Kind(c.QueryParam("kind"))
+	if _, ok := Units[kind]; kind != "" && !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown reading kind")
+	}
+	now := time.Now().UTC()
+	readings, err := h.store.Readings(c.Request().Context(), id, kind, now.AddDate(0, 0, -days), now.Add(24*time.Hour))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"readings": readings})
+}
+
+// AddReading records a reading; taken_at defaults to now
+func (h *Handler) AddReading(c echo.Context) error {
+	id, err := userID(c)
+	if err != nil {
+		return err
+	}
+	var r Reading
+	if err := c.Bind(&r); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	r.UserID = id
+	if r.TakenAt.IsZero() {
+		r.TakenAt = time.Now().UTC()
+	}
+	if err := r.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	r, err = h.store.AddReading(c.Request().Context(), r)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, r)
+}
+
+// DeleteReading removes one of the caller's readings
+func (h *Handler) DeleteReading(c echo.Context) error {
+	id, err := userID(c)
+	if err != nil {
+		return err
+	}
+	readingID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid reading id")
+	}
+	err = h.store.DeleteReading(c.Request().Context(), id, readingID)
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,106 @@
+// Package healthrecords keeps the health details users share for their plans:
+// medications, conditions and readings such as blood glucose. Every value is
+// encrypted at rest with the user's field key; only reading kinds and times
+// are stored in the clear, so readings can be listed by range.
+package healthrecords
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Profile is a user's medications and conditions
+type Profile struct {
+	Medications []string  `json:"medications"`
+	Conditions  []string  `json:"conditions"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// maxProfileItems caps each profile list
+const maxProfileItems = 50
+
+// Validate checks a profile before it is stored
+func (p Profile) Validate() error {
+	for name, list := range map[string][]string{"medications": p.Medications, "conditions": p.Conditions} {
+		if len(list) > maxProfileItems {
+			return fmt.Errorf("at most %d %s", maxProfileItems, name)
+		}
+		for _, item := range list {
+			if strings.TrimSpace(item) == "" || len(item) > 200 {
+				return fmt.Errorf("%s must be between 1 and 200 characters", name)
+			}
+		}
+	}
+	return nil
+}
+
+// Kind is a type of health reading
+type Kind string
+
+const (
+	Glucose       Kind = "glucose"
+	Ketones       Kind = "ketones"
+	HeartRate     Kind = "heart_rate"
+	SystolicBP    Kind = "systolic_bp"
+	DiastolicBP   Kind = "diastolic_bp"
+	HbA1c         Kind = "hba1c"
+	BodyFatPct    Kind = "body_fat_pct"
+	Cholesterol   Kind = "cholesterol"
+	Triglycerides Kind = "triglycerides"
+)
+
+// Units lists the units accepted for each kind, the first being the default
+var Units = map[Kind][]string{
+	Glucose:       {"mg/dL", "mmol/L"},
+	Ketones:       {"mmol/L"},
+	HeartRate:     {"bpm"},
+	SystolicBP:    {"mmHg"},
+	DiastolicBP:   {"mmHg"},
+	HbA1c:         {"%", "mmol/mol"},
+	BodyFatPct:    {"%"},
+	Cholesterol:   {"mg/dL", "mmol/L"},
+	Triglycerides: {"mg/dL", "mmol/L"},
+}
+
+// Reading is one measurement
+type Reading struct {
+	ID      int64     `json:"id"`
+	UserID  string    `json:"-"`
+	Kind    Kind      `json:"kind"`
+	Value   float64   `json:"value"`
+	Unit    string    `json:"unit"`
+	Notes   string    `json:"notes,omitempty"`
+	TakenAt time.Time `json:"taken_at"`
+}
+
+// Validate checks a reading before it is stored, defaulting its unit
+func (r *Reading) Validate() error {
+	units, ok := Units[r.Kind]
+	if !ok {
+		return fmt.Errorf("unknown reading kind %q", r.Kind)
+	}
+	if r.Unit == "" {
+		r.Unit = units[0]
+	}
+	known := false
+	for _, u := range units {
+		known = known || u == r.Unit
+	}
+	if !known {
+		return fmt.Errorf("%s is measured in %s", r.Kind, strings.Join(units, " or "))
+	}
+	if r.Value <= 0 || r.Value > 10000 {
+		return fmt.Errorf("value must be between 0 and 10000")
+	}
+	if r.TakenAt.IsZero() {
+		return fmt.Errorf("taken_at is required")
+	}
+	if r.TakenAt.After(time.Now().Add(24 * time.Hour)) {
+		return fmt.Errorf("taken_at is too far in the future")
+	}
+	if len(r.Notes) > 1000 {
+		return fmt.Errorf("notes must be at most 1000 characters")
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package healthrecords
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"nutrition-health-backend/internal/fieldcrypt"
+)
+
+// Encrypted fields
+const (
+	medicationsField = "health_profiles.medications"
+	conditionsField  = "health_profiles.conditions"
+	valueField       = "health_readings.value"
+	notesField       = "health_readings.notes"
+)
+
+// ErrNotFound is returned when a reading does not exist for the user
+var ErrNotFound = errors.New("reading not found")
+
+// Store persists health profiles and readings, sealing every value with the
+// cipher before it is written and opening it after it is read
+type Store struct {
+	db     *sql.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewStore creates a health records store
+func NewStore(db *sql.DB, cipher *fieldcrypt.Cipher) *Store {
+	return &Store{db: db, cipher: cipher}
+}
+
+// Migrate creates the health profile and reading tables
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS health_profiles (
+			user_id TEXT PRIMARY KEY,
+			medications TEXT NOT NULL DEFAULT '',
+			conditions TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS health_readings (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			value TEXT NOT NULL,
+			unit TEXT NOT NULL,
+			notes TEXT NOT NULL DEFAULT '',
+			taken_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_health_readings_user_kind ON health_readings(user_id, kind, taken_at)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("health records migration: %w", err)
+		}
+	}
+	return nil
+}
+
+// Profile returns the user's profile; users without one get an empty profile
+func (s *Store) Profile(ctx context.Context, userID string) (Profile, error) {
+	var medications, conditions string
+	var p Profile
+	err := s.db.QueryRowContext(ctx,
+		`SELECT medications, conditions, updated_at FROM health_profiles WHERE user_id = ?`,
+		userID).Scan(&medications, &conditions, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Profile{Medications: []string{}, Conditions: []string{}}, nil
+	}
+	if err != nil {
+		return Profile{}, err
+	}
+	if err := s.cipher.Open(ctx, userID, map[string]*string{medicationsField: &medications, conditionsField: &conditions}); err != nil {
+		return Profile{}, err
+	}
+	if p.Medications, err = decodeList(medications); err != nil {
+		return Profile{}, err
+	}
+	if p.Conditions, err = decodeList(conditions); err != nil {
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// SaveProfile replaces the user's profile
+func (s *Store) SaveProfile(ctx context.Context, userID string, p Profile) (Profile, error) {
+	if err := p.Validate(); err != nil {
+		return Profile{}, err
+	}
+	medications, err := encodeList(p.Medications)
+	if err != nil {
+		return Profile{}, err
+	}
+	conditions, err := encodeList(p.Conditions)
+	if err != nil {
+		return Profile{}, err
+	}
+	if err := s.cipher.Seal(ctx, userID, map[string]*string{medicationsField: &medications, conditionsField: &conditions}); err != nil {
+		return Profile{}, fmt.Errorf("failed to encrypt health profile: %w", err)
+	}
+	p.UpdatedAt = time.Now().UTC()
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO health_profiles (user_id, medications, conditions, updated_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET medications = excluded.medications,
+		 	conditions = excluded.conditions, updated_at = excluded.updated_at`,
+		userID, medications, conditions, p.UpdatedAt)
+	if err != nil {
+		return Profile{}, err
+	}
+	if p.Medications == nil {
+		p.Medications = []string{}
+	}
+	if p.Conditions == nil {
+		p.Conditions = []string{}
+	}
+	return p, nil
+}
+
+func encodeList(list []string) (string, error) {
+	if len(list) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(list)
+	return string(b), err
+}
+
+func decodeList(s string) ([]string, error) {
+	list := []string{}
+	if s == "" {
+		return list, nil
+	}
+	if err := json.Unmarshal([]byte(s), &list); err != nil {
+		return nil, fmt.Errorf("corrupt health profile: %w", err)
+	}
+	return list, nil
+}
+
+// AddReading stores a reading and returns it with its ID
+func (s *Store) AddReading(ctx context.Context, r Reading) (Reading, error) {
+	if err := r.Validate(); err != nil {
+		return Reading{}, err
+	}
+	value, notes := strconv.FormatFloat(r.Value, 'f', -1, 64), r.Notes
+	if err := s.cipher.Seal(ctx, r.UserID, map[string]*string{valueField: &value, notesField: &notes}); err != nil {
+		return Reading{}, fmt.Errorf("failed to encrypt reading: %w", err)
+	}
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO health_readings (user_id, kind, value, unit, notes, taken_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		r.UserID, string(r.Kind), value, r.Unit, notes, r.TakenAt.UTC())
+	if err != nil {
+		return Reading{}, fmt.Errorf("failed to store reading: %w", err)
+	}
+	r.ID, err = res.LastInsertId()
+	return r, err
+}
+
+// Readings returns the user's readings taken in [from, to), oldest first;
+// an empty kind returns every kind
+func (s *Store) Readings(ctx context.Context, userID string, kind Kind, from, to time.Time) ([]Reading, error) {
+	query := `SELECT id, kind, value, unit, notes, taken_at FROM health_readings
+		WHERE user_id = ? AND taken_at >= ? AND taken_at < ?`
+	args := []interface{}{userID, from.UTC(), to.UTC()}
+	if kind != "" {
+		query += ` AND kind = ?`
+		args = append(args, string(kind))
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY taken_at, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query readings: %w", err)
+	}
+	defer rows.Close()
+
+	readings := []Reading{}
+	for rows.Next() {
+		r := Reading{UserID: userID}
+		var k, value string
+		if err := rows.Scan(&r.ID, &k, &value, &r.Unit, &r.Notes, &r.TakenAt); err != nil {
+			return nil, err
+		}
+		if err := s.cipher.Open(ctx, userID, map[string]*string{valueField: &value, notesField: &r.Notes}); err != nil {
+			return nil, err
+		}
+		if r.Value, err = strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("corrupt reading %d: %w", r.ID, err)
+		}
+		r.Kind = Kind(k)
+		readings = append(readings, r)
+	}
+	return readings, rows.Err()
+}
+
+// DeleteReading removes one of the user's readings
+func (s *Store) DeleteReading(ctx context.Context, userID string, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM health_readings WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package insights
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"nutrition-health-backend/internal/localtime"
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes weekly insights over HTTP
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates an insights handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes mounts the insights routes on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/insights", h.Get)
+}
+
+// Get returns the latest weekly report, or ?week=YYYY-MM-DD for the week starting that Monday
+func (h *Handler) Get(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	ctx := c.Request().Context()
+	var r Report
+	var err error
+	if week := c.QueryParam("week"); week != "" {
+		if _, perr := time.Parse(localtime.DateLayout, week); perr != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "week must be a date in YYYY-MM-DD format")
+		}
+		r, err = h.service.Get(ctx, userID, week)
+	} else {
+		r, err = h.service.Latest(ctx, userID)
+	}
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,238 @@
+package insights
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/nutrition"
+)
+
+// Insight kinds
+const (
+	Trend       = "trend"
+	OverTarget  = "over_target"
+	UnderTarget = "under_target"
+)
+
+// Trend directions
+const (
+	Up   = "up"
+	Down = "down"
+)
+
+// Day is one local day's logged totals
+type Day struct {
+	Date      string              `json:"date"`
+	Nutrients nutrition.Nutrients `json:"nutrients"`
+}
+
+// logged reports whether anything was logged that day
+func (d Day) logged() bool {
+	return d.Nutrients.Calories > 0
+}
+
+// Insight is one observation about a week, in English and Arabic
+type Insight struct {
+	Kind     string `json:"kind"`
+	Nutrient string `json:"nutrient"`
+	// Direction and ChangePct describe a week-over-week trend of the daily average
+	Direction string `json:"direction,omitempty"`
+	ChangePct int    `json:"change_pct,omitempty"`
+	// Days of OutOf logged days were off target
+	Days   int     `json:"days,omitempty"`
+	OutOf  int     `json:"out_of,omitempty"`
+	Text   string  `json:"text"`
+	TextAr string  `json:"text_ar"`
+	score  float64 // ranks insights, most notable first
+}
+
+// Report is a user's insights for one local week
+type Report struct {
+	UserID      string    `json:"user_id"`
+	WeekStart   string    `json:"week_start"`
+	WeekEnd     string    `json:"week_end"`
+	LoggedDays  int       `json:"logged_days"`
+	Insights    []Insight `json:"insights"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// Options tune which changes are worth reporting
+type Options struct {
+	// TrendThreshold is the relative change in the daily average reported as a trend
+	TrendThreshold float64
+	// MinDays is how many logged days each week needs before trends are compared
+	MinDays int
+	// TargetDays is how many days off target are reported
+	TargetDays int
+	// Nutrients are the nutrients checked for trends
+	Nutrients []string
+	// Max caps the insights per report
+	Max int
+}
+
+// LoadOptions reads INSIGHTS_* settings
+func LoadOptions() Options {
+	return Options{
+		TrendThreshold: envconfig.Float("INSIGHTS_TREND_THRESHOLD", 0.15),
+		MinDays:        envconfig.Int("INSIGHTS_MIN_DAYS", 3),
+		TargetDays:     envconfig.Int("INSIGHTS_TARGET_DAYS", 3),
+		Nutrients:      envconfig.List("INSIGHTS_NUTRIENTS", []string{"calories", "protein_g", "carbs_g", "fat_g", "fiber_g", "sugar_g", "sodium_mg"}),
+		Max:            envconfig.Int("INSIGHTS_MAX", 5),
+	}
+}
+
+// Validate checks the options
+func (o Options) Validate() error {
+	if o.TrendThreshold <= 0 || o.TrendThreshold >= 1 {
+		return fmt.Errorf("INSIGHTS_TREND_THRESHOLD must be between 0 and 1")
+	}
+	if o.MinDays < 1 || o.MinDays > 7 {
+		return fmt.Errorf("INSIGHTS_MIN_DAYS must be between 1 and 7")
+	}
+	if o.TargetDays < 1 || o.TargetDays > 7 {
+		return fmt.Errorf("INSIGHTS_TARGET_DAYS must be between 1 and 7")
+	}
+	if o.Max < 1 {
+		return fmt.Errorf("INSIGHTS_MAX must be at least 1")
+	}
+	known := nutrition.Nutrients{}.Map()
+	for _, n := range o.Nutrients {
+		if _, ok := known[n]; !ok {
+			return fmt.Errorf("INSIGHTS_NUTRIENTS: unknown nutrient %q", n)
+		}
+	}
+	return nil
+}
+
+// Analyze compares a week with the one before it and with the user's daily
+// bounds. Days with nothing logged are left out, so a missed day doesn't read
+// as a drop.
+func Analyze(week, previous []Day, bounds []nutrition.Bound, opts Options) []Insight {
+	current := loggedDays(week)
+	var out []Insight
+
+	if before := loggedDays(previous); len(current) >= opts.MinDays && len(before) >= opts.MinDays {
+		now, then := average(current), average(before)
+		for _, n := range opts.Nutrients {
+			if then[n] <= 0 {
+				continue
+			}
+			change := (now[n] - then[n]) / then[n]
+			if math.Abs(change) < opts.TrendThreshold {
+				continue
+			}
+			out = append(out, trend(n, change))
+		}
+	}
+
+	if len(current) > 0 {
+		over, under := map[string]int{}, map[string]int{}
+		for _, d := range current {
+			for _, a := range nutrition.Adherence(d.Nutrients, nil, bounds).Nutrients {
+				switch a.Status {
+				case nutrition.Over:
+					over[a.Nutrient]++
+				case nutrition.Under:
+					under[a.Nutrient]++
+				}
+			}
+		}
+		for _, b := range bounds {
+			if days := over[b.Nutrient]; days >= opts.TargetDays {
+				out = append(out, offTarget(OverTarget, b.Nutrient, days, len(current)))
+			}
+			if days := under[b.Nutrient]; days >= opts.TargetDays {
+				out = append(out, offTarget(UnderTarget, b.Nutrient, days, len(current)))
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].score > out[j].score })
+	if len(out) > opts.Max {
+		out = out[:opts.Max]
+	}
+	return out
+}
+
+func loggedDays(days []Day) []Day {
+	var out []Day
+	for _, d := range days {
+		if d.logged() {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func average(days []Day) map[string]float64 {
+	var total nutrition.Nutrients
+	for _, d := range days {
+		total = total.Add(d.Nutrients)
+	}
+	return total.Scale(1 / float64(len(days))).Map()
+}
+
+func trend(nutrient string, change float64) Insight {
+	pct := int(math.Round(math.Abs(change) * 100))
+	l := labelFor(nutrient)
+	in := Insight{Kind: Trend, Nutrient: nutrient, ChangePct: pct, score: math.Abs(change)}
+	if change < 0 {
+		in.Direction = Down
+		in.Text = fmt.Sprintf("%s intake dropped %d%% this week", l.intake, pct)
+		in.TextAr = fmt.Sprintf("انخفض تناول %s بنسبة %d%% هذا الأسبوع", l.ar, pct)
+	} else {
+		in.Direction = Up
+		in.Text = fmt.Sprintf("%s intake rose %d%% this week", l.intake, pct)
+		in.TextAr = fmt.Sprintf("ارتفع تناول %s بنسبة %d%% هذا الأسبوع", l.ar, pct)
+	}
+	return in
+}
+
+func offTarget(kind, nutrient string, days, outOf int) Insight {
+	l := labelFor(nutrient)
+	// Days off target outrank all but the largest trends
+	in := Insight{Kind: kind, Nutrient: nutrient, Days: days, OutOf: outOf, score: 0.5 + float64(days)/float64(outOf)}
+	if kind == OverTarget {
+		in.Text = fmt.Sprintf("%s above target %d of %d days", l.name, days, outOf)
+		in.TextAr = fmt.Sprintf("%s أعلى من الهدف في %d من %d أيام", l.ar, days, outOf)
+	} else {
+		in.Text = fmt.Sprintf("%s below target %d of %d days", l.name, days, outOf)
+		in.TextAr = fmt.Sprintf("%s أقل من الهدف في %d من %d أيام", l.ar, days, outOf)
+	}
+	return in
+}
+
+type label struct {
+	name, intake, ar string
+}
+
+var labels = map[string]label{
+	"calories":  {"Calories", "Calorie", "السعرات الحرارية"},
+	"protein_g": {"Protein", "Protein", "البروتين"},
+	"carbs_g":   {"Carbs", "Carb", "الكربوهيدرات"},
+	"fat_g":     {"Fat", "Fat", "الدهون"},
+	"fiber_g":   {"Fiber", "Fiber", "الألياف"},
+	"sugar_g":   {"Sugar", "Sugar", "السكر"},
+	"sodium_mg": {"Sodium", "Sodium", "الصوديوم"},
+}
+
+// labelFor names a nutrient; limits on nutrients without a label, such as
+// saturated_fat_g, fall back to the key without its unit
+func labelFor(nutrient string) label {
+	if l, ok := labels[nutrient]; ok {
+		return l
+	}
+	name := nutrient
+	for _, unit := range []string{"_mg", "_mcg", "_g"} {
+		name = strings.TrimSuffix(name, unit)
+	}
+	name = strings.ReplaceAll(name, "_", " ")
+	if name != "" {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return label{name: name, intake: name, ar: name}
+}
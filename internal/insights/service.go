@@ -0,0 +1,188 @@
+package insights
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/localtime"
+	"nutrition-health-backend/internal/nutrition"
+)
+
+// Diary provides logged intake, typically backed by the diary
+type Diary interface {
+	// ActiveUsers returns the users with entries since the given time
+	ActiveUsers(ctx context.Context, since time.Time) ([]string, error)
+	// DailyTotals returns the totals for each local day in [from, to) with entries
+	DailyTotals(ctx context.Context, userID string, from, to time.Time, loc *time.Location) ([]Day, error)
+}
+
+// Targets resolves a user's daily nutrient bounds, e.g. from targets.Targets.Constraints
+type Targets interface {
+	Bounds(ctx context.Context, userID string) ([]nutrition.Bound, error)
+}
+
+// Locator resolves a user's timezone; implemented by localtime.Store
+type Locator interface {
+	Location(ctx context.Context, userID string) (*time.Location, error)
+}
+
+// Service produces weekly insights from diary data. Weeks run Monday to
+// Sunday in the user's timezone; a week's report is generated SendHour hours
+// after it ends, so notifications don't arrive at midnight.
+type Service struct {
+	store   *Store
+	diary   Diary
+	targets Targets
+	locator Locator
+	opts    Options
+	// Interval is how often users are checked for a finished week (INSIGHTS_POLL_INTERVAL)
+	Interval time.Duration
+	// SendHour is the local hour on Monday from which reports are generated (INSIGHTS_SEND_HOUR)
+	SendHour int
+	// Notify publishes each new report on TopicInsights (INSIGHTS_NOTIFY)
+	Notify bool
+}
+
+// NewService creates the insights service; with nil targets only trends are reported
+func NewService(store *Store, diary Diary, targets Targets, locator Locator) *Service {
+	return &Service{
+		store:    store,
+		diary:    diary,
+		targets:  targets,
+		locator:  locator,
+		opts:     LoadOptions(),
+		Interval: envconfig.Duration("INSIGHTS_POLL_INTERVAL", time.Hour),
+		SendHour: envconfig.Int("INSIGHTS_SEND_HOUR", 8),
+		Notify:   envconfig.Bool("INSIGHTS_NOTIFY", true),
+	}
+}
+
+// Start generates reports for finished weeks every Interval until the context is cancelled
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunOnce(ctx, time.Now()); err != nil {
+				log.Printf("⚠️ Insights job failed: %v", err)
+			}
+		}
+	}
+}
+
+// RunOnce generates the reports that are due at now and returns how many were written
+func (s *Service) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	users, err := s.diary.ActiveUsers(ctx, now.Add(-14*24*time.Hour))
+	if err != nil {
+		return 0, err
+	}
+	written := 0
+	for _, userID := range users {
+		if ctx.Err() != nil {
+			return written, ctx.Err()
+		}
+		ok, err := s.runUser(ctx, userID, now)
+		if err != nil {
+			log.Printf("⚠️ Insights failed for user %s: %v", userID, err)
+		}
+		if ok {
+			written++
+		}
+	}
+	if written > 0 {
+		log.Printf("💡 Generated weekly insights for %d users", written)
+	}
+	return written, nil
+}
+
+func (s *Service) runUser(ctx context.Context, userID string, now time.Time) (bool, error) {
+	loc, err := s.locator.Location(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	start, end := lastWeek(now, loc)
+	if now.Before(end.Add(time.Duration(s.SendHour) * time.Hour)) {
+		return false, nil
+	}
+	if done, err := s.store.Exists(ctx, userID, localtime.Date(start, loc)); err != nil || done {
+		return false, err
+	}
+	r, err := s.generate(ctx, userID, start, end, loc)
+	if err != nil {
+		return false, err
+	}
+	return true, s.store.Save(ctx, r, s.Notify)
+}
+
+// Generate builds the report for the last finished week without storing it
+func (s *Service) Generate(ctx context.Context, userID string, now time.Time) (Report, error) {
+	loc, err := s.locator.Location(ctx, userID)
+	if err != nil {
+		return Report{}, err
+	}
+	start, end := lastWeek(now, loc)
+	return s.generate(ctx, userID, start, end, loc)
+}
+
+// Latest returns the user's newest report. A user with no stored report, e.g.
+// one who started logging this week, gets last week's generated on demand;
+// it isn't stored, so the job still saves and announces it when it's due.
+func (s *Service) Latest(ctx context.Context, userID string) (Report, error) {
+	r, err := s.store.Latest(ctx, userID)
+	if err != ErrNotFound || s.diary == nil {
+		return r, err
+	}
+	return s.Generate(ctx, userID, time.Now())
+}
+
+// Get returns the user's stored report for the week starting on weekStart
+func (s *Service) Get(ctx context.Context, userID, weekStart string) (Report, error) {
+	return s.store.Get(ctx, userID, weekStart)
+}
+
+func (s *Service) generate(ctx context.Context, userID string, start, end time.Time, loc *time.Location) (Report, error) {
+	prevStart, _ := localtime.WeekBounds(start.Add(-time.Minute), loc, time.Monday)
+	days, err := s.diary.DailyTotals(ctx, userID, prevStart, end, loc)
+	if err != nil {
+		return Report{}, err
+	}
+	var bounds []nutrition.Bound
+	if s.targets != nil {
+		if bounds, err = s.targets.Bounds(ctx, userID); err != nil {
+			return Report{}, err
+		}
+	}
+
+	weekStart := localtime.Date(start, loc)
+	var week, previous []Day
+	for _, d := range days {
+		if d.Date >= weekStart {
+			week = append(week, d)
+		} else {
+			previous = append(previous, d)
+		}
+	}
+	insights := Analyze(week, previous, bounds, s.opts)
+	if insights == nil {
+		insights = []Insight{}
+	}
+	return Report{
+		UserID:      userID,
+		WeekStart:   weekStart,
+		WeekEnd:     localtime.Date(end.Add(-time.Nanosecond), loc),
+		LoggedDays:  len(loggedDays(week)),
+		Insights:    insights,
+		GeneratedAt: time.Now().UTC(),
+	}, nil
+}
+
+// lastWeek returns the UTC bounds of the most recent local week to have ended before now
+func lastWeek(now time.Time, loc *time.Location) (start, end time.Time) {
+	current, _ := localtime.WeekBounds(now, loc, time.Monday)
+	return localtime.WeekBounds(current.Add(-time.Minute), loc, time.Monday)
+}
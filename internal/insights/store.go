@@ -0,0 +1,151 @@
+package insights
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"nutrition-health-backend/internal/outbox"
+	"nutrition-health-backend/internal/txn"
+)
+
+// TopicInsights is the outbox topic for weekly insight notifications
+const TopicInsights = "notification.insights"
+
+// ErrNotFound is returned when no report exists for the week
+var ErrNotFound = errors.New("insights not found")
+
+// Notification is the payload published when a week's insights are ready
+type Notification struct {
+	UserID    string   `json:"user_id"`
+	WeekStart string   `json:"week_start"`
+	Title     string   `json:"title"`
+	TitleAr   string   `json:"title_ar"`
+	Lines     []string `json:"lines"`
+	LinesAr   []string `json:"lines_ar"`
+}
+
+// Store persists weekly insight reports
+type Store struct {
+	db  *sql.DB
+	txm *txn.Manager
+}
+
+// NewStore creates an insights store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db, txm: txn.NewManager(db)}
+}
+
+// Migrate creates the insight_reports table
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS insight_reports (
+			user_id TEXT NOT NULL,
+			week_start TEXT NOT NULL,
+			week_end TEXT NOT NULL,
+			logged_days INTEGER NOT NULL,
+			insights TEXT NOT NULL,
+			generated_at DATETIME NOT NULL,
+			notified_at DATETIME,
+			PRIMARY KEY (user_id, week_start)
+		)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("insights migration: %w", err)
+		}
+	}
+	return nil
+}
+
+// Save stores the report, replacing an earlier one for the same week. With
+// notify set, a report with insights is published once per week.
+func (s *Store) Save(ctx context.Context, r Report, notify bool) error {
+	body, err := json.Marshal(r.Insights)
+	if err != nil {
+		return err
+	}
+	return s.txm.Do(ctx, func(ctx context.Context) error {
+		q := s.txm.Querier(ctx)
+		if _, err := q.ExecContext(ctx,
+			`INSERT INTO insight_reports (user_id, week_start, week_end, logged_days, insights, generated_at)
+			 VALUES (?, ?, ?, ?, ?, ?)
+			 ON CONFLICT(user_id, week_start) DO UPDATE SET
+			   week_end = excluded.week_end, logged_days = excluded.logged_days,
+			   insights = excluded.insights, generated_at = excluded.generated_at`,
+			r.UserID, r.WeekStart, r.WeekEnd, r.LoggedDays, string(body), r.GeneratedAt.UTC()); err != nil {
+			return fmt.Errorf("failed to save insights: %w", err)
+		}
+		if !notify || len(r.Insights) == 0 {
+			return nil
+		}
+		res, err := q.ExecContext(ctx,
+			`UPDATE insight_reports SET notified_at = ? WHERE user_id = ? AND week_start = ? AND notified_at IS NULL`,
+			time.Now().UTC(), r.UserID, r.WeekStart)
+		if err != nil {
+			return err
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return nil
+		}
+		return outbox.Enqueue(ctx, q, TopicInsights, r.UserID+":"+r.WeekStart, notification(r), map[string]string{"user_id": r.UserID})
+	})
+}
+
+func notification(r Report) Notification {
+	n := Notification{
+		UserID:    r.UserID,
+		WeekStart: r.WeekStart,
+		Title:     "Your weekly nutrition insights",
+		TitleAr:   "ملخصك الغذائي الأسبوعي",
+	}
+	for _, in := range r.Insights {
+		n.Lines = append(n.Lines, in.Text)
+		n.LinesAr = append(n.LinesAr, in.TextAr)
+	}
+	return n
+}
+
+// Get returns the user's report for the week starting on weekStart
+func (s *Store) Get(ctx context.Context, userID, weekStart string) (Report, error) {
+	return s.scan(s.db.QueryRowContext(ctx,
+		`SELECT user_id, week_start, week_end, logged_days, insights, generated_at
+		 FROM insight_reports WHERE user_id = ? AND week_start = ?`, userID, weekStart))
+}
+
+// Latest returns the user's most recent report
+func (s *Store) Latest(ctx context.Context, userID string) (Report, error) {
+	return s.scan(s.db.QueryRowContext(ctx,
+		`SELECT user_id, week_start, week_end, logged_days, insights, generated_at
+		 FROM insight_reports WHERE user_id = ? ORDER BY week_start DESC LIMIT 1`, userID))
+}
+
+// Exists reports whether the week's report has been generated
+func (s *Store) Exists(ctx context.Context, userID, weekStart string) (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM insight_reports WHERE user_id = ? AND week_start = ?`, userID, weekStart).Scan(&n)
+	return n > 0, err
+}
+
+func (s *Store) scan(row *sql.Row) (Report, error) {
+	var r Report
+	var body string
+	err := row.Scan(&r.UserID, &r.WeekStart, &r.WeekEnd, &r.LoggedDays, &body, &r.GeneratedAt)
+	if err == sql.ErrNoRows {
+		return Report{}, ErrNotFound
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	if err := json.Unmarshal([]byte(body), &r.Insights); err != nil {
+		return Report{}, fmt.Errorf("corrupt insights for %s: %w", r.WeekStart, err)
+	}
+	if r.Insights == nil {
+		r.Insights = []Insight{}
+	}
+	return r, nil
+}
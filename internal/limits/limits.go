@@ -0,0 +1,94 @@
+package limits
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Rule bounds how long a request may run and how large its body may be
+type Rule struct {
+	Prefix  string
+	Timeout time.Duration
+	MaxBody int64
+	// Stream routes hold the response open (SSE, WebSocket) and get no timeout
+	Stream bool
+}
+
+// Config holds the default rule and per-prefix overrides
+type Config struct {
+	Default Rule
+	Routes  []Rule
+}
+
+// DefaultUploadPrefixes are the upload and file import routes of apiVersion,
+// given the larger upload limits
+func DefaultUploadPrefixes(apiVersion string) []string {
+	base := "/api/" + apiVersion
+	return []string{base + "/foods/recognize", base + "/uploads", base + "/import"}
+}
+
+// DefaultStreamPrefixes are the long-lived realtime routes of apiVersion
+func DefaultStreamPrefixes(apiVersion string) []string {
+	return []string{"/api/" + apiVersion + "/realtime"}
+}
+
+// LoadConfig reads REQUEST_TIMEOUT, REQUEST_MAX_BODY, the UPLOAD_* overrides
+// and STREAM_ROUTE_PREFIXES; default prefixes are under apiVersion
+func LoadConfig(apiVersion string) Config {
+	cfg := Config{
+		Default: Rule{
+			Timeout: envconfig.Duration("REQUEST_TIMEOUT", 15*time.Second),
+			MaxBody: envconfig.Bytes("REQUEST_MAX_BODY", 1<<20),
+		},
+	}
+	upload := Rule{
+		Timeout: envconfig.Duration("UPLOAD_TIMEOUT", 60*time.Second),
+		MaxBody: envconfig.Bytes("UPLOAD_MAX_BODY", 10<<20),
+	}
+	for _, prefix := range envconfig.List("UPLOAD_ROUTE_PREFIXES", DefaultUploadPrefixes(apiVersion)) {
+		r := upload
+		r.Prefix = prefix
+		cfg.Routes = append(cfg.Routes, r)
+	}
+	for _, prefix := range envconfig.List("STREAM_ROUTE_PREFIXES", DefaultStreamPrefixes(apiVersion)) {
+		cfg.Routes = append(cfg.Routes, Rule{Prefix: prefix, MaxBody: cfg.Default.MaxBody, Stream: true})
+	}
+	return cfg
+}
+
+// Validate rejects non-positive limits; stream routes need only a body limit
+func (c Config) Validate() error {
+	for _, r := range append([]Rule{c.Default}, c.Routes...) {
+		name := r.Prefix
+		if name == "" {
+			name = "default"
+		}
+		if r.Timeout <= 0 && !r.Stream {
+			return fmt.Errorf("%s timeout must be positive", name)
+		}
+		if r.MaxBody <= 0 {
+			return fmt.Errorf("%s body limit must be positive", name)
+		}
+	}
+	return nil
+}
+
+// With returns a copy of c with an extra route rule
+func (c Config) With(r Rule) Config {
+	c.Routes = append(append([]Rule{}, c.Routes...), r)
+	return c
+}
+
+// For returns the rule for path: the longest matching prefix, else the default
+func (c Config) For(path string) Rule {
+	best := c.Default
+	for _, r := range c.Routes {
+		if r.Prefix != "" && strings.HasPrefix(path, r.Prefix) && len(r.Prefix) > len(best.Prefix) {
+			best = r
+		}
+	}
+	return best
+}
@@ -0,0 +1,81 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"nutrition-health-backend/internal/problem"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware applies the per-route timeout and body limit. The deadline rides
+// on the request context, so services and DB queries using c.Request().Context()
+// are cancelled with it; handlers that ignore the context still run to completion
+// but their late result is replaced with a 408. Stream routes skip the deadline.
+func Middleware(cfg Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			rule := cfg.For(req.URL.Path)
+
+			if req.ContentLength > rule.MaxBody {
+				return tooLarge(c, rule)
+			}
+			body := &limitedBody{}
+			if req.Body != nil {
+				body.ReadCloser = http.MaxBytesReader(c.Response(), req.Body, rule.MaxBody)
+				req.Body = body
+			}
+
+			if rule.Stream {
+				return next(c)
+			}
+
+			ctx, cancel := context.WithTimeout(req.Context(), rule.Timeout)
+			defer cancel()
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+
+			// Handlers usually turn a failed Bind into their own 400, so the
+			// body itself says whether the limit was hit
+			var maxErr *http.MaxBytesError
+			switch {
+			case body.exceeded || errors.As(err, &maxErr):
+				return tooLarge(c, rule)
+			case ctx.Err() == context.DeadlineExceeded && !c.Response().Committed:
+				return problem.Write(c, problem.New(http.StatusRequestTimeout,
+					fmt.Sprintf("request did not complete within %s", rule.Timeout)))
+			}
+			return err
+		}
+	}
+}
+
+func tooLarge(c echo.Context, rule Rule) error {
+	if c.Response().Committed {
+		return nil
+	}
+	c.Response().Header().Set(echo.HeaderConnection, "close")
+	return problem.Write(c, problem.New(http.StatusRequestEntityTooLarge,
+		fmt.Sprintf("request body exceeds %d bytes", rule.MaxBody)))
+}
+
+// limitedBody remembers whether reading stopped at the body limit
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
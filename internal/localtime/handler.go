@@ -0,0 +1,88 @@
+package localtime
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Header lets anonymous clients send their zone, e.g. "Europe/Berlin"
+const Header = "X-Timezone"
+
+// Middleware binds the request's location to the request context: the stored
+// zone for authenticated users, otherwise a valid X-Timezone header, otherwise
+// Default. Mount it after the auth middleware.
+func (s *Store) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			var loc *time.Location
+			if userID := reqctx.UserID(c); userID != "" {
+				stored, err := s.Location(req.Context(), userID)
+				if err != nil {
+					log.Printf("⚠️ Failed to load timezone for user %s: %v", userID, err)
+				}
+				loc = stored
+			}
+			if loc == nil {
+				if header, err := Load(req.Header.Get(Header)); err == nil {
+					loc = header
+				}
+			}
+			if loc != nil {
+				c.SetRequest(req.WithContext(WithLocation(req.Context(), loc)))
+			}
+			return next(c)
+		}
+	}
+}
+
+// RegisterRoutes mounts GET/PUT /profile/timezone on an authenticated group
+func (s *Store) RegisterRoutes(g *echo.Group) {
+	g.GET("/profile/timezone", func(c echo.Context) error {
+		userID := reqctx.UserID(c)
+		if userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+		loc, err := s.Location(c.Request().Context(), userID)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, zoneResponse(loc))
+	})
+	g.PUT("/profile/timezone", func(c echo.Context) error {
+		userID := reqctx.UserID(c)
+		if userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+		var req struct {
+			Timezone string `json:"timezone"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+		loc, err := s.Set(c.Request().Context(), userID, req.Timezone)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return c.JSON(http.StatusOK, zoneResponse(loc))
+	})
+}
+
+func zoneResponse(loc *time.Location) map[string]interface{} {
+	now := time.Now().In(loc)
+	name, offset := now.Zone()
+	start, end := DayBounds(now, loc)
+	return map[string]interface{}{
+		"timezone":       loc.String(),
+		"abbreviation":   name,
+		"offset_seconds": offset,
+		"local_date":     now.Format(DateLayout),
+		"day_start":      start,
+		"day_end":        end,
+	}
+}
@@ -0,0 +1,157 @@
+package localtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// DateLayout is the format used for local calendar days
+const DateLayout = "2006-01-02"
+
+// Default returns USER_DEFAULT_TIMEZONE, used for users who have not set one
+func Default() *time.Location {
+	loc, err := Load(envconfig.String("USER_DEFAULT_TIMEZONE", "UTC"))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Load resolves an IANA zone name such as "Africa/Cairo". Abbreviations like
+// "EST" and the server-dependent "Local" are rejected.
+func Load(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("invalid timezone %q", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", name)
+	}
+	return loc, nil
+}
+
+type ctxKey struct{}
+
+// WithLocation binds the user's location to ctx
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, ctxKey{}, loc)
+}
+
+// FromContext returns the location bound to ctx, or Default
+func FromContext(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(ctxKey{}).(*time.Location); ok {
+		return loc
+	}
+	return Default()
+}
+
+// Date returns the local calendar day of t, e.g. for grouping diary entries
+func Date(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(DateLayout)
+}
+
+// DayBounds returns the UTC instants at which the local day containing t
+// starts and ends. Days are 23 or 25 hours long across DST transitions, so the
+// end is the next local midnight rather than start+24h.
+func DayBounds(t time.Time, loc *time.Location) (start, end time.Time) {
+	l := t.In(loc)
+	start = midnight(l.Year(), l.Month(), l.Day(), loc)
+	end = midnight(l.Year(), l.Month(), l.Day()+1, loc)
+	return start.UTC(), end.UTC()
+}
+
+// ParseDay returns the UTC bounds of a local calendar day in DateLayout
+func ParseDay(date string, loc *time.Location) (start, end time.Time, err error) {
+	d, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q", date)
+	}
+	start = midnight(d.Year(), d.Month(), d.Day(), loc)
+	end = midnight(d.Year(), d.Month(), d.Day()+1, loc)
+	return start.UTC(), end.UTC(), nil
+}
+
+// WeekBounds returns the UTC bounds of the local week containing t, starting on weekStart
+func WeekBounds(t time.Time, loc *time.Location, weekStart time.Weekday) (start, end time.Time) {
+	l := t.In(loc)
+	offset := (int(l.Weekday()) - int(weekStart) + 7) % 7
+	start = midnight(l.Year(), l.Month(), l.Day()-offset, loc)
+	end = midnight(l.Year(), l.Month(), l.Day()-offset+7, loc)
+	return start.UTC(), end.UTC()
+}
+
+// Next returns the first instant after t at which the local clock reads
+// hour:minute. On a spring-forward day a time inside the gap fires at the
+// first valid instant after it; on a fall-back day the earlier of the two
+// occurrences is used, so a daily reminder fires exactly once.
+func Next(t time.Time, loc *time.Location, hour, minute int) time.Time {
+	l := t.In(loc)
+	for i := 0; i < 3; i++ {
+		candidate := wallClock(l.Year(), l.Month(), l.Day()+i, hour, minute, loc)
+		if candidate.After(t) {
+			return candidate.UTC()
+		}
+	}
+	return wallClock(l.Year(), l.Month(), l.Day()+3, hour, minute, loc).UTC()
+}
+
+// midnight returns the first instant of the local day; in zones that skip
+// midnight for DST (e.g. America/Santiago) this is the first valid time that day
+func midnight(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return wallClock(year, month, day, 0, 0, loc)
+}
+
+func wallClock(year int, month time.Month, day, hour, minute int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, minute, 0, 0, loc)
+	want := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	l := t.In(loc)
+	if !time.Date(l.Year(), l.Month(), l.Day(), l.Hour(), l.Minute(), 0, 0, time.UTC).Equal(want) {
+		// The wall time falls in a DST gap: use the transition instant, which is
+		// the first valid time after it
+		return transition(t.Add(-3*time.Hour), t.Add(3*time.Hour), loc)
+	}
+	// When the wall time occurs twice, prefer the earlier instant
+	for _, shift := range []time.Duration{-time.Hour, -30 * time.Minute} {
+		earlier := t.Add(shift).In(loc)
+		if earlier.Hour() == l.Hour() && earlier.Minute() == l.Minute() {
+			return earlier
+		}
+	}
+	return t
+}
+
+// Transitions returns the instants in [from, to) at which loc's UTC offset
+// changes, oldest first
+func Transitions(loc *time.Location, from, to time.Time) []time.Time {
+	var out []time.Time
+	_, off := from.In(loc).Zone()
+	for t := from; t.Before(to); {
+		next := t.Add(24 * time.Hour)
+		if _, o := next.In(loc).Zone(); o != off {
+			at := transition(t, next, loc)
+			if at.Before(to) {
+				out = append(out, at)
+			}
+			off = o
+		}
+		t = next
+	}
+	return out
+}
+
+// transition finds the instant in (from, to] at which loc's UTC offset changes
+func transition(from, to time.Time, loc *time.Location) time.Time {
+	_, before := from.In(loc).Zone()
+	for to.Sub(from) > time.Second {
+		mid := from.Add(to.Sub(from) / 2)
+		if _, off := mid.In(loc).Zone(); off == before {
+			from = mid
+		} else {
+			to = mid
+		}
+	}
+	return to.Truncate(time.Second)
+}
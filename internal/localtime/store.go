@@ -0,0 +1,64 @@
+package localtime
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"nutrition-health-backend/internal/txn"
+)
+
+// Store persists each user's IANA timezone. Timestamps elsewhere stay in UTC;
+// only day, week and schedule boundaries are computed in this zone. Reads and
+// writes join the transaction ctx carries, if any.
+type Store struct {
+	txm *txn.Manager
+}
+
+// NewStore creates a timezone store
+func NewStore(db *sql.DB) *Store {
+	return &Store{txm: txn.NewManager(db)}
+}
+
+// Migrate creates the user_timezones table
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS user_timezones (
+		user_id TEXT PRIMARY KEY,
+		timezone TEXT NOT NULL,
+		updated_at DATETIME NOT NULL
+	)`)
+	return err
+}
+
+// Location returns the user's zone, or Default when unset or no longer valid
+func (s *Store) Location(ctx context.Context, userID string) (*time.Location, error) {
+	var name string
+	err := s.txm.Querier(ctx).QueryRowContext(ctx, `SELECT timezone FROM user_timezones WHERE user_id = ?`, userID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	loc, err := Load(name)
+	if err != nil {
+		return Default(), nil
+	}
+	return loc, nil
+}
+
+// Set validates and stores the user's zone
+func (s *Store) Set(ctx context.Context, userID, name string) (*time.Location, error) {
+	loc, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.txm.Querier(ctx).ExecContext(ctx,
+		`INSERT INTO user_timezones (user_id, timezone, updated_at) VALUES (?, ?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET timezone = excluded.timezone, updated_at = excluded.updated_at`,
+		userID, loc.String(), time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Format is the log output format
+type Format string
+
+const (
+	Console Format = "console"
+	JSON    Format = "json"
+)
+
+// Stdout is where JSON logs go
+var Stdout io.Writer = os.Stdout
+
+// FormatFromEnv reads LOG_FORMAT, defaulting to JSON in production and console elsewhere
+func FormatFromEnv(environment string) Format {
+	def := Console
+	if environment == "production" {
+		def = JSON
+	}
+	switch strings.ToLower(envconfig.String("LOG_FORMAT", string(def))) {
+	case "json":
+		return JSON
+	default:
+		return Console
+	}
+}
+
+// UseJSON switches the standard logger to one JSON object per line, so the
+// startup and background-job logs share the access log schema
+func UseJSON(w io.Writer) {
+	log.SetFlags(0)
+	log.SetOutput(&jsonWriter{out: w})
+}
+
+// Entry is the stable log schema shipped to Loki/ELK. Field names must not change.
+type Entry struct {
+	Time          string  `json:"ts"`
+	Level         string  `json:"level"`
+	Message       string  `json:"msg"`
+	CorrelationID string  `json:"correlation_id,omitempty"`
+	UserID        string  `json:"user_id,omitempty"`
+	Method        string  `json:"method,omitempty"`
+	Route         string  `json:"route,omitempty"`
+	Path          string  `json:"path,omitempty"`
+	Status        int     `json:"status,omitempty"`
+	LatencyMs     float64 `json:"latency_ms,omitempty"`
+	BytesIn       int64   `json:"bytes_in,omitempty"`
+	BytesOut      int64   `json:"bytes_out,omitempty"`
+	RemoteIP      string  `json:"remote_ip,omitempty"`
+	UserAgent     string  `json:"user_agent,omitempty"`
+	Error         string  `json:"error,omitempty"`
+}
+
+var writeMu sync.Mutex
+
+// Write encodes an entry as a single line
+func Write(w io.Writer, e Entry) {
+	if e.Time == "" {
+		e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	w.Write(append(line, '\n'))
+}
+
+// jsonWriter adapts std log lines (emoji-prefixed) to JSON entries
+type jsonWriter struct {
+	out io.Writer
+}
+
+func (w *jsonWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	level := levelFor(msg)
+	Write(w.out, Entry{Level: level, Message: stripEmoji(msg)})
+	return len(p), nil
+}
+
+// levelFor maps the emoji conventions used across the codebase to levels
+func levelFor(msg string) string {
+	switch {
+	case strings.HasPrefix(msg, "❌"):
+		return "error"
+	case strings.HasPrefix(msg, "⚠️"), strings.HasPrefix(msg, "🐢"):
+		return "warn"
+	default:
+		return "info"
+	}
+}
+
+func stripEmoji(msg string) string {
+	return strings.TrimLeftFunc(msg, func(r rune) bool {
+		return r > unicode.MaxLatin1 && !unicode.IsLetter(r) || unicode.IsSpace(r)
+	})
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CorrelationHeader is the header set by middleware.CorrelationID
+const CorrelationHeader = "X-Correlation-ID"
+
+// AccessLog writes one JSON entry per request in the Entry schema. It replaces
+// middleware.StructuredLogger when LOG_FORMAT=json.
+func AccessLog(w io.Writer) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// Let the error handler write the response so the logged status is final
+				c.Error(err)
+			}
+
+			req, res := c.Request(), c.Response()
+			correlationID := res.Header().Get(CorrelationHeader)
+			if correlationID == "" {
+				correlationID = req.Header.Get(CorrelationHeader)
+			}
+
+			entry := Entry{
+				Level:         "info",
+				Message:       "request",
+				CorrelationID: correlationID,
+				UserID:        reqctx.UserID(c),
+				Method:        req.Method,
+				Route:         c.Path(),
+				Path:          req.URL.Path,
+				Status:        res.Status,
+				LatencyMs:     float64(time.Since(start).Microseconds()) / 1000,
+				BytesIn:       req.ContentLength,
+				BytesOut:      res.Size,
+				RemoteIP:      c.RealIP(),
+				UserAgent:     req.UserAgent(),
+			}
+			if err != nil {
+				entry.Error = err.Error()
+			}
+			switch {
+			case res.Status >= http.StatusInternalServerError:
+				entry.Level = "error"
+			case res.Status >= http.StatusBadRequest:
+				entry.Level = "warn"
+			}
+
+			Write(w, entry)
+			return nil
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package maintenance
+
+import (
+	"net/http"
+
+	"nutrition-health-backend/internal/runtimecfg"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterRoutes mounts GET/PUT/DELETE /maintenance on an admin-protected group
+func (s *Service) RegisterRoutes(g *echo.Group) {
+	g.GET("/maintenance", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, s.Current(c.Request().Context()))
+	})
+	g.PUT("/maintenance", func(c echo.Context) error {
+		var st State
+		if err := c.Bind(&st); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+		st.Actor = actor(c)
+		if err := st.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err := s.Set(c.Request().Context(), st); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, s.Current(c.Request().Context()))
+	})
+	g.DELETE("/maintenance", func(c echo.Context) error {
+		if err := s.Set(c.Request().Context(), State{Mode: Off, Actor: actor(c)}); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+}
+
+func actor(c echo.Context) string {
+	if a := c.Request().Header.Get(runtimecfg.ActorHeader); a != "" {
+		return a
+	}
+	return "admin@" + c.RealIP()
+}
@@ -0,0 +1,122 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Mode selects which requests are refused
+type Mode string
+
+const (
+	Off      Mode = "off"
+	ReadOnly Mode = "read_only" // writes get 503, reads still served
+	Full     Mode = "full"      // everything except health and admin gets 503
+)
+
+// State is the current maintenance setting, shared through Redis
+type State struct {
+	Mode       Mode      `json:"mode"`
+	Message    string    `json:"message,omitempty"`
+	RetryAfter int       `json:"retry_after_seconds"`
+	Since      time.Time `json:"since,omitempty"`
+	Actor      string    `json:"actor,omitempty"`
+}
+
+// Validate checks the mode and Retry-After value
+func (s State) Validate() error {
+	switch s.Mode {
+	case Off, ReadOnly, Full:
+	default:
+		return fmt.Errorf("mode must be off, read_only or full, got %q", s.Mode)
+	}
+	if s.RetryAfter < 0 {
+		return fmt.Errorf("retry_after_seconds must not be negative")
+	}
+	return nil
+}
+
+// redisKey holds the State as JSON
+const redisKey = "maintenance:state"
+
+// cacheTTL bounds how stale an instance's view of the flag may be
+const cacheTTL = 2 * time.Second
+
+// defaultRetryAfter is sent when the operator gives no estimate
+const defaultRetryAfter = 120
+
+// Service reads and writes the maintenance state. Without Redis the state
+// lives in memory and only affects this instance.
+type Service struct {
+	client *redis.Client
+
+	mu      sync.Mutex
+	state   State
+	fetched time.Time
+}
+
+// NewService creates a service; client may be nil
+func NewService(client *redis.Client) *Service {
+	return &Service{client: client, state: State{Mode: Off}}
+}
+
+// Current returns the state, re-reading Redis at most every cacheTTL.
+// If Redis fails the last known state is kept.
+func (s *Service) Current(ctx context.Context) State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == nil || time.Since(s.fetched) < cacheTTL {
+		return s.state
+	}
+	s.fetched = time.Now()
+
+	data, err := s.client.Get(ctx, redisKey).Bytes()
+	switch {
+	case err == redis.Nil:
+		s.state = State{Mode: Off}
+	case err != nil:
+		log.Printf("⚠️ Maintenance state read failed, keeping %s: %v", s.state.Mode, err)
+	default:
+		var st State
+		if err := json.Unmarshal(data, &st); err != nil {
+			log.Printf("⚠️ Corrupt maintenance state, keeping %s: %v", s.state.Mode, err)
+		} else {
+			s.state = st
+		}
+	}
+	return s.state
+}
+
+// Set stores a new state and logs an audit line
+func (s *Service) Set(ctx context.Context, st State) error {
+	if err := st.Validate(); err != nil {
+		return err
+	}
+	if st.Mode != Off && st.RetryAfter == 0 {
+		st.RetryAfter = defaultRetryAfter
+	}
+	st.Since = time.Now().UTC()
+
+	if s.client != nil {
+		data, err := json.Marshal(st)
+		if err != nil {
+			return err
+		}
+		if err := s.client.Set(ctx, redisKey, data, 0).Err(); err != nil {
+			return fmt.Errorf("store maintenance state: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.state, s.fetched = st, time.Now()
+	s.mu.Unlock()
+
+	log.Printf("AUDIT maintenance mode=%s actor=%s retry_after=%ds", st.Mode, st.Actor, st.RetryAfter)
+	return nil
+}
@@ -0,0 +1,70 @@
+package maintenance
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nutrition-health-backend/internal/problem"
+
+	"github.com/labstack/echo/v4"
+)
+
+// exemptPrefixes keep health probes truthful and let operators turn
+// maintenance off, through either admin API
+func exemptPrefixes(apiVersion string) []string {
+	return []string{"/health", "/admin", "/metrics", "/api/" + apiVersion + "/admin"}
+}
+
+// exempt matches path against prefixes on whole segments, so /administer is not /admin
+func exempt(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware refuses requests with 503 and Retry-After while maintenance is
+// on; apiVersion locates the versioned admin API
+func (s *Service) Middleware(apiVersion string) echo.MiddlewareFunc {
+	prefixes := exemptPrefixes(apiVersion)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if exempt(c.Request().URL.Path, prefixes) {
+				return next(c)
+			}
+
+			st := s.Current(c.Request().Context())
+			if !blocks(st.Mode, c.Request().Method) {
+				return next(c)
+			}
+
+			detail := st.Message
+			if detail == "" {
+				detail = "the service is undergoing maintenance"
+				if st.Mode == ReadOnly {
+					detail = "the service is read-only during maintenance"
+				}
+			}
+			c.Response().Header().Set("Retry-After", strconv.Itoa(st.RetryAfter))
+			c.Response().Header().Set("X-Maintenance-Mode", string(st.Mode))
+			return problem.Write(c, problem.New(http.StatusServiceUnavailable, detail))
+		}
+	}
+}
+
+func blocks(mode Mode, method string) bool {
+	switch mode {
+	case Full:
+		return true
+	case ReadOnly:
+		switch method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return false
+		}
+		return true
+	}
+	return false
+}
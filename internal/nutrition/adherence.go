@@ -0,0 +1,73 @@
+package nutrition
+
+// Bound limits one nutrient; a zero Min or Max means unbounded on that side
+type Bound struct {
+	Nutrient string  `json:"nutrient"`
+	Min      float64 `json:"min,omitempty"`
+	Max      float64 `json:"max,omitempty"`
+}
+
+// Adherence statuses
+const (
+	Under  = "under"
+	Within = "within"
+	Over   = "over"
+)
+
+// NutrientAdherence compares one nutrient against its bound
+type NutrientAdherence struct {
+	Bound
+	Actual float64 `json:"actual"`
+	Status string  `json:"status"`
+	// Pct is actual as a percentage of the band's midpoint, or of the one bound set
+	Pct float64 `json:"pct"`
+}
+
+// AdherenceReport summarises a day or period
+type AdherenceReport struct {
+	Nutrients []NutrientAdherence `json:"nutrients"`
+	// Score is the share of bounds met, 0..1
+	Score float64 `json:"score"`
+}
+
+// Adherence compares actual intake with bounds, in the order given. Bounds
+// for nutrients Nutrients doesn't carry are compared against extra.
+func Adherence(actual Nutrients, extra map[string]float64, bounds []Bound) AdherenceReport {
+	values := actual.Map()
+	for k, v := range extra {
+		values[k] = v
+	}
+	report := AdherenceReport{Nutrients: make([]NutrientAdherence, 0, len(bounds))}
+	met := 0
+	for _, b := range bounds {
+		v := values[b.Nutrient]
+		a := NutrientAdherence{Bound: b, Actual: v, Status: Within}
+		switch {
+		case b.Min > 0 && v < b.Min:
+			a.Status = Under
+		case b.Max > 0 && v > b.Max:
+			a.Status = Over
+		default:
+			met++
+		}
+		if ref := reference(b); ref > 0 {
+			a.Pct = round1(v / ref * 100)
+		}
+		report.Nutrients = append(report.Nutrients, a)
+	}
+	if len(bounds) > 0 {
+		report.Score = float64(met) / float64(len(bounds))
+	}
+	return report
+}
+
+func reference(b Bound) float64 {
+	switch {
+	case b.Min > 0 && b.Max > 0:
+		return (b.Min + b.Max) / 2
+	case b.Max > 0:
+		return b.Max
+	default:
+		return b.Min
+	}
+}
@@ -0,0 +1,14 @@
+// Package nutrition is the single implementation of nutrition math shared by
+// handlers, the planner and background jobs.
+//
+// Amounts are per 100 g unless a name says otherwise. Quantities in other units
+// are converted to grams with the units package, using a food's density for
+// volumes. Functions are pure and never round intermediate values; call Round
+// only on values shown to users.
+//
+//   - Nutrients, Add, Scale, Sub: the value type and its arithmetic
+//   - ForGrams, ForQuantity, PerServing: per-serving scaling
+//   - Rollup: recipe totals and per-serving values from ingredients
+//   - Energy, MacroGrams: Atwater energy conversions
+//   - Adherence: how an intake compares to target bounds
+package nutrition
@@ -0,0 +1,75 @@
+package nutrition
+
+import "math"
+
+// Nutrients are energy and nutrient amounts
+type Nutrients struct {
+	Calories float64 `json:"calories"`
+	ProteinG float64 `json:"protein_g"`
+	CarbsG   float64 `json:"carbs_g"`
+	FatG     float64 `json:"fat_g"`
+	FiberG   float64 `json:"fiber_g,omitempty"`
+	SugarG   float64 `json:"sugar_g,omitempty"`
+	SodiumMg float64 `json:"sodium_mg,omitempty"`
+}
+
+// Add returns n plus o
+func (n Nutrients) Add(o Nutrients) Nutrients {
+	return Nutrients{
+		Calories: n.Calories + o.Calories,
+		ProteinG: n.ProteinG + o.ProteinG,
+		CarbsG:   n.CarbsG + o.CarbsG,
+		FatG:     n.FatG + o.FatG,
+		FiberG:   n.FiberG + o.FiberG,
+		SugarG:   n.SugarG + o.SugarG,
+		SodiumMg: n.SodiumMg + o.SodiumMg,
+	}
+}
+
+// Scale returns n multiplied by f
+func (n Nutrients) Scale(f float64) Nutrients {
+	return Nutrients{
+		Calories: n.Calories * f,
+		ProteinG: n.ProteinG * f,
+		CarbsG:   n.CarbsG * f,
+		FatG:     n.FatG * f,
+		FiberG:   n.FiberG * f,
+		SugarG:   n.SugarG * f,
+		SodiumMg: n.SodiumMg * f,
+	}
+}
+
+// Sub returns n minus o
+func (n Nutrients) Sub(o Nutrients) Nutrients {
+	return n.Add(o.Scale(-1))
+}
+
+// Round rounds for display: whole calories and milligrams, grams to one decimal
+func (n Nutrients) Round() Nutrients {
+	return Nutrients{
+		Calories: math.Round(n.Calories),
+		ProteinG: round1(n.ProteinG),
+		CarbsG:   round1(n.CarbsG),
+		FatG:     round1(n.FatG),
+		FiberG:   round1(n.FiberG),
+		SugarG:   round1(n.SugarG),
+		SodiumMg: math.Round(n.SodiumMg),
+	}
+}
+
+// Map returns the nutrients keyed by the names used in target constraints
+func (n Nutrients) Map() map[string]float64 {
+	return map[string]float64{
+		"calories":  n.Calories,
+		"protein_g": n.ProteinG,
+		"carbs_g":   n.CarbsG,
+		"fat_g":     n.FatG,
+		"fiber_g":   n.FiberG,
+		"sugar_g":   n.SugarG,
+		"sodium_mg": n.SodiumMg,
+	}
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
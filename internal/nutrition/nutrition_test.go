@@ -0,0 +1,252 @@
+package nutrition
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"nutrition-health-backend/internal/units"
+)
+
+const epsilon = 1e-6
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= epsilon*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func nearNutrients(a, b Nutrients) bool {
+	am, bm := a.Map(), b.Map()
+	for k, v := range am {
+		if !near(v, bm[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+var rice = Nutrients{Calories: 130, ProteinG: 2.7, CarbsG: 28, FatG: 0.3, FiberG: 0.4, SodiumMg: 1}
+
+func TestForGrams(t *testing.T) {
+	tests := []struct {
+		name  string
+		grams float64
+		want  Nutrients
+	}{
+		{"zero", 0, Nutrients{}},
+		{"per 100 g", 100, rice},
+		{"half", 50, Nutrients{Calories: 65, ProteinG: 1.35, CarbsG: 14, FatG: 0.15, FiberG: 0.2, SodiumMg: 0.5}},
+		{"double", 200, Nutrients{Calories: 260, ProteinG: 5.4, CarbsG: 56, FatG: 0.6, FiberG: 0.8, SodiumMg: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ForGrams(rice, tt.grams); !nearNutrients(got, tt.want) {
+				t.Errorf("ForGrams(%v) = %+v, want %+v", tt.grams, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollup(t *testing.T) {
+	butter := Nutrients{Calories: 717, FatG: 81, ProteinG: 0.9}
+	tests := []struct {
+		name        string
+		ingredients []Ingredient
+		servings    float64
+		wantTotal   Nutrients
+		wantGrams   float64
+		wantSkipped []string
+		wantErr     bool
+	}{
+		{
+			name:        "single ingredient",
+			ingredients: []Ingredient{{Name: "rice", Per100g: rice, Quantity: units.Quantity{Amount: 200, Unit: units.Gram}}},
+			servings:    2,
+			wantTotal:   ForGrams(rice, 200),
+			wantGrams:   200,
+		},
+		{
+			name: "mass units are converted",
+			ingredients: []Ingredient{
+				{Name: "rice", Per100g: rice, Quantity: units.Quantity{Amount: 1, Unit: units.Kilogram}},
+				{Name: "butter", Per100g: butter, Quantity: units.Quantity{Amount: 10, Unit: units.Gram}},
+			},
+			servings:  4,
+			wantTotal: ForGrams(rice, 1000).Add(ForGrams(butter, 10)),
+			wantGrams: 1010,
+		},
+		{
+			name: "unknown unit is skipped",
+			ingredients: []Ingredient{
+				{Name: "rice", Per100g: rice, Quantity: units.Quantity{Amount: 100, Unit: units.Gram}},
+				{Name: "saffron", Per100g: rice, Quantity: units.Quantity{Amount: 1, Unit: "pinch"}},
+			},
+			servings:    1,
+			wantTotal:   rice,
+			wantGrams:   100,
+			wantSkipped: []string{"saffron"},
+		},
+		{name: "no ingredients", servings: 1},
+		{name: "zero servings", servings: 0, wantErr: true},
+		{name: "negative servings", servings: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Rollup(tt.ingredients, tt.servings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Rollup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !nearNutrients(got.Total, tt.wantTotal) {
+				t.Errorf("Total = %+v, want %+v", got.Total, tt.wantTotal)
+			}
+			if !nearNutrients(got.PerServing, tt.wantTotal.Scale(1/tt.servings)) {
+				t.Errorf("PerServing = %+v, want Total / %v", got.PerServing, tt.servings)
+			}
+			if !near(got.TotalG, tt.wantGrams) {
+				t.Errorf("TotalG = %v, want %v", got.TotalG, tt.wantGrams)
+			}
+			if len(got.Skipped) != len(tt.wantSkipped) {
+				t.Fatalf("Skipped = %v, want %v", got.Skipped, tt.wantSkipped)
+			}
+			for i := range got.Skipped {
+				if got.Skipped[i] != tt.wantSkipped[i] {
+					t.Errorf("Skipped = %v, want %v", got.Skipped, tt.wantSkipped)
+				}
+			}
+		})
+	}
+}
+
+func TestMacroGrams(t *testing.T) {
+	tests := []struct {
+		name                            string
+		calories, pPct, cPct, fPct      float64
+		wantProtein, wantCarbs, wantFat float64
+	}{
+		{"balanced 2000", 2000, 30, 40, 30, 150, 200, 66.666667},
+		{"keto 1800", 1800, 20, 5, 75, 90, 22.5, 150},
+		{"zero calories", 0, 30, 40, 30, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, c, f := MacroGrams(tt.calories, tt.pPct, tt.cPct, tt.fPct)
+			if !near(p, tt.wantProtein) || !near(c, tt.wantCarbs) || math.Abs(f-tt.wantFat) > 1e-4 {
+				t.Errorf("MacroGrams() = %v, %v, %v, want %v, %v, %v", p, c, f, tt.wantProtein, tt.wantCarbs, tt.wantFat)
+			}
+			// Splitting by percentages summing to 100 preserves the energy
+			if got := Energy(Nutrients{ProteinG: p, CarbsG: c, FatG: f}); !near(got, tt.calories) {
+				t.Errorf("Energy of split = %v, want %v", got, tt.calories)
+			}
+		})
+	}
+}
+
+func TestAdherence(t *testing.T) {
+	actual := Nutrients{Calories: 2100, ProteinG: 40, SodiumMg: 2500}
+	bounds := []Bound{
+		{Nutrient: "calories", Min: 1800, Max: 2200},
+		{Nutrient: "protein_g", Min: 50},
+		{Nutrient: "sodium_mg", Max: 2300},
+		{Nutrient: "potassium_mg", Min: 3000},
+	}
+	report := Adherence(actual, map[string]float64{"potassium_mg": 3500}, bounds)
+
+	want := []struct {
+		status string
+		actual float64
+		pct    float64
+	}{
+		{Within, 2100, 105},
+		{Under, 40, 80},
+		{Over, 2500, 108.7},
+		{Within, 3500, 116.7},
+	}
+	if len(report.Nutrients) != len(want) {
+		t.Fatalf("got %d nutrients, want %d", len(report.Nutrients), len(want))
+	}
+	for i, w := range want {
+		got := report.Nutrients[i]
+		if got.Nutrient != bounds[i].Nutrient {
+			t.Errorf("nutrient %d = %s, want %s (bounds order)", i, got.Nutrient, bounds[i].Nutrient)
+		}
+		if got.Status != w.status || got.Actual != w.actual || got.Pct != w.pct {
+			t.Errorf("%s = %s %v (%v%%), want %s %v (%v%%)", got.Nutrient, got.Status, got.Actual, got.Pct, w.status, w.actual, w.pct)
+		}
+	}
+	if report.Score != 0.5 {
+		t.Errorf("Score = %v, want 0.5", report.Score)
+	}
+
+	if empty := Adherence(actual, nil, nil); empty.Score != 0 || len(empty.Nutrients) != 0 {
+		t.Errorf("Adherence with no bounds = %+v, want empty report", empty)
+	}
+}
+
+// nutrientsGen builds Nutrients with realistic magnitudes for property tests
+func nutrientsGen(r *rand.Rand) Nutrients {
+	return Nutrients{
+		Calories: r.Float64() * 900,
+		ProteinG: r.Float64() * 100,
+		CarbsG:   r.Float64() * 100,
+		FatG:     r.Float64() * 100,
+		FiberG:   r.Float64() * 50,
+		SugarG:   r.Float64() * 100,
+		SodiumMg: r.Float64() * 5000,
+	}
+}
+
+func TestScaleAddLinearity(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	property := func(fa, fb float64) bool {
+		a, b := nutrientsGen(r), nutrientsGen(r)
+		fa, fb = math.Mod(fa, 10), math.Mod(fb, 10)
+		// (a + b) * f == a*f + b*f
+		if !nearNutrients(a.Add(b).Scale(fa), a.Scale(fa).Add(b.Scale(fa))) {
+			return false
+		}
+		// a * (fa + fb) == a*fa + a*fb
+		if !nearNutrients(a.Scale(fa+fb), a.Scale(fa).Add(a.Scale(fb))) {
+			return false
+		}
+		// a + b - b == a
+		return nearNutrients(a.Add(b).Sub(b), a)
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 500, Rand: r}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRollupEqualsSumOfParts(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	massUnits := []units.Unit{units.Gram, units.Kilogram, units.Ounce, units.Pound}
+	property := func(n uint8, servings uint8) bool {
+		ingredients := make([]Ingredient, int(n%12))
+		var want Nutrients
+		var wantGrams float64
+		for i := range ingredients {
+			q := units.Quantity{Amount: r.Float64() * 5, Unit: massUnits[r.Intn(len(massUnits))]}
+			ingredients[i] = Ingredient{Name: "food", Per100g: nutrientsGen(r), Quantity: q}
+			grams, err := units.ToGrams(q, 0)
+			if err != nil {
+				return false
+			}
+			want = want.Add(ForGrams(ingredients[i].Per100g, grams))
+			wantGrams += grams
+		}
+		s := float64(servings%8) + 1
+		got, err := Rollup(ingredients, s)
+		if err != nil {
+			return false
+		}
+		return nearNutrients(got.Total, want) &&
+			nearNutrients(got.PerServing.Scale(s), got.Total) &&
+			near(got.TotalG, wantGrams) &&
+			len(got.Skipped) == 0
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 300, Rand: r}); err != nil {
+		t.Error(err)
+	}
+}
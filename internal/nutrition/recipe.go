@@ -0,0 +1,51 @@
+package nutrition
+
+import (
+	"fmt"
+
+	"nutrition-health-backend/internal/units"
+)
+
+// Ingredient is one recipe line
+type Ingredient struct {
+	Name     string         `json:"name"`
+	Per100g  Nutrients      `json:"per_100g"`
+	Quantity units.Quantity `json:"quantity"`
+}
+
+// RecipeTotals is the result of Rollup
+type RecipeTotals struct {
+	Total      Nutrients `json:"total"`
+	PerServing Nutrients `json:"per_serving"`
+	TotalG     float64   `json:"total_g"`
+	// Skipped lists ingredients whose quantity could not be converted to grams
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Rollup sums ingredients and divides by servings. An ingredient with an
+// unconvertible quantity is skipped and reported rather than failing the recipe.
+func Rollup(ingredients []Ingredient, servings float64) (RecipeTotals, error) {
+	if servings <= 0 {
+		return RecipeTotals{}, fmt.Errorf("servings must be positive")
+	}
+	var totals RecipeTotals
+	for _, ing := range ingredients {
+		grams, err := units.ToGrams(ing.Quantity, units.DensityFor(ing.Name))
+		if err != nil {
+			totals.Skipped = append(totals.Skipped, ing.Name)
+			continue
+		}
+		totals.TotalG += grams
+		totals.Total = totals.Total.Add(ForGrams(ing.Per100g, grams))
+	}
+	totals.PerServing = totals.Total.Scale(1 / servings)
+	return totals, nil
+}
+
+// Per100g converts recipe totals into per-100 g values, for logging a recipe by weight
+func (r RecipeTotals) Per100g() Nutrients {
+	if r.TotalG <= 0 {
+		return Nutrients{}
+	}
+	return r.Total.Scale(100 / r.TotalG)
+}
@@ -0,0 +1,49 @@
+package nutrition
+
+import (
+	"fmt"
+
+	"nutrition-health-backend/internal/units"
+)
+
+// Atwater energy factors, kcal per gram
+const (
+	KcalPerGramProtein = 4
+	KcalPerGramCarbs   = 4
+	KcalPerGramFat     = 9
+)
+
+// ForGrams scales per-100 g values to an amount in grams
+func ForGrams(per100g Nutrients, grams float64) Nutrients {
+	return per100g.Scale(grams / 100)
+}
+
+// ForQuantity scales per-100 g values to q, converting volumes with the
+// density of the named food
+func ForQuantity(per100g Nutrients, q units.Quantity, food string) (Nutrients, error) {
+	grams, err := units.ToGrams(q, units.DensityFor(food))
+	if err != nil {
+		return Nutrients{}, err
+	}
+	return ForGrams(per100g, grams), nil
+}
+
+// PerServing divides a total across servings
+func PerServing(total Nutrients, servings float64) (Nutrients, error) {
+	if servings <= 0 {
+		return Nutrients{}, fmt.Errorf("servings must be positive")
+	}
+	return total.Scale(1 / servings), nil
+}
+
+// Energy returns the Atwater energy of n's macros, ignoring its Calories field
+func Energy(n Nutrients) float64 {
+	return n.ProteinG*KcalPerGramProtein + n.CarbsG*KcalPerGramCarbs + n.FatG*KcalPerGramFat
+}
+
+// MacroGrams splits calories by percentage into protein, carbs and fat grams
+func MacroGrams(calories, proteinPct, carbsPct, fatPct float64) (protein, carbs, fat float64) {
+	return calories * proteinPct / 100 / KcalPerGramProtein,
+		calories * carbsPct / 100 / KcalPerGramCarbs,
+		calories * fatPct / 100 / KcalPerGramFat
+}
@@ -0,0 +1,82 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"nutrition-health-backend/internal/txn"
+)
+
+// ErrNoTransaction is returned by Add outside a unit of work; an event written
+// outside the change's transaction defeats the point of the outbox
+var ErrNoTransaction = errors.New("outbox: event must be added inside a transaction")
+
+// Event is a domain event waiting to be published
+type Event struct {
+	ID        int64             `json:"id"`
+	Topic     string            `json:"topic"`
+	Key       string            `json:"key,omitempty"`
+	Payload   json.RawMessage   `json:"payload"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Attempts  int               `json:"attempts"`
+}
+
+// Migrate creates the outbox_events table
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS outbox_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			topic TEXT NOT NULL,
+			event_key TEXT NOT NULL DEFAULT '',
+			payload TEXT NOT NULL,
+			headers TEXT,
+			created_at DATETIME NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at DATETIME NOT NULL,
+			delivered_at DATETIME,
+			dead INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox_events(delivered_at, dead, next_attempt_at)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Enqueue writes an event with q, which should be the transaction making the change
+func Enqueue(ctx context.Context, q txn.Querier, topic, key string, payload interface{}, headers map[string]string) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var hdrs interface{}
+	if len(headers) > 0 {
+		encoded, err := json.Marshal(headers)
+		if err != nil {
+			return err
+		}
+		hdrs = string(encoded)
+	}
+	now := time.Now().UTC()
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO outbox_events (topic, event_key, payload, headers, created_at, next_attempt_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		topic, key, string(data), hdrs, now, now)
+	return err
+}
+
+// Add enqueues an event in the transaction bound to ctx by txn.Manager.Do
+func Add(ctx context.Context, topic, key string, payload interface{}) error {
+	tx, ok := txn.FromContext(ctx)
+	if !ok {
+		return ErrNoTransaction
+	}
+	return Enqueue(ctx, tx, topic, key, payload, nil)
+}
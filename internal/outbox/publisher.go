@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Publisher delivers an event; an error leaves it in the outbox for retry
+type Publisher interface {
+	Publish(ctx context.Context, ev Event) error
+}
+
+// RedisPublisher publishes events on the "events:<topic>" channel
+type RedisPublisher struct {
+	client *redis.Client
+}
+
+// NewRedisPublisher creates a Redis publisher
+func NewRedisPublisher(client *redis.Client) *RedisPublisher {
+	return &RedisPublisher{client: client}
+}
+
+func (p *RedisPublisher) Publish(ctx context.Context, ev Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return p.client.Publish(ctx, "events:"+ev.Topic, data).Err()
+}
+
+// SignatureHeader carries the hex HMAC-SHA256 of "<timestamp>.<body>"
+const SignatureHeader = "X-Webhook-Signature"
+
+// TimestampHeader is the Unix time the signature was made at
+const TimestampHeader = "X-Webhook-Timestamp"
+
+// WebhookPublisher POSTs events as JSON to each URL, signed with secret
+type WebhookPublisher struct {
+	urls   []string
+	secret []byte
+	client *http.Client
+}
+
+// NewWebhookPublisher creates a webhook publisher
+func NewWebhookPublisher(urls []string, secret string) *WebhookPublisher {
+	return &WebhookPublisher{
+		urls:   urls,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	var errs []error
+	for _, url := range p.urls {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Event-Topic", ev.Topic)
+		req.Header.Set("X-Event-ID", strconv.FormatInt(ev.ID, 10))
+		req.Header.Set(TimestampHeader, ts)
+		if len(p.secret) > 0 {
+			req.Header.Set(SignatureHeader, signature)
+		}
+		resp, err := p.client.Do(req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", url, err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			errs = append(errs, fmt.Errorf("%s: status %d", url, resp.StatusCode))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Multi publishes to every publisher; the event is delivered only when all succeed
+type Multi []Publisher
+
+func (m Multi) Publish(ctx context.Context, ev Event) error {
+	var errs []error
+	for _, p := range m {
+		if err := p.Publish(ctx, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,226 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Config tunes the relay and its webhook targets
+type Config struct {
+	PollInterval  time.Duration
+	BatchSize     int
+	MaxAttempts   int
+	Retention     time.Duration
+	WebhookURLs   []string
+	WebhookSecret string
+}
+
+// LoadConfig reads the OUTBOX_* settings
+func LoadConfig() Config {
+	return Config{
+		PollInterval:  envconfig.Duration("OUTBOX_POLL_INTERVAL", time.Second),
+		BatchSize:     envconfig.Int("OUTBOX_BATCH_SIZE", 100),
+		MaxAttempts:   envconfig.Int("OUTBOX_MAX_ATTEMPTS", 10),
+		Retention:     envconfig.Duration("OUTBOX_RETENTION", 7*24*time.Hour),
+		WebhookURLs:   envconfig.List("OUTBOX_WEBHOOK_URLS", nil),
+		WebhookSecret: envconfig.String("OUTBOX_WEBHOOK_SECRET", ""),
+	}
+}
+
+// Validate checks the relay settings
+func (c Config) Validate() error {
+	if c.PollInterval <= 0 || c.BatchSize <= 0 || c.MaxAttempts <= 0 {
+		return fmt.Errorf("OUTBOX_POLL_INTERVAL, OUTBOX_BATCH_SIZE and OUTBOX_MAX_ATTEMPTS must be positive")
+	}
+	for _, u := range c.WebhookURLs {
+		if !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+			return fmt.Errorf("OUTBOX_WEBHOOK_URLS entry %q must be an http(s) URL", u)
+		}
+	}
+	if len(c.WebhookURLs) > 0 && len(c.WebhookSecret) < 16 {
+		return fmt.Errorf("OUTBOX_WEBHOOK_SECRET must be at least 16 characters when webhooks are configured")
+	}
+	return nil
+}
+
+// Relay publishes pending outbox rows and marks them delivered. Delivery is
+// at least once: consumers should de-duplicate on the event ID.
+type Relay struct {
+	db        *sql.DB
+	publisher Publisher
+	cfg       Config
+	wake      chan struct{}
+}
+
+// NewRelay creates a relay
+func NewRelay(db *sql.DB, publisher Publisher, cfg Config) *Relay {
+	return &Relay{db: db, publisher: publisher, cfg: cfg, wake: make(chan struct{}, 1)}
+}
+
+// Notify asks the relay to poll now, e.g. right after a commit that added events
+func (r *Relay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start relays until ctx is cancelled
+func (r *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		for {
+			n, err := r.RunOnce(ctx)
+			if err != nil {
+				log.Printf("⚠️ Outbox relay failed: %v", err)
+				break
+			}
+			// Keep draining while batches come back full
+			if n < r.cfg.BatchSize {
+				break
+			}
+		}
+		if time.Since(lastPrune) > time.Hour {
+			if err := r.prune(ctx); err != nil {
+				log.Printf("⚠️ Outbox prune failed: %v", err)
+			}
+			lastPrune = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// RunOnce publishes one batch of due events and returns how many were attempted
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	events, err := r.due(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, ev := range events {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if err := r.publisher.Publish(ctx, ev); err != nil {
+			r.failed(ctx, ev, err)
+			continue
+		}
+		if _, err := r.db.ExecContext(ctx,
+			`UPDATE outbox_events SET delivered_at = ?, attempts = attempts + 1, last_error = NULL WHERE id = ?`,
+			time.Now().UTC(), ev.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+func (r *Relay) due(ctx context.Context) ([]Event, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, topic, event_key, payload, COALESCE(headers, ''), created_at, attempts FROM outbox_events
+		 WHERE delivered_at IS NULL AND dead = 0 AND next_attempt_at <= ?
+		 ORDER BY id LIMIT ?`, time.Now().UTC(), r.cfg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var (
+			ev      Event
+			payload string
+			headers string
+		)
+		if err := rows.Scan(&ev.ID, &ev.Topic, &ev.Key, &payload, &headers, &ev.CreatedAt, &ev.Attempts); err != nil {
+			return nil, err
+		}
+		ev.Payload = json.RawMessage(payload)
+		if headers != "" {
+			json.Unmarshal([]byte(headers), &ev.Headers)
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+// failed schedules a retry with exponential backoff, or parks the event as dead
+func (r *Relay) failed(ctx context.Context, ev Event, cause error) {
+	attempts := ev.Attempts + 1
+	dead := attempts >= r.cfg.MaxAttempts
+	next := time.Now().UTC().Add(backoff(attempts))
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE outbox_events SET attempts = ?, next_attempt_at = ?, dead = ?, last_error = ? WHERE id = ?`,
+		attempts, next, dead, cause.Error(), ev.ID); err != nil {
+		log.Printf("⚠️ Outbox retry bookkeeping failed for event %d: %v", ev.ID, err)
+	}
+	if dead {
+		log.Printf("❌ Outbox event %d (%s) dead after %d attempts: %v", ev.ID, ev.Topic, attempts, cause)
+	} else {
+		log.Printf("⚠️ Outbox event %d (%s) attempt %d failed: %v", ev.ID, ev.Topic, attempts, cause)
+	}
+}
+
+// backoff doubles from 2s up to an hour
+func backoff(attempts int) time.Duration {
+	d := 2 * time.Second
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+func (r *Relay) prune(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM outbox_events WHERE delivered_at IS NOT NULL AND delivered_at < ?`,
+		time.Now().UTC().Add(-r.cfg.Retention))
+	return err
+}
+
+// Backlog is the outbox depth, reported by the health check
+type Backlog struct {
+	Pending      int64   `json:"pending"`
+	Dead         int64   `json:"dead"`
+	OldestAgeSec float64 `json:"oldest_pending_age_seconds"`
+}
+
+// Backlog counts undelivered events
+func (r *Relay) Backlog(ctx context.Context) (Backlog, error) {
+	var (
+		b      Backlog
+		oldest sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(CASE WHEN dead = 0 THEN 1 ELSE 0 END), 0),
+		        COALESCE(SUM(dead), 0),
+		        MIN(CASE WHEN dead = 0 THEN created_at END)
+		 FROM outbox_events WHERE delivered_at IS NULL`).Scan(&b.Pending, &b.Dead, &oldest)
+	if err != nil {
+		return b, err
+	}
+	if oldest.Valid {
+		for _, layout := range []string{"2006-01-02 15:04:05.999999999-07:00", time.RFC3339Nano} {
+			if t, err := time.Parse(layout, oldest.String); err == nil {
+				b.OldestAgeSec = time.Since(t).Seconds()
+				break
+			}
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,90 @@
+package portions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"nutrition-health-backend/internal/localtime"
+	"nutrition-health-backend/internal/reqctx"
+	"nutrition-health-backend/internal/units"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	// ErrFoodNotFound is returned by FoodSource for unknown foods
+	ErrFoodNotFound = errors.New("food not found")
+	// ErrNoTargets is returned by RemainingSource for users without daily targets
+	ErrNoTargets = errors.New("no daily targets set")
+)
+
+// FoodSource looks up a food's nutrient profile; implemented by the food service
+type FoodSource interface {
+	Food(ctx context.Context, id string) (Food, error)
+}
+
+// RemainingSource returns the user's targets minus what they have logged in
+// [from, to); implemented by the diary service
+type RemainingSource interface {
+	Remaining(ctx context.Context, userID string, from, to time.Time) (Macros, error)
+}
+
+// Handler serves portion suggestions
+type Handler struct {
+	foods     FoodSource
+	remaining RemainingSource
+}
+
+// NewHandler creates a portion suggestion handler
+func NewHandler(foods FoodSource, remaining RemainingSource) *Handler {
+	return &Handler{foods: foods, remaining: remaining}
+}
+
+// RegisterRoutes mounts GET /foods/:id/suggest-portion on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/foods/:id/suggest-portion", h.Suggest)
+}
+
+// Suggest handles GET /foods/:id/suggest-portion?date=YYYY-MM-DD (default today, user's zone)
+func (h *Handler) Suggest(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	ctx := c.Request().Context()
+	loc := localtime.FromContext(ctx)
+
+	date := c.QueryParam("date")
+	if date == "" {
+		date = localtime.Date(time.Now(), loc)
+	}
+	from, to, err := localtime.ParseDay(date, loc)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	food, err := h.foods.Food(ctx, c.Param("id"))
+	if errors.Is(err, ErrFoodNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	remaining, err := h.remaining.Remaining(ctx, userID, from, to)
+	if errors.Is(err, ErrNoTargets) {
+		return echo.NewHTTPError(http.StatusConflict, "choose daily targets before asking for a portion")
+	}
+	if err != nil {
+		return err
+	}
+	suggestion := Suggest(food, remaining, DefaultOptions())
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"food_id":    food.ID,
+		"date":       date,
+		"remaining":  remaining.Round(),
+		"suggestion": suggestion,
+		"display":    units.Display(suggestion.Grams, units.FromContext(ctx)),
+	})
+}
@@ -0,0 +1,137 @@
+package portions
+
+import (
+	"math"
+
+	"nutrition-health-backend/internal/nutrition"
+)
+
+// Macros are the nutrient amounts suggestions are computed over
+type Macros = nutrition.Nutrients
+
+// Food is the nutrient profile of the chosen food
+type Food struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Per100g Macros `json:"per_100g"`
+	// ServingG is the food's household serving; 0 means grams only
+	ServingG float64 `json:"serving_g"`
+}
+
+// Options tunes the suggestion
+type Options struct {
+	// Weights rank how much each macro matters; calories default to the highest
+	Weights Macros
+	// StepG rounds the suggestion; when the food has a serving, quarter servings
+	// are used instead unless less than a quarter fits
+	StepG float64
+	// MaxServings caps the suggestion at this many servings (or 100 g units)
+	MaxServings float64
+}
+
+// DefaultOptions favours calories, then protein
+func DefaultOptions() Options {
+	return Options{Weights: Macros{Calories: 3, ProteinG: 2, CarbsG: 1, FatG: 1}, StepG: 5, MaxServings: 3}
+}
+
+// Suggestion is the portion that best fits what is left of the day
+type Suggestion struct {
+	Grams     float64 `json:"grams"`
+	Servings  float64 `json:"servings,omitempty"`
+	Nutrients Macros  `json:"nutrients"`
+	After     Macros  `json:"remaining_after"`
+	// Limit names the macro that capped the portion, if any
+	Limit string `json:"limit,omitempty"`
+	// Reason explains a zero suggestion
+	Reason string `json:"reason,omitempty"`
+}
+
+// Suggest finds the grams of food that best fit remaining. It minimises the
+// weighted squared relative error against each remaining macro, caps the
+// result so calories and no macro with a positive remainder overshoot, then
+// rounds down to a practical step.
+func Suggest(food Food, remaining Macros, opts Options) Suggestion {
+	perG := nutrition.ForGrams(food.Per100g, 1)
+	if remaining.Calories <= 0 {
+		return Suggestion{After: remaining, Reason: "calorie target reached"}
+	}
+	if perG.Calories <= 0 && perG.ProteinG <= 0 && perG.CarbsG <= 0 && perG.FatG <= 0 {
+		return Suggestion{After: remaining, Reason: "food has no nutrient data"}
+	}
+
+	type term struct {
+		name              string
+		per, left, weight float64
+	}
+	terms := []term{
+		{"calories", perG.Calories, remaining.Calories, opts.Weights.Calories},
+		{"protein_g", perG.ProteinG, remaining.ProteinG, opts.Weights.ProteinG},
+		{"carbs_g", perG.CarbsG, remaining.CarbsG, opts.Weights.CarbsG},
+		{"fat_g", perG.FatG, remaining.FatG, opts.Weights.FatG},
+	}
+
+	// Least squares over relative error: sum w*((g*p - r)/r)^2
+	var num, den float64
+	for _, t := range terms {
+		if t.left <= 0 || t.weight <= 0 {
+			continue
+		}
+		num += t.weight * t.per / t.left
+		den += t.weight * t.per * t.per / (t.left * t.left)
+	}
+	grams := 0.0
+	if den > 0 {
+		grams = num / den
+	}
+
+	limit := ""
+	for _, t := range terms {
+		if t.per <= 0 {
+			continue
+		}
+		// A macro already over target only caps the portion when it is calories
+		if t.left <= 0 && t.name != "calories" {
+			continue
+		}
+		if max := t.left / t.per; max < grams {
+			grams, limit = max, t.name
+		}
+	}
+
+	unit := 100.0
+	step := opts.StepG
+	if food.ServingG > 0 {
+		unit = food.ServingG
+		step = food.ServingG / 4
+	}
+	if opts.MaxServings > 0 && grams > opts.MaxServings*unit {
+		grams, limit = opts.MaxServings*unit, "max_servings"
+	}
+	if step > 0 {
+		rounded := math.Floor(grams/step) * step
+		if rounded <= 0 && food.ServingG > 0 && opts.StepG > 0 {
+			// Less than a quarter serving fits; fall back to gram steps
+			rounded = math.Floor(grams/opts.StepG) * opts.StepG
+		}
+		grams = rounded
+	}
+	if grams <= 0 {
+		return Suggestion{After: remaining, Limit: limit, Reason: "no portion fits the remaining " + limit}
+	}
+
+	nutrients := nutrition.ForGrams(food.Per100g, grams)
+	s := Suggestion{
+		Grams:     round(grams),
+		Nutrients: nutrients.Round(),
+		After:     remaining.Sub(nutrients).Round(),
+		Limit:     limit,
+	}
+	if food.ServingG > 0 {
+		s.Servings = round(grams / food.ServingG)
+	}
+	return s
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
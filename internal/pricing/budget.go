@@ -0,0 +1,88 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"nutrition-health-backend/internal/targets"
+)
+
+// ErrOverBudget rejects a candidate plan with a day costing more than its Budget
+var ErrOverBudget = errors.New("plan is over budget")
+
+// PlannedFood is one food on a candidate plan day
+type PlannedFood struct {
+	FoodID string  `json:"food_id"`
+	Name   string  `json:"name,omitempty"`
+	Grams  float64 `json:"grams"`
+}
+
+// WithBudget returns constraints with the cost bound replaced by
+// Budget(maxPerDay); a zero budget removes it
+func WithBudget(constraints []targets.Constraint, maxPerDay float64) []targets.Constraint {
+	out := make([]targets.Constraint, 0, len(constraints)+1)
+	for _, c := range constraints {
+		if c.Nutrient != CostNutrient {
+			out = append(out, c)
+		}
+	}
+	if maxPerDay > 0 {
+		out = append(out, Budget(maxPerDay))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Nutrient < out[j].Nutrient })
+	return out
+}
+
+// DayCost sums a day's foods at per-gram prices; unpriced foods add nothing,
+// so missing price data never rejects a plan
+func DayCost(foods []PlannedFood, perGram map[string]float64) float64 {
+	var total float64
+	for _, f := range foods {
+		total += perGram[f.FoodID] * f.Grams
+	}
+	return round2(total)
+}
+
+// WithinBudget checks each day of a plan, keyed by date, against the cost
+// bound in constraints. Without one every plan passes.
+func WithinBudget(days map[string][]PlannedFood, perGram map[string]float64, constraints []targets.Constraint) error {
+	var budget float64
+	for _, c := range constraints {
+		if c.Nutrient == CostNutrient {
+			budget = c.Max
+		}
+	}
+	if budget <= 0 {
+		return nil
+	}
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates {
+		if cost := DayCost(days[date], perGram); cost > budget {
+			return fmt.Errorf("%w: %s costs %.2f, budget is %.2f", ErrOverBudget, date, cost, budget)
+		}
+	}
+	return nil
+}
+
+// CheckPlan prices a candidate plan for userID in region and checks it
+// against the Budget in constraints; the plan generator calls it before
+// accepting a plan
+func (e *Estimator) CheckPlan(ctx context.Context, userID, region string, days map[string][]PlannedFood, constraints []targets.Constraint) error {
+	foods := map[string]string{}
+	for _, day := range days {
+		for _, f := range day {
+			foods[f.FoodID] = f.Name
+		}
+	}
+	perGram, err := e.CostPerGram(ctx, userID, region, foods)
+	if err != nil {
+		return err
+	}
+	return WithinBudget(days, perGram, constraints)
+}
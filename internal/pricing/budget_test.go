@@ -0,0 +1,52 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+
+	"nutrition-health-backend/internal/targets"
+)
+
+var perGram = map[string]float64{"rice": 0.004, "chicken": 0.012, "salmon": 0.03}
+
+func TestWithinBudget(t *testing.T) {
+	macros := []targets.Constraint{{Nutrient: "calories", Min: 1800, Max: 2200}}
+	week := map[string][]PlannedFood{
+		"2026-10-12": {{FoodID: "rice", Grams: 300}, {FoodID: "chicken", Grams: 200}},
+		"2026-10-13": {{FoodID: "rice", Grams: 300}, {FoodID: "salmon", Grams: 250}},
+	}
+	tests := []struct {
+		name        string
+		days        map[string][]PlannedFood
+		constraints []targets.Constraint
+		wantErr     bool
+	}{
+		{"no budget", week, macros, false},
+		{"within budget", week, WithBudget(macros, 10), false},
+		{"over budget", week, WithBudget(macros, 5), true},
+		{"exactly on budget", map[string][]PlannedFood{"2026-10-12": {{FoodID: "chicken", Grams: 250}}}, WithBudget(nil, 3), false},
+		{"unpriced foods are free", map[string][]PlannedFood{"2026-10-12": {{FoodID: "truffle", Grams: 500}}}, WithBudget(nil, 1), false},
+		{"budget removed", week, WithBudget(WithBudget(macros, 5), 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WithinBudget(tt.days, perGram, tt.constraints)
+			if tt.wantErr != errors.Is(err, ErrOverBudget) {
+				t.Errorf("WithinBudget() = %v, want over budget %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithBudget(t *testing.T) {
+	got := WithBudget([]targets.Constraint{{Nutrient: CostNutrient, Max: 20}, {Nutrient: "protein_g", Min: 100}}, 8)
+	want := []targets.Constraint{{Nutrient: CostNutrient, Max: 8}, {Nutrient: "protein_g", Min: 100}}
+	if len(got) != len(want) {
+		t.Fatalf("WithBudget() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("WithBudget()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
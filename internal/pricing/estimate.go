@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"context"
+	"math"
+
+	"nutrition-health-backend/internal/units"
+)
+
+// Item is one ingredient, recipe component or shopping list line to price
+type Item struct {
+	FoodID   string         `json:"food_id"`
+	Name     string         `json:"name,omitempty"`
+	Quantity units.Quantity `json:"quantity"`
+}
+
+// Line is the estimated cost of one item
+type Line struct {
+	FoodID string `json:"food_id"`
+	// Quantity is the item's amount in the user's unit system, for shopping lists
+	Quantity units.Quantity `json:"quantity"`
+	Cost     float64        `json:"cost"`
+	Currency string         `json:"currency"`
+	Source   string         `json:"source"`
+}
+
+// Estimate is the total cost of a set of items. Items without a price, or
+// priced in a currency other than the total's, are listed in Missing rather
+// than guessed, and Partial is set so clients can show "from".
+type Estimate struct {
+	Total    float64  `json:"total"`
+	Currency string   `json:"currency,omitempty"`
+	Lines    []Line   `json:"lines"`
+	Missing  []string `json:"missing,omitempty"`
+	Partial  bool     `json:"partial"`
+}
+
+// Estimator prices items for recipes, plans and shopping lists
+type Estimator struct {
+	store *Store
+}
+
+// NewEstimator creates an estimator
+func NewEstimator(store *Store) *Estimator {
+	return &Estimator{store: store}
+}
+
+// Estimate prices items for userID in region; line quantities follow the
+// unit system bound to ctx
+func (e *Estimator) Estimate(ctx context.Context, userID, region string, items []Item) (Estimate, error) {
+	system := units.FromContext(ctx)
+	ids := make([]string, 0, len(items))
+	for _, it := range items {
+		ids = append(ids, it.FoodID)
+	}
+	prices, err := e.store.Resolve(ctx, userID, region, ids)
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	est := Estimate{Lines: []Line{}}
+	for _, it := range items {
+		p, ok := prices[it.FoodID]
+		if !ok || (est.Currency != "" && p.Currency != est.Currency) {
+			est.Missing = append(est.Missing, it.FoodID)
+			continue
+		}
+		cost, err := itemCost(p, it)
+		if err != nil {
+			est.Missing = append(est.Missing, it.FoodID)
+			continue
+		}
+		est.Currency = p.Currency
+		est.Total += cost
+		est.Lines = append(est.Lines, Line{
+			FoodID:   it.FoodID,
+			Quantity: units.DisplayQuantity(it.Quantity, system),
+			Cost:     round2(cost),
+			Currency: p.Currency,
+			Source:   p.Source,
+		})
+	}
+	est.Total = round2(est.Total)
+	est.Partial = len(est.Missing) > 0
+	return est, nil
+}
+
+// CostPerGram returns the resolved price per gram of each food, for the plan
+// generator to score candidates against a Budget constraint
+func (e *Estimator) CostPerGram(ctx context.Context, userID, region string, foods map[string]string) (map[string]float64, error) {
+	ids := make([]string, 0, len(foods))
+	for id := range foods {
+		ids = append(ids, id)
+	}
+	prices, err := e.store.Resolve(ctx, userID, region, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]float64, len(prices))
+	for id, p := range prices {
+		if perGram, err := p.PerGram(units.DensityFor(foods[id])); err == nil {
+			out[id] = perGram
+		}
+	}
+	return out, nil
+}
+
+func itemCost(p Price, it Item) (float64, error) {
+	density := units.DensityFor(it.Name)
+	// Same dimension needs no density; convert the item into the price's unit
+	q, err := units.Convert(it.Quantity, p.Quantity.Unit, density)
+	if err != nil {
+		return 0, err
+	}
+	return p.Amount * q.Amount / p.Quantity.Amount, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
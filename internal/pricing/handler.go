@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"fmt"
+	"net/http"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes prices and cost estimates over HTTP
+type Handler struct {
+	store     *Store
+	estimator *Estimator
+}
+
+// NewHandler creates a pricing handler
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store, estimator: NewEstimator(store)}
+}
+
+// RegisterRoutes mounts POST /prices/estimate; on an authenticated group the
+// caller's own prices are used, otherwise only admin prices
+func (h *Handler) RegisterRoutes(api *echo.Group) {
+	api.POST("/prices/estimate", h.Estimate)
+}
+
+// RegisterUserRoutes mounts PUT/DELETE /prices/:food_id for user-entered prices on an authenticated group
+func (h *Handler) RegisterUserRoutes(g *echo.Group) {
+	g.PUT("/prices/:food_id", func(c echo.Context) error {
+		userID := reqctx.UserID(c)
+		if userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+		return h.save(c, userID)
+	})
+	g.DELETE("/prices/:food_id", func(c echo.Context) error {
+		userID := reqctx.UserID(c)
+		if userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+		return h.delete(c, userID)
+	})
+}
+
+// RegisterAdminRoutes mounts regional price maintenance on an admin-protected group
+func (h *Handler) RegisterAdminRoutes(g *echo.Group) {
+	g.GET("/prices", func(c echo.Context) error {
+		prices, err := h.store.List(c.Request().Context(), region(c))
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"region": region(c), "prices": prices})
+	})
+	g.PUT("/prices/:food_id", func(c echo.Context) error { return h.save(c, "") })
+	g.DELETE("/prices/:food_id", func(c echo.Context) error { return h.delete(c, "") })
+}
+
+// maxEstimateItems caps one estimate; each item is a price lookup
+const maxEstimateItems = 200
+
+type estimateRequest struct {
+	Region string `json:"region"`
+	Items  []Item `json:"items"`
+}
+
+// Estimate prices a list of items
+func (h *Handler) Estimate(c echo.Context) error {
+	var req estimateRequest
+	if err := c.Bind(&req); err != nil || len(req.Items) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "items are required")
+	}
+	if len(req.Items) > maxEstimateItems {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("at most %d items per estimate", maxEstimateItems))
+	}
+	if req.Region == "" {
+		req.Region = region(c)
+	}
+	est, err := h.estimator.Estimate(c.Request().Context(), reqctx.UserID(c), req.Region, req.Items)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, est)
+}
+
+func (h *Handler) save(c echo.Context, userID string) error {
+	var p Price
+	if err := c.Bind(&p); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	p.FoodID = c.Param("food_id")
+	p.UserID = userID
+	if p.Region == "" {
+		p.Region = region(c)
+	}
+	if err := p.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	saved, err := h.store.Save(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, saved)
+}
+
+func (h *Handler) delete(c echo.Context, userID string) error {
+	if err := h.store.Delete(c.Request().Context(), c.Param("food_id"), region(c), userID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// region reads ?region=, falling back to DefaultRegion
+func region(c echo.Context) string {
+	if r := c.QueryParam("region"); r != "" {
+		return r
+	}
+	return DefaultRegion()
+}
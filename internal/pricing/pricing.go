@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/targets"
+	"nutrition-health-backend/internal/units"
+)
+
+// Sources of a price; user-entered prices win over admin prices for that user
+const (
+	SourceAdmin = "admin"
+	SourceUser  = "user"
+)
+
+// CostNutrient is the constraint key the planner sums per-serving cost under
+const CostNutrient = targets.CostNutrient
+
+// DefaultRegion returns PRICE_DEFAULT_REGION, used when a region has no price
+func DefaultRegion() string {
+	return strings.ToLower(envconfig.String("PRICE_DEFAULT_REGION", "default"))
+}
+
+// Price is what Quantity of a food costs in a region
+type Price struct {
+	FoodID    string         `json:"food_id"`
+	Region    string         `json:"region"`
+	Amount    float64        `json:"amount"`
+	Currency  string         `json:"currency"`
+	Quantity  units.Quantity `json:"quantity"`
+	Source    string         `json:"source"`
+	UserID    string         `json:"-"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Validate checks a price before it is stored
+func (p Price) Validate() error {
+	if p.FoodID == "" || p.Region == "" {
+		return fmt.Errorf("food_id and region are required")
+	}
+	if p.Amount < 0 {
+		return fmt.Errorf("amount must not be negative")
+	}
+	if len(p.Currency) != 3 {
+		return fmt.Errorf("currency must be an ISO 4217 code")
+	}
+	if !p.Quantity.Unit.Valid() || p.Quantity.Amount <= 0 {
+		return fmt.Errorf("quantity must be a positive amount in a supported unit")
+	}
+	return nil
+}
+
+// PerGram returns the price of one gram of the food; density converts volume prices
+func (p Price) PerGram(density float64) (float64, error) {
+	grams, err := units.ToGrams(p.Quantity, density)
+	if err != nil {
+		return 0, err
+	}
+	if grams <= 0 {
+		return 0, fmt.Errorf("price quantity is zero")
+	}
+	return p.Amount / grams, nil
+}
+
+// Budget converts a maximum daily spend into a planner constraint
+func Budget(maxPerDay float64) targets.Constraint {
+	return targets.Constraint{Nutrient: CostNutrient, Max: maxPerDay}
+}
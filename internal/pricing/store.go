@@ -0,0 +1,156 @@
+package pricing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/units"
+)
+
+// Store persists food prices per region
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a price store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate creates the food_prices table; admin prices have an empty user_id
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS food_prices (
+			food_id TEXT NOT NULL,
+			region TEXT NOT NULL,
+			user_id TEXT NOT NULL DEFAULT '',
+			amount REAL NOT NULL,
+			currency TEXT NOT NULL,
+			quantity REAL NOT NULL,
+			unit TEXT NOT NULL,
+			updated_at DATETIME NOT NULL,
+			PRIMARY KEY (food_id, region, user_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_food_prices_region ON food_prices(region, user_id)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save upserts a price
+func (s *Store) Save(ctx context.Context, p Price) (Price, error) {
+	p.Region = strings.ToLower(p.Region)
+	p.Currency = strings.ToUpper(p.Currency)
+	if err := p.Validate(); err != nil {
+		return Price{}, err
+	}
+	p.Source = SourceAdmin
+	if p.UserID != "" {
+		p.Source = SourceUser
+	}
+	p.UpdatedAt = time.Now().UTC()
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO food_prices (food_id, region, user_id, amount, currency, quantity, unit, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(food_id, region, user_id) DO UPDATE SET amount = excluded.amount, currency = excluded.currency,
+		 quantity = excluded.quantity, unit = excluded.unit, updated_at = excluded.updated_at`,
+		p.FoodID, p.Region, p.UserID, p.Amount, p.Currency, p.Quantity.Amount, string(p.Quantity.Unit), p.UpdatedAt)
+	if err != nil {
+		return Price{}, fmt.Errorf("failed to save price: %w", err)
+	}
+	return p, nil
+}
+
+// Delete removes a price; userID "" targets the admin price
+func (s *Store) Delete(ctx context.Context, foodID, region, userID string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM food_prices WHERE food_id = ? AND region = ? AND user_id = ?`,
+		foodID, strings.ToLower(region), userID)
+	return err
+}
+
+// List returns the admin prices for a region
+func (s *Store) List(ctx context.Context, region string) ([]Price, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT food_id, region, user_id, amount, currency, quantity, unit, updated_at FROM food_prices
+		 WHERE region = ? AND user_id = '' ORDER BY food_id`, strings.ToLower(region))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var prices []Price
+	for rows.Next() {
+		p, err := scanPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// Resolve returns the best price per food: the user's own price in region,
+// then the admin price in region, then the admin price in DefaultRegion
+func (s *Store) Resolve(ctx context.Context, userID, region string, foodIDs []string) (map[string]Price, error) {
+	resolved := make(map[string]Price, len(foodIDs))
+	if len(foodIDs) == 0 {
+		return resolved, nil
+	}
+	region = strings.ToLower(region)
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(foodIDs)), ",")
+	args := []interface{}{region, DefaultRegion(), userID}
+	for _, id := range foodIDs {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT food_id, region, user_id, amount, currency, quantity, unit, updated_at FROM food_prices
+		 WHERE region IN (?, ?) AND user_id IN ('', ?) AND food_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rank := func(p Price) int {
+		switch {
+		case p.UserID != "" && p.Region == region:
+			return 3
+		case p.UserID == "" && p.Region == region:
+			return 2
+		case p.UserID == "":
+			return 1
+		}
+		return 0
+	}
+	for rows.Next() {
+		p, err := scanPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		if current, ok := resolved[p.FoodID]; !ok || rank(p) > rank(current) {
+			if rank(p) > 0 {
+				resolved[p.FoodID] = p
+			}
+		}
+	}
+	return resolved, rows.Err()
+}
+
+func scanPrice(rows *sql.Rows) (Price, error) {
+	var p Price
+	var unit string
+	if err := rows.Scan(&p.FoodID, &p.Region, &p.UserID, &p.Amount, &p.Currency, &p.Quantity.Amount, &unit, &p.UpdatedAt); err != nil {
+		return Price{}, err
+	}
+	p.Quantity.Unit = units.Unit(unit)
+	p.Source = SourceAdmin
+	if p.UserID != "" {
+		p.Source = SourceUser
+	}
+	return p, nil
+}
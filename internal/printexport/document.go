@@ -0,0 +1,130 @@
+package printexport
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"nutrition-health-backend/internal/nutrition"
+)
+
+// Export kinds
+const (
+	KindRecipe = "recipe"
+	KindPlan   = "plan"
+)
+
+// ErrNotFound is returned by sources when the recipe or plan doesn't exist for the user
+var ErrNotFound = errors.New("recipe or plan not found")
+
+// Line is one food with the nutrients of its quantity
+type Line struct {
+	FoodID    string              `json:"food_id"`
+	Name      string              `json:"name"`
+	Grams     float64             `json:"grams"`
+	Nutrients nutrition.Nutrients `json:"nutrients"`
+}
+
+// Meal is one planned slot
+type Meal struct {
+	Slot  string `json:"slot"`
+	Lines []Line `json:"lines"`
+}
+
+// Day is one planned day and its totals
+type Day struct {
+	Date   string              `json:"date"`
+	Meals  []Meal              `json:"meals"`
+	Totals nutrition.Nutrients `json:"totals"`
+}
+
+// ShoppingItem is a food's total quantity across the document
+type ShoppingItem struct {
+	Name  string  `json:"name"`
+	Grams float64 `json:"grams"`
+}
+
+// Document is a recipe or weekly plan ready to render
+type Document struct {
+	Kind         string
+	Title        string
+	Servings     int
+	Instructions string
+	// Ingredients and PerServing are set for recipes
+	Ingredients []Line
+	PerServing  nutrition.Nutrients
+	// Days and DailyAverage are set for plans
+	Days         []Day
+	DailyAverage nutrition.Nutrients
+	Shopping     []ShoppingItem
+}
+
+// Source loads documents, typically from the recipe and meal plan tables
+type Source interface {
+	Recipe(ctx context.Context, userID, id string) (Document, error)
+	// Plan returns the week of planned meals starting on weekStart (YYYY-MM-DD)
+	Plan(ctx context.Context, userID, weekStart string) (Document, error)
+}
+
+// NewRecipe builds a recipe document with per-serving nutrition
+func NewRecipe(title string, servings int, instructions string, lines []Line) Document {
+	if servings < 1 {
+		servings = 1
+	}
+	var total nutrition.Nutrients
+	for _, l := range lines {
+		total = total.Add(l.Nutrients)
+	}
+	return Document{
+		Kind:         KindRecipe,
+		Title:        title,
+		Servings:     servings,
+		Instructions: instructions,
+		Ingredients:  lines,
+		PerServing:   total.Scale(1 / float64(servings)).Round(),
+		Shopping:     shoppingList(lines),
+	}
+}
+
+// NewPlan builds a weekly plan document with day totals
+func NewPlan(title string, days []Day) Document {
+	var all []Line
+	var total nutrition.Nutrients
+	for i := range days {
+		var day nutrition.Nutrients
+		for _, m := range days[i].Meals {
+			for _, l := range m.Lines {
+				day = day.Add(l.Nutrients)
+			}
+			all = append(all, m.Lines...)
+		}
+		days[i].Totals = day.Round()
+		total = total.Add(day)
+	}
+	doc := Document{Kind: KindPlan, Title: title, Servings: 1, Days: days, Shopping: shoppingList(all)}
+	if len(days) > 0 {
+		doc.DailyAverage = total.Scale(1 / float64(len(days))).Round()
+	}
+	return doc
+}
+
+// shoppingList sums quantities per food, largest first
+func shoppingList(lines []Line) []ShoppingItem {
+	index := map[string]int{}
+	var items []ShoppingItem
+	for _, l := range lines {
+		key := l.FoodID
+		if key == "" {
+			key = strings.ToLower(l.Name)
+		}
+		if i, ok := index[key]; ok {
+			items[i].Grams += l.Grams
+			continue
+		}
+		index[key] = len(items)
+		items = append(items, ShoppingItem{Name: l.Name, Grams: l.Grams})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Grams > items[j].Grams })
+	return items
+}
@@ -0,0 +1,139 @@
+package printexport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes recipe and plan exports over HTTP
+type Handler struct {
+	store  *Store
+	runner *Runner
+}
+
+// NewHandler creates an export handler
+func NewHandler(store *Store, runner *Runner) *Handler {
+	return &Handler{store: store, runner: runner}
+}
+
+// RegisterRoutes mounts /exports routes on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.POST("/exports", h.Create)
+	g.GET("/exports/:id", h.Status).Name = "printexport.status"
+	g.GET("/exports/:id/download", h.Download)
+}
+
+// Create queues an export of a recipe ({"kind":"recipe","id":"12"}) or a
+// weekly plan ({"kind":"plan","week_start":"2026-10-05"}). The locale comes
+// from the body, ?lang= or Accept-Language; Arabic renders right to left.
+func (h *Handler) Create(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	var req struct {
+		Kind      string `json:"kind"`
+		ID        string `json:"id"`
+		WeekStart string `json:"week_start"`
+		Format    string `json:"format"`
+		Locale    string `json:"locale"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	ref := req.ID
+	switch req.Kind {
+	case KindRecipe:
+		if _, err := strconv.ParseInt(ref, 10, 64); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "id must be a recipe ID")
+		}
+	case KindPlan:
+		ref = req.WeekStart
+		if _, err := time.Parse("2006-01-02", ref); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "week_start must be YYYY-MM-DD")
+		}
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "kind must be recipe or plan")
+	}
+	if req.Format == "" {
+		req.Format = FormatPDF
+	}
+	if req.Format != FormatPDF && req.Format != FormatHTML {
+		return echo.NewHTTPError(http.StatusBadRequest, "format must be pdf or html")
+	}
+	if !h.runner.Supports(req.Format) {
+		return echo.NewHTTPError(http.StatusNotImplemented, fmt.Sprintf("%s export is not available; html print export is", req.Format))
+	}
+
+	job, err := h.store.Create(c.Request().Context(), userID, req.Kind, ref, req.Format, locale(c, req.Locale))
+	if err != nil {
+		return err
+	}
+	h.runner.Notify()
+	c.Response().Header().Set(echo.HeaderLocation, c.Echo().Reverse("printexport.status", job.ID))
+	return c.JSON(http.StatusAccepted, job)
+}
+
+// Status reports whether an export is ready
+func (h *Handler) Status(c echo.Context) error {
+	job, err := h.store.Get(c.Request().Context(), reqctx.UserID(c), c.Param("id"))
+	if errors.Is(err, ErrJobNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+// Download returns a ready export's file
+func (h *Handler) Download(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := reqctx.UserID(c)
+	job, err := h.store.Get(ctx, userID, c.Param("id"))
+	if errors.Is(err, ErrJobNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	if job.Status != JobReady {
+		return echo.NewHTTPError(http.StatusConflict, "export is "+job.Status)
+	}
+	data, contentType, err := h.store.Output(ctx, userID, job.ID)
+	if err != nil {
+		return err
+	}
+	disposition := "inline"
+	if job.Format == FormatPDF {
+		disposition = "attachment"
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`%s; filename="%s-%s.%s"`, disposition, job.Kind, job.Ref, job.Format))
+	return c.Blob(http.StatusOK, contentType, data)
+}
+
+// locale picks ar or en from the request, defaulting to en
+func locale(c echo.Context, requested string) string {
+	lang := requested
+	if lang == "" {
+		lang = c.QueryParam("lang")
+	}
+	if lang == "" {
+		accept := c.Request().Header.Get("Accept-Language")
+		lang = strings.TrimSpace(strings.SplitN(strings.SplitN(accept, ",", 2)[0], ";", 2)[0])
+	}
+	if strings.HasPrefix(strings.ToLower(lang), "ar") {
+		return "ar"
+	}
+	return "en"
+}
@@ -0,0 +1,270 @@
+package printexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/nutrition"
+)
+
+// Output formats
+const (
+	FormatPDF  = "pdf"
+	FormatHTML = "html"
+)
+
+// ErrPDFUnavailable is returned for PDF exports when the converter isn't installed
+var ErrPDFUnavailable = errors.New("pdf export is not available")
+
+// Config selects the fonts and the HTML to PDF converter
+type Config struct {
+	// PDFCommand converts {input}, an HTML file, to the PDF {output}
+	PDFCommand []string
+	// ArabicFont is a TTF/OTF file embedded in Arabic documents
+	ArabicFont string
+}
+
+// LoadConfig reads EXPORT_PDF_COMMAND and EXPORT_ARABIC_FONT
+func LoadConfig() Config {
+	return Config{
+		PDFCommand: strings.Fields(envconfig.String("EXPORT_PDF_COMMAND",
+			"chromium-browser --headless --no-sandbox --disable-gpu --no-pdf-header-footer --print-to-pdf={output} {input}")),
+		ArabicFont: envconfig.String("EXPORT_ARABIC_FONT", ""),
+	}
+}
+
+// Validate checks the converter command and font file
+func (c Config) Validate() error {
+	joined := strings.Join(c.PDFCommand, " ")
+	if len(c.PDFCommand) > 0 && (!strings.Contains(joined, "{input}") || !strings.Contains(joined, "{output}")) {
+		return fmt.Errorf("EXPORT_PDF_COMMAND must contain {input} and {output}")
+	}
+	if c.ArabicFont != "" {
+		if _, err := os.Stat(c.ArabicFont); err != nil {
+			return fmt.Errorf("EXPORT_ARABIC_FONT: %w", err)
+		}
+	}
+	return nil
+}
+
+// Renderer writes a document in one format
+type Renderer interface {
+	ContentType() string
+	Render(ctx context.Context, doc Document, locale string, w io.Writer) error
+}
+
+// HTMLRenderer renders printable HTML, right to left for Arabic with the
+// configured font embedded so print output doesn't depend on installed fonts
+type HTMLRenderer struct {
+	fontFace template.CSS
+}
+
+// NewHTMLRenderer loads the Arabic font, if configured
+func NewHTMLRenderer(cfg Config) (*HTMLRenderer, error) {
+	r := &HTMLRenderer{}
+	if cfg.ArabicFont == "" {
+		return r, nil
+	}
+	font, err := os.ReadFile(cfg.ArabicFont)
+	if err != nil {
+		return nil, fmt.Errorf("EXPORT_ARABIC_FONT: %w", err)
+	}
+	mime := "font/ttf"
+	if strings.EqualFold(filepath.Ext(cfg.ArabicFont), ".otf") {
+		mime = "font/otf"
+	}
+	r.fontFace = template.CSS(`@font-face { font-family: "ExportArabic"; src: url(data:` + mime + `;base64,` +
+		base64.StdEncoding.EncodeToString(font) + `); }`)
+	return r, nil
+}
+
+// ContentType implements Renderer
+func (r *HTMLRenderer) ContentType() string { return "text/html; charset=utf-8" }
+
+// Render implements Renderer
+func (r *HTMLRenderer) Render(ctx context.Context, doc Document, locale string, w io.Writer) error {
+	t := labels["en"]
+	dir := "ltr"
+	if locale == "ar" {
+		t, dir = labels["ar"], "rtl"
+	}
+	return page.Execute(w, map[string]interface{}{
+		"Doc":      doc,
+		"T":        t,
+		"Lang":     locale,
+		"Dir":      dir,
+		"FontFace": r.fontFace,
+		"Rows":     nutrientRows(t),
+	})
+}
+
+// CommandRenderer renders PDFs by printing the HTML rendering with an
+// external converter such as headless Chromium, which shapes Arabic text and
+// embeds the fonts it used
+type CommandRenderer struct {
+	html    *HTMLRenderer
+	command []string
+}
+
+// NewCommandRenderer returns ErrPDFUnavailable when the converter isn't on PATH
+func NewCommandRenderer(html *HTMLRenderer, cfg Config) (*CommandRenderer, error) {
+	if len(cfg.PDFCommand) == 0 {
+		return nil, ErrPDFUnavailable
+	}
+	if _, err := exec.LookPath(cfg.PDFCommand[0]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPDFUnavailable, err)
+	}
+	return &CommandRenderer{html: html, command: cfg.PDFCommand}, nil
+}
+
+// ContentType implements Renderer
+func (r *CommandRenderer) ContentType() string { return "application/pdf" }
+
+// Render implements Renderer
+func (r *CommandRenderer) Render(ctx context.Context, doc Document, locale string, w io.Writer) error {
+	dir, err := os.MkdirTemp("", "printexport")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	input, output := filepath.Join(dir, "export.html"), filepath.Join(dir, "export.pdf")
+	var page bytes.Buffer
+	if err := r.html.Render(ctx, doc, locale, &page); err != nil {
+		return err
+	}
+	if err := os.WriteFile(input, page.Bytes(), 0o600); err != nil {
+		return err
+	}
+	args := make([]string, len(r.command))
+	for i, a := range r.command {
+		args[i] = strings.NewReplacer("{input}", input, "{output}", output).Replace(a)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pdf converter: %v: %s", err, strings.TrimSpace(tail(stderr.String(), 500)))
+	}
+	f, err := os.Open(output)
+	if err != nil {
+		return fmt.Errorf("pdf converter wrote no output: %w", err)
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func tail(s string, n int) string {
+	if len(s) > n {
+		return s[len(s)-n:]
+	}
+	return s
+}
+
+// nutrientRow is one line of the nutrition table
+type nutrientRow struct {
+	Label string
+	Unit  string
+	Value func(nutrition.Nutrients) float64
+}
+
+func nutrientRows(t map[string]string) []nutrientRow {
+	return []nutrientRow{
+		{t["calories"], t["kcal"], func(n nutrition.Nutrients) float64 { return n.Calories }},
+		{t["protein"], t["g"], func(n nutrition.Nutrients) float64 { return n.ProteinG }},
+		{t["carbs"], t["g"], func(n nutrition.Nutrients) float64 { return n.CarbsG }},
+		{t["fat"], t["g"], func(n nutrition.Nutrients) float64 { return n.FatG }},
+		{t["fiber"], t["g"], func(n nutrition.Nutrients) float64 { return n.FiberG }},
+		{t["sugar"], t["g"], func(n nutrition.Nutrients) float64 { return n.SugarG }},
+		{t["sodium"], t["mg"], func(n nutrition.Nutrients) float64 { return n.SodiumMg }},
+	}
+}
+
+var labels = map[string]map[string]string{
+	"en": {
+		"recipe": "Recipe", "plan": "Weekly plan", "servings": "Servings", "per_serving": "Nutrition per serving",
+		"daily_average": "Daily average", "ingredients": "Ingredients", "instructions": "Instructions",
+		"shopping": "Shopping list", "totals": "Day total", "nutrient": "Nutrient", "amount": "Amount",
+		"calories": "Calories", "protein": "Protein", "carbs": "Carbohydrates", "fat": "Fat",
+		"fiber": "Fiber", "sugar": "Sugar", "sodium": "Sodium", "kcal": "kcal", "g": "g", "mg": "mg",
+		"breakfast": "Breakfast", "lunch": "Lunch", "dinner": "Dinner", "snack": "Snack",
+	},
+	"ar": {
+		"recipe": "وصفة", "plan": "الخطة الأسبوعية", "servings": "عدد الحصص", "per_serving": "القيمة الغذائية لكل حصة",
+		"daily_average": "المتوسط اليومي", "ingredients": "المكونات", "instructions": "طريقة التحضير",
+		"shopping": "قائمة التسوق", "totals": "مجموع اليوم", "nutrient": "العنصر", "amount": "الكمية",
+		"calories": "السعرات الحرارية", "protein": "البروتين", "carbs": "الكربوهيدرات", "fat": "الدهون",
+		"fiber": "الألياف", "sugar": "السكر", "sodium": "الصوديوم", "kcal": "سعرة", "g": "غ", "mg": "ملغ",
+		"breakfast": "الفطور", "lunch": "الغداء", "dinner": "العشاء", "snack": "وجبة خفيفة",
+	},
+}
+
+var page = template.Must(template.New("export").Funcs(template.FuncMap{
+	"num": func(v float64) string { return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64) },
+	"value": func(row nutrientRow, n nutrition.Nutrients) string {
+		return strconv.FormatFloat(row.Value(n), 'f', -1, 64)
+	},
+	"slot": func(t map[string]string, slot string) string {
+		if l, ok := t[strings.ToLower(slot)]; ok {
+			return l
+		}
+		return slot
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}" dir="{{.Dir}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Doc.Title}}</title>
+<style>
+{{.FontFace}}
+@page { size: A4; margin: 16mm; }
+body { font-family: "ExportArabic", "Noto Naskh Arabic", "Noto Sans", "DejaVu Sans", sans-serif; font-size: 11pt; color: #222; }
+h1 { font-size: 18pt; margin: 0 0 4mm; }
+h2 { font-size: 13pt; margin: 6mm 0 2mm; border-bottom: 1px solid #999; }
+table { width: 100%; border-collapse: collapse; }
+th, td { padding: 1.5mm 2mm; border-bottom: 1px solid #ddd; text-align: start; }
+td.n { text-align: end; white-space: nowrap; }
+.appendix { page-break-before: always; }
+.day { page-break-inside: avoid; }
+</style>
+</head>
+<body>
+{{$t := .T}}{{$rows := .Rows}}
+{{if eq .Doc.Kind "recipe"}}
+<h1>{{.Doc.Title}}</h1>
+<p>{{index $t "servings"}}: {{.Doc.Servings}}</p>
+<h2>{{index $t "per_serving"}}</h2>
+<table>{{range $rows}}<tr><td>{{.Label}}</td><td class="n">{{value . $.Doc.PerServing}} {{.Unit}}</td></tr>{{end}}</table>
+<h2>{{index $t "ingredients"}}</h2>
+<table>{{range .Doc.Ingredients}}<tr><td>{{.Name}}</td><td class="n">{{num .Grams}} {{index $t "g"}}</td></tr>{{end}}</table>
+{{if .Doc.Instructions}}<h2>{{index $t "instructions"}}</h2><p>{{.Doc.Instructions}}</p>{{end}}
+{{else}}
+<h1>{{index $t "plan"}} – <bdi>{{.Doc.Title}}</bdi></h1>
+<h2>{{index $t "daily_average"}}</h2>
+<table>{{range $rows}}<tr><td>{{.Label}}</td><td class="n">{{value . $.Doc.DailyAverage}} {{.Unit}}</td></tr>{{end}}</table>
+{{range .Doc.Days}}<div class="day">
+<h2><bdi>{{.Date}}</bdi></h2>
+<table>{{range .Meals}}{{$slot := slot $t .Slot}}{{range .Lines}}<tr><td>{{$slot}}</td><td>{{.Name}}</td><td class="n">{{num .Grams}} {{index $t "g"}}</td><td class="n">{{num .Nutrients.Calories}} {{index $t "kcal"}}</td></tr>{{end}}{{end}}
+<tr><th colspan="3">{{index $t "totals"}}</th><th class="n">{{num .Totals.Calories}} {{index $t "kcal"}}</th></tr></table>
+</div>{{end}}
+{{end}}
+<div class="appendix">
+<h2>{{index $t "shopping"}}</h2>
+<table>{{range .Doc.Shopping}}<tr><td>☐ {{.Name}}</td><td class="n">{{num .Grams}} {{index $t "g"}}</td></tr>{{end}}</table>
+</div>
+</body>
+</html>
+`))
@@ -0,0 +1,123 @@
+package printexport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Runner renders queued exports in the background
+type Runner struct {
+	store     *Store
+	source    Source
+	renderers map[string]Renderer
+	// keep is how long finished exports stay downloadable (EXPORT_RETENTION)
+	keep time.Duration
+	wake chan struct{}
+}
+
+// NewRunner creates a runner; formats without a renderer fail their jobs
+func NewRunner(store *Store, source Source, renderers map[string]Renderer) *Runner {
+	return &Runner{
+		store:     store,
+		source:    source,
+		renderers: renderers,
+		keep:      envconfig.Duration("EXPORT_RETENTION", 24*time.Hour),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Supports reports whether format can be rendered
+func (r *Runner) Supports(format string) bool {
+	_, ok := r.renderers[format]
+	return ok
+}
+
+// Notify wakes the runner after a job is queued
+func (r *Runner) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start processes jobs until ctx is cancelled, also polling so jobs queued on
+// other instances are picked up
+func (r *Runner) Start(ctx context.Context) {
+	if err := r.store.requeueStale(ctx, 15*time.Minute); err != nil {
+		log.Printf("⚠️ Failed to requeue stale exports: %v", err)
+	}
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+	for {
+		for {
+			job, ok, err := r.store.claim(ctx)
+			if err != nil {
+				log.Printf("⚠️ Failed to claim export: %v", err)
+			}
+			if !ok {
+				break
+			}
+			r.process(ctx, job)
+		}
+		if err := r.store.purge(ctx, r.keep); err != nil {
+			log.Printf("⚠️ Failed to purge old exports: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) process(ctx context.Context, job Job) {
+	started := time.Now()
+	var out bytes.Buffer
+	contentType, err := r.render(ctx, job, &out)
+	if err != nil {
+		job.Status, job.Error = JobFailed, err.Error()
+		log.Printf("❌ Export %s (%s %s) failed: %v", job.ID, job.Kind, job.Format, err)
+	} else {
+		job.Status = JobReady
+	}
+	if err := r.store.finish(ctx, job, contentType, out.Bytes()); err != nil {
+		log.Printf("⚠️ Failed to save export %s: %v", job.ID, err)
+		return
+	}
+	if job.Status == JobReady {
+		log.Printf("🖨️ Export %s (%s %s, %s): %d bytes in %s",
+			job.ID, job.Kind, job.Format, job.Locale, out.Len(), time.Since(started).Round(time.Millisecond))
+	}
+}
+
+func (r *Runner) render(ctx context.Context, job Job, out *bytes.Buffer) (string, error) {
+	renderer, ok := r.renderers[job.Format]
+	if !ok {
+		return "", fmt.Errorf("format %q is not available", job.Format)
+	}
+	var doc Document
+	var err error
+	switch job.Kind {
+	case KindRecipe:
+		doc, err = r.source.Recipe(ctx, job.UserID, job.Ref)
+	case KindPlan:
+		doc, err = r.source.Plan(ctx, job.UserID, job.Ref)
+	default:
+		err = fmt.Errorf("unknown export kind %q", job.Kind)
+	}
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	if err := renderer.Render(ctx, doc, job.Locale, out); err != nil {
+		return "", err
+	}
+	return renderer.ContentType(), nil
+}
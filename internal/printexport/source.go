@@ -0,0 +1,129 @@
+package printexport
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"nutrition-health-backend/internal/nutrition"
+	"nutrition-health-backend/internal/repo"
+	"nutrition-health-backend/internal/tenant"
+)
+
+// SQLSource reads recipes and meal plans through the repo queries
+type SQLSource struct {
+	db *sql.DB
+}
+
+// NewSQLSource creates a source on db
+func NewSQLSource(db *sql.DB) *SQLSource {
+	return &SQLSource{db: db}
+}
+
+// Recipe implements Source
+func (s *SQLSource) Recipe(ctx context.Context, userID, id string) (Document, error) {
+	recipeID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return Document{}, ErrNotFound
+	}
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return Document{}, err
+	}
+	q := repo.New(s.db)
+	r, err := q.GetRecipe(ctx, repo.GetRecipeParams{ID: recipeID, UserID: userID, TenantID: tenantID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Document{}, ErrNotFound
+	}
+	if err != nil {
+		return Document{}, err
+	}
+	ingredients, err := q.ListRecipeIngredients(ctx, recipeID)
+	if err != nil {
+		return Document{}, err
+	}
+	foods := foodCache{q: q, foods: map[int64]repo.Food{}}
+	lines := make([]Line, 0, len(ingredients))
+	for _, in := range ingredients {
+		l, err := foods.line(ctx, in.FoodID, in.Quantity)
+		if err != nil {
+			return Document{}, err
+		}
+		lines = append(lines, l)
+	}
+	return NewRecipe(r.Name, int(r.Servings), r.Instructions, lines), nil
+}
+
+// Plan implements Source
+func (s *SQLSource) Plan(ctx context.Context, userID, weekStart string) (Document, error) {
+	start, err := time.Parse("2006-01-02", weekStart)
+	if err != nil {
+		return Document{}, ErrNotFound
+	}
+	end := start.AddDate(0, 0, 6).Format("2006-01-02")
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return Document{}, err
+	}
+	q := repo.New(s.db)
+	items, err := q.ListPlannedItems(ctx, repo.ListPlannedItemsParams{UserID: userID, TenantID: tenantID, DateFrom: weekStart, DateTo: end})
+	if err != nil {
+		return Document{}, err
+	}
+	if len(items) == 0 {
+		return Document{}, ErrNotFound
+	}
+
+	foods := foodCache{q: q, foods: map[int64]repo.Food{}}
+	var days []Day
+	for _, it := range items {
+		l, err := foods.line(ctx, it.FoodID, it.Quantity)
+		if err != nil {
+			return Document{}, err
+		}
+		if len(days) == 0 || days[len(days)-1].Date != it.Date {
+			days = append(days, Day{Date: it.Date})
+		}
+		day := &days[len(days)-1]
+		if n := len(day.Meals); n == 0 || day.Meals[n-1].Slot != it.MealType {
+			day.Meals = append(day.Meals, Meal{Slot: it.MealType})
+		}
+		meal := &day.Meals[len(day.Meals)-1]
+		meal.Lines = append(meal.Lines, l)
+	}
+	return NewPlan(weekStart, days), nil
+}
+
+// foodCache loads each food once per document
+type foodCache struct {
+	q     *repo.Queries
+	foods map[int64]repo.Food
+}
+
+func (c foodCache) line(ctx context.Context, foodID int64, grams float64) (Line, error) {
+	f, ok := c.foods[foodID]
+	if !ok {
+		var err error
+		if f, err = c.q.GetFood(ctx, foodID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return Line{}, err
+		}
+		c.foods[foodID] = f
+	}
+	per100 := nutrition.Nutrients{
+		Calories: f.Calories,
+		ProteinG: f.Protein,
+		CarbsG:   f.Carbs,
+		FatG:     f.Fat,
+		FiberG:   f.Fiber,
+		SugarG:   f.Sugar,
+		SodiumMg: f.Sodium,
+	}
+	return Line{
+		FoodID:    strconv.FormatInt(foodID, 10),
+		Name:      f.Name,
+		Grams:     grams,
+		Nutrients: per100.Scale(grams / 100).Round(),
+	}, nil
+}
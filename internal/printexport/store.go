@@ -0,0 +1,166 @@
+package printexport
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Job statuses
+const (
+	JobQueued     = "queued"
+	JobProcessing = "processing"
+	JobReady      = "ready"
+	JobFailed     = "failed"
+)
+
+// ErrJobNotFound is returned for unknown or foreign export jobs
+var ErrJobNotFound = errors.New("export not found")
+
+// Job is one requested export
+type Job struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"-"`
+	Kind      string    `json:"kind"`
+	Ref       string    `json:"ref"`
+	Format    string    `json:"format"`
+	Locale    string    `json:"locale"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Size      int       `json:"size,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store persists export jobs and their output
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates an export store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate creates the export_jobs table
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS export_jobs (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			ref TEXT NOT NULL,
+			format TEXT NOT NULL,
+			locale TEXT NOT NULL,
+			status TEXT NOT NULL,
+			error TEXT,
+			content_type TEXT,
+			output BLOB,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_export_jobs_status ON export_jobs(status, created_at)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Create queues an export
+func (s *Store) Create(ctx context.Context, userID, kind, ref, format, locale string) (Job, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return Job{}, err
+	}
+	now := time.Now().UTC()
+	job := Job{ID: hex.EncodeToString(b), UserID: userID, Kind: kind, Ref: ref, Format: format, Locale: locale,
+		Status: JobQueued, CreatedAt: now, UpdatedAt: now}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO export_jobs (id, user_id, kind, ref, format, locale, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, userID, kind, ref, format, locale, JobQueued, now, now)
+	return job, err
+}
+
+// Get returns a job owned by userID
+func (s *Store) Get(ctx context.Context, userID, id string) (Job, error) {
+	var j Job
+	var errMsg sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, user_id, kind, ref, format, locale, status, error, COALESCE(LENGTH(output), 0), created_at, updated_at
+		 FROM export_jobs WHERE id = ? AND user_id = ?`, id, userID).
+		Scan(&j.ID, &j.UserID, &j.Kind, &j.Ref, &j.Format, &j.Locale, &j.Status, &errMsg, &j.Size, &j.CreatedAt, &j.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return Job{}, ErrJobNotFound
+	}
+	j.Error = errMsg.String
+	return j, err
+}
+
+// Output returns a ready job's file and content type
+func (s *Store) Output(ctx context.Context, userID, id string) ([]byte, string, error) {
+	var data []byte
+	var contentType string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT output, content_type FROM export_jobs WHERE id = ? AND user_id = ? AND status = ?`, id, userID, JobReady).
+		Scan(&data, &contentType)
+	if err == sql.ErrNoRows {
+		return nil, "", ErrJobNotFound
+	}
+	return data, contentType, err
+}
+
+// claim moves one queued job to processing, or returns ok=false
+func (s *Store) claim(ctx context.Context) (job Job, ok bool, err error) {
+	var id string
+	err = s.db.QueryRowContext(ctx, `SELECT id FROM export_jobs WHERE status = ? ORDER BY created_at LIMIT 1`, JobQueued).Scan(&id)
+	if err == sql.ErrNoRows {
+		return Job{}, false, nil
+	}
+	if err != nil {
+		return Job{}, false, err
+	}
+	res, err := s.db.ExecContext(ctx, `UPDATE export_jobs SET status = ?, updated_at = ? WHERE id = ? AND status = ?`,
+		JobProcessing, time.Now().UTC(), id, JobQueued)
+	if err != nil {
+		return Job{}, false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		// Another instance claimed it first
+		return Job{}, false, nil
+	}
+	err = s.db.QueryRowContext(ctx, `SELECT id, user_id, kind, ref, format, locale FROM export_jobs WHERE id = ?`, id).
+		Scan(&job.ID, &job.UserID, &job.Kind, &job.Ref, &job.Format, &job.Locale)
+	job.Status = JobProcessing
+	return job, err == nil, err
+}
+
+// requeueStale returns jobs left processing by a crashed instance to the queue
+func (s *Store) requeueStale(ctx context.Context, olderThan time.Duration) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE export_jobs SET status = ? WHERE status = ? AND updated_at < ?`,
+		JobQueued, JobProcessing, time.Now().UTC().Add(-olderThan))
+	return err
+}
+
+// purge deletes exports older than maxAge; their files are only kept for download
+func (s *Store) purge(ctx context.Context, maxAge time.Duration) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM export_jobs WHERE status IN (?, ?) AND updated_at < ?`,
+		JobReady, JobFailed, time.Now().UTC().Add(-maxAge))
+	return err
+}
+
+func (s *Store) finish(ctx context.Context, j Job, contentType string, output []byte) error {
+	var errMsg interface{}
+	if j.Error != "" {
+		errMsg = j.Error
+	}
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE export_jobs SET status = ?, error = ?, content_type = ?, output = ?, updated_at = ? WHERE id = ?`,
+		j.Status, errMsg, contentType, output, time.Now().UTC(), j.ID)
+	return err
+}
@@ -0,0 +1,64 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ContentType is the RFC 7807 media type
+const ContentType = "application/problem+json"
+
+// Details is an RFC 7807 problem document
+type Details struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Status        int    `json:"status"`
+	Detail        string `json:"detail,omitempty"`
+	Instance      string `json:"instance,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// Extensions are extra members merged into the document (RFC 7807 §3.2)
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON flattens Extensions into the problem object
+func (p Details) MarshalJSON() ([]byte, error) {
+	type plain Details
+	base, err := json.Marshal(plain(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return base, err
+	}
+	merged := map[string]interface{}{}
+	if err := json.Unmarshal(base, &merged); err != nil {
+		return nil, err
+	}
+	for k, v := range p.Extensions {
+		if _, taken := merged[k]; !taken {
+			merged[k] = v
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// New builds a problem with the standard title for the status
+func New(status int, detail string) Details {
+	return Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Write sends p as problem+json, filling in the request path and correlation ID
+func Write(c echo.Context, p Details) error {
+	if p.Instance == "" {
+		p.Instance = c.Request().URL.Path
+	}
+	if p.CorrelationID == "" {
+		p.CorrelationID = c.Response().Header().Get("X-Correlation-ID")
+	}
+	c.Response().Header().Set(echo.HeaderContentType, ContentType)
+	return c.JSON(p.Status, p)
+}
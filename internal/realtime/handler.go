@@ -0,0 +1,182 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+// Authorizer reports whether viewer may watch userID's events, e.g. a coach and
+// their client; nil allows only a user's own stream
+type Authorizer func(ctx context.Context, viewerID, userID string) (bool, error)
+
+// Handler serves realtime streams over WebSocket, falling back to SSE
+type Handler struct {
+	hub       *Hub
+	authorize Authorizer
+}
+
+// NewHandler creates a realtime handler
+func NewHandler(hub *Hub, authorize Authorizer) *Handler {
+	return &Handler{hub: hub, authorize: authorize}
+}
+
+// RegisterRoutes mounts /realtime on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/realtime", h.Stream)
+}
+
+// Stream upgrades to a WebSocket when asked and otherwise serves an SSE stream.
+// ?watch=a,b adds other users' events for viewers allowed to see them. Events
+// missed while disconnected are recovered through /sync, not replayed here.
+func (h *Handler) Stream(c echo.Context) error {
+	viewer := reqctx.UserID(c)
+	if viewer == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	users, err := h.audience(c, viewer)
+	if err != nil {
+		return err
+	}
+	sub, err := h.hub.Subscribe(viewer, users)
+	if errors.Is(err, ErrTooManyStreams) {
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	defer sub.Close()
+
+	if strings.EqualFold(c.Request().Header.Get(echo.HeaderUpgrade), "websocket") {
+		srv := websocket.Server{
+			Handshake: h.checkOrigin,
+			Handler:   func(ws *websocket.Conn) { h.serveSocket(ws, sub) },
+		}
+		srv.ServeHTTP(c.Response(), c.Request())
+		return nil
+	}
+	return h.serveEvents(c, sub)
+}
+
+func (h *Handler) audience(c echo.Context, viewer string) ([]string, error) {
+	users := []string{viewer}
+	for _, u := range strings.Split(c.QueryParam("watch"), ",") {
+		if u = strings.TrimSpace(u); u == "" || u == viewer {
+			continue
+		}
+		ok := false
+		if h.authorize != nil {
+			var err error
+			if ok, err = h.authorize(c.Request().Context(), viewer, u); err != nil {
+				return nil, err
+			}
+		}
+		if !ok {
+			return nil, echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("not allowed to watch %s", u))
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// checkOrigin allows non-browser clients (no Origin), the API's own host, and
+// REALTIME_ALLOWED_ORIGINS
+func (h *Handler) checkOrigin(cfg *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(u.Host, req.Host) {
+		return nil
+	}
+	for _, allowed := range h.hub.cfg.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return nil
+		}
+	}
+	return fmt.Errorf("origin %s not allowed", origin)
+}
+
+func (h *Handler) serveSocket(ws *websocket.Conn, sub *Subscription) {
+	defer ws.Close()
+	// Client frames are not used; reading detects the close
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		var discard json.RawMessage
+		for websocket.JSON.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	heartbeat := time.NewTicker(h.hub.cfg.Heartbeat)
+	defer heartbeat.Stop()
+	send := func(m Message) bool {
+		ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return websocket.JSON.Send(ws, m) == nil
+	}
+	for {
+		select {
+		case <-gone:
+			return
+		case msg, ok := <-sub.C:
+			if !ok || !send(msg) {
+				return
+			}
+		case now := <-heartbeat.C:
+			if !send(Message{Topic: TopicHeartbeat, At: now.UTC()}) {
+				return
+			}
+		}
+	}
+}
+
+func (h *Handler) serveEvents(c echo.Context, sub *Subscription) error {
+	w := c.Response()
+	// The server's write timeout would otherwise cut the stream
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", 3000)
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.hub.cfg.Heartbeat)
+	defer heartbeat.Stop()
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			if msg.ID > 0 {
+				fmt.Fprintf(w, "id: %d\n", msg.ID)
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Topic, data)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		w.Flush()
+	}
+}
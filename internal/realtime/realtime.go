@@ -0,0 +1,263 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/outbox"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// UserHeader is the outbox header carrying the subject user; the same header
+// the analytics opt-out filter reads
+const UserHeader = "user_id"
+
+// AudienceHeader lists further comma-separated user IDs to notify, e.g. the
+// coach on a client's diary change or the client on a coach comment
+const AudienceHeader = "audience"
+
+// TopicHeartbeat is sent on idle WebSocket streams
+const TopicHeartbeat = "realtime.heartbeat"
+
+// ErrTooManyStreams is returned when a user already has MaxStreams open
+var ErrTooManyStreams = errors.New("too many open realtime streams")
+
+// Config controls which events are pushed and stream limits
+type Config struct {
+	Topics         []string
+	Heartbeat      time.Duration
+	Buffer         int
+	MaxStreams     int
+	AllowedOrigins []string
+}
+
+// LoadConfig reads REALTIME_TOPICS (topic prefixes), REALTIME_HEARTBEAT, REALTIME_BUFFER,
+// REALTIME_MAX_STREAMS and REALTIME_ALLOWED_ORIGINS
+func LoadConfig() Config {
+	return Config{
+		Topics:         envconfig.List("REALTIME_TOPICS", []string{"diary.", "coach.", "notification."}),
+		Heartbeat:      envconfig.Duration("REALTIME_HEARTBEAT", 25*time.Second),
+		Buffer:         envconfig.Int("REALTIME_BUFFER", 64),
+		MaxStreams:     envconfig.Int("REALTIME_MAX_STREAMS", 5),
+		AllowedOrigins: envconfig.List("REALTIME_ALLOWED_ORIGINS", nil),
+	}
+}
+
+// Validate rejects empty topics and non-positive limits
+func (c Config) Validate() error {
+	if len(c.Topics) == 0 {
+		return fmt.Errorf("REALTIME_TOPICS must list at least one topic prefix")
+	}
+	if c.Heartbeat <= 0 || c.Buffer <= 0 || c.MaxStreams <= 0 {
+		return fmt.Errorf("REALTIME_HEARTBEAT, REALTIME_BUFFER and REALTIME_MAX_STREAMS must be positive")
+	}
+	return nil
+}
+
+// Message is an event pushed to clients
+type Message struct {
+	ID      int64           `json:"id,omitempty"`
+	Topic   string          `json:"topic"`
+	UserID  string          `json:"user_id,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	At      time.Time       `json:"at"`
+}
+
+// Hub fans outbox events out to the streams open on this instance. With Redis
+// it listens on the RedisPublisher's event channels, so every instance sees
+// every event; without Redis it must be added to the relay's publishers.
+type Hub struct {
+	cfg    Config
+	client *redis.Client
+
+	mu      sync.Mutex
+	streams map[string]map[*Subscription]struct{}
+	open    map[string]int
+	closed  bool
+}
+
+// NewHub creates a hub; client may be nil for a single instance
+func NewHub(client *redis.Client, cfg Config) *Hub {
+	return &Hub{
+		cfg:     cfg,
+		client:  client,
+		streams: map[string]map[*Subscription]struct{}{},
+		open:    map[string]int{},
+	}
+}
+
+// Subscription receives messages for a set of users until closed. C is closed
+// when the hub drops the stream, because the client fell behind or on shutdown.
+type Subscription struct {
+	C <-chan Message
+
+	ch     chan Message
+	hub    *Hub
+	viewer string
+	users  []string
+	done   bool
+}
+
+// Subscribe opens a stream for viewer receiving events addressed to users
+func (h *Hub) Subscribe(viewer string, users []string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, errors.New("realtime hub is shut down")
+	}
+	if h.open[viewer] >= h.cfg.MaxStreams {
+		return nil, ErrTooManyStreams
+	}
+	ch := make(chan Message, h.cfg.Buffer)
+	s := &Subscription{C: ch, ch: ch, hub: h, viewer: viewer, users: users}
+	for _, u := range users {
+		if h.streams[u] == nil {
+			h.streams[u] = map[*Subscription]struct{}{}
+		}
+		h.streams[u][s] = struct{}{}
+	}
+	h.open[viewer]++
+	return s, nil
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s)
+}
+
+func (h *Hub) removeLocked(s *Subscription) {
+	if s.done {
+		return
+	}
+	s.done = true
+	for _, u := range s.users {
+		delete(h.streams[u], s)
+		if len(h.streams[u]) == 0 {
+			delete(h.streams, u)
+		}
+	}
+	if h.open[s.viewer]--; h.open[s.viewer] <= 0 {
+		delete(h.open, s.viewer)
+	}
+	close(s.ch)
+}
+
+// Streams is the number of open streams on this instance
+func (h *Hub) Streams() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := 0
+	for _, c := range h.open {
+		n += c
+	}
+	return n
+}
+
+// Publish delivers ev to local streams; used as an outbox publisher without Redis
+func (h *Hub) Publish(ctx context.Context, ev outbox.Event) error {
+	h.deliver(ev)
+	return nil
+}
+
+// Start relays Redis event channels to local streams until ctx is cancelled,
+// then closes every stream so handlers return before the server shuts down
+func (h *Hub) Start(ctx context.Context) {
+	defer h.shutdown()
+	if h.client == nil {
+		<-ctx.Done()
+		return
+	}
+	patterns := make([]string, len(h.cfg.Topics))
+	for i, t := range h.cfg.Topics {
+		patterns[i] = "events:" + t + "*"
+	}
+	ps := h.client.PSubscribe(ctx, patterns...)
+	defer ps.Close()
+	ch := ps.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var ev outbox.Event
+			if err := json.Unmarshal([]byte(msg.Payload), &ev); err != nil {
+				log.Printf("⚠️ Realtime: bad event on %s: %v", msg.Channel, err)
+				continue
+			}
+			h.deliver(ev)
+		}
+	}
+}
+
+func (h *Hub) shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, subs := range h.streams {
+		for s := range subs {
+			h.removeLocked(s)
+		}
+	}
+}
+
+func (h *Hub) wants(topic string) bool {
+	for _, t := range h.cfg.Topics {
+		if strings.HasPrefix(topic, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hub) deliver(ev outbox.Event) {
+	if !h.wants(ev.Topic) {
+		return
+	}
+	msg := Message{ID: ev.ID, Topic: ev.Topic, UserID: ev.Headers[UserHeader], Payload: ev.Payload, At: ev.CreatedAt}
+	recipients := recipients(ev.Headers)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sent := map[*Subscription]bool{}
+	for _, u := range recipients {
+		for s := range h.streams[u] {
+			if sent[s] {
+				continue
+			}
+			sent[s] = true
+			select {
+			case s.ch <- msg:
+			default:
+				// A client this far behind resyncs on reconnect
+				log.Printf("⚠️ Realtime: dropping slow stream for %s", s.viewer)
+				h.removeLocked(s)
+			}
+		}
+	}
+}
+
+func recipients(headers map[string]string) []string {
+	var out []string
+	if u := headers[UserHeader]; u != "" {
+		out = append(out, u)
+	}
+	for _, u := range strings.Split(headers[AudienceHeader], ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			out = append(out, u)
+		}
+	}
+	return out
+}
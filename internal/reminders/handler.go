@@ -0,0 +1,200 @@
+package reminders
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes reminder rules over HTTP
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a reminder handler
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes mounts the reminder routes on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/reminders", h.List)
+	g.POST("/reminders", h.Create)
+	g.GET("/reminders/quiet-hours", h.GetQuietHours)
+	g.PUT("/reminders/quiet-hours", h.SetQuietHours)
+	g.PUT("/reminders/:id", h.Update)
+	g.DELETE("/reminders/:id", h.Delete)
+	g.POST("/reminders/:id/snooze", h.Snooze)
+}
+
+type ruleRequest struct {
+	Kind    Kind           `json:"kind"`
+	Meal    string         `json:"meal"`
+	Time    *Clock         `json:"time"`
+	Days    []time.Weekday `json:"days"`
+	Message string         `json:"message"`
+	Enabled *bool          `json:"enabled"`
+}
+
+// rule builds and validates the rule, so store errors are never client errors
+func (req ruleRequest) rule(userID string) (Rule, error) {
+	if req.Time == nil {
+		return Rule{}, errors.New("time is required")
+	}
+	r := Rule{UserID: userID, Kind: req.Kind, Meal: req.Meal, Time: *req.Time, Days: req.Days, Message: req.Message, Enabled: true}
+	if req.Enabled != nil {
+		r.Enabled = *req.Enabled
+	}
+	return r, r.Validate()
+}
+
+// List returns the user's rules
+func (h *Handler) List(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	rules, err := h.store.List(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"reminders": rules})
+}
+
+// Create adds a rule
+func (h *Handler) Create(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	var req ruleRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body: time must be HH:MM")
+	}
+	r, err := req.rule(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if r, err = h.store.Create(c.Request().Context(), r); err != nil {
+		return h.error(err)
+	}
+	return c.JSON(http.StatusCreated, r)
+}
+
+// Update replaces a rule
+func (h *Handler) Update(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid reminder id")
+	}
+	var req ruleRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body: time must be HH:MM")
+	}
+	r, err := req.rule(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	r.ID = id
+	if r, err = h.store.Update(c.Request().Context(), r); err != nil {
+		return h.error(err)
+	}
+	return c.JSON(http.StatusOK, r)
+}
+
+// Delete removes a rule
+func (h *Handler) Delete(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid reminder id")
+	}
+	if err := h.store.Delete(c.Request().Context(), userID, id); err != nil {
+		return h.error(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Snooze repeats the last reminder after ?minutes= (default 15, at most a day)
+func (h *Handler) Snooze(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid reminder id")
+	}
+	minutes := 15
+	if v := c.QueryParam("minutes"); v != "" {
+		if minutes, err = strconv.Atoi(v); err != nil || minutes < 1 || minutes > 24*60 {
+			return echo.NewHTTPError(http.StatusBadRequest, "minutes must be between 1 and 1440")
+		}
+	}
+	r, err := h.store.Snooze(c.Request().Context(), userID, id, time.Duration(minutes)*time.Minute)
+	if err != nil {
+		return h.error(err)
+	}
+	return c.JSON(http.StatusOK, r)
+}
+
+// GetQuietHours returns the user's quiet window, null when unset
+func (h *Handler) GetQuietHours(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	q, err := h.store.QuietHours(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"quiet_hours": q})
+}
+
+// SetQuietHours sets the window from {"start":"22:00","end":"07:00"}, or clears it with {}
+func (h *Handler) SetQuietHours(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	var req struct {
+		Start *Clock `json:"start"`
+		End   *Clock `json:"end"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body: times must be HH:MM")
+	}
+	var q *QuietHours
+	switch {
+	case req.Start != nil && req.End != nil:
+		q = &QuietHours{Start: *req.Start, End: *req.End}
+	case req.Start != nil || req.End != nil:
+		return echo.NewHTTPError(http.StatusBadRequest, "start and end are both required")
+	}
+	if err := h.store.SetQuietHours(c.Request().Context(), userID, q); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"quiet_hours": q})
+}
+
+func (h *Handler) error(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNothingToSnooze):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package reminders
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/localtime"
+)
+
+// Activity reports what the user has already logged, backed by the diary and
+// measurements; conditional rules fire only when the answer is no
+type Activity interface {
+	MealLogged(ctx context.Context, userID, meal string, from, to time.Time) (bool, error)
+	WeightLogged(ctx context.Context, userID string, from, to time.Time) (bool, error)
+}
+
+// Job evaluates due reminder rules and publishes the reminders that apply
+type Job struct {
+	store    *Store
+	activity Activity
+	// Interval is how often due rules are checked (REMINDER_POLL_INTERVAL)
+	Interval time.Duration
+	// MaxLate drops occurrences this far overdue, e.g. after downtime (REMINDER_MAX_LATE)
+	MaxLate time.Duration
+}
+
+// NewJob creates the reminder job; with a nil activity conditional rules always fire
+func NewJob(store *Store, activity Activity) *Job {
+	return &Job{
+		store:    store,
+		activity: activity,
+		Interval: envconfig.Duration("REMINDER_POLL_INTERVAL", time.Minute),
+		MaxLate:  envconfig.Duration("REMINDER_MAX_LATE", time.Hour),
+	}
+}
+
+// Start checks for due rules every Interval until the context is cancelled
+func (j *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				log.Printf("⚠️ Reminder job failed: %v", err)
+			}
+		}
+	}
+}
+
+// RunOnce evaluates due rules and returns how many reminders were sent; a
+// backlog larger than one batch carries over to the next tick
+func (j *Job) RunOnce(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	rules, err := j.store.due(ctx, now, 500)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, r := range rules {
+		n, err := j.evaluate(ctx, r, now)
+		if err != nil {
+			log.Printf("⚠️ Reminder %d for user %s failed: %v", r.ID, r.UserID, err)
+		}
+		sent += n
+	}
+	if sent > 0 {
+		log.Printf("🔔 Sent %d reminders", sent)
+	}
+	return sent, nil
+}
+
+func (j *Job) evaluate(ctx context.Context, r Rule, now time.Time) (int, error) {
+	sent := 0
+	if r.SnoozeAt != nil && !r.SnoozeAt.After(now) {
+		n, err := j.notification(ctx, r, *r.lastOccurrence, true)
+		if err != nil {
+			return sent, err
+		}
+		if err := j.store.endSnooze(ctx, r, now, n); err != nil {
+			return sent, err
+		}
+		if n != nil {
+			sent++
+		}
+	}
+	if !r.NextAt.After(now) {
+		var n *Notification
+		if now.Sub(r.NextAt) <= j.MaxLate {
+			var err error
+			if n, err = j.notification(ctx, r, r.Occurrence, false); err != nil {
+				return sent, err
+			}
+		}
+		if err := j.store.advance(ctx, r, now, n); err != nil {
+			return sent, err
+		}
+		if n != nil {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// notification returns the reminder for occurrence, or nil when its condition
+// is already met for that local day
+func (j *Job) notification(ctx context.Context, r Rule, occurrence time.Time, snoozed bool) (*Notification, error) {
+	if j.activity != nil && r.Kind != Custom {
+		loc, err := j.store.locator.Location(ctx, r.UserID)
+		if err != nil {
+			return nil, err
+		}
+		from, to := localtime.DayBounds(occurrence, loc)
+		var done bool
+		switch r.Kind {
+		case MealNotLogged:
+			done, err = j.activity.MealLogged(ctx, r.UserID, r.Meal, from, to)
+		case WeighIn:
+			done, err = j.activity.WeightLogged(ctx, r.UserID, from, to)
+		}
+		if err != nil || done {
+			return nil, err
+		}
+	}
+	return &Notification{RuleID: r.ID, Kind: r.Kind, Meal: r.Meal, Text: r.Text(), Occurrence: occurrence, Snoozed: snoozed}, nil
+}
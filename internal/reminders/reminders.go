@@ -0,0 +1,191 @@
+package reminders
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/localtime"
+)
+
+// Kind is what a rule reminds the user about
+type Kind string
+
+const (
+	// MealNotLogged fires only when Meal has no diary entry that day
+	MealNotLogged Kind = "meal_not_logged"
+	// WeighIn fires only when no weight was recorded that day
+	WeighIn Kind = "weigh_in"
+	// Custom always fires
+	Custom Kind = "custom"
+)
+
+// Kinds lists every supported rule kind
+var Kinds = []Kind{MealNotLogged, WeighIn, Custom}
+
+// Meals are the diary meals a MealNotLogged rule may watch
+var Meals = []string{"breakfast", "lunch", "dinner", "snack"}
+
+// TopicReminder is the outbox topic reminders are delivered on
+const TopicReminder = "notification.reminder"
+
+// Rule is a user's reminder: at Time local time on Days, when its condition holds
+type Rule struct {
+	ID      int64          `json:"id"`
+	UserID  string         `json:"-"`
+	Kind    Kind           `json:"kind"`
+	Meal    string         `json:"meal,omitempty"`
+	Time    Clock          `json:"time"`
+	Days    []time.Weekday `json:"days,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Enabled bool           `json:"enabled"`
+	// Occurrence is the next scheduled firing; NextAt is when it is evaluated,
+	// later than Occurrence when deferred by quiet hours
+	Occurrence time.Time  `json:"occurrence"`
+	NextAt     time.Time  `json:"next_at"`
+	SnoozeAt   *time.Time `json:"snoozed_until,omitempty"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	// lastOccurrence is the occurrence the last reminder was sent for; a snooze repeats it
+	lastOccurrence *time.Time
+}
+
+// Notification is the payload published on TopicReminder
+type Notification struct {
+	RuleID     int64     `json:"rule_id"`
+	Kind       Kind      `json:"kind"`
+	Meal       string    `json:"meal,omitempty"`
+	Text       string    `json:"text"`
+	Occurrence time.Time `json:"occurrence"`
+	Snoozed    bool      `json:"snoozed,omitempty"`
+}
+
+// Validate checks the rule before it is stored
+func (r Rule) Validate() error {
+	switch r.Kind {
+	case MealNotLogged:
+		if !validMeal(r.Meal) {
+			return fmt.Errorf("meal must be one of %s", strings.Join(Meals, ", "))
+		}
+	case WeighIn, Custom:
+	default:
+		return fmt.Errorf("unknown reminder kind %q", r.Kind)
+	}
+	if r.Kind == Custom && strings.TrimSpace(r.Message) == "" {
+		return fmt.Errorf("custom reminders need a message")
+	}
+	if len(r.Message) > 200 {
+		return fmt.Errorf("message must be at most 200 characters")
+	}
+	for _, d := range r.Days {
+		if d < time.Sunday || d > time.Saturday {
+			return fmt.Errorf("days must be 0 (Sunday) to 6 (Saturday)")
+		}
+	}
+	return nil
+}
+
+func validMeal(m string) bool {
+	for _, known := range Meals {
+		if m == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Text is the notification body
+func (r Rule) Text() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	switch r.Kind {
+	case MealNotLogged:
+		return fmt.Sprintf("Don't forget to log your %s", r.Meal)
+	case WeighIn:
+		return "Time for your weigh-in"
+	}
+	return "Reminder"
+}
+
+// Clock is a local wall-clock time, "HH:MM" in JSON
+type Clock struct {
+	Hour, Minute int
+}
+
+// ParseClock parses "HH:MM"
+func ParseClock(s string) (Clock, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return Clock{}, fmt.Errorf("time must be HH:MM")
+	}
+	return Clock{Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+func (c Clock) String() string { return fmt.Sprintf("%02d:%02d", c.Hour, c.Minute) }
+
+func (c Clock) minutes() int { return c.Hour*60 + c.Minute }
+
+func (c Clock) MarshalText() ([]byte, error) { return []byte(c.String()), nil }
+
+func (c *Clock) UnmarshalText(b []byte) error {
+	parsed, err := ParseClock(string(b))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// QuietHours is a daily window, possibly overnight (22:00-07:00), in which
+// reminders are held until it ends
+type QuietHours struct {
+	Start Clock `json:"start"`
+	End   Clock `json:"end"`
+}
+
+// Contains reports whether t falls in the window in loc
+func (q *QuietHours) Contains(t time.Time, loc *time.Location) bool {
+	if q == nil || q.Start == q.End {
+		return false
+	}
+	l := t.In(loc)
+	m := l.Hour()*60 + l.Minute()
+	start, end := q.Start.minutes(), q.End.minutes()
+	if start < end {
+		return m >= start && m < end
+	}
+	return m >= start || m < end
+}
+
+// Defer moves t to the end of the window when it falls inside it
+func (q *QuietHours) Defer(t time.Time, loc *time.Location) time.Time {
+	if !q.Contains(t, loc) {
+		return t
+	}
+	return localtime.Next(t, loc, q.End.Hour, q.End.Minute)
+}
+
+// next returns the rule's first occurrence after t in loc
+func (r Rule) next(t time.Time, loc *time.Location) time.Time {
+	at := t
+	for i := 0; i < 8; i++ {
+		at = localtime.Next(at, loc, r.Time.Hour, r.Time.Minute)
+		if r.onDay(at.In(loc).Weekday()) {
+			return at
+		}
+	}
+	return at
+}
+
+func (r Rule) onDay(d time.Weekday) bool {
+	if len(r.Days) == 0 {
+		return true
+	}
+	for _, day := range r.Days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
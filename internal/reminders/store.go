@@ -0,0 +1,340 @@
+package reminders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/outbox"
+	"nutrition-health-backend/internal/txn"
+)
+
+// ErrNotFound is returned for a rule the user does not own
+var ErrNotFound = errors.New("reminder not found")
+
+// Locator resolves a user's timezone; implemented by localtime.Store
+type Locator interface {
+	Location(ctx context.Context, userID string) (*time.Location, error)
+}
+
+// Store persists reminder rules and per-user quiet hours
+type Store struct {
+	db      *sql.DB
+	txm     *txn.Manager
+	locator Locator
+}
+
+// NewStore creates a reminder store
+func NewStore(db *sql.DB, locator Locator) *Store {
+	return &Store{db: db, txm: txn.NewManager(db), locator: locator}
+}
+
+// Migrate creates the reminder_rules and reminder_settings tables
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS reminder_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			meal TEXT NOT NULL DEFAULT '',
+			at_time TEXT NOT NULL,
+			days TEXT NOT NULL DEFAULT '',
+			message TEXT NOT NULL DEFAULT '',
+			enabled INTEGER NOT NULL DEFAULT 1,
+			occurrence_at DATETIME NOT NULL,
+			next_at DATETIME NOT NULL,
+			snooze_at DATETIME,
+			last_sent_at DATETIME,
+			last_occurrence_at DATETIME,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_reminder_rules_user ON reminder_rules(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_reminder_rules_due ON reminder_rules(enabled, next_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_reminder_rules_snooze ON reminder_rules(snooze_at) WHERE snooze_at IS NOT NULL`,
+		`CREATE TABLE IF NOT EXISTS reminder_settings (
+			user_id TEXT PRIMARY KEY,
+			quiet_start TEXT NOT NULL,
+			quiet_end TEXT NOT NULL
+		)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schedule sets the rule's next occurrence after now, deferred past quiet hours
+func (s *Store) schedule(ctx context.Context, r *Rule, after time.Time) error {
+	loc, err := s.locator.Location(ctx, r.UserID)
+	if err != nil {
+		return err
+	}
+	quiet, err := s.QuietHours(ctx, r.UserID)
+	if err != nil {
+		return err
+	}
+	r.Occurrence = r.next(after, loc)
+	r.NextAt = quiet.Defer(r.Occurrence, loc)
+	return nil
+}
+
+// Create validates, schedules and stores a rule
+func (s *Store) Create(ctx context.Context, r Rule) (Rule, error) {
+	if err := r.Validate(); err != nil {
+		return r, err
+	}
+	r.CreatedAt = time.Now().UTC()
+	if err := s.schedule(ctx, &r, r.CreatedAt); err != nil {
+		return r, err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO reminder_rules (user_id, kind, meal, at_time, days, message, enabled, occurrence_at, next_at, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.UserID, r.Kind, r.Meal, r.Time.String(), formatDays(r.Days), r.Message, r.Enabled, r.Occurrence, r.NextAt, r.CreatedAt)
+	if err != nil {
+		return r, err
+	}
+	r.ID, err = res.LastInsertId()
+	return r, err
+}
+
+// Update replaces a rule's settings and reschedules it
+func (s *Store) Update(ctx context.Context, r Rule) (Rule, error) {
+	if err := r.Validate(); err != nil {
+		return r, err
+	}
+	existing, err := s.Get(ctx, r.UserID, r.ID)
+	if err != nil {
+		return r, err
+	}
+	r.CreatedAt, r.LastSentAt, r.lastOccurrence = existing.CreatedAt, existing.LastSentAt, existing.lastOccurrence
+	if err := s.schedule(ctx, &r, time.Now().UTC()); err != nil {
+		return r, err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE reminder_rules SET kind = ?, meal = ?, at_time = ?, days = ?, message = ?, enabled = ?, occurrence_at = ?, next_at = ?,
+		 snooze_at = NULL WHERE id = ? AND user_id = ?`,
+		r.Kind, r.Meal, r.Time.String(), formatDays(r.Days), r.Message, r.Enabled, r.Occurrence, r.NextAt, r.ID, r.UserID)
+	return r, err
+}
+
+// Delete removes a rule
+func (s *Store) Delete(ctx context.Context, userID string, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM reminder_rules WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const ruleColumns = `id, user_id, kind, meal, at_time, days, message, enabled, occurrence_at, next_at, snooze_at, last_sent_at,
+	last_occurrence_at, created_at`
+
+// Get returns one of the user's rules
+func (s *Store) Get(ctx context.Context, userID string, id int64) (Rule, error) {
+	rules, err := s.query(ctx, `SELECT `+ruleColumns+` FROM reminder_rules WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return Rule{}, err
+	}
+	if len(rules) == 0 {
+		return Rule{}, ErrNotFound
+	}
+	return rules[0], nil
+}
+
+// List returns the user's rules
+func (s *Store) List(ctx context.Context, userID string) ([]Rule, error) {
+	return s.query(ctx, `SELECT `+ruleColumns+` FROM reminder_rules WHERE user_id = ? ORDER BY at_time, id`, userID)
+}
+
+// due returns enabled rules whose occurrence or snooze has come up
+func (s *Store) due(ctx context.Context, now time.Time, limit int) ([]Rule, error) {
+	return s.query(ctx, `SELECT `+ruleColumns+` FROM reminder_rules
+		WHERE enabled = 1 AND (next_at <= ? OR snooze_at <= ?) ORDER BY next_at LIMIT ?`, now, now, limit)
+}
+
+func (s *Store) query(ctx context.Context, query string, args ...interface{}) ([]Rule, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Rule
+	for rows.Next() {
+		var r Rule
+		var at, days string
+		var snooze, sent, last sql.NullTime
+		if err := rows.Scan(&r.ID, &r.UserID, &r.Kind, &r.Meal, &at, &days, &r.Message, &r.Enabled,
+			&r.Occurrence, &r.NextAt, &snooze, &sent, &last, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		if r.Time, err = ParseClock(at); err != nil {
+			return nil, err
+		}
+		r.Days = parseDays(days)
+		r.SnoozeAt, r.LastSentAt, r.lastOccurrence = timePtr(snooze), timePtr(sent), timePtr(last)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// ErrNothingToSnooze is returned when the rule has not sent a reminder yet
+var ErrNothingToSnooze = errors.New("no reminder has been sent for this rule yet")
+
+// Snooze repeats the last reminder after d, held past quiet hours; a
+// conditional rule is re-checked, so logging the meal meanwhile cancels it
+func (s *Store) Snooze(ctx context.Context, userID string, id int64, d time.Duration) (Rule, error) {
+	r, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return r, err
+	}
+	if r.lastOccurrence == nil {
+		return r, ErrNothingToSnooze
+	}
+	loc, err := s.locator.Location(ctx, userID)
+	if err != nil {
+		return r, err
+	}
+	quiet, err := s.QuietHours(ctx, userID)
+	if err != nil {
+		return r, err
+	}
+	at := quiet.Defer(time.Now().UTC().Add(d), loc)
+	r.SnoozeAt = &at
+	_, err = s.db.ExecContext(ctx, `UPDATE reminder_rules SET snooze_at = ? WHERE id = ? AND user_id = ?`, at, id, userID)
+	return r, err
+}
+
+// advance moves past the rule's current occurrence, recording and enqueuing n
+// in the same transaction when it is sent
+func (s *Store) advance(ctx context.Context, r Rule, now time.Time, n *Notification) error {
+	next := r
+	if err := s.schedule(ctx, &next, maxTime(r.Occurrence, now)); err != nil {
+		return err
+	}
+	query := `UPDATE reminder_rules SET occurrence_at = ?, next_at = ?`
+	args := []interface{}{next.Occurrence, next.NextAt}
+	if n != nil {
+		// A fresh occurrence replaces any pending snooze of the previous one
+		query += `, last_sent_at = ?, last_occurrence_at = ?, snooze_at = NULL`
+		args = append(args, now, r.Occurrence)
+	}
+	// The next_at guard skips rules edited while being evaluated
+	return s.record(ctx, r, n, query+` WHERE id = ? AND next_at = ?`, append(args, r.ID, r.NextAt)...)
+}
+
+// endSnooze clears a due snooze, recording and enqueuing n when it is sent
+func (s *Store) endSnooze(ctx context.Context, r Rule, now time.Time, n *Notification) error {
+	query := `UPDATE reminder_rules SET snooze_at = NULL`
+	var args []interface{}
+	if n != nil {
+		query += `, last_sent_at = ?`
+		args = append(args, now)
+	}
+	return s.record(ctx, r, n, query+` WHERE id = ? AND snooze_at = ?`, append(args, r.ID, *r.SnoozeAt)...)
+}
+
+func (s *Store) record(ctx context.Context, r Rule, n *Notification, query string, args ...interface{}) error {
+	return s.txm.Do(ctx, func(ctx context.Context) error {
+		q := s.txm.Querier(ctx)
+		res, err := q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 || n == nil {
+			return nil
+		}
+		return outbox.Enqueue(ctx, q, TopicReminder, strconv.FormatInt(r.ID, 10), n, map[string]string{"user_id": r.UserID})
+	})
+}
+
+// QuietHours returns the user's quiet window, or nil when none is set
+func (s *Store) QuietHours(ctx context.Context, userID string) (*QuietHours, error) {
+	var start, end string
+	err := s.db.QueryRowContext(ctx, `SELECT quiet_start, quiet_end FROM reminder_settings WHERE user_id = ?`, userID).Scan(&start, &end)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var q QuietHours
+	if q.Start, err = ParseClock(start); err != nil {
+		return nil, err
+	}
+	if q.End, err = ParseClock(end); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// SetQuietHours stores the window, or clears it when q is nil, and reschedules the user's rules
+func (s *Store) SetQuietHours(ctx context.Context, userID string, q *QuietHours) error {
+	var err error
+	if q == nil {
+		_, err = s.db.ExecContext(ctx, `DELETE FROM reminder_settings WHERE user_id = ?`, userID)
+	} else {
+		_, err = s.db.ExecContext(ctx,
+			`INSERT INTO reminder_settings (user_id, quiet_start, quiet_end) VALUES (?, ?, ?)
+			 ON CONFLICT(user_id) DO UPDATE SET quiet_start = excluded.quiet_start, quiet_end = excluded.quiet_end`,
+			userID, q.Start.String(), q.End.String())
+	}
+	if err != nil {
+		return err
+	}
+	loc, err := s.locator.Location(ctx, userID)
+	if err != nil {
+		return err
+	}
+	rules, err := s.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if _, err := s.db.ExecContext(ctx, `UPDATE reminder_rules SET next_at = ? WHERE id = ?`,
+			q.Defer(r.Occurrence, loc), r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatDays(days []time.Weekday) string {
+	parts := make([]string, len(days))
+	for i, d := range days {
+		parts[i] = strconv.Itoa(int(d))
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseDays(s string) []time.Weekday {
+	var out []time.Weekday
+	for _, part := range strings.Split(s, ",") {
+		if n, err := strconv.Atoi(part); err == nil {
+			out = append(out, time.Weekday(n))
+		}
+	}
+	return out
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
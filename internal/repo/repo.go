@@ -0,0 +1,23 @@
+// Package repo holds the type-safe queries sqlc generates from db/queries
+// against db/schema.sql, which is dumped from the migrations. Only this file
+// is written by hand.
+package repo
+
+//go:generate go run ../.. schema-dump ../../db/schema.sql
+//go:generate sqlc generate -f ../../sqlc.yaml
+
+import (
+	"context"
+	"database/sql"
+
+	"nutrition-health-backend/internal/txn"
+)
+
+// FromContext returns queries on the transaction txn.Manager.Do bound to
+// ctx, or on db outside a unit of work
+func FromContext(ctx context.Context, db *sql.DB) *Queries {
+	if tx, ok := txn.FromContext(ctx); ok {
+		return New(tx)
+	}
+	return New(db)
+}
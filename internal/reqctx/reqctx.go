@@ -0,0 +1,24 @@
+package reqctx
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UserIDKey is the echo context key the auth middleware stores the user ID under
+const UserIDKey = "user_id"
+
+// UserID returns the authenticated user ID, or "" for anonymous requests
+func UserID(c echo.Context) string {
+	switch v := c.Get(UserIDKey).(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,100 @@
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"nutrition-health-backend/internal/runtimecfg"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterRoutes mounts the retention admin API on an admin-protected group
+func (j *Job) RegisterRoutes(g *echo.Group) {
+	g.GET("/retention", j.handleStatus)
+	g.POST("/retention/run", j.handleRun)
+}
+
+func (j *Job) handleStatus(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"dry_run":  j.cfg.DryRun,
+		"run_hour": j.cfg.RunHour,
+		"policies": j.policies,
+		"last_run": j.Last(),
+	})
+}
+
+// handleRun prunes now; ?dry_run=true only counts, and defaults to RETENTION_DRY_RUN
+func (j *Job) handleRun(c echo.Context) error {
+	dryRun := j.cfg.DryRun
+	if v := c.QueryParam("dry_run"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "dry_run must be true or false")
+		}
+		dryRun = b
+	}
+	who := c.Request().Header.Get(runtimecfg.ActorHeader)
+	if who == "" {
+		who = "admin@" + c.RealIP()
+	}
+	run, err := j.RunOnce(c.Request().Context(), dryRun)
+	if errors.Is(err, ErrRunning) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("AUDIT retention run by %s: dry_run=%t rows=%d", who, dryRun, run.Rows)
+	return c.JSON(http.StatusOK, run)
+}
+
+// MetricsHandler exports rows purged in the Prometheus text format
+func (j *Job) MetricsHandler(c echo.Context) error {
+	j.mu.Lock()
+	keys := make([]string, 0, len(j.purged))
+	for k := range j.purged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	purged, failed := make(map[string]int64, len(keys)), make(map[string]int64, len(keys))
+	for _, k := range keys {
+		purged[k], failed[k] = j.purged[k], j.errors[k]
+	}
+	last := j.last
+	j.mu.Unlock()
+
+	labels := func(key string) string {
+		policy, target, _ := strings.Cut(key, "|")
+		return fmt.Sprintf("policy=%s,target=%s", strconv.Quote(policy), strconv.Quote(target))
+	}
+	var b strings.Builder
+	b.WriteString("# HELP retention_purged_rows_total Rows deleted by retention policies.\n")
+	b.WriteString("# TYPE retention_purged_rows_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "retention_purged_rows_total{%s} %d\n", labels(k), purged[k])
+	}
+	b.WriteString("# HELP retention_errors_total Failed retention deletes.\n")
+	b.WriteString("# TYPE retention_errors_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "retention_errors_total{%s} %d\n", labels(k), failed[k])
+	}
+	if last != nil {
+		b.WriteString("# HELP retention_last_run_rows Rows deleted, or counted on a dry run, by the last run.\n")
+		b.WriteString("# TYPE retention_last_run_rows gauge\n")
+		for _, r := range last.Results {
+			if r.Skipped == "" {
+				fmt.Fprintf(&b, "retention_last_run_rows{%s,dry_run=\"%t\"} %d\n", labels(r.Policy+"|"+r.Target), last.DryRun, r.Rows)
+			}
+		}
+		b.WriteString("# HELP retention_last_run_timestamp_seconds Start of the last retention run.\n")
+		b.WriteString("# TYPE retention_last_run_timestamp_seconds gauge\n")
+		fmt.Fprintf(&b, "retention_last_run_timestamp_seconds %d\n", last.StartedAt.Unix())
+	}
+	return c.String(http.StatusOK, b.String())
+}
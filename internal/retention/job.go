@@ -0,0 +1,186 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/dbschema"
+)
+
+// ErrRunning is returned when a prune is already in progress
+var ErrRunning = errors.New("retention run already in progress")
+
+// Result is the outcome of one policy target in a run
+type Result struct {
+	Policy string    `json:"policy"`
+	Target string    `json:"target"`
+	Cutoff time.Time `json:"cutoff"`
+	// Rows were deleted, or on a dry run would have been
+	Rows     int64   `json:"rows"`
+	DryRun   bool    `json:"dry_run,omitempty"`
+	Skipped  string  `json:"skipped,omitempty"`
+	Error    string  `json:"error,omitempty"`
+	Duration float64 `json:"duration_ms"`
+}
+
+// Run summarises one pass over the policies
+type Run struct {
+	StartedAt time.Time `json:"started_at"`
+	DryRun    bool      `json:"dry_run"`
+	Rows      int64     `json:"rows"`
+	Results   []Result  `json:"results"`
+}
+
+// Job enforces the retention policies once a day
+type Job struct {
+	db       *sql.DB
+	policies []Policy
+	cfg      Config
+
+	running sync.Mutex
+
+	mu     sync.Mutex
+	last   *Run
+	purged map[string]int64 // rows deleted since start, by policy and target
+	errors map[string]int64
+}
+
+// NewJob creates the pruning job
+func NewJob(db *sql.DB, policies []Policy, cfg Config) *Job {
+	return &Job{
+		db:       db,
+		policies: policies,
+		cfg:      cfg,
+		purged:   make(map[string]int64),
+		errors:   make(map[string]int64),
+	}
+}
+
+// Start prunes every night at RunHour UTC until the context is cancelled
+func (j *Job) Start(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(j.nextRun(time.Now().UTC())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if _, err := j.RunOnce(ctx, j.cfg.DryRun); err != nil {
+				log.Printf("⚠️ Retention job failed: %v", err)
+			}
+		}
+	}
+}
+
+// RunOnce applies every policy; one target failing doesn't stop the others
+func (j *Job) RunOnce(ctx context.Context, dryRun bool) (Run, error) {
+	if !j.running.TryLock() {
+		return Run{}, ErrRunning
+	}
+	defer j.running.Unlock()
+
+	run := Run{StartedAt: time.Now().UTC(), DryRun: dryRun, Results: []Result{}}
+	for _, p := range j.policies {
+		cutoff := run.StartedAt.Add(-p.Retain)
+		for _, t := range p.Targets {
+			if ctx.Err() != nil {
+				return run, ctx.Err()
+			}
+			r := j.apply(ctx, p, t, cutoff, dryRun)
+			run.Rows += r.Rows
+			run.Results = append(run.Results, r)
+		}
+	}
+
+	j.mu.Lock()
+	j.last = &run
+	j.mu.Unlock()
+
+	verb := "Purged"
+	if dryRun {
+		verb = "Dry run: would purge"
+	}
+	log.Printf("🧹 %s %d rows past retention", verb, run.Rows)
+	return run, nil
+}
+
+func (j *Job) apply(ctx context.Context, p Policy, t Target, cutoff time.Time, dryRun bool) Result {
+	start := time.Now()
+	r := Result{Policy: p.Name, Target: t.String(), Cutoff: cutoff, DryRun: dryRun}
+	defer func() { r.Duration = float64(time.Since(start).Microseconds()) / 1000 }()
+
+	cols, err := dbschema.Columns(ctx, j.db, t.Table)
+	switch {
+	case err != nil:
+		r.Error = err.Error()
+	case cols == nil:
+		r.Skipped = "table does not exist"
+	case !cols[t.Column]:
+		r.Skipped = "column does not exist"
+	case dryRun:
+		err = j.db.QueryRowContext(ctx,
+			fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s < ?`, t.Table, t.Column), cutoff).Scan(&r.Rows)
+	default:
+		r.Rows, err = j.purge(ctx, t, cutoff)
+	}
+	if err != nil {
+		r.Error = err.Error()
+		log.Printf("⚠️ Retention %s on %s failed: %v", p.Name, t, err)
+	}
+
+	if !dryRun {
+		j.mu.Lock()
+		j.purged[p.Name+"|"+t.String()] += r.Rows
+		if r.Error != "" {
+			j.errors[p.Name+"|"+t.String()]++
+		}
+		j.mu.Unlock()
+	}
+	if r.Rows > 0 && !dryRun {
+		log.Printf("🧹 Retention %s: deleted %d rows from %s older than %s", p.Name, r.Rows, t.Table, cutoff.Format(time.RFC3339))
+	}
+	return r
+}
+
+// purge deletes in batches so other writers get the lock between statements
+func (j *Job) purge(ctx context.Context, t Target, cutoff time.Time) (int64, error) {
+	q := fmt.Sprintf(`DELETE FROM %s WHERE rowid IN (SELECT rowid FROM %s WHERE %s < ? LIMIT ?)`,
+		t.Table, t.Table, t.Column)
+	var total int64
+	for {
+		res, err := j.db.ExecContext(ctx, q, cutoff, j.cfg.BatchSize)
+		if err != nil {
+			return total, err
+		}
+		n, _ := res.RowsAffected()
+		total += n
+		if n < int64(j.cfg.BatchSize) {
+			return total, nil
+		}
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case <-time.After(j.cfg.BatchPause):
+		}
+	}
+}
+
+// Last returns the most recent run, or nil before the first
+func (j *Job) Last() *Run {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.last
+}
+
+func (j *Job) nextRun(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), j.cfg.RunHour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.Add(24 * time.Hour)
+	}
+	return next
+}
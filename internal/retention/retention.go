@@ -0,0 +1,113 @@
+package retention
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/dbschema"
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Target is a table and the timestamp column its rows age by
+type Target struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+}
+
+func (t Target) String() string {
+	return t.Table + "." + t.Column
+}
+
+// Policy deletes rows whose column is older than Retain; targets that don't
+// exist are skipped
+type Policy struct {
+	Name    string        `json:"name"`
+	Targets []Target      `json:"targets"`
+	Retain  time.Duration `json:"retain"`
+}
+
+// defaults are the built-in policies; each can be tuned with
+// RETENTION_<NAME> (a duration, 0 disables) and RETENTION_<NAME>_TABLES
+// (table.column entries)
+var defaults = []struct {
+	name    string
+	retain  time.Duration
+	targets []string
+}{
+	{"audit_logs", 365 * 24 * time.Hour, []string{dbschema.AuditLogs + ".created_at"}},
+	{"request_logs", 30 * 24 * time.Hour, []string{dbschema.RequestLogs + ".created_at"}},
+	// Soft-deleted rows are purged this long after deleted_at; live rows have
+	// a NULL deleted_at and never match. Foods are left out: diary entries,
+	// recipes and plans keep pointing at deleted foods.
+	{"soft_deleted", 30 * 24 * time.Hour, []string{
+		dbschema.DiaryEntries + ".deleted_at", dbschema.Recipes + ".deleted_at", dbschema.MealPlans + ".deleted_at",
+	}},
+	// Sessions are removed this long after they expire
+	{"sessions", 24 * time.Hour, []string{dbschema.Sessions + ".expires_at", dbschema.RefreshTokens + ".expires_at"}},
+	{"notifications", 90 * 24 * time.Hour, []string{dbschema.Notifications + ".created_at"}},
+}
+
+var targetPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\.([A-Za-z_][A-Za-z0-9_]*)$`)
+
+// LoadPolicies returns the enabled policies
+func LoadPolicies() ([]Policy, error) {
+	var out []Policy
+	for _, d := range defaults {
+		key := "RETENTION_" + strings.ToUpper(d.name)
+		p := Policy{Name: d.name, Retain: envconfig.Duration(key, d.retain)}
+		if p.Retain < 0 {
+			return nil, fmt.Errorf("%s must not be negative", key)
+		}
+		if p.Retain == 0 {
+			continue
+		}
+		for _, t := range envconfig.List(key+"_TABLES", d.targets) {
+			m := targetPattern.FindStringSubmatch(t)
+			if m == nil {
+				return nil, fmt.Errorf("%s_TABLES: %q must be table.column", key, t)
+			}
+			p.Targets = append(p.Targets, Target{Table: m[1], Column: m[2]})
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// Config controls the pruning job
+type Config struct {
+	// DryRun counts the rows that would be purged without deleting them; it
+	// is the default so a deployment reviews the counts before deleting
+	DryRun bool
+	// RunHour is the UTC hour of day the job runs
+	RunHour int
+	// BatchSize rows are deleted per statement, with BatchPause between
+	// statements so the pruning job doesn't hold the write lock for long
+	BatchSize  int
+	BatchPause time.Duration
+}
+
+// LoadConfig reads RETENTION_* job settings
+func LoadConfig() Config {
+	return Config{
+		DryRun:     envconfig.Bool("RETENTION_DRY_RUN", true),
+		RunHour:    envconfig.Int("RETENTION_RUN_HOUR", 3),
+		BatchSize:  envconfig.Int("RETENTION_BATCH_SIZE", 1000),
+		BatchPause: envconfig.Duration("RETENTION_BATCH_PAUSE", 50*time.Millisecond),
+	}
+}
+
+// Validate checks the job settings
+func (c Config) Validate() error {
+	if c.RunHour < 0 || c.RunHour > 23 {
+		return fmt.Errorf("RETENTION_RUN_HOUR must be between 0 and 23")
+	}
+	if c.BatchSize < 1 {
+		return fmt.Errorf("RETENTION_BATCH_SIZE must be positive")
+	}
+	if c.BatchPause < 0 {
+		return fmt.Errorf("RETENTION_BATCH_PAUSE must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package runtimecfg
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ActorHeader lets operators name themselves in the reload audit event
+const ActorHeader = "X-Admin-Actor"
+
+// RegisterRoutes mounts GET /config and POST /config/reload on an admin-protected group
+func (m *Manager) RegisterRoutes(g *echo.Group) {
+	g.GET("/config", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, m.Current())
+	})
+	g.POST("/config/reload", func(c echo.Context) error {
+		actor := c.Request().Header.Get(ActorHeader)
+		if actor == "" {
+			actor = "admin@" + c.RealIP()
+		}
+		changes, err := m.Reload(actor)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"changes":  changes,
+			"settings": m.Current(),
+		})
+	})
+}
@@ -0,0 +1,133 @@
+package runtimecfg
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Settings are the values that can change without a restart
+type Settings struct {
+	RateLimitReqs         int           `json:"rate_limit_requests"`
+	RateLimitWindow       time.Duration `json:"rate_limit_window"`
+	CORSOrigins           []string      `json:"cors_origins"`
+	CORSCredentialOrigins []string      `json:"cors_credential_origins"`
+	LogLevel              string        `json:"log_level"`
+}
+
+// Change describes one setting that differs after a reload
+type Change struct {
+	Setting string      `json:"setting"`
+	Old     interface{} `json:"old"`
+	New     interface{} `json:"new"`
+}
+
+// Loader re-reads settings from their sources (.env, environment, secrets)
+type Loader func() (Settings, error)
+
+// Manager holds the live settings and rebuilds dependent middleware on reload
+type Manager struct {
+	load Loader
+
+	mu       sync.RWMutex
+	current  Settings
+	version  int
+	onChange []func(Settings)
+}
+
+// NewManager loads the initial settings
+func NewManager(load Loader) (*Manager, error) {
+	s, err := load()
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{load: load, current: s, version: 1}, nil
+}
+
+// Current returns the live settings
+func (m *Manager) Current() Settings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// OnChange registers a hook run after every reload that changed something
+func (m *Manager) OnChange(fn func(Settings)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChange = append(m.onChange, fn)
+}
+
+// Reload re-reads settings and applies them, recording an audit event naming the actor
+func (m *Manager) Reload(actor string) ([]Change, error) {
+	next, err := m.load()
+	if err != nil {
+		log.Printf("⚠️ Config reload by %s failed: %v", actor, err)
+		return nil, err
+	}
+
+	m.mu.Lock()
+	changes := diff(m.current, next)
+	if len(changes) > 0 {
+		m.current = next
+		m.version++
+	}
+	hooks := append([]func(Settings){}, m.onChange...)
+	m.mu.Unlock()
+
+	if len(changes) == 0 {
+		log.Printf("🔧 Config reload by %s: no changes", actor)
+		return nil, nil
+	}
+	for _, c := range changes {
+		log.Printf("🔧 AUDIT config_reload actor=%s setting=%s old=%v new=%v", actor, c.Setting, c.Old, c.New)
+	}
+	for _, fn := range hooks {
+		fn(next)
+	}
+	return changes, nil
+}
+
+// Middleware returns a middleware built from the live settings and rebuilt
+// after each reload, so static middleware (CORS, rate limiting) picks up changes
+func (m *Manager) Middleware(build func(Settings) echo.MiddlewareFunc) echo.MiddlewareFunc {
+	var mu sync.Mutex
+	builtVersion := 0
+	var built echo.MiddlewareFunc
+
+	current := func() echo.MiddlewareFunc {
+		m.mu.RLock()
+		version, settings := m.version, m.current
+		m.mu.RUnlock()
+
+		mu.Lock()
+		defer mu.Unlock()
+		if version != builtVersion {
+			built, builtVersion = build(settings), version
+		}
+		return built
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return current()(next)(c)
+		}
+	}
+}
+
+func diff(old, next Settings) []Change {
+	var changes []Change
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(next)
+	t := ov.Type()
+	for i := 0; i < t.NumField(); i++ {
+		a, b := ov.Field(i).Interface(), nv.Field(i).Interface()
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, Change{Setting: t.Field(i).Tag.Get("json"), Old: fmt.Sprint(a), New: fmt.Sprint(b)})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,57 @@
+package searchgaps
+
+import (
+	"context"
+	"time"
+
+	"nutrition-health-backend/internal/stats"
+)
+
+// Collector feeds the day's top zero-result searches into the admin stats
+type Collector struct {
+	store *Store
+	// Top is how many queries are listed in the stats detail
+	Top int
+}
+
+// NewCollector creates a stats collector over the store
+func NewCollector(store *Store) *Collector {
+	return &Collector{store: store, Top: 10}
+}
+
+func (c *Collector) Name() string { return "search_gaps" }
+
+func (c *Collector) Collect(ctx context.Context, day time.Time) ([]stats.Value, error) {
+	var total float64
+	err := c.store.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(count), 0) FROM search_misses WHERE day = ?`, day.Format("2006-01-02")).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.store.db.QueryContext(ctx,
+		`SELECT query, language, count FROM search_misses WHERE day = ? ORDER BY count DESC LIMIT ?`,
+		day.Format("2006-01-02"), c.Top)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type top struct {
+		Query    string `json:"query"`
+		Language string `json:"language"`
+		Count    int64  `json:"count"`
+	}
+	tops := []top{}
+	for rows.Next() {
+		var t top
+		if err := rows.Scan(&t.Query, &t.Language, &t.Count); err != nil {
+			return nil, err
+		}
+		tops = append(tops, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return []stats.Value{{Metric: stats.ZeroResultSearches, Value: total, Detail: tops}}, nil
+}
@@ -0,0 +1,116 @@
+package searchgaps
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/runtimecfg"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FoodCreator creates a placeholder food for the content team to complete
+type FoodCreator interface {
+	CreateStub(ctx context.Context, name, language string) (foodID string, err error)
+}
+
+// Handler serves the admin food-gap report
+type Handler struct {
+	store   *Store
+	creator FoodCreator
+}
+
+// NewHandler creates the handler; without a creator, stub creation returns 501
+func NewHandler(store *Store, creator FoodCreator) *Handler {
+	return &Handler{store: store, creator: creator}
+}
+
+// RegisterRoutes mounts the report on an admin-protected group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/search-gaps", h.Report)
+	g.POST("/search-gaps/stub", h.CreateStub)
+}
+
+// Report lists frequent zero-result queries: ?days= (default 30), ?lang=, ?limit= (default 50), ?include_resolved=true
+func (h *Handler) Report(c echo.Context) error {
+	days, err := intParam(c, "days", 30, 1, 365)
+	if err != nil {
+		return err
+	}
+	limit, err := intParam(c, "limit", 50, 1, 500)
+	if err != nil {
+		return err
+	}
+	gaps, err := h.store.Report(c.Request().Context(), ReportOptions{
+		Since:           time.Now().Add(-time.Duration(days) * 24 * time.Hour),
+		Language:        strings.ToLower(c.QueryParam("lang")),
+		IncludeResolved: c.QueryParam("include_resolved") == "true",
+		Limit:           limit,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"gaps": gaps, "days": days})
+}
+
+type stubRequest struct {
+	Query    string `json:"query"`
+	Language string `json:"language"`
+	Name     string `json:"name"`
+}
+
+// CreateStub creates a food stub from a logged query and marks the gap resolved
+func (h *Handler) CreateStub(c echo.Context) error {
+	if h.creator == nil {
+		return echo.NewHTTPError(http.StatusNotImplemented, "food stub creation is not configured")
+	}
+	var req stubRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if Normalize(req.Query) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "query is required")
+	}
+	if req.Language == "" {
+		req.Language = DetectLanguage(req.Query, "")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = strings.TrimSpace(req.Query)
+	}
+
+	ctx := c.Request().Context()
+	foodID, err := h.creator.CreateStub(ctx, name, req.Language)
+	if err != nil {
+		return err
+	}
+	actor := c.Request().Header.Get(runtimecfg.ActorHeader)
+	if actor == "" {
+		actor = "admin@" + c.RealIP()
+	}
+	if err := h.store.Resolve(ctx, req.Query, req.Language, foodID, actor); err != nil {
+		return err
+	}
+	log.Printf("AUDIT food stub by %s: %s %q for query %q", actor, foodID, name, req.Query)
+	return c.JSON(http.StatusCreated, map[string]string{
+		"food_id":  foodID,
+		"name":     name,
+		"language": req.Language,
+	})
+}
+
+func intParam(c echo.Context, name string, def, min, max int) (int, error) {
+	v := c.QueryParam(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, name+" must be between "+strconv.Itoa(min)+" and "+strconv.Itoa(max))
+	}
+	return n, nil
+}
@@ -0,0 +1,142 @@
+package searchgaps
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultSearchRoutes are the endpoints under apiPrefix (e.g. /api/v1) whose
+// empty responses are logged
+func DefaultSearchRoutes(apiPrefix string) []string {
+	apiPrefix = strings.TrimSuffix(apiPrefix, "/")
+	return []string{apiPrefix + "/foods/search", apiPrefix + "/search"}
+}
+
+// captureLimit bounds how much of a response is kept for inspection; an empty
+// result set is always small
+const captureLimit = 16 << 10
+
+// resultFields are the array keys search endpoints wrap their results in
+var resultFields = []string{"foods", "results", "items", "data"}
+
+// Middleware records searches that return no results without changing the
+// response. Routes come from SEARCH_ROUTE_PREFIXES, defaulting to the search
+// routes under apiPrefix.
+func Middleware(store *Store, apiPrefix string) echo.MiddlewareFunc {
+	routes := envconfig.List("SEARCH_ROUTE_PREFIXES", DefaultSearchRoutes(apiPrefix))
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Method != http.MethodGet || !matches(routes, req.URL.Path) {
+				return next(c)
+			}
+			query := c.QueryParam("q")
+			if query == "" {
+				query = c.QueryParam("query")
+			}
+			if Normalize(query) == "" {
+				return next(c)
+			}
+
+			res := c.Response()
+			tee := &teeWriter{ResponseWriter: res.Writer}
+			res.Writer = tee
+			err := next(c)
+			res.Writer = tee.ResponseWriter
+
+			if err != nil || res.Status != http.StatusOK || tee.overflow ||
+				!strings.HasPrefix(res.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) ||
+				!IsEmptyResult(tee.body) {
+				return err
+			}
+
+			lang := DetectLanguage(query, requestLanguage(c))
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := store.Record(ctx, query, lang, time.Now()); err != nil {
+					log.Printf("⚠️ Failed to record zero-result search: %v", err)
+				}
+			}()
+			return nil
+		}
+	}
+}
+
+// IsEmptyResult reports whether a search response body holds no results:
+// an empty array, total/count of zero, or only empty result arrays
+func IsEmptyResult(body []byte) bool {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return false
+	}
+	switch t := v.(type) {
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		for _, key := range []string{"total", "count"} {
+			if n, ok := t[key].(float64); ok {
+				return n == 0
+			}
+		}
+		found := false
+		for _, key := range resultFields {
+			if arr, ok := t[key].([]interface{}); ok {
+				if len(arr) > 0 {
+					return false
+				}
+				found = true
+			}
+		}
+		return found
+	}
+	return false
+}
+
+func matches(prefixes []string, path string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestLanguage uses ?lang= or the first Accept-Language tag
+func requestLanguage(c echo.Context) string {
+	if lang := c.QueryParam("lang"); lang != "" {
+		return strings.ToLower(lang)
+	}
+	accept := c.Request().Header.Get("Accept-Language")
+	if accept == "" {
+		return ""
+	}
+	tag := strings.TrimSpace(strings.SplitN(strings.SplitN(accept, ",", 2)[0], ";", 2)[0])
+	return strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+}
+
+// teeWriter passes the response through while keeping a copy of small bodies
+type teeWriter struct {
+	http.ResponseWriter
+	body     []byte
+	overflow bool
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	if !w.overflow {
+		if len(w.body)+len(b) > captureLimit {
+			w.overflow, w.body = true, nil
+		} else {
+			w.body = append(w.body, b...)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,165 @@
+package searchgaps
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Gap is a search that keeps returning nothing, aggregated over a window
+type Gap struct {
+	Query      string     `json:"query"`
+	Language   string     `json:"language"`
+	Count      int64      `json:"count"`
+	Sample     string     `json:"sample"`
+	FirstSeen  time.Time  `json:"first_seen"`
+	LastSeen   time.Time  `json:"last_seen"`
+	FoodID     string     `json:"food_id,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Store persists zero-result searches per day in search_misses
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate creates the search_misses and search_gap_resolutions tables
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS search_misses (
+			day TEXT NOT NULL,
+			query TEXT NOT NULL,
+			language TEXT NOT NULL,
+			count INTEGER NOT NULL DEFAULT 0,
+			sample TEXT NOT NULL,
+			first_seen DATETIME NOT NULL,
+			last_seen DATETIME NOT NULL,
+			PRIMARY KEY (day, query, language)
+		)`,
+		`CREATE TABLE IF NOT EXISTS search_gap_resolutions (
+			query TEXT NOT NULL,
+			language TEXT NOT NULL,
+			food_id TEXT NOT NULL,
+			actor TEXT NOT NULL,
+			resolved_at DATETIME NOT NULL,
+			PRIMARY KEY (query, language)
+		)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Normalize lower-cases and collapses whitespace so "  Ful  Medames" and "ful medames" group together
+func Normalize(q string) string {
+	return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
+
+// DetectLanguage returns "ar" for queries containing Arabic script, else fallback
+func DetectLanguage(q, fallback string) string {
+	for _, r := range q {
+		if unicode.Is(unicode.Arabic, r) {
+			return "ar"
+		}
+	}
+	if fallback == "" {
+		return "en"
+	}
+	return fallback
+}
+
+// Record counts one zero-result search
+func (s *Store) Record(ctx context.Context, raw, language string, at time.Time) error {
+	query := Normalize(raw)
+	if query == "" {
+		return nil
+	}
+	at = at.UTC()
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO search_misses (day, query, language, count, sample, first_seen, last_seen)
+		 VALUES (?, ?, ?, 1, ?, ?, ?)
+		 ON CONFLICT(day, query, language) DO UPDATE SET count = count + 1, last_seen = excluded.last_seen`,
+		at.Format("2006-01-02"), query, language, strings.TrimSpace(raw), at, at)
+	return err
+}
+
+// ReportOptions filters the gap report
+type ReportOptions struct {
+	Since           time.Time
+	Language        string
+	IncludeResolved bool
+	Limit           int
+}
+
+// Report lists the most frequent zero-result queries since opts.Since
+func (s *Store) Report(ctx context.Context, opts ReportOptions) ([]Gap, error) {
+	q := `SELECT m.query, m.language, SUM(m.count), MIN(m.sample), MIN(m.first_seen), MAX(m.last_seen),
+	             COALESCE(r.food_id, ''), r.resolved_at
+	      FROM search_misses m
+	      LEFT JOIN search_gap_resolutions r ON r.query = m.query AND r.language = m.language
+	      WHERE m.day >= ?`
+	args := []interface{}{opts.Since.UTC().Format("2006-01-02")}
+	if opts.Language != "" {
+		q += ` AND m.language = ?`
+		args = append(args, opts.Language)
+	}
+	if !opts.IncludeResolved {
+		q += ` AND r.food_id IS NULL`
+	}
+	q += ` GROUP BY m.query, m.language ORDER BY SUM(m.count) DESC, MAX(m.last_seen) DESC LIMIT ?`
+	args = append(args, opts.Limit)
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gaps []Gap
+	for rows.Next() {
+		var (
+			g          Gap
+			first      string
+			last       string
+			resolvedAt sql.NullTime
+		)
+		if err := rows.Scan(&g.Query, &g.Language, &g.Count, &g.Sample, &first, &last, &g.FoodID, &resolvedAt); err != nil {
+			return nil, err
+		}
+		g.FirstSeen, g.LastSeen = parseTime(first), parseTime(last)
+		if resolvedAt.Valid {
+			g.ResolvedAt = &resolvedAt.Time
+		}
+		gaps = append(gaps, g)
+	}
+	return gaps, rows.Err()
+}
+
+// Resolve links a gap to the food created for it so it drops out of the report
+func (s *Store) Resolve(ctx context.Context, query, language, foodID, actor string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO search_gap_resolutions (query, language, food_id, actor, resolved_at) VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(query, language) DO UPDATE SET food_id = excluded.food_id, actor = excluded.actor, resolved_at = excluded.resolved_at`,
+		Normalize(query), language, foodID, actor, time.Now().UTC())
+	return err
+}
+
+// aggregated MIN/MAX over DATETIME columns come back as text
+func parseTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999-07:00", time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,39 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileProvider reads Docker/Kubernetes secrets mounted as one file per secret.
+// A secret named JWT_SECRET is read from jwt_secret or JWT_SECRET in the directory.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a file provider for a secrets directory
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+func (f *FileProvider) Name() string { return "file" }
+
+func (f *FileProvider) Fetch(ctx context.Context, names []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, name := range names {
+		for _, candidate := range []string{strings.ToLower(name), name} {
+			data, err := os.ReadFile(filepath.Join(f.dir, candidate))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			values[name] = strings.TrimRight(string(data), "\r\n")
+			break
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,101 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Provider fetches secret values by name
+type Provider interface {
+	// Fetch returns the values it holds for the given names; missing names are omitted
+	Fetch(ctx context.Context, names []string) (map[string]string, error)
+	Name() string
+}
+
+// DefaultKeys are the settings loaded from the secrets backend when SECRETS_KEYS is unset
+var DefaultKeys = []string{
+	"JWT_SECRET",
+	"SMTP_USERNAME",
+	"SMTP_PASSWORD",
+	"STRIPE_SECRET_KEY",
+	"STRIPE_WEBHOOK_SECRET",
+	"ADMIN_TOKEN",
+	"SENTRY_DSN",
+	"S3_SECRET_ACCESS_KEY",
+	"FIELD_ENCRYPTION_KEYS",
+	"OUTBOX_WEBHOOK_SECRET",
+}
+
+// Config selects and configures the secrets backend
+type Config struct {
+	Provider string // SECRETS_PROVIDER: env, file, vault or ssm
+	Keys     []string
+	// RefreshInterval re-fetches secrets periodically; zero disables refresh
+	RefreshInterval time.Duration
+}
+
+// LoadConfig reads SECRETS_* settings
+func LoadConfig() Config {
+	return Config{
+		Provider:        envconfig.String("SECRETS_PROVIDER", "env"),
+		Keys:            envconfig.List("SECRETS_KEYS", DefaultKeys),
+		RefreshInterval: envconfig.Duration("SECRETS_REFRESH_INTERVAL", 0),
+	}
+}
+
+// NewProvider builds the configured provider; "env" returns nil (plain environment)
+func NewProvider(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case "", "env":
+		return nil, nil
+	case "file", "docker":
+		return NewFileProvider(envconfig.String("SECRETS_DIR", "/run/secrets")), nil
+	case "vault":
+		return NewVaultProvider(VaultConfigFromEnv())
+	case "ssm":
+		return NewSSMProvider(SSMConfigFromEnv())
+	default:
+		return nil, fmt.Errorf("unknown SECRETS_PROVIDER %q", cfg.Provider)
+	}
+}
+
+// LoadIntoEnv fetches secrets and exports them as environment variables, so
+// config.Load and the feature loaders pick them up unchanged. Call it before config.Load.
+func LoadIntoEnv(ctx context.Context, p Provider, keys []string) (int, error) {
+	values, err := p.Fetch(ctx, keys)
+	if err != nil {
+		return 0, fmt.Errorf("%s secrets: %w", p.Name(), err)
+	}
+	for name, value := range values {
+		if err := os.Setenv(name, value); err != nil {
+			return 0, err
+		}
+	}
+	return len(values), nil
+}
+
+// Refresh re-fetches secrets on an interval until ctx is cancelled. Values are
+// re-exported to the environment; components that read settings at startup
+// only see the new value after a restart or config reload.
+func Refresh(ctx context.Context, p Provider, keys []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			if _, err := LoadIntoEnv(fetchCtx, p, keys); err != nil {
+				log.Printf("⚠️ Secret refresh failed: %v", err)
+			}
+			cancel()
+		}
+	}
+}
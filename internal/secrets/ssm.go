@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/awssig"
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// SSMConfig points at SecureString parameters named <Prefix><KEY>
+type SSMConfig struct {
+	Region string // AWS_REGION
+	Prefix string // SSM_PREFIX, e.g. "/nutrition/production/"
+	Creds  awssig.Credentials
+}
+
+// SSMConfigFromEnv reads AWS_* and SSM_PREFIX settings
+func SSMConfigFromEnv() SSMConfig {
+	return SSMConfig{
+		Region: envconfig.String("AWS_REGION", "us-east-1"),
+		Prefix: envconfig.String("SSM_PREFIX", "/nutrition/"),
+		Creds: awssig.Credentials{
+			AccessKeyID:     envconfig.String("AWS_ACCESS_KEY_ID", ""),
+			SecretAccessKey: envconfig.String("AWS_SECRET_ACCESS_KEY", ""),
+			SessionToken:    envconfig.String("AWS_SESSION_TOKEN", ""),
+		},
+	}
+}
+
+// SSMProvider reads secrets from AWS Systems Manager Parameter Store
+type SSMProvider struct {
+	cfg      SSMConfig
+	endpoint string
+	client   *http.Client
+}
+
+// ssmBatchSize is the GetParameters limit per call
+const ssmBatchSize = 10
+
+// NewSSMProvider creates an SSM provider
+func NewSSMProvider(cfg SSMConfig) (*SSMProvider, error) {
+	if cfg.Creds.AccessKeyID == "" || cfg.Creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for SSM")
+	}
+	return &SSMProvider{
+		cfg:      cfg,
+		endpoint: fmt.Sprintf("https://ssm.%s.amazonaws.com/", cfg.Region),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (s *SSMProvider) Name() string { return "ssm" }
+
+func (s *SSMProvider) Fetch(ctx context.Context, names []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for start := 0; start < len(names); start += ssmBatchSize {
+		end := start + ssmBatchSize
+		if end > len(names) {
+			end = len(names)
+		}
+		if err := s.fetchBatch(ctx, names[start:end], values); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func (s *SSMProvider) fetchBatch(ctx context.Context, names []string, values map[string]string) error {
+	params := make([]string, len(names))
+	for i, name := range names {
+		params[i] = s.cfg.Prefix + name
+	}
+	payload, err := json.Marshal(map[string]interface{}{"Names": params, "WithDecryption": true})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonSSM.GetParameters")
+	awssig.Sign(req, s.cfg.Creds, s.cfg.Region, "ssm", awssig.PayloadHash(payload), time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("ssm returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var body struct {
+		Parameters []struct {
+			Name  string `json:"Name"`
+			Value string `json:"Value"`
+		} `json:"Parameters"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("invalid ssm response: %w", err)
+	}
+	for _, p := range body.Parameters {
+		values[strings.TrimPrefix(p.Name, s.cfg.Prefix)] = p.Value
+	}
+	return nil
+}
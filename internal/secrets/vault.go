@@ -0,0 +1,83 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// VaultConfig points at a KV v2 secret holding one field per setting
+type VaultConfig struct {
+	Addr  string // VAULT_ADDR
+	Token string // VAULT_TOKEN
+	Mount string // VAULT_MOUNT, default "secret"
+	Path  string // VAULT_PATH, e.g. "nutrition/production"
+}
+
+// VaultConfigFromEnv reads VAULT_* settings
+func VaultConfigFromEnv() VaultConfig {
+	return VaultConfig{
+		Addr:  envconfig.String("VAULT_ADDR", ""),
+		Token: envconfig.String("VAULT_TOKEN", ""),
+		Mount: envconfig.String("VAULT_MOUNT", "secret"),
+		Path:  envconfig.String("VAULT_PATH", ""),
+	}
+}
+
+// VaultProvider reads secrets from HashiCorp Vault's KV v2 engine
+type VaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+}
+
+// NewVaultProvider creates a Vault provider
+func NewVaultProvider(cfg VaultConfig) (*VaultProvider, error) {
+	if cfg.Addr == "" || cfg.Token == "" || cfg.Path == "" {
+		return nil, fmt.Errorf("VAULT_ADDR, VAULT_TOKEN and VAULT_PATH are required")
+	}
+	return &VaultProvider{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (v *VaultProvider) Name() string { return "vault" }
+
+func (v *VaultProvider) Fetch(ctx context.Context, names []string) (map[string]string, error) {
+	url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimRight(v.cfg.Addr, "/"), v.cfg.Mount, strings.Trim(v.cfg.Path, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.cfg.Token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vault returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid vault response: %w", err)
+	}
+
+	values := make(map[string]string)
+	for _, name := range names {
+		if raw, ok := body.Data.Data[name]; ok {
+			values[name] = fmt.Sprint(raw)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,81 @@
+package seeds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nutrition-health-backend/internal/dbschema"
+)
+
+// ManifestFile lists the datasets in a seed directory, in load order
+const ManifestFile = "manifest.json"
+
+// Dataset describes one versioned data file and the table it fills.
+// Rows are matched on Key so re-seeding updates content instead of duplicating it.
+type Dataset struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	File    string   `json:"file"`
+	Table   string   `json:"table"`
+	Key     []string `json:"key"`
+}
+
+// Manifest is the parsed manifest.json
+type Manifest struct {
+	Datasets []Dataset `json:"datasets"`
+}
+
+// LoadManifest reads dir/manifest.json; a missing manifest means no datasets
+func LoadManifest(dir string) (Manifest, error) {
+	var m Manifest
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("parse %s: %w", ManifestFile, err)
+	}
+	seen := map[string]bool{}
+	for _, d := range m.Datasets {
+		if err := d.Validate(); err != nil {
+			return m, err
+		}
+		if seen[d.Name] {
+			return m, fmt.Errorf("dataset %q listed twice", d.Name)
+		}
+		seen[d.Name] = true
+	}
+	return m, nil
+}
+
+// Validate checks the dataset definition; table and key names are interpolated
+// into SQL so they must be plain identifiers
+func (d Dataset) Validate() error {
+	if d.Name == "" || d.Version == "" || d.File == "" {
+		return fmt.Errorf("dataset %q: name, version and file are required", d.Name)
+	}
+	switch strings.ToLower(filepath.Ext(d.File)) {
+	case ".csv", ".json":
+	default:
+		return fmt.Errorf("dataset %q: file must be .csv or .json", d.Name)
+	}
+	if !dbschema.Valid(d.Table) {
+		return fmt.Errorf("dataset %q: invalid table %q", d.Name, d.Table)
+	}
+	if len(d.Key) == 0 {
+		return fmt.Errorf("dataset %q: key columns are required", d.Name)
+	}
+	for _, k := range d.Key {
+		if !dbschema.Valid(k) {
+			return fmt.Errorf("dataset %q: invalid key column %q", d.Name, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package seeds
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// row maps column names to values
+type row map[string]interface{}
+
+// parseRows decodes a CSV (header row = columns, empty cell = NULL) or a JSON
+// array of objects (nested values stored as JSON text)
+func parseRows(file string, data []byte) ([]string, []row, error) {
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		return parseJSON(data)
+	}
+	return parseCSV(data)
+}
+
+func parseCSV(data []byte) ([]string, []row, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("missing header row")
+	}
+	columns := records[0]
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+	rows := make([]row, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		r := row{}
+		for i, col := range columns {
+			if rec[i] == "" {
+				r[col] = nil
+			} else {
+				r[col] = rec[i]
+			}
+		}
+		rows = append(rows, r)
+	}
+	return columns, rows, nil
+}
+
+func parseJSON(data []byte) ([]string, []row, error) {
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, nil, err
+	}
+	set := map[string]bool{}
+	rows := make([]row, 0, len(objects))
+	for _, obj := range objects {
+		r := row{}
+		for k, v := range obj {
+			set[k] = true
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+				encoded, err := json.Marshal(v)
+				if err != nil {
+					return nil, nil, err
+				}
+				r[k] = string(encoded)
+			default:
+				r[k] = v
+			}
+		}
+		rows = append(rows, r)
+	}
+	columns := make([]string, 0, len(set))
+	for k := range set {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	return columns, rows, nil
+}
@@ -0,0 +1,267 @@
+package seeds
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/dbschema"
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// DefaultDir is where datasets live unless SEED_DATA_DIR says otherwise
+const DefaultDir = "data/seeds"
+
+// Dir returns the configured dataset directory
+func Dir() string {
+	return envconfig.String("SEED_DATA_DIR", DefaultDir)
+}
+
+// Result reports what happened to one dataset
+type Result struct {
+	Dataset string `json:"dataset"`
+	Version string `json:"version"`
+	Skipped bool   `json:"skipped"`
+	Rows    int    `json:"rows"`
+}
+
+// Migrate creates the seed_versions table
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS seed_versions (
+		dataset TEXT PRIMARY KEY,
+		version TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		rows INTEGER NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`)
+	return err
+}
+
+// Seeder loads the datasets in a directory
+type Seeder struct {
+	db       *sql.DB
+	dir      string
+	manifest Manifest
+}
+
+// NewSeeder reads the manifest in dir
+func NewSeeder(db *sql.DB, dir string) (*Seeder, error) {
+	m, err := LoadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Seeder{db: db, dir: dir, manifest: m}, nil
+}
+
+// Datasets returns the dataset names in load order
+func (s *Seeder) Datasets() []string {
+	names := make([]string, 0, len(s.manifest.Datasets))
+	for _, d := range s.manifest.Datasets {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+// Apply loads the named datasets (all when names is empty) in manifest order.
+// A dataset whose file checksum matches seed_versions is skipped.
+func (s *Seeder) Apply(ctx context.Context, names ...string) ([]Result, error) {
+	if err := Migrate(s.db); err != nil {
+		return nil, err
+	}
+	selected, err := s.selection(names)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, d := range selected {
+		res, err := s.apply(ctx, d)
+		if err != nil {
+			return results, fmt.Errorf("dataset %s: %w", d.Name, err)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (s *Seeder) selection(names []string) ([]Dataset, error) {
+	if len(names) == 0 {
+		return s.manifest.Datasets, nil
+	}
+	want := map[string]bool{}
+	for _, n := range names {
+		want[n] = true
+	}
+	var out []Dataset
+	for _, d := range s.manifest.Datasets {
+		if want[d.Name] {
+			out = append(out, d)
+			delete(want, d.Name)
+		}
+	}
+	if len(want) > 0 {
+		var unknown []string
+		for n := range want {
+			unknown = append(unknown, n)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown dataset(s): %s (available: %s)",
+			strings.Join(unknown, ", "), strings.Join(s.Datasets(), ", "))
+	}
+	return out, nil
+}
+
+func (s *Seeder) apply(ctx context.Context, d Dataset) (Result, error) {
+	res := Result{Dataset: d.Name, Version: d.Version}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, d.File))
+	if err != nil {
+		return res, err
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	var applied string
+	err = s.db.QueryRowContext(ctx, `SELECT checksum FROM seed_versions WHERE dataset = ?`, d.Name).Scan(&applied)
+	if err != nil && err != sql.ErrNoRows {
+		return res, err
+	}
+	if applied == checksum {
+		res.Skipped = true
+		return res, nil
+	}
+
+	columns, rows, err := parseRows(d.File, data)
+	if err != nil {
+		return res, err
+	}
+	for _, col := range columns {
+		if !dbschema.Valid(col) {
+			return res, fmt.Errorf("invalid column %q", col)
+		}
+	}
+	for _, k := range d.Key {
+		if !contains(columns, k) {
+			return res, fmt.Errorf("key column %q missing from %s", k, d.File)
+		}
+	}
+	if err := s.checkTable(ctx, d.Table, columns); err != nil {
+		return res, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback()
+
+	for i, r := range rows {
+		if err := upsert(ctx, tx, d, r); err != nil {
+			return res, fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO seed_versions (dataset, version, checksum, rows, applied_at) VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(dataset) DO UPDATE SET version = excluded.version, checksum = excluded.checksum,
+		 rows = excluded.rows, applied_at = excluded.applied_at`,
+		d.Name, d.Version, checksum, len(rows), time.Now().UTC()); err != nil {
+		return res, err
+	}
+	if err := tx.Commit(); err != nil {
+		return res, err
+	}
+	res.Rows = len(rows)
+	return res, nil
+}
+
+// checkTable reports a missing target table or column by name instead of as an SQL error
+func (s *Seeder) checkTable(ctx context.Context, table string, columns []string) error {
+	have, err := dbschema.Columns(ctx, s.db, table)
+	if err != nil {
+		return err
+	}
+	if have == nil {
+		return fmt.Errorf("table %s does not exist", table)
+	}
+	var missing []string
+	for _, col := range columns {
+		if !have[col] {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("table %s has no column(s) %s", table, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// upsert updates the row matched by the dataset key, inserting it when absent.
+// It avoids ON CONFLICT so target tables need no unique index on the key.
+func upsert(ctx context.Context, tx *sql.Tx, d Dataset, r row) error {
+	var sets, where []string
+	var setArgs, keyArgs []interface{}
+	cols := make([]string, 0, len(r))
+	for col := range r {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	for _, col := range cols {
+		if contains(d.Key, col) {
+			continue
+		}
+		sets = append(sets, col+" = ?")
+		setArgs = append(setArgs, r[col])
+	}
+	for _, k := range d.Key {
+		if r[k] == nil {
+			return fmt.Errorf("key column %q is empty", k)
+		}
+		where = append(where, k+" = ?")
+		keyArgs = append(keyArgs, r[k])
+	}
+
+	if len(sets) > 0 {
+		q := fmt.Sprintf(`UPDATE %s SET %s WHERE %s`, d.Table, strings.Join(sets, ", "), strings.Join(where, " AND "))
+		result, err := tx.ExecContext(ctx, q, append(setArgs, keyArgs...)...)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			return nil
+		}
+	} else {
+		var exists int
+		q := fmt.Sprintf(`SELECT 1 FROM %s WHERE %s LIMIT 1`, d.Table, strings.Join(where, " AND "))
+		if err := tx.QueryRowContext(ctx, q, keyArgs...).Scan(&exists); err == nil {
+			return nil
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+	}
+
+	args := make([]interface{}, len(cols))
+	for i, col := range cols {
+		args[i] = r[col]
+	}
+	q := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, d.Table, strings.Join(cols, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", "))
+	_, err := tx.ExecContext(ctx, q, args...)
+	return err
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// ShutdownTimeout is how long in-flight requests get to finish (SHUTDOWN_TIMEOUT)
+func ShutdownTimeout() time.Duration {
+	return envconfig.Duration("SHUTDOWN_TIMEOUT", 30*time.Second)
+}
+
+// H2CEnabled reports whether cleartext HTTP/2 is served when TLS is off (SERVER_H2C)
+func H2CEnabled() bool {
+	return envconfig.Bool("SERVER_H2C", false)
+}
+
+// Lifecycle tracks background workers and the flush hooks run during shutdown
+type Lifecycle struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	draining atomic.Bool
+
+	mu      sync.Mutex
+	running map[string]int
+	flushes []namedHook
+}
+
+type namedHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewLifecycle creates a lifecycle whose context is cancelled when shutdown begins
+func NewLifecycle() *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Lifecycle{ctx: ctx, cancel: cancel, running: make(map[string]int)}
+}
+
+// Context is cancelled when workers should stop taking new work
+func (l *Lifecycle) Context() context.Context {
+	return l.ctx
+}
+
+// Go runs a background worker; it must return once its context is cancelled
+func (l *Lifecycle) Go(name string, fn func(ctx context.Context)) {
+	l.mu.Lock()
+	l.running[name]++
+	l.mu.Unlock()
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		defer func() {
+			l.mu.Lock()
+			if l.running[name]--; l.running[name] == 0 {
+				delete(l.running, name)
+			}
+			l.mu.Unlock()
+		}()
+		fn(l.ctx)
+	}()
+}
+
+// OnFlush registers a hook run after in-flight requests drain, for buffered events
+func (l *Lifecycle) OnFlush(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flushes = append(l.flushes, namedHook{name: name, fn: fn})
+}
+
+// Draining reports whether shutdown has started; readiness should fail from then on
+func (l *Lifecycle) Draining() bool {
+	return l.draining.Load()
+}
+
+// StopWorkers marks the server as draining, cancels worker contexts and waits
+// for them to return, up to the context deadline
+func (l *Lifecycle) StopWorkers(ctx context.Context) {
+	l.draining.Store(true)
+	l.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Println("✅ Background workers stopped")
+	case <-ctx.Done():
+		l.mu.Lock()
+		names := make([]string, 0, len(l.running))
+		for name := range l.running {
+			names = append(names, name)
+		}
+		l.mu.Unlock()
+		sort.Strings(names)
+		log.Printf("⚠️ Background workers did not stop in time: %s", strings.Join(names, ", "))
+	}
+}
+
+// Flush runs the flush hooks in registration order
+func (l *Lifecycle) Flush(ctx context.Context) {
+	l.mu.Lock()
+	hooks := append([]namedHook{}, l.flushes...)
+	l.mu.Unlock()
+
+	for _, h := range hooks {
+		if err := h.fn(ctx); err != nil {
+			log.Printf("⚠️ Flush %s failed: %v", h.name, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"nutrition-health-backend/internal/envconfig"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ProxyConfig lists the reverse proxies whose X-Forwarded-For is believed.
+// The client IP feeds consent records, admin audit actors and rate limits,
+// so forwarding headers from anyone else are ignored.
+type ProxyConfig struct {
+	TrustedProxies []string
+}
+
+// LoadProxyConfig reads TRUSTED_PROXIES, comma-separated IPs or CIDRs
+func LoadProxyConfig() ProxyConfig {
+	return ProxyConfig{TrustedProxies: envconfig.List("TRUSTED_PROXIES", nil)}
+}
+
+// Validate checks every entry parses
+func (c ProxyConfig) Validate() error {
+	_, err := c.ranges()
+	return err
+}
+
+func (c ProxyConfig) ranges() ([]*net.IPNet, error) {
+	var out []*net.IPNet
+	for _, entry := range c.TrustedProxies {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("TRUSTED_PROXIES entry %q is not an IP or CIDR", entry)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			entry = fmt.Sprintf("%s/%d", entry, bits)
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES entry %q is not an IP or CIDR", entry)
+		}
+		out = append(out, network)
+	}
+	return out, nil
+}
+
+// IPExtractor returns the peer address when no proxy is trusted; otherwise
+// the nearest X-Forwarded-For hop outside the trusted ranges
+func (c ProxyConfig) IPExtractor() (echo.IPExtractor, error) {
+	ranges, err := c.ranges()
+	if err != nil || len(ranges) == 0 {
+		return echo.ExtractIPDirect(), err
+	}
+	opts := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, r := range ranges {
+		opts = append(opts, echo.TrustIPRange(r))
+	}
+	return echo.ExtractIPFromXFFHeader(opts...), nil
+}
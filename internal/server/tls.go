@@ -0,0 +1,118 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLSConfig enables native HTTPS for deployments without a fronting proxy
+type TLSConfig struct {
+	// Static certificate; takes precedence over autocert
+	CertFile string
+	KeyFile  string
+	// AutocertDomains enables ACME (Let's Encrypt) certificates for these hosts
+	AutocertDomains []string
+	CacheDir        string
+	Email           string
+	// RedirectAddr serves HTTP->HTTPS redirects (and ACME challenges); empty disables it
+	RedirectAddr          string
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+}
+
+// LoadTLSConfig reads TLS_* and HSTS_* settings
+func LoadTLSConfig() TLSConfig {
+	return TLSConfig{
+		CertFile:              envconfig.String("TLS_CERT_FILE", ""),
+		KeyFile:               envconfig.String("TLS_KEY_FILE", ""),
+		AutocertDomains:       envconfig.List("TLS_AUTOCERT_DOMAINS", nil),
+		CacheDir:              envconfig.String("TLS_AUTOCERT_CACHE_DIR", "./data/certs"),
+		Email:                 envconfig.String("TLS_AUTOCERT_EMAIL", ""),
+		RedirectAddr:          envconfig.String("TLS_REDIRECT_ADDR", ":80"),
+		HSTSMaxAge:            envconfig.Duration("HSTS_MAX_AGE", 365*24*time.Hour),
+		HSTSIncludeSubdomains: envconfig.Bool("HSTS_INCLUDE_SUBDOMAINS", false),
+	}
+}
+
+// Enabled reports whether the server should terminate TLS itself
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || len(c.AutocertDomains) > 0
+}
+
+// Autocert reports whether certificates come from ACME
+func (c TLSConfig) Autocert() bool {
+	return c.CertFile == "" && len(c.AutocertDomains) > 0
+}
+
+// Validate checks the TLS settings
+func (c TLSConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if c.Autocert() && c.CacheDir == "" {
+		return fmt.Errorf("TLS_AUTOCERT_CACHE_DIR is required for autocert")
+	}
+	if c.HSTSMaxAge < 0 {
+		return fmt.Errorf("HSTS_MAX_AGE must not be negative")
+	}
+	return nil
+}
+
+// ConfigureAutocert sets up echo's ACME manager for the configured domains
+func ConfigureAutocert(e *echo.Echo, c TLSConfig) {
+	e.AutoTLSManager.Prompt = autocert.AcceptTOS
+	e.AutoTLSManager.HostPolicy = autocert.HostWhitelist(c.AutocertDomains...)
+	e.AutoTLSManager.Cache = autocert.DirCache(c.CacheDir)
+	e.AutoTLSManager.Email = c.Email
+}
+
+// RedirectServer returns an HTTP server that redirects to HTTPS on httpsAddr's port.
+// With autocert it also answers ACME http-01 challenges.
+func RedirectServer(e *echo.Echo, c TLSConfig, httpsAddr string) *http.Server {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+	if c.Autocert() {
+		handler = e.AutoTLSManager.HTTPHandler(handler)
+	}
+
+	return &http.Server{
+		Addr:              c.RedirectAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// HSTS sets Strict-Transport-Security on responses served over HTTPS
+func HSTS(c TLSConfig) echo.MiddlewareFunc {
+	value := "max-age=" + strconv.FormatInt(int64(c.HSTSMaxAge.Seconds()), 10)
+	if c.HSTSIncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if ctx.IsTLS() || strings.EqualFold(ctx.Request().Header.Get(echo.HeaderXForwardedProto), "https") {
+				ctx.Response().Header().Set(echo.HeaderStrictTransportSecurity, value)
+			}
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/problem"
+
+	"github.com/labstack/echo/v4"
+)
+
+// keyIDKey is the context key holding the verified signing key ID
+const keyIDKey = "signing_key_id"
+
+// KeyID returns the signing key that authenticated the request, if any
+func KeyID(c echo.Context) string {
+	id, _ := c.Get(keyIDKey).(string)
+	return id
+}
+
+// Middleware verifies request signatures on the configured prefixes. It must
+// run after the body limit so reading the body for the digest is bounded.
+func Middleware(cfg Config, secrets Secrets, nonces NonceStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if cfg.Mode == ModeOff {
+			return next
+		}
+		return func(c echo.Context) error {
+			if !cfg.covers(c.Request().URL.Path) {
+				return next(c)
+			}
+			keyID, err := verify(c, cfg, secrets, nonces)
+			switch {
+			case err == ErrUnsigned && cfg.Mode == ModeOptional:
+				return next(c)
+			case err != nil:
+				return reject(c, err)
+			}
+			c.Set(keyIDKey, keyID)
+			return next(c)
+		}
+	}
+}
+
+func (c Config) covers(path string) bool {
+	for _, p := range c.Prefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func verify(c echo.Context, cfg Config, secrets Secrets, nonces NonceStore) (string, error) {
+	req := c.Request()
+	keyID, ts, nonce, sig := req.Header.Get(KeyHeader), req.Header.Get(TimestampHeader),
+		req.Header.Get(NonceHeader), req.Header.Get(SignatureHeader)
+	if keyID == "" && ts == "" && nonce == "" && sig == "" {
+		return "", ErrUnsigned
+	}
+	if keyID == "" || ts == "" || sig == "" || len(nonce) < 16 || len(nonce) > 128 {
+		return "", ErrMalformed
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	if skew := time.Since(time.Unix(unix, 0)); skew > cfg.MaxSkew || skew < -cfg.MaxSkew {
+		return "", ErrStale
+	}
+	keys, err := secrets.Secrets(req.Context(), keyID)
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		return "", ErrUnknown
+	}
+
+	var body []byte
+	if req.Body != nil {
+		if body, err = io.ReadAll(req.Body); err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				return "", errTooLarge
+			}
+			return "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	toSign := StringToSign(ts, nonce, req.Method, req.URL.RequestURI(), body)
+	matched := false
+	for _, secret := range keys {
+		if hmac.Equal([]byte(Compute(secret, toSign)), []byte(sig)) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return "", ErrInvalid
+	}
+
+	// Claim the nonce only for a valid signature, so forged requests can't burn
+	// a partner's nonces; it must outlive the timestamp window on both sides
+	fresh, err := nonces.Claim(req.Context(), keyID, nonce, 2*cfg.MaxSkew)
+	if err != nil {
+		return "", err
+	}
+	if !fresh {
+		return "", ErrReplayed
+	}
+	return keyID, nil
+}
+
+var (
+	errVerifyUnavailable = errors.New("signature verification is temporarily unavailable")
+	errTooLarge          = errors.New("request body too large to verify")
+)
+
+func reject(c echo.Context, err error) error {
+	status := http.StatusUnauthorized
+	switch err {
+	case errTooLarge:
+		// The body limit was hit while hashing; the connection can't be reused
+		status = http.StatusRequestEntityTooLarge
+		c.Response().Header().Set(echo.HeaderConnection, "close")
+	case ErrUnsigned, ErrMalformed, ErrUnknown, ErrStale, ErrInvalid, ErrReplayed:
+		log.Printf("🔏 Rejected signed request to %s from key %q: %v", c.Request().URL.Path, c.Request().Header.Get(KeyHeader), err)
+		if err == ErrReplayed {
+			status = http.StatusConflict
+		}
+	default:
+		// Store lookups failed; the request may be fine
+		log.Printf("⚠️ Signature verification failed: %v", err)
+		status = http.StatusServiceUnavailable
+		err = errVerifyUnavailable
+	}
+	p := problem.New(status, err.Error())
+	if status == http.StatusUnauthorized {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `HMAC-SHA256 headers="`+strings.Join(
+			[]string{KeyHeader, TimestampHeader, NonceHeader, SignatureHeader}, " ")+`"`)
+	}
+	return problem.Write(c, p)
+}
@@ -0,0 +1,160 @@
+package signing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nutrition-health-backend/internal/limits"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	oldSecret = []byte(strings.Repeat("o", 32))
+	newSecret = []byte(strings.Repeat("n", 32))
+)
+
+func newTestServer(mode string) *echo.Echo {
+	cfg := Config{Mode: mode, Prefixes: []string{"/api/v1/partner"}, MaxSkew: 5 * time.Minute}
+	secrets := StaticSecrets{"partner": {newSecret, oldSecret}}
+	e := echo.New()
+	e.Use(limits.Middleware(limits.Config{Default: limits.Rule{Timeout: time.Second, MaxBody: 64}}))
+	e.Use(Middleware(cfg, secrets, NewMemoryNonces()))
+	e.POST("/api/v1/partner/foods", func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, KeyID(c)+":"+string(body))
+	})
+	e.POST("/api/v1/foods", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+	return e
+}
+
+func TestMiddleware(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		mode   string
+		path   string
+		body   string
+		sign   func(req *http.Request, body []byte)
+		want   int
+		wantIn string
+	}{
+		{"valid", ModeRequired, "/api/v1/partner/foods", `{"a":1}`, func(req *http.Request, body []byte) {
+			Sign(req, "partner", newSecret, body, "nonce-valid-0000000", now)
+		}, http.StatusOK, `partner:{"a":1}`},
+		{"rotated secret", ModeRequired, "/api/v1/partner/foods", "x", func(req *http.Request, body []byte) {
+			Sign(req, "partner", oldSecret, body, "nonce-rotated-00000", now)
+		}, http.StatusOK, "partner:x"},
+		{"unsigned required", ModeRequired, "/api/v1/partner/foods", "x", nil, http.StatusUnauthorized, ErrUnsigned.Error()},
+		{"unsigned optional", ModeOptional, "/api/v1/partner/foods", "x", nil, http.StatusOK, ":x"},
+		{"bad signature optional", ModeOptional, "/api/v1/partner/foods", "x", func(req *http.Request, body []byte) {
+			Sign(req, "partner", []byte(strings.Repeat("w", 32)), body, "nonce-optional-0000", now)
+		}, http.StatusUnauthorized, ErrInvalid.Error()},
+		{"uncovered route", ModeRequired, "/api/v1/foods", "x", nil, http.StatusNoContent, ""},
+		{"short nonce", ModeRequired, "/api/v1/partner/foods", "x", func(req *http.Request, body []byte) {
+			Sign(req, "partner", newSecret, body, "short", now)
+		}, http.StatusUnauthorized, ErrMalformed.Error()},
+		{"missing header", ModeRequired, "/api/v1/partner/foods", "x", func(req *http.Request, body []byte) {
+			Sign(req, "partner", newSecret, body, "nonce-missing-00000", now)
+			req.Header.Del(SignatureHeader)
+		}, http.StatusUnauthorized, ErrMalformed.Error()},
+		{"unknown key", ModeRequired, "/api/v1/partner/foods", "x", func(req *http.Request, body []byte) {
+			Sign(req, "stranger", newSecret, body, "nonce-unknown-00000", now)
+		}, http.StatusUnauthorized, ErrUnknown.Error()},
+		{"stale", ModeRequired, "/api/v1/partner/foods", "x", func(req *http.Request, body []byte) {
+			Sign(req, "partner", newSecret, body, "nonce-stale-0000000", now.Add(-10*time.Minute))
+		}, http.StatusUnauthorized, ErrStale.Error()},
+		{"from the future", ModeRequired, "/api/v1/partner/foods", "x", func(req *http.Request, body []byte) {
+			Sign(req, "partner", newSecret, body, "nonce-future-000000", now.Add(10*time.Minute))
+		}, http.StatusUnauthorized, ErrStale.Error()},
+		{"tampered body", ModeRequired, "/api/v1/partner/foods", "tampered", func(req *http.Request, body []byte) {
+			Sign(req, "partner", newSecret, []byte("original"), "nonce-tampered-0000", now)
+		}, http.StatusUnauthorized, ErrInvalid.Error()},
+		{"tampered query", ModeRequired, "/api/v1/partner/foods?limit=1000", "x", func(req *http.Request, body []byte) {
+			Sign(req, "partner", newSecret, body, "nonce-query-0000000", now)
+			req.URL.RawQuery = "limit=1"
+		}, http.StatusUnauthorized, ErrInvalid.Error()},
+		{"body over the limit", ModeRequired, "/api/v1/partner/foods", strings.Repeat("x", 100), func(req *http.Request, body []byte) {
+			Sign(req, "partner", newSecret, body, "nonce-large-0000000", now)
+			req.ContentLength = -1
+		}, http.StatusRequestEntityTooLarge, errTooLarge.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestServer(tt.mode)
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.sign != nil {
+				tt.sign(req, []byte(tt.body))
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code != tt.want || !strings.Contains(rec.Body.String(), tt.wantIn) {
+				t.Errorf("status %d body %q, want %d containing %q", rec.Code, rec.Body.String(), tt.want, tt.wantIn)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	e := newTestServer(ModeRequired)
+	send := func(nonce, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/partner/foods", strings.NewReader(body))
+		Sign(req, "partner", newSecret, []byte(body), nonce, time.Now())
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if got := send("nonce-replay-000000", "x"); got != http.StatusOK {
+		t.Fatalf("first request = %d, want 200", got)
+	}
+	if got := send("nonce-replay-000000", "x"); got != http.StatusConflict {
+		t.Errorf("replayed request = %d, want 409", got)
+	}
+	if got := send("nonce-replay-000001", "x"); got != http.StatusOK {
+		t.Errorf("fresh nonce = %d, want 200", got)
+	}
+
+	// A forged request must not burn the nonce for the real one
+	forged := httptest.NewRequest(http.MethodPost, "/api/v1/partner/foods", strings.NewReader("y"))
+	Sign(forged, "partner", []byte(strings.Repeat("f", 32)), []byte("y"), "nonce-replay-000002", time.Now())
+	e.ServeHTTP(httptest.NewRecorder(), forged)
+	if got := send("nonce-replay-000002", "y"); got != http.StatusOK {
+		t.Errorf("request after a forgery with its nonce = %d, want 200", got)
+	}
+}
+
+func TestParseSecrets(t *testing.T) {
+	long := strings.Repeat("s", 32)
+	tests := []struct {
+		spec    string
+		want    map[string]int
+		wantErr bool
+	}{
+		{"", map[string]int{}, false},
+		{"a:" + long, map[string]int{"a": 1}, false},
+		{"a:" + long + ", a:" + long + "2,b:" + long, map[string]int{"a": 2, "b": 1}, false},
+		{"a:short", nil, true},
+		{":" + long, nil, true},
+		{"nocolon", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSecrets(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSecrets() error = %v, want error %v", err, tt.wantErr)
+			}
+			for id, n := range tt.want {
+				if len(got[id]) != n {
+					t.Errorf("key %q has %d secrets, want %d", id, len(got[id]), n)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package signing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NonceStore records nonces so each may be used once within its TTL
+type NonceStore interface {
+	// Claim reports whether the nonce was unused, and marks it used
+	Claim(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error)
+}
+
+// RedisNonces shares used nonces across instances. Keys are not tenant
+// scoped: the signature doesn't cover the host, so a nonce per brand would
+// let a captured request be replayed against another brand.
+type RedisNonces struct {
+	client *redis.Client
+}
+
+// NewRedisNonces creates a Redis nonce store
+func NewRedisNonces(client *redis.Client) *RedisNonces {
+	return &RedisNonces{client: client}
+}
+
+func (r *RedisNonces) Claim(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, "signing:nonce:"+keyID+":"+nonce, 1, ttl).Result()
+}
+
+// MemoryNonces is a single-instance fallback when Redis is unavailable
+type MemoryNonces struct {
+	mu      sync.Mutex
+	seen    map[string]time.Time
+	nextGC  time.Time
+	maxSize int
+}
+
+// NewMemoryNonces creates an in-process nonce store
+func NewMemoryNonces() *MemoryNonces {
+	return &MemoryNonces{seen: map[string]time.Time{}, maxSize: 100000}
+}
+
+func (m *MemoryNonces) Claim(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	key := keyID + ":" + nonce
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.After(m.nextGC) || len(m.seen) >= m.maxSize {
+		for k, exp := range m.seen {
+			if now.After(exp) {
+				delete(m.seen, k)
+			}
+		}
+		m.nextGC = now.Add(time.Minute)
+	}
+	if exp, ok := m.seen[key]; ok && now.Before(exp) {
+		return false, nil
+	}
+	m.seen[key] = now.Add(ttl)
+	return true, nil
+}
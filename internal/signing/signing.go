@@ -0,0 +1,131 @@
+package signing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Request headers carrying the signature
+const (
+	KeyHeader       = "X-Signature-Key"
+	TimestampHeader = "X-Signature-Timestamp"
+	NonceHeader     = "X-Signature-Nonce"
+	SignatureHeader = "X-Signature"
+)
+
+// Modes for SIGNING_MODE
+const (
+	ModeOff      = "off"
+	ModeOptional = "optional"
+	ModeRequired = "required"
+)
+
+// Config controls where signatures are checked and how fresh they must be
+type Config struct {
+	Mode     string
+	Prefixes []string
+	MaxSkew  time.Duration
+}
+
+// LoadConfig reads SIGNING_MODE, SIGNING_ROUTE_PREFIXES and SIGNING_MAX_SKEW;
+// prefixes default to the partner routes of apiVersion. In optional mode
+// unsigned requests pass but a bad signature is still rejected.
+func LoadConfig(apiVersion string) Config {
+	return Config{
+		Mode:     envconfig.String("SIGNING_MODE", ModeOff),
+		Prefixes: envconfig.List("SIGNING_ROUTE_PREFIXES", []string{"/api/" + apiVersion + "/partner"}),
+		MaxSkew:  envconfig.Duration("SIGNING_MAX_SKEW", 5*time.Minute),
+	}
+}
+
+// Validate rejects unknown modes and a non-positive skew
+func (c Config) Validate() error {
+	switch c.Mode {
+	case ModeOff, ModeOptional, ModeRequired:
+	default:
+		return fmt.Errorf("SIGNING_MODE must be off, optional or required")
+	}
+	if c.MaxSkew <= 0 {
+		return fmt.Errorf("SIGNING_MAX_SKEW must be positive")
+	}
+	return nil
+}
+
+// Secrets looks up the signing secrets for a key ID; more than one is
+// accepted while a secret is being rotated
+type Secrets interface {
+	Secrets(ctx context.Context, keyID string) ([][]byte, error)
+}
+
+// StaticSecrets is a fixed key ID to secrets table
+type StaticSecrets map[string][][]byte
+
+func (s StaticSecrets) Secrets(ctx context.Context, keyID string) ([][]byte, error) {
+	return s[keyID], nil
+}
+
+// LoadSecrets reads SIGNING_KEYS, comma-separated id:secret entries; an id
+// listed twice accepts both secrets during rotation
+func LoadSecrets() (StaticSecrets, error) {
+	return ParseSecrets(envconfig.String("SIGNING_KEYS", ""))
+}
+
+// ParseSecrets parses id:secret entries
+func ParseSecrets(spec string) (StaticSecrets, error) {
+	out := StaticSecrets{}
+	for _, entry := range strings.Split(spec, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || len(secret) < 32 {
+			return nil, fmt.Errorf("SIGNING_KEYS entries must look like id:secret with a secret of at least 32 characters")
+		}
+		out[id] = append(out[id], []byte(secret))
+	}
+	return out, nil
+}
+
+// Verification errors; the message is returned to the caller
+var (
+	ErrUnsigned  = errors.New("request signature required")
+	ErrMalformed = errors.New("malformed signature headers")
+	ErrUnknown   = errors.New("unknown signing key")
+	ErrStale     = errors.New("request timestamp outside the allowed window")
+	ErrInvalid   = errors.New("signature does not match")
+	ErrReplayed  = errors.New("nonce already used")
+)
+
+// StringToSign is what the HMAC covers: version, timestamp, nonce, method,
+// path with query, and the hex SHA-256 of the body, newline separated
+func StringToSign(timestamp, nonce, method, uri string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{"v1", timestamp, nonce, strings.ToUpper(method), uri, hex.EncodeToString(sum[:])}, "\n")
+}
+
+// Compute returns the "v1=<hex>" signature value
+func Compute(secret []byte, stringToSign string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(stringToSign))
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign adds signature headers to req for body; partners and tests use it to
+// produce requests the middleware accepts
+func Sign(req *http.Request, keyID string, secret, body []byte, nonce string, now time.Time) {
+	ts := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set(KeyHeader, keyID)
+	req.Header.Set(TimestampHeader, ts)
+	req.Header.Set(NonceHeader, nonce)
+	req.Header.Set(SignatureHeader, Compute(secret, StringToSign(ts, nonce, req.Method, req.URL.RequestURI(), body)))
+}
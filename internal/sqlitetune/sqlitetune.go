@@ -0,0 +1,101 @@
+package sqlitetune
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Options are the SQLite PRAGMAs run on each new connection and the pool limits
+type Options struct {
+	JournalMode string        // DB_JOURNAL_MODE, e.g. WAL
+	Synchronous string        // DB_SYNCHRONOUS, e.g. NORMAL
+	BusyTimeout time.Duration // DB_BUSY_TIMEOUT
+	// CacheSizeKB is the page cache per connection; applied as a negative cache_size
+	CacheSizeKB  int // DB_CACHE_SIZE_KB
+	MaxOpenConns int // DB_MAX_OPEN_CONNS
+	// SerializeWrites limits the pool to one connection so writers never contend
+	SerializeWrites bool // DB_SERIALIZE_WRITES
+}
+
+// OptionsFromEnv loads tuning options, defaulting to WAL with a 5s busy timeout
+func OptionsFromEnv() Options {
+	return Options{
+		JournalMode:     strings.ToUpper(envconfig.String("DB_JOURNAL_MODE", "WAL")),
+		Synchronous:     strings.ToUpper(envconfig.String("DB_SYNCHRONOUS", "NORMAL")),
+		BusyTimeout:     envconfig.Duration("DB_BUSY_TIMEOUT", 5*time.Second),
+		CacheSizeKB:     envconfig.Int("DB_CACHE_SIZE_KB", 20000),
+		MaxOpenConns:    envconfig.Int("DB_MAX_OPEN_CONNS", 4),
+		SerializeWrites: envconfig.Bool("DB_SERIALIZE_WRITES", false),
+	}
+}
+
+var (
+	journalModes = map[string]bool{"DELETE": true, "TRUNCATE": true, "PERSIST": true, "MEMORY": true, "WAL": true, "OFF": true}
+	syncModes    = map[string]bool{"OFF": true, "NORMAL": true, "FULL": true, "EXTRA": true}
+)
+
+// Validate checks the options against the values SQLite accepts
+func (o Options) Validate() error {
+	if !journalModes[o.JournalMode] {
+		return fmt.Errorf("invalid journal mode %q", o.JournalMode)
+	}
+	if !syncModes[o.Synchronous] {
+		return fmt.Errorf("invalid synchronous mode %q", o.Synchronous)
+	}
+	if o.BusyTimeout < 0 || o.CacheSizeKB < 0 {
+		return fmt.Errorf("busy timeout and cache size must not be negative")
+	}
+	if o.MaxOpenConns < 1 {
+		return fmt.Errorf("max open connections must be at least 1")
+	}
+	return nil
+}
+
+// Driver returns a SQLite driver that runs the PRAGMAs on every connection
+// it opens. busy_timeout, synchronous and cache_size are per-connection in
+// SQLite, so connections the pool opens after startup need them too.
+func Driver(o Options) driver.Driver {
+	pragmas := []string{
+		fmt.Sprintf("PRAGMA busy_timeout = %d", o.BusyTimeout.Milliseconds()),
+		fmt.Sprintf("PRAGMA journal_mode = %s", o.JournalMode),
+		fmt.Sprintf("PRAGMA synchronous = %s", o.Synchronous),
+		fmt.Sprintf("PRAGMA cache_size = -%d", o.CacheSizeKB),
+	}
+	return &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			for _, p := range pragmas {
+				if _, err := conn.Exec(p, nil); err != nil {
+					return fmt.Errorf("%s: %w", p, err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Apply configures the pool; the PRAGMAs come from the connections Driver opens
+func Apply(db *sql.DB, o Options) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	conns := o.MaxOpenConns
+	if o.SerializeWrites {
+		conns = 1
+	}
+	db.SetMaxOpenConns(conns)
+	db.SetMaxIdleConns(conns)
+
+	// Open one connection now so PRAGMA errors surface at startup
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to open connection: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package startup
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler serves the startup probe: 200 once startup is done, 503 with the
+// per-step progress until then
+func (t *Tracker) Handler(c echo.Context) error {
+	report := t.Report()
+	status := http.StatusOK
+	if report.Status != Done {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, report)
+}
+
+// Gate wraps a readiness handler so it fails until startup is done
+func (t *Tracker) Gate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !t.Done() {
+			report := t.Report()
+			return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+				"status":   "starting",
+				"progress": report.Progress,
+			})
+		}
+		return next(c)
+	}
+}
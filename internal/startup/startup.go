@@ -0,0 +1,195 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Step states
+const (
+	Pending = "pending"
+	Running = "running"
+	Done    = "done"
+	Failed  = "failed"
+)
+
+// Step is one startup task, run in the order added
+type Step struct {
+	Name string
+	Run  func(ctx context.Context) error
+	// Optional steps that keep failing are reported but don't hold startup
+	Optional bool
+}
+
+// StepStatus is a step's progress
+type StepStatus struct {
+	Name     string     `json:"name"`
+	Status   string     `json:"status"`
+	Optional bool       `json:"optional,omitempty"`
+	Attempts int        `json:"attempts"`
+	Error    string     `json:"error,omitempty"`
+	Started  *time.Time `json:"started_at,omitempty"`
+	Duration float64    `json:"duration_ms,omitempty"`
+}
+
+// Report is the startup progress served to probes
+type Report struct {
+	Status   string       `json:"status"`
+	Progress float64      `json:"progress"`
+	Elapsed  float64      `json:"elapsed_ms"`
+	Steps    []StepStatus `json:"steps"`
+}
+
+// Tracker sequences the startup steps and reports their progress. Required
+// steps are retried until they pass or STARTUP_TIMEOUT expires; optional ones
+// get STARTUP_OPTIONAL_ATTEMPTS tries.
+type Tracker struct {
+	timeout          time.Duration
+	optionalAttempts int
+	retryDelay       time.Duration
+
+	mu      sync.RWMutex
+	steps   []Step
+	status  []StepStatus
+	began   time.Time
+	elapsed time.Duration
+	state   string
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		timeout:          envconfig.Duration("STARTUP_TIMEOUT", 5*time.Minute),
+		optionalAttempts: envconfig.Int("STARTUP_OPTIONAL_ATTEMPTS", 3),
+		retryDelay:       envconfig.Duration("STARTUP_RETRY_DELAY", 2*time.Second),
+		state:            Pending,
+	}
+}
+
+// Validate checks the startup settings
+func (t *Tracker) Validate() error {
+	if t.timeout <= 0 {
+		return fmt.Errorf("STARTUP_TIMEOUT must be positive")
+	}
+	if t.optionalAttempts < 1 {
+		return fmt.Errorf("STARTUP_OPTIONAL_ATTEMPTS must be at least 1")
+	}
+	if t.retryDelay <= 0 {
+		return fmt.Errorf("STARTUP_RETRY_DELAY must be positive")
+	}
+	return nil
+}
+
+// Add appends a step; steps added after Run starts are ignored
+func (t *Tracker) Add(s Step) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.steps = append(t.steps, s)
+	t.status = append(t.status, StepStatus{Name: s.Name, Status: Pending, Optional: s.Optional})
+}
+
+// Run executes the steps in order; it returns when they finish, a required
+// step gives up, or ctx is cancelled
+func (t *Tracker) Run(ctx context.Context) {
+	t.mu.Lock()
+	t.began, t.state = time.Now(), Running
+	steps := append([]Step{}, t.steps...)
+	t.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	for i, s := range steps {
+		if err := t.run(ctx, i, s); err != nil && !s.Optional {
+			t.finish(Failed)
+			log.Printf("❌ Startup step %s failed, startup probe will keep failing: %v", s.Name, err)
+			return
+		}
+	}
+	t.finish(Done)
+	log.Printf("✅ Startup completed in %s", time.Since(t.began).Round(time.Millisecond))
+}
+
+func (t *Tracker) run(ctx context.Context, i int, s Step) error {
+	start := time.Now()
+	started := start.UTC()
+	t.update(i, func(st *StepStatus) { st.Status, st.Started = Running, &started })
+	for attempt := 1; ; attempt++ {
+		err := s.Run(ctx)
+		t.update(i, func(st *StepStatus) {
+			st.Attempts = attempt
+			st.Duration = milliseconds(time.Since(start))
+			st.Error = ""
+			if err != nil {
+				st.Error = err.Error()
+			}
+		})
+		if err == nil {
+			t.update(i, func(st *StepStatus) { st.Status = Done })
+			log.Printf("✅ Startup step %s done (%s)", s.Name, time.Since(start).Round(time.Millisecond))
+			return nil
+		}
+		if s.Optional && attempt >= t.optionalAttempts {
+			t.update(i, func(st *StepStatus) { st.Status = Failed })
+			log.Printf("⚠️ Optional startup step %s skipped after %d attempts: %v", s.Name, attempt, err)
+			return err
+		}
+		log.Printf("⏳ Startup step %s attempt %d failed: %v", s.Name, attempt, err)
+		select {
+		case <-ctx.Done():
+			t.update(i, func(st *StepStatus) { st.Status = Failed })
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-time.After(t.retryDelay):
+		}
+	}
+}
+
+func (t *Tracker) update(i int, fn func(*StepStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn(&t.status[i])
+}
+
+func (t *Tracker) finish(state string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state, t.elapsed = state, time.Since(t.began)
+}
+
+// Done reports whether every required step has passed
+func (t *Tracker) Done() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.state == Done
+}
+
+// Report returns a snapshot of the progress
+func (t *Tracker) Report() Report {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	r := Report{Status: t.state, Steps: append([]StepStatus{}, t.status...)}
+	finished := 0
+	for _, s := range r.Steps {
+		if s.Status == Done || (s.Status == Failed && s.Optional) {
+			finished++
+		}
+	}
+	if len(r.Steps) > 0 {
+		r.Progress = float64(finished) / float64(len(r.Steps))
+	}
+	switch {
+	case t.state == Done || t.state == Failed:
+		r.Elapsed = milliseconds(t.elapsed)
+	case !t.began.IsZero():
+		r.Elapsed = milliseconds(time.Since(t.began))
+	}
+	return r
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
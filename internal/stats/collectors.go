@@ -0,0 +1,272 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/dbschema"
+	"nutrition-health-backend/internal/repo"
+	"nutrition-health-backend/internal/tenant"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ActivitySource is a table whose rows count as user activity
+type ActivitySource struct {
+	Table      string
+	UserColumn string
+	TimeColumn string
+}
+
+// DefaultActivitySources are the tables that record what users log. The sync
+// journal is left out: it repeats diary and weight writes.
+var DefaultActivitySources = []ActivitySource{
+	{Table: "diary_entries", UserColumn: "user_id", TimeColumn: "logged_at"},
+	{Table: "weight_logs", UserColumn: "user_id", TimeColumn: "logged_at"},
+	{Table: "symptom_entries", UserColumn: "user_id", TimeColumn: "logged_at"},
+	{Table: "health_readings", UserColumn: "user_id", TimeColumn: "created_at"},
+}
+
+// ActivityCollector derives active users and logged entries from activity tables
+type ActivityCollector struct {
+	db      *sql.DB
+	sources []ActivitySource
+}
+
+// NewActivityCollector creates the collector; missing tables are skipped
+func NewActivityCollector(db *sql.DB, sources ...ActivitySource) *ActivityCollector {
+	if len(sources) == 0 {
+		sources = DefaultActivitySources
+	}
+	return &ActivityCollector{db: db, sources: sources}
+}
+
+func (a *ActivityCollector) Name() string { return "activity" }
+
+func (a *ActivityCollector) Collect(ctx context.Context, day time.Time) ([]Value, error) {
+	sources, err := a.existing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	end := day.Add(24 * time.Hour)
+	dau, err := a.distinctUsers(ctx, sources, day, end)
+	if err != nil {
+		return nil, err
+	}
+	wau, err := a.distinctUsers(ctx, sources, day.Add(-6*24*time.Hour), end)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries float64
+	for _, s := range sources {
+		var n int64
+		q := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s >= ? AND %s < ?`, s.Table, s.TimeColumn, s.TimeColumn)
+		if err := a.db.QueryRowContext(ctx, q, day, end).Scan(&n); err != nil {
+			return nil, err
+		}
+		entries += float64(n)
+	}
+
+	return []Value{
+		{Metric: DailyActiveUsers, Value: dau},
+		{Metric: WeeklyActiveUsers, Value: wau},
+		{Metric: EntriesLogged, Value: entries},
+	}, nil
+}
+
+func (a *ActivityCollector) distinctUsers(ctx context.Context, sources []ActivitySource, from, to time.Time) (float64, error) {
+	parts := make([]string, 0, len(sources))
+	args := make([]interface{}, 0, 2*len(sources))
+	for _, s := range sources {
+		parts = append(parts, fmt.Sprintf(`SELECT %s AS user_id FROM %s WHERE %s >= ? AND %s < ?`,
+			s.UserColumn, s.Table, s.TimeColumn, s.TimeColumn))
+		args = append(args, from, to)
+	}
+	var n int64
+	q := `SELECT COUNT(DISTINCT user_id) FROM (` + strings.Join(parts, " UNION ALL ") + `)`
+	if err := a.db.QueryRowContext(ctx, q, args...).Scan(&n); err != nil {
+		return 0, err
+	}
+	return float64(n), nil
+}
+
+func (a *ActivityCollector) existing(ctx context.Context) ([]ActivitySource, error) {
+	var out []ActivitySource
+	for _, s := range a.sources {
+		cols, err := dbschema.Columns(ctx, a.db, s.Table)
+		if err != nil {
+			return nil, err
+		}
+		if cols[s.UserColumn] && cols[s.TimeColumn] {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// PlanCollector counts the meal plans created each day
+type PlanCollector struct {
+	db *sql.DB
+}
+
+// NewPlanCollector creates the collector
+func NewPlanCollector(db *sql.DB) *PlanCollector {
+	return &PlanCollector{db: db}
+}
+
+func (p *PlanCollector) Name() string { return "plans" }
+
+func (p *PlanCollector) Collect(ctx context.Context, day time.Time) ([]Value, error) {
+	tenantID, err := tenant.Filter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n, err := repo.New(p.db).CountMealPlansCreated(ctx, repo.CountMealPlansCreatedParams{
+		TenantID:    tenantID,
+		CreatedFrom: day,
+		CreatedTo:   day.Add(24 * time.Hour),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []Value{{Metric: PlanGenerations, Value: float64(n)}}, nil
+}
+
+// Counter names incremented via Counters.Incr
+const (
+	CountExternalAPICalls  = "external_api_calls"
+	CountExternalAPIErrors = "external_api_errors"
+	counterRetention       = 8 * 24 * time.Hour
+	counterKeyPrefix       = "stats:count:"
+	cacheBaselineKeyPrefix = "stats:cache_baseline:"
+)
+
+// Counters records per-day event counts in Redis so every instance contributes
+type Counters struct {
+	client *redis.Client
+}
+
+// NewCounters creates a counter set; a nil client makes Incr a no-op
+func NewCounters(client *redis.Client) *Counters {
+	return &Counters{client: client}
+}
+
+func counterKey(name string, day time.Time) string {
+	return counterKeyPrefix + name + ":" + dateKey(day)
+}
+
+// Incr bumps a counter for the current UTC day
+func (c *Counters) Incr(ctx context.Context, name string) {
+	if c == nil || c.client == nil {
+		return
+	}
+	key := counterKey(name, time.Now())
+	pipe := c.client.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, counterRetention)
+	pipe.Exec(ctx)
+}
+
+func (c *Counters) get(ctx context.Context, name string, day time.Time) (float64, error) {
+	n, err := c.client.Get(ctx, counterKey(name, day)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return float64(n), err
+}
+
+func (c *Counters) Name() string { return "counters" }
+
+// Collect reports the external API error rate
+func (c *Counters) Collect(ctx context.Context, day time.Time) ([]Value, error) {
+	if c.client == nil {
+		return nil, nil
+	}
+	calls, err := c.get(ctx, CountExternalAPICalls, day)
+	if err != nil {
+		return nil, err
+	}
+	errs, err := c.get(ctx, CountExternalAPIErrors, day)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := 0.0
+	if calls > 0 {
+		rate = errs / calls
+	}
+	return []Value{
+		{Metric: ExternalAPIErrorRate, Value: rate, Detail: map[string]float64{"calls": calls, "errors": errs}},
+	}, nil
+}
+
+// CacheCollector reports the Redis keyspace hit rate for the day, measured
+// against the counters seen at the day's first collection
+type CacheCollector struct {
+	client *redis.Client
+}
+
+// NewCacheCollector creates the collector
+func NewCacheCollector(client *redis.Client) *CacheCollector {
+	return &CacheCollector{client: client}
+}
+
+func (c *CacheCollector) Name() string { return "cache" }
+
+func (c *CacheCollector) Collect(ctx context.Context, day time.Time) ([]Value, error) {
+	// Redis only exposes lifetime counters, so past days can't be recomputed
+	if c.client == nil || !startOfDay(time.Now()).Equal(day) {
+		return nil, nil
+	}
+	info, err := c.client.Info(ctx, "stats").Result()
+	if err != nil {
+		return nil, err
+	}
+	hits, misses := infoField(info, "keyspace_hits"), infoField(info, "keyspace_misses")
+
+	key := cacheBaselineKeyPrefix + dateKey(day)
+	c.client.HSetNX(ctx, key, "hits", hits)
+	c.client.HSetNX(ctx, key, "misses", misses)
+	c.client.Expire(ctx, key, counterRetention)
+	base, err := c.client.HMGet(ctx, key, "hits", "misses").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	dh := hits - parseInfoValue(base[0])
+	dm := misses - parseInfoValue(base[1])
+	if dh < 0 || dm < 0 {
+		// Redis restarted during the day; fall back to lifetime counters
+		dh, dm = hits, misses
+	}
+	rate := 0.0
+	if dh+dm > 0 {
+		rate = float64(dh) / float64(dh+dm)
+	}
+	return []Value{{Metric: CacheHitRate, Value: rate, Detail: map[string]int64{"hits": dh, "misses": dm}}}, nil
+}
+
+func infoField(info, field string) int64 {
+	for _, line := range strings.Split(info, "\n") {
+		if strings.HasPrefix(line, field+":") {
+			n, _ := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, field+":")), 10, 64)
+			return n
+		}
+	}
+	return 0
+}
+
+func parseInfoValue(v interface{}) int64 {
+	s, _ := v.(string)
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
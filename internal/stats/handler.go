@@ -0,0 +1,51 @@
+package stats
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxDays bounds ?days= on the stats endpoint
+const maxDays = 90
+
+// Handler serves the admin statistics dashboard
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates the handler
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes mounts GET /stats on an admin-protected group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/stats", h.Stats)
+}
+
+// Stats returns per-day metrics for the last ?days= days (default 7) plus the latest day
+func (h *Handler) Stats(c echo.Context) error {
+	days := 7
+	if v := c.QueryParam("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxDays {
+			return echo.NewHTTPError(http.StatusBadRequest, "days must be between 1 and 90")
+		}
+		days = n
+	}
+
+	since := startOfDay(time.Now()).Add(-time.Duration(days-1) * 24 * time.Hour)
+	history, err := h.store.Days(c.Request().Context(), since)
+	if err != nil {
+		return err
+	}
+
+	resp := map[string]interface{}{"days": history}
+	if len(history) > 0 {
+		resp["latest"] = history[0]
+	}
+	return c.JSON(http.StatusOK, resp)
+}
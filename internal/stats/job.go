@@ -0,0 +1,76 @@
+package stats
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// Job aggregates collector output into the stats table on an interval
+type Job struct {
+	store      *Store
+	collectors []Collector
+	// Interval is how often today's (and yesterday's final) numbers are recomputed
+	Interval time.Duration
+}
+
+// NewJob creates the aggregation job; STATS_INTERVAL sets the period (default 1h)
+func NewJob(store *Store, collectors ...Collector) *Job {
+	return &Job{
+		store:      store,
+		collectors: collectors,
+		Interval:   envconfig.Duration("STATS_INTERVAL", time.Hour),
+	}
+}
+
+// Add registers another collector before Start
+func (j *Job) Add(c Collector) {
+	j.collectors = append(j.collectors, c)
+}
+
+// Start aggregates immediately, then once per interval until ctx is cancelled
+func (j *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("⚠️ Stats aggregation failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce recomputes yesterday and today
+func (j *Job) RunOnce(ctx context.Context) error {
+	today := startOfDay(time.Now())
+	for _, day := range []time.Time{today.Add(-24 * time.Hour), today} {
+		if err := j.aggregate(ctx, day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *Job) aggregate(ctx context.Context, day time.Time) error {
+	var values []Value
+	for _, c := range j.collectors {
+		vs, err := c.Collect(ctx, day)
+		if err != nil {
+			// One broken source shouldn't blank the whole dashboard
+			log.Printf("⚠️ Stats collector %s failed for %s: %v", c.Name(), dateKey(day), err)
+			continue
+		}
+		values = append(values, vs...)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return j.store.Save(ctx, day, values)
+}
@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"context"
+	"time"
+)
+
+// Metric names reported by the built-in collectors
+const (
+	DailyActiveUsers     = "daily_active_users"
+	WeeklyActiveUsers    = "weekly_active_users"
+	EntriesLogged        = "entries_logged"
+	PlanGenerations      = "plan_generations"
+	CacheHitRate         = "cache_hit_rate"
+	ExternalAPIErrorRate = "external_api_error_rate"
+	ZeroResultSearches   = "zero_result_searches"
+)
+
+// Value is one aggregated metric for a day; Detail carries list-shaped data
+// such as the top zero-result search terms
+type Value struct {
+	Metric string      `json:"metric"`
+	Value  float64     `json:"value"`
+	Detail interface{} `json:"detail,omitempty"`
+}
+
+// Collector computes metrics for the UTC day starting at day
+type Collector interface {
+	Name() string
+	Collect(ctx context.Context, day time.Time) ([]Value, error)
+}
+
+// Day is the stored set of metrics for one UTC day
+type Day struct {
+	Date       string           `json:"date"`
+	Metrics    map[string]Value `json:"metrics"`
+	ComputedAt time.Time        `json:"computed_at"`
+}
+
+// startOfDay truncates t to midnight UTC
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// dateKey formats a day as YYYY-MM-DD
+func dateKey(day time.Time) string {
+	return day.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Store keeps the aggregated metrics in the admin_daily_stats table
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a stats store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate creates the admin_daily_stats table
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS admin_daily_stats (
+		day TEXT NOT NULL,
+		metric TEXT NOT NULL,
+		value REAL NOT NULL,
+		detail TEXT,
+		computed_at DATETIME NOT NULL,
+		PRIMARY KEY (day, metric)
+	)`)
+	return err
+}
+
+// Save replaces the stored values for a day
+func (s *Store) Save(ctx context.Context, day time.Time, values []Value) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	for _, v := range values {
+		var detail sql.NullString
+		if v.Detail != nil {
+			data, err := json.Marshal(v.Detail)
+			if err != nil {
+				return err
+			}
+			detail = sql.NullString{String: string(data), Valid: true}
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO admin_daily_stats (day, metric, value, detail, computed_at) VALUES (?, ?, ?, ?, ?)
+			 ON CONFLICT(day, metric) DO UPDATE SET value = excluded.value, detail = excluded.detail, computed_at = excluded.computed_at`,
+			dateKey(day), v.Metric, v.Value, detail, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Days returns stored metrics from since (inclusive), newest first
+func (s *Store) Days(ctx context.Context, since time.Time) ([]Day, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT day, metric, value, detail, computed_at FROM admin_daily_stats
+		 WHERE day >= ? ORDER BY day DESC, metric`, dateKey(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []Day
+	for rows.Next() {
+		var (
+			date     string
+			v        Value
+			detail   sql.NullString
+			computed time.Time
+		)
+		if err := rows.Scan(&date, &v.Metric, &v.Value, &detail, &computed); err != nil {
+			return nil, err
+		}
+		if detail.Valid {
+			var d interface{}
+			if err := json.Unmarshal([]byte(detail.String), &d); err == nil {
+				v.Detail = d
+			}
+		}
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, Day{Date: date, Metrics: map[string]Value{}})
+		}
+		cur := &days[len(days)-1]
+		cur.Metrics[v.Metric] = v
+		if computed.After(cur.ComputedAt) {
+			cur.ComputedAt = computed
+		}
+	}
+	return days, rows.Err()
+}
@@ -0,0 +1,147 @@
+package symptoms
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AnalysisOptions tunes the correlation analysis
+type AnalysisOptions struct {
+	// Lookback is how far before a symptom entry intakes count as exposure
+	Lookback time.Duration
+	// MinExposures skips items eaten before fewer entries than this
+	MinExposures int
+	// MinSamples skips symptom kinds with fewer entries than this
+	MinSamples int
+}
+
+// DefaultAnalysisOptions returns the options used by the nightly job
+func DefaultAnalysisOptions() AnalysisOptions {
+	return AnalysisOptions{
+		Lookback:     48 * time.Hour,
+		MinExposures: 3,
+		MinSamples:   5,
+	}
+}
+
+type itemKey struct {
+	name     string
+	itemType string
+}
+
+// Analyze computes, per symptom kind, the Pearson correlation between having
+// eaten a food or ingredient within the lookback window and the symptom severity.
+// Results are sorted by correlation strength, strongest first.
+func Analyze(userID string, entries []Entry, intakes []Intake, opts AnalysisOptions) []Correlation {
+	now := time.Now().UTC()
+
+	byKind := make(map[Kind][]Entry)
+	for _, e := range entries {
+		byKind[e.Kind] = append(byKind[e.Kind], e)
+	}
+
+	var results []Correlation
+	for kind, kindEntries := range byKind {
+		if len(kindEntries) < opts.MinSamples {
+			continue
+		}
+
+		// Build the exposure matrix: which items preceded each entry
+		exposures := make(map[itemKey][]bool)
+		for i, e := range kindEntries {
+			start := e.LoggedAt.Add(-opts.Lookback)
+			for _, in := range intakes {
+				if in.EatenAt.Before(start) || in.EatenAt.After(e.LoggedAt) {
+					continue
+				}
+				markExposure(exposures, itemKey{normalizeItem(in.FoodName), "food"}, i, len(kindEntries))
+				for _, ing := range in.Ingredients {
+					markExposure(exposures, itemKey{normalizeItem(ing), "ingredient"}, i, len(kindEntries))
+				}
+			}
+		}
+
+		severities := make([]float64, len(kindEntries))
+		for i, e := range kindEntries {
+			severities[i] = float64(e.Severity)
+		}
+
+		for key, exposed := range exposures {
+			count := 0
+			xs := make([]float64, len(exposed))
+			for i, hit := range exposed {
+				if hit {
+					xs[i] = 1
+					count++
+				}
+			}
+			if count < opts.MinExposures {
+				continue
+			}
+
+			r, ok := pearson(xs, severities)
+			if !ok {
+				continue
+			}
+
+			results = append(results, Correlation{
+				UserID:      userID,
+				Kind:        kind,
+				Item:        key.name,
+				ItemType:    key.itemType,
+				Coefficient: math.Round(r*1000) / 1000,
+				Exposures:   count,
+				Samples:     len(kindEntries),
+				ComputedAt:  now,
+			})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return math.Abs(results[i].Coefficient) > math.Abs(results[j].Coefficient)
+	})
+	return results
+}
+
+func markExposure(exposures map[itemKey][]bool, key itemKey, index, size int) {
+	if key.name == "" {
+		return
+	}
+	if exposures[key] == nil {
+		exposures[key] = make([]bool, size)
+	}
+	exposures[key][index] = true
+}
+
+func normalizeItem(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// pearson returns the correlation coefficient, or false if either series is constant
+func pearson(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	if n == 0 {
+		return 0, false
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
@@ -0,0 +1,109 @@
+package symptoms
+
+import (
+	"net/http"
+	"time"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes the symptom journal over HTTP
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a symptom handler
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes mounts the symptom routes on an authenticated API group
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.POST("/symptoms", h.Log)
+	g.GET("/symptoms", h.List)
+	g.GET("/insights/symptoms", h.Insights)
+}
+
+type logRequest struct {
+	Kind     Kind      `json:"kind"`
+	Severity int       `json:"severity"`
+	Notes    string    `json:"notes"`
+	LoggedAt time.Time `json:"logged_at"`
+}
+
+// Log records a symptom entry
+func (h *Handler) Log(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	var req logRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if req.LoggedAt.IsZero() {
+		req.LoggedAt = time.Now().UTC()
+	}
+
+	entry := Entry{
+		UserID:   userID,
+		Kind:     req.Kind,
+		Severity: req.Severity,
+		Notes:    req.Notes,
+		LoggedAt: req.LoggedAt,
+	}
+	if err := entry.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	id, err := h.store.Log(c.Request().Context(), entry)
+	if err != nil {
+		return err
+	}
+	entry.ID = id
+	return c.JSON(http.StatusCreated, entry)
+}
+
+// List returns the user's symptom entries for the last ?days= days (default 30)
+func (h *Handler) List(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	days := 30
+	if err := echo.QueryParamsBinder(c).Int("days", &days).BindError(); err != nil || days <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "days must be a positive integer")
+	}
+
+	entries, err := h.store.Entries(c.Request().Context(), userID, time.Now().UTC().AddDate(0, 0, -days))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"entries": entries})
+}
+
+// Insights returns the latest food/symptom correlations, optionally filtered by ?kind=
+func (h *Handler) Insights(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	kind := Kind(c.QueryParam("kind"))
+	if kind != "" && !kind.Valid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown symptom kind")
+	}
+
+	results, err := h.store.Correlations(c.Request().Context(), userID, kind)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"correlations": results,
+		"lookback":     DefaultAnalysisOptions().Lookback.String(),
+	})
+}
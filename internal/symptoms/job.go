@@ -0,0 +1,90 @@
+package symptoms
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// IntakeSource provides the foods a user ate in a time range, typically backed by the diary
+type IntakeSource interface {
+	IntakesBetween(ctx context.Context, userID string, from, to time.Time) ([]Intake, error)
+}
+
+// Job recomputes symptom correlations for every active user once a night
+type Job struct {
+	store   *Store
+	intakes IntakeSource
+	opts    AnalysisOptions
+	// RunAt is the UTC hour of day the job runs
+	RunAt int
+	// History is how much journal history feeds each analysis
+	History time.Duration
+}
+
+// NewJob creates the nightly correlation job
+func NewJob(store *Store, intakes IntakeSource) *Job {
+	return &Job{
+		store:   store,
+		intakes: intakes,
+		opts:    DefaultAnalysisOptions(),
+		RunAt:   3,
+		History: 60 * 24 * time.Hour,
+	}
+}
+
+// Start runs the job every night until the context is cancelled
+func (j *Job) Start(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(j.nextRun(time.Now().UTC())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if err := j.RunOnce(ctx); err != nil {
+				log.Printf("⚠️ Symptom correlation job failed: %v", err)
+			}
+		}
+	}
+}
+
+// RunOnce recomputes correlations for all users with recent journal entries
+func (j *Job) RunOnce(ctx context.Context) error {
+	to := time.Now().UTC()
+	from := to.Add(-j.History)
+
+	users, err := j.store.ActiveUsers(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range users {
+		if err := j.analyzeUser(ctx, userID, from, to); err != nil {
+			log.Printf("⚠️ Symptom analysis failed for user %s: %v", userID, err)
+		}
+	}
+	log.Printf("✅ Symptom correlations updated for %d users", len(users))
+	return nil
+}
+
+func (j *Job) analyzeUser(ctx context.Context, userID string, from, to time.Time) error {
+	entries, err := j.store.Entries(ctx, userID, from)
+	if err != nil {
+		return err
+	}
+	// Intakes just before the first entry still count as exposure
+	intakes, err := j.intakes.IntakesBetween(ctx, userID, from.Add(-j.opts.Lookback), to)
+	if err != nil {
+		return err
+	}
+	return j.store.ReplaceCorrelations(ctx, userID, Analyze(userID, entries, intakes, j.opts))
+}
+
+func (j *Job) nextRun(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), j.RunAt, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.Add(24 * time.Hour)
+	}
+	return next
+}
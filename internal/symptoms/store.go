@@ -0,0 +1,174 @@
+package symptoms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"nutrition-health-backend/internal/fieldcrypt"
+)
+
+// notesField names entry notes for field encryption
+const notesField = "symptom_entries.notes"
+
+// Store persists symptom entries and computed correlations
+type Store struct {
+	db     *sql.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewStore creates a symptom store; notes are encrypted at rest when the
+// cipher has keys configured
+func NewStore(db *sql.DB, cipher *fieldcrypt.Cipher) *Store {
+	return &Store{db: db, cipher: cipher}
+}
+
+// Migrate creates the symptom journal tables
+func Migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS symptom_entries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			severity INTEGER NOT NULL,
+			notes TEXT NOT NULL DEFAULT '',
+			logged_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_symptom_entries_user_logged ON symptom_entries(user_id, logged_at)`,
+		`CREATE TABLE IF NOT EXISTS symptom_correlations (
+			user_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			item TEXT NOT NULL,
+			item_type TEXT NOT NULL,
+			coefficient REAL NOT NULL,
+			exposures INTEGER NOT NULL,
+			samples INTEGER NOT NULL,
+			computed_at DATETIME NOT NULL,
+			PRIMARY KEY (user_id, kind, item, item_type)
+		)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("symptoms migration: %w", err)
+		}
+	}
+	return nil
+}
+
+// Log stores a symptom entry and returns its ID
+func (s *Store) Log(ctx context.Context, e Entry) (int64, error) {
+	if err := e.Validate(); err != nil {
+		return 0, err
+	}
+	if err := s.cipher.Seal(ctx, e.UserID, map[string]*string{notesField: &e.Notes}); err != nil {
+		return 0, fmt.Errorf("failed to encrypt symptom notes: %w", err)
+	}
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO symptom_entries (user_id, kind, severity, notes, logged_at) VALUES (?, ?, ?, ?, ?)`,
+		e.UserID, string(e.Kind), e.Severity, e.Notes, e.LoggedAt.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to log symptom: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// Entries returns a user's symptom entries logged since the given time
+func (s *Store) Entries(ctx context.Context, userID string, since time.Time) ([]Entry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, kind, severity, notes, logged_at FROM symptom_entries
+		 WHERE user_id = ? AND logged_at >= ? ORDER BY logged_at`,
+		userID, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symptoms: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		var kind string
+		if err := rows.Scan(&e.ID, &e.UserID, &kind, &e.Severity, &e.Notes, &e.LoggedAt); err != nil {
+			return nil, err
+		}
+		if err := s.cipher.Open(ctx, e.UserID, map[string]*string{notesField: &e.Notes}); err != nil {
+			return nil, err
+		}
+		e.Kind = Kind(kind)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// ActiveUsers returns users who logged at least one symptom since the given time
+func (s *Store) ActiveUsers(ctx context.Context, since time.Time) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT DISTINCT user_id FROM symptom_entries WHERE logged_at >= ?`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symptom users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		users = append(users, id)
+	}
+	return users, rows.Err()
+}
+
+// ReplaceCorrelations swaps a user's stored correlations for a fresh result set
+func (s *Store) ReplaceCorrelations(ctx context.Context, userID string, results []Correlation) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM symptom_correlations WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to clear correlations: %w", err)
+	}
+	for _, r := range results {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO symptom_correlations (user_id, kind, item, item_type, coefficient, exposures, samples, computed_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			userID, string(r.Kind), r.Item, r.ItemType, r.Coefficient, r.Exposures, r.Samples, r.ComputedAt); err != nil {
+			return fmt.Errorf("failed to store correlation: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Correlations returns a user's latest correlations, strongest first
+func (s *Store) Correlations(ctx context.Context, userID string, kind Kind) ([]Correlation, error) {
+	query := `SELECT user_id, kind, item, item_type, coefficient, exposures, samples, computed_at
+		FROM symptom_correlations WHERE user_id = ?`
+	args := []interface{}{userID}
+	if kind != "" {
+		query += ` AND kind = ?`
+		args = append(args, string(kind))
+	}
+	query += ` ORDER BY ABS(coefficient) DESC`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query correlations: %w", err)
+	}
+	defer rows.Close()
+
+	var results []Correlation
+	for rows.Next() {
+		var r Correlation
+		var k string
+		if err := rows.Scan(&r.UserID, &k, &r.Item, &r.ItemType, &r.Coefficient, &r.Exposures, &r.Samples, &r.ComputedAt); err != nil {
+			return nil, err
+		}
+		r.Kind = Kind(k)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
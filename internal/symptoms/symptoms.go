@@ -0,0 +1,75 @@
+package symptoms
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kind identifies a tracked symptom
+type Kind string
+
+const (
+	Bloating Kind = "bloating"
+	Headache Kind = "headache"
+	Energy   Kind = "energy"
+)
+
+// Kinds lists every supported symptom kind
+var Kinds = []Kind{Bloating, Headache, Energy}
+
+// MaxSeverity is the top of the 0-10 severity scale
+const MaxSeverity = 10
+
+// Entry is a single symptom journal entry
+type Entry struct {
+	ID       int64     `json:"id"`
+	UserID   string    `json:"user_id"`
+	Kind     Kind      `json:"kind"`
+	Severity int       `json:"severity"`
+	Notes    string    `json:"notes,omitempty"`
+	LoggedAt time.Time `json:"logged_at"`
+}
+
+// Validate checks the entry before it is stored
+func (e Entry) Validate() error {
+	if !e.Kind.Valid() {
+		return fmt.Errorf("unknown symptom kind %q", e.Kind)
+	}
+	if e.Severity < 0 || e.Severity > MaxSeverity {
+		return fmt.Errorf("severity must be between 0 and %d", MaxSeverity)
+	}
+	if e.LoggedAt.IsZero() {
+		return fmt.Errorf("logged_at is required")
+	}
+	return nil
+}
+
+// Valid reports whether k is a supported symptom kind
+func (k Kind) Valid() bool {
+	for _, known := range Kinds {
+		if k == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Intake is a food eaten by the user, as recorded in the diary
+type Intake struct {
+	FoodID      string    `json:"food_id"`
+	FoodName    string    `json:"food_name"`
+	Ingredients []string  `json:"ingredients,omitempty"`
+	EatenAt     time.Time `json:"eaten_at"`
+}
+
+// Correlation links a symptom to a food or ingredient eaten beforehand
+type Correlation struct {
+	UserID      string    `json:"user_id"`
+	Kind        Kind      `json:"kind"`
+	Item        string    `json:"item"`
+	ItemType    string    `json:"item_type"` // "food" or "ingredient"
+	Coefficient float64   `json:"coefficient"`
+	Exposures   int       `json:"exposures"`
+	Samples     int       `json:"samples"`
+	ComputedAt  time.Time `json:"computed_at"`
+}
@@ -0,0 +1,218 @@
+package targets
+
+import (
+	"errors"
+	"net/http"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler exposes target templates over HTTP
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a template handler
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes mounts read routes on the API group and write routes on the admin group
+func (h *Handler) RegisterRoutes(api, admin *echo.Group) {
+	api.GET("/targets/templates", h.List)
+	api.GET("/targets/templates/:key", h.Get)
+	api.POST("/targets/templates/:key/preview", h.Preview)
+
+	admin.PUT("/targets/templates/:key", h.Save)
+	admin.DELETE("/targets/templates/:key", h.Delete)
+}
+
+// RegisterUserRoutes mounts the caller's own targets on an authenticated group
+func (h *Handler) RegisterUserRoutes(g *echo.Group) {
+	g.GET("/targets/me", h.Mine)
+	g.PUT("/targets/me", h.Choose)
+	g.DELETE("/targets/me", h.Clear)
+}
+
+// List returns all templates
+func (h *Handler) List(c echo.Context) error {
+	templates, err := h.store.List(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"templates": templates})
+}
+
+// Get returns a single template
+func (h *Handler) Get(c echo.Context) error {
+	t, err := h.store.Get(c.Request().Context(), c.Param("key"))
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, t)
+}
+
+type previewRequest struct {
+	MaintenanceCalories float64   `json:"maintenance_calories"`
+	Overrides           Overrides `json:"overrides"`
+	// MaxDailyBudget caps the plan's daily food cost; zero means no budget
+	MaxDailyBudget float64 `json:"max_daily_budget,omitempty"`
+}
+
+// Preview resolves a template against the caller's calories and overrides without saving
+func (h *Handler) Preview(c echo.Context) error {
+	var req previewRequest
+	if err := c.Bind(&req); err != nil || req.MaintenanceCalories <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "maintenance_calories is required")
+	}
+	if req.MaxDailyBudget < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "max_daily_budget must not be negative")
+	}
+	req.Overrides = withBudget(req.Overrides, req.MaxDailyBudget)
+
+	t, err := h.store.Get(c.Request().Context(), c.Param("key"))
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	targets := Apply(t, req.MaintenanceCalories, req.Overrides)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"targets":     targets,
+		"constraints": targets.Constraints(),
+	})
+}
+
+// withBudget adds a daily cost limit to o; a zero budget leaves it unchanged
+func withBudget(o Overrides, maxDailyBudget float64) Overrides {
+	if maxDailyBudget <= 0 {
+		return o
+	}
+	limits := make(map[string]Limit, len(o.Limits)+1)
+	for nutrient, l := range o.Limits {
+		limits[nutrient] = l
+	}
+	limits[CostNutrient] = Limit{Max: maxDailyBudget}
+	o.Limits = limits
+	return o
+}
+
+// Mine returns the caller's choice and the targets it resolves to
+func (h *Handler) Mine(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	ctx := c.Request().Context()
+	choice, err := h.store.Choice(ctx, userID)
+	if errors.Is(err, ErrNoChoice) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	targets, err := h.store.ForUser(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusConflict, "chosen template no longer exists")
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"choice":      choice,
+		"targets":     targets,
+		"constraints": targets.Constraints(),
+	})
+}
+
+type chooseRequest struct {
+	TemplateKey string `json:"template_key"`
+	previewRequest
+}
+
+// Choose saves the caller's template, maintenance calories and overrides
+func (h *Handler) Choose(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	var req chooseRequest
+	if err := c.Bind(&req); err != nil || req.TemplateKey == "" || req.MaintenanceCalories <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "template_key and maintenance_calories are required")
+	}
+	if req.MaxDailyBudget < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "max_daily_budget must not be negative")
+	}
+
+	targets, err := h.store.SetChoice(c.Request().Context(), userID, Choice{
+		TemplateKey:         req.TemplateKey,
+		MaintenanceCalories: req.MaintenanceCalories,
+		Overrides:           withBudget(req.Overrides, req.MaxDailyBudget),
+	})
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"targets":     targets,
+		"constraints": targets.Constraints(),
+	})
+}
+
+// Clear removes the caller's choice
+func (h *Handler) Clear(c echo.Context) error {
+	userID := reqctx.UserID(c)
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	err := h.store.ClearChoice(c.Request().Context(), userID)
+	if errors.Is(err, ErrNoChoice) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Save creates or updates a template (admin only)
+func (h *Handler) Save(c echo.Context) error {
+	var t Template
+	if err := c.Bind(&t); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	t.Key = c.Param("key")
+	t.Builtin = false
+
+	if existing, err := h.store.Get(c.Request().Context(), t.Key); err == nil {
+		t.Builtin = existing.Builtin
+	}
+	if err := t.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := h.store.Save(c.Request().Context(), t); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, t)
+}
+
+// Delete removes a template (admin only)
+func (h *Handler) Delete(c echo.Context) error {
+	err := h.store.Delete(c.Request().Context(), c.Param("key"))
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
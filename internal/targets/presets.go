@@ -0,0 +1,80 @@
+package targets
+
+// Builtin returns the templates seeded on first migration. Admins may edit them afterwards.
+func Builtin() []Template {
+	return []Template{
+		{
+			Key:           "diabetic_friendly",
+			Name:          "Diabetic-friendly",
+			NameAr:        "مناسب لمرضى السكري",
+			Description:   "Moderate carbohydrate with high fiber and limited added sugar.",
+			DescriptionAr: "كربوهيدرات معتدلة مع ألياف عالية وسكر مضاف محدود.",
+			ProteinPct:    20,
+			CarbsPct:      45,
+			FatPct:        35,
+			Limits: map[string]Limit{
+				"fiber_g":         {Min: 30},
+				"sugar_g":         {Max: 25},
+				"saturated_fat_g": {Max: 20},
+			},
+		},
+		{
+			Key:               "athlete_bulking",
+			Name:              "Athlete bulking",
+			NameAr:            "زيادة الكتلة العضلية للرياضيين",
+			Description:       "Calorie surplus with high protein to support muscle gain.",
+			DescriptionAr:     "فائض في السعرات مع بروتين عالٍ لدعم بناء العضلات.",
+			CalorieAdjustment: 400,
+			ProteinPct:        25,
+			CarbsPct:          50,
+			FatPct:            25,
+		},
+		{
+			Key:               "pregnancy",
+			Name:              "Pregnancy",
+			NameAr:            "الحمل",
+			Description:       "Second/third trimester energy increase with folate and iron targets.",
+			DescriptionAr:     "زيادة الطاقة في الثلث الثاني والثالث مع أهداف حمض الفوليك والحديد.",
+			CalorieAdjustment: 340,
+			ProteinPct:        20,
+			CarbsPct:          50,
+			FatPct:            30,
+			Limits: map[string]Limit{
+				"folate_mcg": {Min: 600},
+				"iron_mg":    {Min: 27},
+				"sodium_mg":  {Max: 2300},
+			},
+		},
+		{
+			Key:           "renal",
+			Name:          "Renal diet",
+			NameAr:        "حمية الكلى",
+			Description:   "Reduced protein, sodium, potassium and phosphorus. Use under medical supervision.",
+			DescriptionAr: "بروتين وصوديوم وبوتاسيوم وفوسفور منخفض. يُستخدم تحت إشراف طبي.",
+			ProteinPct:    10,
+			CarbsPct:      55,
+			FatPct:        35,
+			Limits: map[string]Limit{
+				"sodium_mg":     {Max: 2000},
+				"potassium_mg":  {Max: 2000},
+				"phosphorus_mg": {Max: 800},
+			},
+		},
+		{
+			Key:           "dash",
+			Name:          "DASH",
+			NameAr:        "حمية داش",
+			Description:   "Dietary Approaches to Stop Hypertension: low sodium, high potassium and fiber.",
+			DescriptionAr: "النظام الغذائي لإيقاف ارتفاع ضغط الدم: صوديوم منخفض وبوتاسيوم وألياف عالية.",
+			ProteinPct:    18,
+			CarbsPct:      55,
+			FatPct:        27,
+			Limits: map[string]Limit{
+				"sodium_mg":       {Max: 2300},
+				"potassium_mg":    {Min: 4700},
+				"fiber_g":         {Min: 30},
+				"saturated_fat_g": {Max: 16},
+			},
+		},
+	}
+}
@@ -0,0 +1,222 @@
+package targets
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"nutrition-health-backend/internal/nutrition"
+)
+
+var (
+	// ErrNotFound is returned when a template key does not exist
+	ErrNotFound = errors.New("target template not found")
+	// ErrNoChoice is returned for users who haven't chosen targets
+	ErrNoChoice = errors.New("no targets chosen")
+)
+
+// Store persists target templates and each user's choice of template
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a template store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate creates the template and user choice tables and seeds the
+// built-in templates
+func Migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS nutrition_target_templates (
+		key TEXT PRIMARY KEY,
+		data TEXT NOT NULL,
+		builtin INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("target templates migration: %w", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS user_nutrition_targets (
+		user_id TEXT PRIMARY KEY,
+		template_key TEXT NOT NULL,
+		maintenance_calories REAL NOT NULL,
+		overrides TEXT NOT NULL DEFAULT '{}',
+		updated_at DATETIME NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("user targets migration: %w", err)
+	}
+
+	for _, t := range Builtin() {
+		t.Builtin = true
+		data, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(`INSERT OR IGNORE INTO nutrition_target_templates (key, data, builtin) VALUES (?, ?, 1)`,
+			t.Key, string(data)); err != nil {
+			return fmt.Errorf("failed to seed template %s: %w", t.Key, err)
+		}
+	}
+	return nil
+}
+
+// List returns all templates ordered by key
+func (s *Store) List(ctx context.Context) ([]Template, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM nutrition_target_templates ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []Template
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var t Template
+		if err := json.Unmarshal([]byte(data), &t); err != nil {
+			return nil, fmt.Errorf("corrupt template row: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// Get returns a template by key
+func (s *Store) Get(ctx context.Context, key string) (Template, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM nutrition_target_templates WHERE key = ?`, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Template{}, ErrNotFound
+	}
+	if err != nil {
+		return Template{}, err
+	}
+	var t Template
+	if err := json.Unmarshal([]byte(data), &t); err != nil {
+		return Template{}, fmt.Errorf("corrupt template row: %w", err)
+	}
+	return t, nil
+}
+
+// Save creates or replaces a template
+func (s *Store) Save(ctx context.Context, t Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO nutrition_target_templates (key, data, builtin) VALUES (?, ?, ?)
+		 ON CONFLICT(key) DO UPDATE SET data = excluded.data, updated_at = CURRENT_TIMESTAMP`,
+		t.Key, string(data), t.Builtin)
+	return err
+}
+
+// Delete removes a template
+func (s *Store) Delete(ctx context.Context, key string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM nutrition_target_templates WHERE key = ?`, key)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Choice is the template and overrides a user picked. It is resolved on
+// read, so edits to the template reach the user's targets.
+type Choice struct {
+	TemplateKey         string    `json:"template_key"`
+	MaintenanceCalories float64   `json:"maintenance_calories"`
+	Overrides           Overrides `json:"overrides"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// Choice returns the user's choice, or ErrNoChoice
+func (s *Store) Choice(ctx context.Context, userID string) (Choice, error) {
+	var c Choice
+	var overrides string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT template_key, maintenance_calories, overrides, updated_at FROM user_nutrition_targets WHERE user_id = ?`,
+		userID).Scan(&c.TemplateKey, &c.MaintenanceCalories, &overrides, &c.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Choice{}, ErrNoChoice
+	}
+	if err != nil {
+		return Choice{}, err
+	}
+	if err := json.Unmarshal([]byte(overrides), &c.Overrides); err != nil {
+		return Choice{}, fmt.Errorf("corrupt user targets row: %w", err)
+	}
+	return c, nil
+}
+
+// SetChoice stores the user's choice and returns the targets it resolves to
+func (s *Store) SetChoice(ctx context.Context, userID string, c Choice) (Targets, error) {
+	t, err := s.Get(ctx, c.TemplateKey)
+	if err != nil {
+		return Targets{}, err
+	}
+	overrides, err := json.Marshal(c.Overrides)
+	if err != nil {
+		return Targets{}, err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO user_nutrition_targets (user_id, template_key, maintenance_calories, overrides, updated_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET template_key = excluded.template_key,
+		 	maintenance_calories = excluded.maintenance_calories,
+		 	overrides = excluded.overrides, updated_at = excluded.updated_at`,
+		userID, c.TemplateKey, c.MaintenanceCalories, string(overrides), time.Now().UTC())
+	if err != nil {
+		return Targets{}, err
+	}
+	return Apply(t, c.MaintenanceCalories, c.Overrides), nil
+}
+
+// ClearChoice removes the user's choice
+func (s *Store) ClearChoice(ctx context.Context, userID string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM user_nutrition_targets WHERE user_id = ?`, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNoChoice
+	}
+	return nil
+}
+
+// ForUser resolves the user's daily targets; ErrNotFound means their template
+// has since been deleted
+func (s *Store) ForUser(ctx context.Context, userID string) (Targets, error) {
+	c, err := s.Choice(ctx, userID)
+	if err != nil {
+		return Targets{}, err
+	}
+	t, err := s.Get(ctx, c.TemplateKey)
+	if err != nil {
+		return Targets{}, err
+	}
+	return Apply(t, c.MaintenanceCalories, c.Overrides), nil
+}
+
+// Bounds returns the user's targets as constraints, or none when they have
+// no usable choice; it implements insights.Targets
+func (s *Store) Bounds(ctx context.Context, userID string) ([]nutrition.Bound, error) {
+	t, err := s.ForUser(ctx, userID)
+	if errors.Is(err, ErrNoChoice) || errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t.Constraints(), nil
+}
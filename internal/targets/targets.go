@@ -0,0 +1,140 @@
+package targets
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"nutrition-health-backend/internal/nutrition"
+)
+
+// Limit bounds a nutrient; a zero Min or Max means unbounded on that side
+type Limit struct {
+	Min float64 `json:"min,omitempty"`
+	Max float64 `json:"max,omitempty"`
+}
+
+// Template is a named set of nutrition targets maintained by admins
+type Template struct {
+	Key           string `json:"key"`
+	Name          string `json:"name"`
+	NameAr        string `json:"name_ar"`
+	Description   string `json:"description,omitempty"`
+	DescriptionAr string `json:"description_ar,omitempty"`
+	// CalorieAdjustment is added to the user's maintenance calories
+	CalorieAdjustment float64 `json:"calorie_adjustment"`
+	// Macro split as a percentage of calories; must sum to 100
+	ProteinPct float64          `json:"protein_pct"`
+	CarbsPct   float64          `json:"carbs_pct"`
+	FatPct     float64          `json:"fat_pct"`
+	Limits     map[string]Limit `json:"limits,omitempty"`
+	Builtin    bool             `json:"builtin"`
+}
+
+// Validate checks a template before it is stored
+func (t Template) Validate() error {
+	if t.Key == "" || t.Name == "" {
+		return fmt.Errorf("template key and name are required")
+	}
+	if t.ProteinPct < 0 || t.CarbsPct < 0 || t.FatPct < 0 {
+		return fmt.Errorf("macro percentages must not be negative")
+	}
+	if sum := t.ProteinPct + t.CarbsPct + t.FatPct; math.Abs(sum-100) > 0.5 {
+		return fmt.Errorf("macro percentages must sum to 100, got %.1f", sum)
+	}
+	for nutrient, l := range t.Limits {
+		if l.Min < 0 || l.Max < 0 || (l.Max > 0 && l.Min > l.Max) {
+			return fmt.Errorf("invalid limit for %s", nutrient)
+		}
+	}
+	return nil
+}
+
+// Targets are the resolved daily targets for a user
+type Targets struct {
+	TemplateKey string           `json:"template_key,omitempty"`
+	Calories    float64          `json:"calories"`
+	ProteinG    float64          `json:"protein_g"`
+	CarbsG      float64          `json:"carbs_g"`
+	FatG        float64          `json:"fat_g"`
+	Limits      map[string]Limit `json:"limits,omitempty"`
+}
+
+// Overrides are user- or coach-specified values that win over the template
+type Overrides struct {
+	Calories *float64         `json:"calories,omitempty"`
+	ProteinG *float64         `json:"protein_g,omitempty"`
+	CarbsG   *float64         `json:"carbs_g,omitempty"`
+	FatG     *float64         `json:"fat_g,omitempty"`
+	Limits   map[string]Limit `json:"limits,omitempty"`
+}
+
+// Apply resolves a template against the user's maintenance calories, then applies overrides
+func Apply(t Template, maintenanceCalories float64, o Overrides) Targets {
+	calories := maintenanceCalories + t.CalorieAdjustment
+	if o.Calories != nil {
+		calories = *o.Calories
+	}
+
+	protein, carbs, fat := nutrition.MacroGrams(calories, t.ProteinPct, t.CarbsPct, t.FatPct)
+	targets := Targets{
+		TemplateKey: t.Key,
+		Calories:    math.Round(calories),
+		ProteinG:    math.Round(protein),
+		CarbsG:      math.Round(carbs),
+		FatG:        math.Round(fat),
+		Limits:      make(map[string]Limit, len(t.Limits)+len(o.Limits)),
+	}
+	if o.ProteinG != nil {
+		targets.ProteinG = *o.ProteinG
+	}
+	if o.CarbsG != nil {
+		targets.CarbsG = *o.CarbsG
+	}
+	if o.FatG != nil {
+		targets.FatG = *o.FatG
+	}
+
+	for nutrient, l := range t.Limits {
+		targets.Limits[nutrient] = l
+	}
+	for nutrient, l := range o.Limits {
+		targets.Limits[nutrient] = l
+	}
+	return targets
+}
+
+// CostNutrient is the limit key a daily budget is stored under; the planner
+// sums per-serving cost from food prices against it
+const CostNutrient = "cost"
+
+// Constraint is a single bound consumed by the planner's constraint engine
+type Constraint = nutrition.Bound
+
+// MacroTolerance is how far a day's plan may deviate from macro targets
+const MacroTolerance = 0.10
+
+// Constraints converts targets into planner constraints, sorted by nutrient
+func (t Targets) Constraints() []Constraint {
+	constraints := []Constraint{
+		band("calories", t.Calories),
+		band("protein_g", t.ProteinG),
+		band("carbs_g", t.CarbsG),
+		band("fat_g", t.FatG),
+	}
+	for nutrient, l := range t.Limits {
+		constraints = append(constraints, Constraint{Nutrient: nutrient, Min: l.Min, Max: l.Max})
+	}
+	sort.Slice(constraints, func(i, j int) bool {
+		return constraints[i].Nutrient < constraints[j].Nutrient
+	})
+	return constraints
+}
+
+func band(nutrient string, target float64) Constraint {
+	return Constraint{
+		Nutrient: nutrient,
+		Min:      math.Round(target * (1 - MacroTolerance)),
+		Max:      math.Round(target * (1 + MacroTolerance)),
+	}
+}
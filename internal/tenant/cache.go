@@ -0,0 +1,94 @@
+package tenant
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// CacheClient returns a client on the same server whose keys all go through
+// CacheKey, for caches that don't know about tenants. Keys already carrying
+// the tenant prefix, such as those a KEYS or SCAN MATCH returned, are left as
+// they are; a SCAN without MATCH still walks every tenant.
+func CacheClient(client *redis.Client) *redis.Client {
+	c := redis.NewClient(client.Options())
+	c.AddHook(cacheHook{})
+	return c
+}
+
+// keyless commands take no keys
+var keyless = map[string]bool{
+	"auth": true, "client": true, "command": true, "config": true, "dbsize": true,
+	"discard": true, "echo": true, "exec": true, "flushall": true, "flushdb": true,
+	"hello": true, "info": true, "multi": true, "ping": true, "publish": true,
+	"psubscribe": true, "punsubscribe": true, "quit": true, "select": true,
+	"subscribe": true, "time": true, "unsubscribe": true, "unwatch": true,
+}
+
+// multiKey commands take only keys
+var multiKey = map[string]bool{
+	"del": true, "exists": true, "mget": true, "pfcount": true, "sdiff": true,
+	"sinter": true, "sunion": true, "touch": true, "unlink": true, "watch": true,
+}
+
+type cacheHook struct{}
+
+func (cacheHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	prefixKeys(ctx, cmd.Args())
+	return ctx, nil
+}
+
+func (cacheHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error { return nil }
+
+func (cacheHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	for _, cmd := range cmds {
+		prefixKeys(ctx, cmd.Args())
+	}
+	return ctx, nil
+}
+
+func (cacheHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error { return nil }
+
+// prefixKeys rewrites the key arguments of a command in place
+func prefixKeys(ctx context.Context, args []interface{}) {
+	if len(args) < 2 {
+		return
+	}
+	name, _ := args[0].(string)
+	name = strings.ToLower(name)
+	prefix := CacheKey(ctx)
+	set := func(i int) {
+		if s, ok := args[i].(string); ok && !strings.HasPrefix(s, prefix) {
+			args[i] = prefix + s
+		}
+	}
+
+	switch {
+	case keyless[name]:
+	case multiKey[name]:
+		for i := 1; i < len(args); i++ {
+			set(i)
+		}
+	case name == "mset" || name == "msetnx":
+		for i := 1; i < len(args); i += 2 {
+			set(i)
+		}
+	case name == "eval" || name == "evalsha":
+		if len(args) < 3 {
+			return
+		}
+		n, _ := args[2].(int)
+		for i := 3; i < 3+n && i < len(args); i++ {
+			set(i)
+		}
+	case name == "scan":
+		for i := 2; i+1 < len(args); i++ {
+			if s, _ := args[i].(string); strings.EqualFold(s, "match") {
+				set(i + 1)
+			}
+		}
+	default:
+		set(1)
+	}
+}
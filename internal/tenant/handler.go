@@ -0,0 +1,66 @@
+package tenant
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterRoutes mounts the public GET /tenant on the API group
+func (r *Resolver) RegisterRoutes(api *echo.Group) {
+	api.GET("/tenant", func(c echo.Context) error {
+		t, ok := Current(c)
+		if !ok {
+			t, _ = r.Get(DefaultID)
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"id":             t.ID,
+			"branding":       t.Branding,
+			"locales":        t.Locales,
+			"default_locale": t.DefaultLocale,
+		})
+	})
+}
+
+// RegisterAdminRoutes mounts tenant management on an admin-protected group
+func (r *Resolver) RegisterAdminRoutes(g *echo.Group) {
+	g.GET("/tenants", func(c echo.Context) error {
+		list, err := r.store.List(c.Request().Context())
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"tenants": list})
+	})
+	g.PUT("/tenants/:id", func(c echo.Context) error {
+		var t Tenant
+		if err := c.Bind(&t); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+		t.ID = c.Param("id")
+		if err := t.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		ctx := c.Request().Context()
+		if err := r.store.Save(ctx, t); err != nil {
+			return err
+		}
+		if err := r.Refresh(ctx); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, t)
+	})
+	g.DELETE("/tenants/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		err := r.store.Delete(ctx, c.Param("id"))
+		if err == ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.Refresh(ctx); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+}
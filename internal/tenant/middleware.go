@@ -0,0 +1,46 @@
+package tenant
+
+import (
+	"net/http"
+	"strings"
+
+	"nutrition-health-backend/internal/problem"
+
+	"github.com/labstack/echo/v4"
+)
+
+// contextKey is the echo context key holding the request's Tenant
+const contextKey = "tenant"
+
+// exemptPrefixes serve infrastructure that is the same for every brand
+var exemptPrefixes = []string{"/health", "/metrics", "/version", "/admin"}
+
+// Current returns the tenant resolved for the request
+func Current(c echo.Context) (Tenant, bool) {
+	t, ok := c.Get(contextKey).(Tenant)
+	return t, ok
+}
+
+// Middleware resolves the tenant from the Host (or trusted header) and binds
+// it to both the echo context and the request context for repositories
+func (r *Resolver) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			for _, prefix := range exemptPrefixes {
+				if strings.HasPrefix(req.URL.Path, prefix) {
+					return next(c)
+				}
+			}
+
+			t, ok := r.Resolve(req.Host, req.Header.Get(r.cfg.Header))
+			if !ok || t.Disabled {
+				return problem.Write(c, problem.New(http.StatusNotFound, "unknown tenant"))
+			}
+			c.Set(contextKey, t)
+			c.SetRequest(req.WithContext(WithTenant(req.Context(), t)))
+			c.Response().Header().Set("X-Tenant", t.ID)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package tenant
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nutrition-health-backend/internal/problem"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimiter limits requests per client IP in fixed windows counted in Redis,
+// so every instance shares the budget. Keys come from CacheKey, so a client
+// calling two brands gets a budget in each, and tenants with an override get
+// their own limit. Mount it after Middleware so the tenant is known.
+func RateLimiter(client *redis.Client, def RateLimit) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			limit := def
+			if t, ok := Current(c); ok {
+				limit = t.Limit(def)
+			}
+			if limit.Requests <= 0 || limit.Window <= 0 {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			window := time.Now().UnixNano() / int64(limit.Window)
+			key := CacheKey(ctx, "ratelimit", c.RealIP(), strconv.FormatInt(window, 10))
+			pipe := client.TxPipeline()
+			count := pipe.Incr(ctx, key)
+			pipe.Expire(ctx, key, limit.Window)
+			if _, err := pipe.Exec(ctx); err != nil {
+				// Redis trouble must not take the API down with it
+				log.Printf("⚠️ Rate limiter unavailable: %v", err)
+				return next(c)
+			}
+
+			remaining := int64(limit.Requests) - count.Val()
+			if remaining < 0 {
+				remaining = 0
+			}
+			h := c.Response().Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+			h.Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+			if count.Val() > int64(limit.Requests) {
+				reset := time.Unix(0, (window+1)*int64(limit.Window))
+				h.Set(echo.HeaderRetryAfter, strconv.Itoa(int(time.Until(reset).Seconds())+1))
+				return problem.Write(c, problem.New(http.StatusTooManyRequests, "rate limit exceeded"))
+			}
+			return next(c)
+		}
+	}
+}
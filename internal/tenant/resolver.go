@@ -0,0 +1,148 @@
+package tenant
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+)
+
+// RefreshInterval is how often instances pick up tenant changes made elsewhere
+const RefreshInterval = 30 * time.Second
+
+// Config controls how requests are matched to tenants
+type Config struct {
+	// Header names the tenant explicitly; only honoured when TrustHeader is set,
+	// i.e. behind a gateway that strips it from client requests
+	Header      string
+	TrustHeader bool
+	// Strict rejects unknown hosts instead of serving the default tenant
+	Strict bool
+}
+
+// LoadConfig reads TENANT_HEADER, TENANT_TRUST_HEADER and TENANT_STRICT
+func LoadConfig() Config {
+	return Config{
+		Header:      envconfig.String("TENANT_HEADER", "X-Tenant-ID"),
+		TrustHeader: envconfig.Bool("TENANT_TRUST_HEADER", false),
+		Strict:      envconfig.Bool("TENANT_STRICT", false),
+	}
+}
+
+// Resolver matches requests to tenants from an in-memory snapshot of the store
+type Resolver struct {
+	store *Store
+	cfg   Config
+
+	mu        sync.RWMutex
+	byID      map[string]Tenant
+	byHost    map[string]Tenant
+	wildcards []wildcard
+}
+
+type wildcard struct {
+	suffix string // ".example.com"
+	tenant Tenant
+}
+
+// NewResolver creates a resolver and loads the initial snapshot
+func NewResolver(ctx context.Context, store *Store, cfg Config) *Resolver {
+	r := &Resolver{store: store, cfg: cfg}
+	r.index(nil)
+	if err := r.Refresh(ctx); err != nil {
+		log.Printf("⚠️ Tenants unavailable, serving default tenant only: %v", err)
+	}
+	return r
+}
+
+// Start refreshes the snapshot until ctx is cancelled
+func (r *Resolver) Start(ctx context.Context) {
+	ticker := time.NewTicker(RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil {
+				log.Printf("⚠️ Tenant refresh failed: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh reloads every tenant from the store
+func (r *Resolver) Refresh(ctx context.Context) error {
+	list, err := r.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	r.index(list)
+	return nil
+}
+
+func (r *Resolver) index(list []Tenant) {
+	byID := map[string]Tenant{DefaultID: {ID: DefaultID, DefaultLocale: "en", Locales: []string{"en", "ar"}}}
+	byHost := map[string]Tenant{}
+	var wildcards []wildcard
+	for _, t := range list {
+		byID[t.ID] = t
+		for _, h := range t.Hosts {
+			h = strings.ToLower(h)
+			if strings.HasPrefix(h, "*.") {
+				wildcards = append(wildcards, wildcard{suffix: h[1:], tenant: t})
+			} else {
+				byHost[h] = t
+			}
+		}
+	}
+	r.mu.Lock()
+	r.byID, r.byHost, r.wildcards = byID, byHost, wildcards
+	r.mu.Unlock()
+}
+
+// Get returns a tenant by ID
+func (r *Resolver) Get(id string) (Tenant, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.byID[id]
+	return t, ok
+}
+
+// Resolve finds the tenant for a host and optional header value. The bool is
+// false when nothing matched and strict mode forbids the default tenant.
+func (r *Resolver) Resolve(host, header string) (Tenant, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if header != "" && r.cfg.TrustHeader {
+		t, ok := r.byID[header]
+		return t, ok
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if t, ok := r.byHost[host]; ok {
+		return t, true
+	}
+	// Longest wildcard suffix wins, so *.eu.example.com beats *.example.com
+	best := -1
+	for i, w := range r.wildcards {
+		if strings.HasSuffix(host, w.suffix) && (best < 0 || len(w.suffix) > len(r.wildcards[best].suffix)) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		return r.wildcards[best].tenant, true
+	}
+	if r.cfg.Strict {
+		return Tenant{}, false
+	}
+	return r.byID[DefaultID], true
+}
@@ -0,0 +1,70 @@
+package tenant
+
+import "testing"
+
+func newTestResolver(cfg Config) *Resolver {
+	r := &Resolver{cfg: cfg}
+	r.index([]Tenant{
+		{ID: "acme", Hosts: []string{"nutrition.acme.com", "*.acme.com"}},
+		{ID: "acme-eu", Hosts: []string{"*.eu.acme.com"}},
+		{ID: "clinic", Hosts: []string{"App.Clinic.org"}},
+	})
+	return r
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		host   string
+		header string
+		want   string
+		wantOK bool
+	}{
+		{"exact host", Config{}, "nutrition.acme.com", "", "acme", true},
+		{"host with port", Config{}, "nutrition.acme.com:8443", "", "acme", true},
+		{"trailing dot", Config{}, "nutrition.acme.com.", "", "acme", true},
+		{"hosts are case insensitive", Config{}, "APP.clinic.ORG", "", "clinic", true},
+		{"wildcard", Config{}, "portal.acme.com", "", "acme", true},
+		{"longest wildcard wins", Config{}, "portal.eu.acme.com", "", "acme-eu", true},
+		{"wildcard needs a subdomain", Config{}, "acme.com", "", DefaultID, true},
+		{"suffix is not a subdomain", Config{}, "notacme.com", "", DefaultID, true},
+		{"unknown host gets the default", Config{}, "example.org", "", DefaultID, true},
+		{"strict rejects unknown hosts", Config{Strict: true}, "example.org", "", "", false},
+		{"strict still matches", Config{Strict: true}, "portal.acme.com", "", "acme", true},
+		{"untrusted header is ignored", Config{}, "nutrition.acme.com", "clinic", "acme", true},
+		{"trusted header wins", Config{TrustHeader: true}, "nutrition.acme.com", "clinic", "clinic", true},
+		{"trusted header must exist", Config{TrustHeader: true}, "nutrition.acme.com", "nope", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := newTestResolver(tt.cfg).Resolve(tt.host, tt.header)
+			if ok != tt.wantOK || got.ID != tt.want {
+				t.Errorf("Resolve(%q, %q) = %q, %v; want %q, %v", tt.host, tt.header, got.ID, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateHosts(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+	}{
+		{"app.example.com", false},
+		{"*.example.com", false},
+		{"", true},
+		{"app.example.com:443", true},
+		{"https://app.example.com", true},
+		{"app.*.example.com", true},
+		{"*example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := Tenant{ID: "acme", Hosts: []string{tt.host}}.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) = %v, want error %v", tt.host, err, tt.wantErr)
+			}
+		})
+	}
+}
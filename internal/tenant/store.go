@@ -0,0 +1,102 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"nutrition-health-backend/internal/dbschema"
+)
+
+// ErrNotFound is returned when a tenant does not exist
+var ErrNotFound = errors.New("tenant not found")
+
+// Store keeps tenants in the tenants table, config as JSON
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Owned are the tables whose rows belong to one tenant; existing rows fall
+// into the default tenant
+var Owned = []string{dbschema.Users, dbschema.DiaryEntries, dbschema.WeightLogs, dbschema.Recipes, dbschema.MealPlans}
+
+// Migrate creates the tenants table and adds tenant_id to the Owned tables
+func Migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS tenants (
+		id TEXT PRIMARY KEY,
+		config TEXT NOT NULL,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return err
+	}
+	for _, table := range Owned {
+		cols, err := dbschema.Columns(context.Background(), db, table)
+		if err != nil {
+			return err
+		}
+		if cols == nil || cols["tenant_id"] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '` + DefaultID + `'`); err != nil {
+			return err
+		}
+		if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_` + table + `_tenant ON ` + table + `(tenant_id)`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns every tenant
+func (s *Store) List(ctx context.Context) ([]Tenant, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT config FROM tenants ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenants []Tenant
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var t Tenant
+		if err := json.Unmarshal([]byte(data), &t); err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, t)
+	}
+	return tenants, rows.Err()
+}
+
+// Save inserts or replaces a tenant
+func (s *Store) Save(ctx context.Context, t Tenant) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO tenants (id, config, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		 ON CONFLICT(id) DO UPDATE SET config = excluded.config, updated_at = CURRENT_TIMESTAMP`,
+		t.ID, string(data))
+	return err
+}
+
+// Delete removes a tenant
+func (s *Store) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM tenants WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
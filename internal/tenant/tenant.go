@@ -0,0 +1,137 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultID is the tenant used for requests that match no brand
+const DefaultID = "default"
+
+// ErrNoTenant is returned by repositories asked to run without a tenant in the context
+var ErrNoTenant = errors.New("tenant: no tenant in context")
+
+// Branding is the white-label presentation of a tenant
+type Branding struct {
+	DisplayName   string `json:"display_name"`
+	DisplayNameAr string `json:"display_name_ar,omitempty"`
+	LogoURL       string `json:"logo_url,omitempty"`
+	PrimaryColor  string `json:"primary_color,omitempty"`
+	SupportEmail  string `json:"support_email,omitempty"`
+}
+
+// RateLimit overrides the global request limit for a tenant; zero values inherit it
+type RateLimit struct {
+	Requests int           `json:"requests,omitempty"`
+	Window   time.Duration `json:"window,omitempty"`
+}
+
+// Tenant is one clinic brand hosted on the instance
+type Tenant struct {
+	ID            string    `json:"id"`
+	Hosts         []string  `json:"hosts"` // exact hostnames or "*.example.com"
+	Branding      Branding  `json:"branding"`
+	Locales       []string  `json:"locales"`
+	DefaultLocale string    `json:"default_locale"`
+	RateLimit     RateLimit `json:"rate_limit"`
+	Disabled      bool      `json:"disabled,omitempty"`
+}
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Validate checks the tenant definition
+func (t Tenant) Validate() error {
+	if !idPattern.MatchString(t.ID) {
+		return fmt.Errorf("id must be lowercase letters, digits and dashes")
+	}
+	for _, h := range t.Hosts {
+		if h == "" || strings.ContainsAny(h, "/: ") {
+			return fmt.Errorf("invalid host %q", h)
+		}
+		if strings.Contains(h, "*") && !strings.HasPrefix(h, "*.") {
+			return fmt.Errorf("wildcard host %q must look like *.example.com", h)
+		}
+	}
+	if t.DefaultLocale != "" && len(t.Locales) > 0 && !contains(t.Locales, t.DefaultLocale) {
+		return fmt.Errorf("default_locale %q is not in locales", t.DefaultLocale)
+	}
+	if t.RateLimit.Requests < 0 || t.RateLimit.Window < 0 {
+		return fmt.Errorf("rate_limit values must not be negative")
+	}
+	return nil
+}
+
+// Limit returns the tenant's rate limit, falling back to def for unset fields
+func (t Tenant) Limit(def RateLimit) RateLimit {
+	l := t.RateLimit
+	if l.Requests == 0 {
+		l.Requests = def.Requests
+	}
+	if l.Window == 0 {
+		l.Window = def.Window
+	}
+	return l
+}
+
+type ctxKey struct{}
+
+// WithTenant binds t to ctx
+func WithTenant(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, ctxKey{}, t)
+}
+
+// FromContext returns the tenant bound to ctx
+func FromContext(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(ctxKey{}).(Tenant)
+	return t, ok
+}
+
+type unscopedKey struct{}
+
+// Unscoped marks ctx as a background job's, whose reads span every tenant;
+// rows are still selected by user, and writes take the user's tenant
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// Background wraps a job's Start so it runs Unscoped
+func Background(start func(context.Context)) func(context.Context) {
+	return func(ctx context.Context) { start(Unscoped(ctx)) }
+}
+
+// Filter returns the tenant_id repositories filter on: the tenant bound to
+// ctx, NULL for Unscoped contexts, or ErrNoTenant so a query can never cross
+// brands by omission
+func Filter(ctx context.Context) (sql.NullString, error) {
+	if t, ok := FromContext(ctx); ok && t.ID != "" {
+		return sql.NullString{String: t.ID, Valid: true}, nil
+	}
+	if unscoped, _ := ctx.Value(unscopedKey{}).(bool); unscoped {
+		return sql.NullString{}, nil
+	}
+	return sql.NullString{}, ErrNoTenant
+}
+
+// CacheKey prefixes a cache key with the tenant so brands never share cached
+// entries; requests without a tenant fall back to the default tenant's space
+func CacheKey(ctx context.Context, parts ...string) string {
+	id := DefaultID
+	if t, ok := FromContext(ctx); ok && t.ID != "" {
+		id = t.ID
+	}
+	return "t:" + id + ":" + strings.Join(parts, ":")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+// Package textmatch normalizes and fuzzy-compares food and brand names in
+// English and Arabic
+package textmatch
+
+import (
+	"strings"
+	"unicode"
+)
+
+var arabicFolds = strings.NewReplacer("أ", "ا", "إ", "ا", "آ", "ا", "ٱ", "ا", "ة", "ه", "ى", "ي", "ؤ", "و", "ئ", "ي", "ـ", "")
+
+// Normalize folds case, punctuation, Arabic diacritics and letter variants so
+// "McDonald's", "mcdonalds" and "ماكدونالدز" compare by their letters only
+func Normalize(s string) string {
+	s = arabicFolds.Replace(strings.ToLower(s))
+	s = strings.ReplaceAll(s, "&", " and ")
+	var b strings.Builder
+	space := true
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Mn, r), r == '\'', r == '’', r == '.':
+			// Drop diacritics and apostrophes without splitting the word
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+		default:
+			if !space {
+				b.WriteByte(' ')
+				space = true
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// trigrams returns the padded character trigrams of a normalized string
+func trigrams(s string) map[string]struct{} {
+	grams := map[string]struct{}{}
+	for _, word := range strings.Fields(s) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			grams[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+	return grams
+}
+
+// Similarity is the Jaccard similarity of the trigram sets of a and b, 0..1
+func Similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for g := range ta {
+		if _, ok := tb[g]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// Compact removes spaces so "burger king" and "burgerking" compare equal
+func Compact(s string) string {
+	return strings.ReplaceAll(s, " ", "")
+}
+
+// BestSimilarity is the higher of the spaced and compact similarity of two normalized names
+func BestSimilarity(a, b string) float64 {
+	score := Similarity(a, b)
+	if compact := Similarity(Compact(a), Compact(b)); compact > score {
+		return compact
+	}
+	return score
+}
@@ -0,0 +1,68 @@
+package txn
+
+import (
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware wraps each write request in a single transaction. The transaction
+// commits just before a 2xx/3xx response is written and rolls back on a 4xx/5xx
+// response, a returned error or a panic, so clients never see success for
+// work that was not persisted.
+func Middleware(m *Manager) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+
+			req := c.Request()
+			tx, err := m.db.BeginTx(req.Context(), nil)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "database unavailable")
+			}
+
+			var once sync.Once
+			var commitErr error
+			finish := func(commit bool) {
+				once.Do(func() {
+					if commit {
+						commitErr = tx.Commit()
+					} else {
+						tx.Rollback()
+					}
+				})
+			}
+
+			res := c.Response()
+			res.Before(func() {
+				finish(res.Status < http.StatusBadRequest)
+				if commitErr != nil {
+					log.Printf("❌ Transaction commit failed for %s %s: %v", req.Method, req.URL.Path, commitErr)
+					res.Status = http.StatusInternalServerError
+				}
+			})
+
+			defer func() {
+				if p := recover(); p != nil {
+					finish(false)
+					panic(p)
+				}
+			}()
+
+			c.SetRequest(req.WithContext(WithTx(req.Context(), tx)))
+			err = next(c)
+
+			// Handlers that return an error (or write nothing) never hit the Before hook
+			finish(err == nil)
+			if err == nil && commitErr != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "failed to save changes")
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package txn
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"nutrition-health-backend/internal/faults"
+)
+
+type ctxKey struct{}
+
+// Querier is satisfied by both *sql.DB and *sql.Tx, so repositories work inside or outside a unit of work
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Manager runs units of work against a database; inject it into services
+type Manager struct {
+	db *sql.DB
+}
+
+// NewManager creates a transaction manager
+func NewManager(db *sql.DB) *Manager {
+	return &Manager{db: db}
+}
+
+// FromContext returns the transaction bound to ctx, if any
+func FromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(ctxKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// WithTx binds a transaction to ctx
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, ctxKey{}, tx)
+}
+
+// Querier returns the transaction bound to ctx, or the database when there is
+// none. Statements are instrumented by the dbmetrics connector underneath.
+func (m *Manager) Querier(ctx context.Context) Querier {
+	if tx, ok := FromContext(ctx); ok {
+		return tx
+	}
+	return m.db
+}
+
+// Do runs fn in a transaction, committing on success and rolling back on error
+// or panic. If ctx already carries a transaction, fn joins it and the outer
+// unit of work decides the outcome.
+func (m *Manager) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := FromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	if err := faults.Inject(ctx, faults.DB); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(WithTx(ctx, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
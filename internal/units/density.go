@@ -0,0 +1,102 @@
+package units
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultDensity is used for foods without density data (water, g/ml)
+const DefaultDensity = 1.0
+
+// densities holds typical densities in g/ml for foods commonly measured by volume
+var densities = map[string]float64{
+	"water":           1.0,
+	"milk":            1.03,
+	"yogurt":          1.05,
+	"laban":           1.03,
+	"olive oil":       0.91,
+	"vegetable oil":   0.92,
+	"oil":             0.92,
+	"ghee":            0.91,
+	"butter":          0.96,
+	"honey":           1.42,
+	"date syrup":      1.35,
+	"tahini":          1.05,
+	"sugar":           0.85,
+	"brown sugar":     0.93,
+	"flour":           0.53,
+	"wheat flour":     0.53,
+	"oats":            0.41,
+	"rice":            0.85,
+	"cooked rice":     0.66,
+	"bulgur":          0.74,
+	"freekeh":         0.72,
+	"lentils":         0.81,
+	"chickpeas":       0.72,
+	"salt":            1.22,
+	"cocoa powder":    0.42,
+	"peanut butter":   1.08,
+	"almonds":         0.6,
+	"shredded cheese": 0.45,
+}
+
+// densityNames lists the known names as words, most specific first: more
+// words, then longer, then alphabetical, so matching is deterministic
+var densityNames = func() [][]string {
+	names := make([][]string, 0, len(densities))
+	for known := range densities {
+		names = append(names, words(known))
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := names[i], names[j]
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		if la, lb := len(strings.Join(a, " ")), len(strings.Join(b, " ")); la != lb {
+			return la > lb
+		}
+		return strings.Join(a, " ") < strings.Join(b, " ")
+	})
+	return names
+}()
+
+// DensityFor returns the density (g/ml) for a food name, falling back to
+// DefaultDensity. Known names match whole words, so "extra virgin olive oil"
+// uses olive oil while "boiled egg" does not match oil.
+func DensityFor(food string) float64 {
+	name := words(food)
+	if d, ok := densities[strings.Join(name, " ")]; ok {
+		return d
+	}
+	for _, known := range densityNames {
+		if containsWords(name, known) {
+			return densities[strings.Join(known, " ")]
+		}
+	}
+	return DefaultDensity
+}
+
+// words lowercases s and splits it on anything that is not a letter or digit
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords reports whether seq appears as consecutive words in name
+func containsWords(name, seq []string) bool {
+	for i := 0; i+len(seq) <= len(name); i++ {
+		match := true
+		for j, w := range seq {
+			if name[i+j] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package units
+
+import (
+	"log"
+	"net/http"
+
+	"nutrition-health-backend/internal/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Header lets anonymous clients ask for a unit system, "metric" or "imperial"
+const Header = "X-Unit-System"
+
+// Middleware binds the request's unit system to the request context: the
+// stored preference for authenticated users, otherwise a valid X-Unit-System
+// header, otherwise Metric. Mount it after the auth middleware.
+func (s *Store) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			system := Metric
+			if header, err := ParseSystem(req.Header.Get(Header)); err == nil {
+				system = header
+			}
+			if userID := reqctx.UserID(c); userID != "" {
+				stored, err := s.System(req.Context(), userID)
+				if err != nil {
+					log.Printf("⚠️ Failed to load unit system for user %s: %v", userID, err)
+				} else {
+					system = stored
+				}
+			}
+			c.SetRequest(req.WithContext(WithSystem(req.Context(), system)))
+			return next(c)
+		}
+	}
+}
+
+// RegisterRoutes mounts GET/PUT /profile/units on an authenticated group
+func (s *Store) RegisterRoutes(g *echo.Group) {
+	g.GET("/profile/units", func(c echo.Context) error {
+		userID := reqctx.UserID(c)
+		if userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+		system, err := s.System(c.Request().Context(), userID)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"system": system})
+	})
+	g.PUT("/profile/units", func(c echo.Context) error {
+		userID := reqctx.UserID(c)
+		if userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+		var req struct {
+			System string `json:"system"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+		system, err := s.Set(c.Request().Context(), userID, req.System)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"system": system})
+	})
+}
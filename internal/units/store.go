@@ -0,0 +1,75 @@
+package units
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Store persists each user's preferred unit system
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a unit preference store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate creates the user_unit_systems table
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS user_unit_systems (
+		user_id TEXT PRIMARY KEY,
+		system TEXT NOT NULL,
+		updated_at DATETIME NOT NULL
+	)`)
+	return err
+}
+
+// System returns the user's preference, or Metric when unset
+func (s *Store) System(ctx context.Context, userID string) (System, error) {
+	var stored string
+	err := s.db.QueryRowContext(ctx, `SELECT system FROM user_unit_systems WHERE user_id = ?`, userID).Scan(&stored)
+	if err == sql.ErrNoRows {
+		return Metric, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	system, err := ParseSystem(stored)
+	if err != nil {
+		return Metric, nil
+	}
+	return system, nil
+}
+
+// Set validates and stores the user's preference
+func (s *Store) Set(ctx context.Context, userID, value string) (System, error) {
+	system, err := ParseSystem(value)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO user_unit_systems (user_id, system, updated_at) VALUES (?, ?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET system = excluded.system, updated_at = excluded.updated_at`,
+		userID, string(system), time.Now().UTC())
+	if err != nil {
+		return "", err
+	}
+	return system, nil
+}
+
+type ctxKey struct{}
+
+// WithSystem binds the user's unit system to ctx
+func WithSystem(ctx context.Context, system System) context.Context {
+	return context.WithValue(ctx, ctxKey{}, system)
+}
+
+// FromContext returns the unit system bound to ctx, or Metric
+func FromContext(ctx context.Context) System {
+	if system, ok := ctx.Value(ctxKey{}).(System); ok {
+		return system
+	}
+	return Metric
+}
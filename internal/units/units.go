@@ -0,0 +1,213 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// System is a user's preferred measurement system
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+// ParseSystem parses a unit system preference, defaulting to metric
+func ParseSystem(s string) (System, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "metric":
+		return Metric, nil
+	case "imperial", "us":
+		return Imperial, nil
+	default:
+		return "", fmt.Errorf("unknown unit system %q", s)
+	}
+}
+
+// Dimension distinguishes mass units from volume units
+type Dimension int
+
+const (
+	Mass Dimension = iota
+	Volume
+)
+
+// Unit is a supported measurement unit
+type Unit string
+
+const (
+	Gram       Unit = "g"
+	Kilogram   Unit = "kg"
+	Milligram  Unit = "mg"
+	Ounce      Unit = "oz"
+	Pound      Unit = "lb"
+	Milliliter Unit = "ml"
+	Liter      Unit = "l"
+	Cup        Unit = "cup"
+	Tablespoon Unit = "tbsp"
+	Teaspoon   Unit = "tsp"
+	FluidOunce Unit = "fl_oz"
+)
+
+type unitInfo struct {
+	dimension Dimension
+	// base is grams for mass units and milliliters for volume units
+	base float64
+}
+
+var unitTable = map[Unit]unitInfo{
+	Gram:       {Mass, 1},
+	Kilogram:   {Mass, 1000},
+	Milligram:  {Mass, 0.001},
+	Ounce:      {Mass, 28.349523125},
+	Pound:      {Mass, 453.59237},
+	Milliliter: {Volume, 1},
+	Liter:      {Volume, 1000},
+	Cup:        {Volume, 240},
+	Tablespoon: {Volume, 15},
+	Teaspoon:   {Volume, 5},
+	FluidOunce: {Volume, 29.5735295625},
+}
+
+var unitAliases = map[string]Unit{
+	"g": Gram, "gram": Gram, "grams": Gram, "جم": Gram, "غرام": Gram,
+	"kg": Kilogram, "kilogram": Kilogram, "kilograms": Kilogram, "كجم": Kilogram,
+	"mg": Milligram, "milligram": Milligram, "milligrams": Milligram,
+	"oz": Ounce, "ounce": Ounce, "ounces": Ounce,
+	"lb": Pound, "lbs": Pound, "pound": Pound, "pounds": Pound,
+	"ml": Milliliter, "milliliter": Milliliter, "milliliters": Milliliter, "مل": Milliliter,
+	"l": Liter, "liter": Liter, "liters": Liter, "litre": Liter, "لتر": Liter,
+	"cup": Cup, "cups": Cup, "كوب": Cup,
+	"tbsp": Tablespoon, "tablespoon": Tablespoon, "tablespoons": Tablespoon, "ملعقة كبيرة": Tablespoon,
+	"tsp": Teaspoon, "teaspoon": Teaspoon, "teaspoons": Teaspoon, "ملعقة صغيرة": Teaspoon,
+	"fl_oz": FluidOunce, "fl oz": FluidOunce, "floz": FluidOunce,
+}
+
+// ParseUnit resolves a unit name or common alias (English or Arabic)
+func ParseUnit(s string) (Unit, error) {
+	if u, ok := unitAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return u, nil
+	}
+	return "", fmt.Errorf("unknown unit %q", s)
+}
+
+// Dimension returns whether the unit measures mass or volume
+func (u Unit) Dimension() Dimension {
+	return unitTable[u].dimension
+}
+
+// Valid reports whether u is a supported unit
+func (u Unit) Valid() bool {
+	_, ok := unitTable[u]
+	return ok
+}
+
+// Quantity is an amount in a given unit
+type Quantity struct {
+	Amount float64 `json:"amount"`
+	Unit   Unit    `json:"unit"`
+}
+
+// String formats the quantity for display
+func (q Quantity) String() string {
+	return fmt.Sprintf("%s %s", trimFloat(q.Amount), q.Unit)
+}
+
+// Convert converts q into the target unit. Density (g/ml) is only used when
+// converting between mass and volume; pass DensityFor(food) for the food being measured.
+func Convert(q Quantity, to Unit, density float64) (Quantity, error) {
+	from, ok := unitTable[q.Unit]
+	if !ok {
+		return Quantity{}, fmt.Errorf("unknown unit %q", q.Unit)
+	}
+	target, ok := unitTable[to]
+	if !ok {
+		return Quantity{}, fmt.Errorf("unknown unit %q", to)
+	}
+
+	base := q.Amount * from.base
+	if from.dimension != target.dimension {
+		if density <= 0 {
+			return Quantity{}, fmt.Errorf("density required to convert %s to %s", q.Unit, to)
+		}
+		if from.dimension == Volume {
+			base *= density // ml -> g
+		} else {
+			base /= density // g -> ml
+		}
+	}
+	return Quantity{Amount: base / target.base, Unit: to}, nil
+}
+
+// ToGrams converts q to grams, the unit nutrient data is stored in
+func ToGrams(q Quantity, density float64) (float64, error) {
+	g, err := Convert(q, Gram, density)
+	if err != nil {
+		return 0, err
+	}
+	return g.Amount, nil
+}
+
+// Display converts a gram amount into the most readable unit for the user's system
+func Display(grams float64, system System) Quantity {
+	if system == Imperial {
+		if grams >= unitTable[Pound].base {
+			return Quantity{Amount: round(grams/unitTable[Pound].base, 2), Unit: Pound}
+		}
+		return Quantity{Amount: round(grams/unitTable[Ounce].base, 1), Unit: Ounce}
+	}
+	if grams >= 1000 {
+		return Quantity{Amount: round(grams/1000, 2), Unit: Kilogram}
+	}
+	return Quantity{Amount: round(grams, 0), Unit: Gram}
+}
+
+// DisplayQuantity renders q in the user's system, keeping volumes as volumes
+// so a recipe's "2 tbsp" stays a household measure; unknown units are returned as is
+func DisplayQuantity(q Quantity, system System) Quantity {
+	info, ok := unitTable[q.Unit]
+	if !ok {
+		return q
+	}
+	base := q.Amount * info.base
+	if info.dimension == Volume {
+		return DisplayVolume(base, system)
+	}
+	return Display(base, system)
+}
+
+// DisplayVolume converts a milliliter amount into a household measure for the user's system
+func DisplayVolume(ml float64, system System) Quantity {
+	if system == Imperial {
+		switch {
+		case ml >= unitTable[Cup].base/4:
+			return Quantity{Amount: roundToFraction(ml/unitTable[Cup].base, 4), Unit: Cup}
+		case ml >= unitTable[Tablespoon].base:
+			return Quantity{Amount: roundToFraction(ml/unitTable[Tablespoon].base, 2), Unit: Tablespoon}
+		default:
+			return Quantity{Amount: roundToFraction(ml/unitTable[Teaspoon].base, 4), Unit: Teaspoon}
+		}
+	}
+	if ml >= 1000 {
+		return Quantity{Amount: round(ml/1000, 2), Unit: Liter}
+	}
+	return Quantity{Amount: round(ml, 0), Unit: Milliliter}
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+// roundToFraction rounds to the nearest 1/n, matching how household measures are written
+func roundToFraction(v float64, n int) float64 {
+	return math.Round(v*float64(n)) / float64(n)
+}
+
+func trimFloat(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,181 @@
+package usda
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"nutrition-health-backend/internal/repo"
+	"nutrition-health-backend/internal/txn"
+)
+
+// Migrate creates the usda_foods table linking FDC IDs to foods
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS usda_foods (
+		fdc_id INTEGER PRIMARY KEY,
+		food_id INTEGER NOT NULL,
+		data_type TEXT NOT NULL,
+		published TEXT NOT NULL DEFAULT '',
+		synced_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("usda migration: %w", err)
+	}
+	return nil
+}
+
+// Lister pages through FDC foods; implemented by Client
+type Lister interface {
+	List(ctx context.Context, dataTypes []string, page, size int) ([]Food, error)
+}
+
+// Options controls a sync run
+type Options struct {
+	DataTypes []string
+	PageSize  int
+	// MaxPages stops after that many pages; zero syncs everything
+	MaxPages int
+}
+
+// Result summarises a sync run
+type Result struct {
+	Pages    int `json:"pages"`
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Skipped  int `json:"skipped"`
+}
+
+// Syncer writes FDC foods into the foods table
+type Syncer struct {
+	db  *sql.DB
+	txm *txn.Manager
+	api Lister
+}
+
+// NewSyncer creates a syncer reading from api
+func NewSyncer(db *sql.DB, api Lister) *Syncer {
+	return &Syncer{db: db, txm: txn.NewManager(db), api: api}
+}
+
+// Sync pages through FDC and upserts every food, one transaction per page,
+// so an interrupted run keeps the pages already written. Foods without a
+// name or energy value are skipped.
+func (s *Syncer) Sync(ctx context.Context, opts Options) (Result, error) {
+	var result Result
+	if len(opts.DataTypes) == 0 {
+		opts.DataTypes = DefaultDataTypes
+	}
+	if opts.PageSize <= 0 || opts.PageSize > 200 {
+		opts.PageSize = 200
+	}
+	for page := 1; opts.MaxPages == 0 || page <= opts.MaxPages; page++ {
+		foods, err := s.api.List(ctx, opts.DataTypes, page, opts.PageSize)
+		if err != nil {
+			return result, err
+		}
+		if len(foods) == 0 {
+			break
+		}
+		err = s.txm.Do(ctx, func(ctx context.Context) error {
+			for _, f := range foods {
+				inserted, err := s.upsert(ctx, f)
+				switch {
+				case errors.Is(err, errSkip):
+					result.Skipped++
+				case err != nil:
+					return fmt.Errorf("FDC food %d: %w", f.FDCID, err)
+				case inserted:
+					result.Inserted++
+				default:
+					result.Updated++
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Pages++
+		if page%10 == 0 {
+			log.Printf("🥦 USDA sync: %d pages, %d new, %d updated", result.Pages, result.Inserted, result.Updated)
+		}
+		if len(foods) < opts.PageSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+var errSkip = errors.New("skipped")
+
+// upsert updates the food linked to f, or creates and links one. A linked
+// food merged into another is relinked to it; one deleted is recreated.
+func (s *Syncer) upsert(ctx context.Context, f Food) (bool, error) {
+	if f.Name == "" || f.Nutrients.Calories <= 0 {
+		return false, errSkip
+	}
+	tx, _ := txn.FromContext(ctx)
+	q := repo.FromContext(ctx, s.db)
+	now := time.Now().UTC()
+
+	var foodID int64
+	err := tx.QueryRowContext(ctx, `SELECT food_id FROM usda_foods WHERE fdc_id = ?`, f.FDCID).Scan(&foodID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	if err == nil {
+		n, err := q.UpdateFoodNutrients(ctx, repo.UpdateFoodNutrientsParams{
+			Calories: f.Nutrients.Calories,
+			Protein:  f.Nutrients.ProteinG,
+			Carbs:    f.Nutrients.CarbsG,
+			Fat:      f.Nutrients.FatG,
+			Fiber:    f.Nutrients.FiberG,
+			Sugar:    f.Nutrients.SugarG,
+			Sodium:   f.Nutrients.SodiumMg,
+			ID:       foodID,
+		})
+		if err != nil {
+			return false, err
+		}
+		if n > 0 {
+			_, err = tx.ExecContext(ctx, `UPDATE usda_foods SET data_type = ?, published = ?, synced_at = ? WHERE fdc_id = ?`,
+				f.DataType, f.Published, now, f.FDCID)
+			return false, err
+		}
+		// An admin merged the food into another; link to that one and keep its values
+		var canonical int64
+		err = tx.QueryRowContext(ctx, `SELECT CAST(canonical_id AS INTEGER) FROM food_merges WHERE duplicate_id = ?`,
+			fmt.Sprint(foodID)).Scan(&canonical)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, `UPDATE usda_foods SET food_id = ?, data_type = ?, published = ?, synced_at = ? WHERE fdc_id = ?`,
+				canonical, f.DataType, f.Published, now, f.FDCID)
+			return false, err
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return false, err
+		}
+	}
+
+	food, err := q.CreateFood(ctx, repo.CreateFoodParams{
+		Name:     f.Name,
+		Calories: f.Nutrients.Calories,
+		Protein:  f.Nutrients.ProteinG,
+		Carbs:    f.Nutrients.CarbsG,
+		Fat:      f.Nutrients.FatG,
+		Fiber:    f.Nutrients.FiberG,
+		Sugar:    f.Nutrients.SugarG,
+		Sodium:   f.Nutrients.SodiumMg,
+	})
+	if err != nil {
+		return false, err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO usda_foods (fdc_id, food_id, data_type, published, synced_at) VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(fdc_id) DO UPDATE SET food_id = excluded.food_id, data_type = excluded.data_type,
+		 	published = excluded.published, synced_at = excluded.synced_at`,
+		f.FDCID, food.ID, f.DataType, f.Published, now)
+	return true, err
+}
@@ -0,0 +1,127 @@
+// Package usda syncs generic foods from USDA FoodData Central into the foods
+// table. Each FDC food is linked to the row it created, so later syncs update
+// nutrients in place instead of adding duplicates.
+package usda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/nutrition"
+)
+
+// DefaultDataTypes are the FDC datasets of generic, unbranded foods
+var DefaultDataTypes = []string{"Foundation", "SR Legacy"}
+
+// Client calls the FoodData Central API
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// NewClient reads USDA_API_KEY and USDA_API_URL; the default transport
+// carries the external API circuit breakers
+func NewClient() *Client {
+	return &Client{
+		baseURL: strings.TrimRight(envconfig.String("USDA_API_URL", "https://api.nal.usda.gov/fdc/v1"), "/"),
+		apiKey:  envconfig.String("USDA_API_KEY", "DEMO_KEY"),
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Food is one FDC food with its nutrients per 100 g
+type Food struct {
+	FDCID     int64
+	Name      string
+	DataType  string
+	Published string
+	Nutrients nutrition.Nutrients
+}
+
+// listItem is an abridged food from /foods/list
+type listItem struct {
+	FDCID           int64  `json:"fdcId"`
+	Description     string `json:"description"`
+	DataType        string `json:"dataType"`
+	PublicationDate string `json:"publicationDate"`
+	FoodNutrients   []struct {
+		Number   string  `json:"number"`
+		Amount   float64 `json:"amount"`
+		UnitName string  `json:"unitName"`
+	} `json:"foodNutrients"`
+}
+
+// List returns one page of foods of the given data types, ordered by FDC ID;
+// an empty page is the end of the list
+func (c *Client) List(ctx context.Context, dataTypes []string, page, size int) ([]Food, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"dataType":   dataTypes,
+		"pageNumber": page,
+		"pageSize":   size,
+		"sortBy":     "fdcId",
+		"sortOrder":  "asc",
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/foods/list?api_key="+c.apiKey, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("FoodData Central request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("FoodData Central returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	var items []listItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to decode FoodData Central page %d: %w", page, err)
+	}
+	foods := make([]Food, 0, len(items))
+	for _, item := range items {
+		foods = append(foods, item.food())
+	}
+	return foods, nil
+}
+
+// food maps FDC nutrient numbers onto Nutrients
+func (item listItem) food() Food {
+	f := Food{FDCID: item.FDCID, Name: strings.TrimSpace(item.Description), DataType: item.DataType, Published: item.PublicationDate}
+	var kj float64
+	for _, n := range item.FoodNutrients {
+		switch n.Number {
+		case "208":
+			f.Nutrients.Calories = n.Amount
+		case "268":
+			kj = n.Amount
+		case "203":
+			f.Nutrients.ProteinG = n.Amount
+		case "205":
+			f.Nutrients.CarbsG = n.Amount
+		case "204":
+			f.Nutrients.FatG = n.Amount
+		case "291":
+			f.Nutrients.FiberG = n.Amount
+		case "269":
+			f.Nutrients.SugarG = n.Amount
+		case "307":
+			f.Nutrients.SodiumMg = n.Amount
+		}
+	}
+	// Foundation foods sometimes only report energy in kJ
+	if f.Nutrients.Calories == 0 && kj > 0 {
+		f.Nutrients.Calories = kj / 4.184
+	}
+	f.Nutrients = f.Nutrients.Round()
+	return f
+}
@@ -0,0 +1,12 @@
+package version
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler serves GET /version
+func Handler(c echo.Context) error {
+	return c.JSON(http.StatusOK, Get())
+}
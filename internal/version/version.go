@@ -0,0 +1,76 @@
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X nutrition-health-backend/internal/version.Version=1.4.0 \
+//	  -X nutrition-health-backend/internal/version.Commit=$(git rev-parse HEAD) \
+//	  -X nutrition-health-backend/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+// Get returns the build info, falling back to the VCS stamp Go embeds
+// when the ldflags were not set (plain `go build` in a checkout)
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	return info
+}
+
+// ShortCommit is the first 12 characters of the commit
+func (i Info) ShortCommit() string {
+	if len(i.Commit) > 12 {
+		return i.Commit[:12]
+	}
+	return i.Commit
+}
+
+// Release is the identifier sent to error reporting, e.g. "nutrition-backend@1.4.0+3f2a9c1b7d4e"
+func (i Info) Release() string {
+	r := "nutrition-backend@" + i.Version
+	if i.Commit != "unknown" {
+		r += "+" + i.ShortCommit()
+	}
+	return r
+}
@@ -2,56 +2,154 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"nutrition-health-backend/internal/admin"
+	"nutrition-health-backend/internal/adminui"
+	"nutrition-health-backend/internal/analytics"
+	"nutrition-health-backend/internal/auth"
+	"nutrition-health-backend/internal/backup"
+	"nutrition-health-backend/internal/batchcook"
+	"nutrition-health-backend/internal/branded"
+	"nutrition-health-backend/internal/calendar"
 	"nutrition-health-backend/internal/config"
+	"nutrition-health-backend/internal/configcheck"
+	"nutrition-health-backend/internal/consent"
+	"nutrition-health-backend/internal/cors"
 	"nutrition-health-backend/internal/database"
+	"nutrition-health-backend/internal/dbmetrics"
+	"nutrition-health-backend/internal/deltasync"
+	"nutrition-health-backend/internal/diagnostics"
+	"nutrition-health-backend/internal/diary"
+	"nutrition-health-backend/internal/diarybatch"
+	"nutrition-health-backend/internal/diaryimport"
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/errreport"
+	"nutrition-health-backend/internal/extapi"
+	"nutrition-health-backend/internal/fakedata"
+	"nutrition-health-backend/internal/faults"
+	"nutrition-health-backend/internal/featureflags"
+	"nutrition-health-backend/internal/fieldcrypt"
+	"nutrition-health-backend/internal/fieldsets"
+	"nutrition-health-backend/internal/foodadmin"
 	"nutrition-health-backend/internal/handlers"
+	"nutrition-health-backend/internal/health"
+	"nutrition-health-backend/internal/healthrecords"
+	"nutrition-health-backend/internal/insights"
+	"nutrition-health-backend/internal/limits"
+	"nutrition-health-backend/internal/localtime"
+	"nutrition-health-backend/internal/logging"
+	"nutrition-health-backend/internal/maintenance"
 	"nutrition-health-backend/internal/middleware"
+	"nutrition-health-backend/internal/outbox"
+	"nutrition-health-backend/internal/portions"
+	"nutrition-health-backend/internal/pricing"
+	"nutrition-health-backend/internal/printexport"
+	"nutrition-health-backend/internal/realtime"
 	"nutrition-health-backend/internal/redis"
+	"nutrition-health-backend/internal/reminders"
+	"nutrition-health-backend/internal/retention"
+	"nutrition-health-backend/internal/runtimecfg"
+	"nutrition-health-backend/internal/searchgaps"
+	"nutrition-health-backend/internal/secrets"
+	"nutrition-health-backend/internal/seeds"
+	"nutrition-health-backend/internal/server"
 	"nutrition-health-backend/internal/services"
+	"nutrition-health-backend/internal/signing"
+	"nutrition-health-backend/internal/sqlitetune"
+	"nutrition-health-backend/internal/startup"
+	"nutrition-health-backend/internal/stats"
+	"nutrition-health-backend/internal/symptoms"
+	"nutrition-health-backend/internal/targets"
+	"nutrition-health-backend/internal/tenant"
+	"nutrition-health-backend/internal/units"
+	"nutrition-health-backend/internal/usda"
+	"nutrition-health-backend/internal/version"
 
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
-	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"golang.org/x/net/http2"
+)
+
+// Secrets backend, loaded once at startup and refreshed by serve
+var (
+	secretsCfg      secrets.Config
+	secretsProvider secrets.Provider
 )
 
 func main() {
 	// Load environment variables
+	processEnv = envKeys()
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️ No .env file found, using system environment")
 	}
 
-	// Check for command-line flags
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "-migrate", "--migrate":
-			runMigrations()
-			return
-		case "-seed", "--seed":
-			runSeeding()
-			return
-		case "-reset", "--reset":
-			runReset()
-			return
-		}
+	os.Exit(runCLI(os.Args[1:]))
+}
+
+// loadSecrets overrides the environment with the secrets backend's values.
+// The CLI calls it once a command that needs them has parsed its flags,
+// before any config is loaded.
+func loadSecrets() error {
+	secretsCfg = secrets.LoadConfig()
+	provider, err := secrets.NewProvider(secretsCfg)
+	if err != nil {
+		return fmt.Errorf("secrets backend init failed: %w", err)
+	}
+	if provider == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	n, err := secrets.LoadIntoEnv(ctx, provider, secretsCfg.Keys)
+	if err != nil {
+		return fmt.Errorf("loading secrets failed: %w", err)
 	}
+	secretsProvider = provider
+	log.Printf("🔐 Loaded %d secrets from %s", n, provider.Name())
+	return nil
+}
+
+// serveOptions selects what a server process runs: the HTTP API, the
+// singleton background jobs (backups, stats aggregation), or both
+type serveOptions struct {
+	HTTP bool
+	Jobs bool
+}
 
+// serve runs the API and/or background jobs until SIGINT/SIGTERM
+func serve(opts serveOptions) {
 	// Load configuration
 	cfg := config.Load()
-	log.Printf("🚀 Starting Nutrition Health Backend v%s", cfg.API.Version)
+	logFormat := logging.FormatFromEnv(cfg.Server.Environment)
+	if logFormat == logging.JSON {
+		logging.UseJSON(logging.Stdout)
+	}
+	build := version.Get()
+	log.Printf("🚀 Starting Nutrition Health Backend %s (commit %s, built %s, %s)",
+		build.Version, build.ShortCommit(), build.BuildTime, build.GoVersion)
+	log.Printf("📖 API version: %s", cfg.API.Version)
 	log.Printf("🌍 Environment: %s", cfg.Server.Environment)
 
-	// Initialize database
-	db, err := database.Initialize(cfg.Database.Path)
-	if err != nil {
-		log.Fatalf("❌ Database init failed: %v", err)
+	// Fail fast on misconfiguration instead of at first use
+	if errs := configcheck.Validate(cfg); len(errs) > 0 {
+		for _, err := range errs {
+			log.Printf("❌ Config: %v", err)
+		}
+		log.Fatalf("❌ %d configuration problem(s), refusing to start", len(errs))
 	}
+
+	// Initialize database
+	db := openDatabase(cfg.Database.Path)
 	defer db.Close()
 	log.Println("✅ Database connected")
 
@@ -63,46 +161,412 @@ func main() {
 		log.Println("⚠️ Redis unavailable (degraded caching)")
 	}
 
-	// Initialize services with DI
-	services := services.NewServices(db, redisClient, cfg)
+	// Background workers stop before the server drains on shutdown
+	lifecycle := server.NewLifecycle()
+	bgCtx := lifecycle.Context()
+
+	if secretsProvider != nil && secretsCfg.RefreshInterval > 0 {
+		lifecycle.Go("secrets", func(ctx context.Context) {
+			secrets.Refresh(ctx, secretsProvider, secretsCfg.Keys, secretsCfg.RefreshInterval)
+		})
+	}
+
+	// Continuous backup to the configured replica
+	var replicator *backup.Replicator
+	if backupCfg := backup.LoadConfig(); backupCfg.Enabled && opts.Jobs {
+		replica, err := backup.NewReplica(backupCfg)
+		if err != nil {
+			log.Fatalf("❌ Backup init failed: %v", err)
+		}
+		replicator = backup.NewReplicator(db, replica, backupCfg)
+		lifecycle.Go("backup", replicator.Start)
+		log.Printf("✅ Continuous backup enabled (every %s to %s)", backupCfg.Interval, backupCfg.Destination)
+	}
+
+	// Settings reloadable on SIGHUP or POST /admin/config/reload
+	runtimeSettings, err := runtimecfg.NewManager(func() (runtimecfg.Settings, error) {
+		return loadRuntimeSettings(secretsProvider, secretsCfg.Keys)
+	})
+	if err != nil {
+		log.Fatalf("❌ Runtime settings init failed: %v", err)
+	}
+	runtimeSettings.OnChange(applyLogLevel)
+	applyLogLevel(runtimeSettings.Current())
+	lifecycle.Go("sighup", func(ctx context.Context) {
+		reloadOnSIGHUP(ctx, runtimeSettings)
+	})
+
+	// Feature flags, shared through Redis when available
+	var flagStore featureflags.Store = featureflags.NewDBStore(db)
+	if redisClient != nil {
+		flagStore = featureflags.NewRedisStore(redisClient)
+	}
+	flags := featureflags.NewService(bgCtx, flagStore)
+	lifecycle.Go("featureflags", flags.Start)
+	runtimeSettings.OnChange(func(runtimecfg.Settings) {
+		if err := flags.Refresh(bgCtx); err != nil {
+			log.Printf("⚠️ Feature flag refresh failed: %v", err)
+		}
+	})
+
+	// Admin dashboard statistics, aggregated hourly into admin_daily_stats
+	statsStore := stats.NewStore(db)
+	statsCounters := stats.NewCounters(redisClient)
+	searchGaps := searchgaps.NewStore(db)
+	statsJob := stats.NewJob(statsStore,
+		stats.NewActivityCollector(db),
+		stats.NewPlanCollector(db),
+		statsCounters,
+		stats.NewCacheCollector(redisClient),
+		searchgaps.NewCollector(searchGaps),
+	)
+	if opts.Jobs {
+		// Jobs reading tenant-owned tables run unscoped, across every brand
+		lifecycle.Go("stats", tenant.Background(statsJob.Start))
+	}
+
+	// Food merge/dedupe tooling over the foods table
+	foodAdmin := foodadmin.NewService(db, foodadmin.DefaultSchema())
+	dedupeJob := foodadmin.NewJob(foodAdmin)
+	if opts.Jobs {
+		lifecycle.Go("food-dedupe", dedupeJob.Start)
+	}
+
+	// Retention pruning of log, session, notification and soft-deleted rows;
+	// RETENTION_* was checked by configcheck at startup
+	retentionPolicies, _ := retention.LoadPolicies()
+	retentionJob := retention.NewJob(db, retentionPolicies, retention.LoadConfig())
+	if opts.Jobs {
+		lifecycle.Go("retention", retentionJob.Start)
+	}
+
+	// Reminder rules, checked against the diary and weight log
+	zones := localtime.NewStore(db)
+	targetStore := targets.NewStore(db)
+	diaryStore := diary.NewStore(db, zones, targetStore)
+	reminderStore := reminders.NewStore(db, zones)
+	if opts.Jobs {
+		lifecycle.Go("reminders", tenant.Background(reminders.NewJob(reminderStore, diaryStore).Start))
+	}
+
+	// Weekly insights from the diary, checked against each user's chosen targets
+	insightsService := insights.NewService(insights.NewStore(db), diaryStore, targetStore, zones)
+	if opts.Jobs {
+		lifecycle.Go("insights", tenant.Background(insightsService.Start))
+	}
+
+	// Envelope encryption for sensitive fields, keys from the secrets backend
+	keyRing, err := fieldcrypt.LoadKeyRing()
+	if err != nil {
+		log.Fatalf("❌ Invalid FIELD_ENCRYPTION_KEYS: %v", err)
+	}
+	fieldCipher := fieldcrypt.New(db, keyRing)
+	if fieldCipher.Enabled() {
+		log.Printf("🔐 Field encryption enabled (primary key %s)", keyRing.Primary())
+	}
+	if opts.Jobs {
+		// Deleted accounts' data keys are dropped, leaving their fields unreadable
+		lifecycle.Go("data-keys", func(ctx context.Context) {
+			fieldCipher.Start(ctx, envconfig.Duration("FIELD_ENCRYPTION_SHRED_INTERVAL", 10*time.Minute))
+		})
+	}
+
+	// Symptom/food correlations, recomputed nightly from the diary
+	symptomStore := symptoms.NewStore(db, fieldCipher)
+	if opts.Jobs {
+		lifecycle.Go("symptoms", tenant.Background(symptoms.NewJob(symptomStore, diaryStore).Start))
+	}
+
+	// Diary history imports, matched against the foods table and committed
+	// through the diary store
+	importStore := diaryimport.NewStore(db)
+	diaryImports := diaryimport.NewRunner(importStore, foodAdmin, diaryStore)
+	if opts.Jobs {
+		lifecycle.Go("diary-import", tenant.Background(diaryImports.Start))
+	}
+
+	// Recipe and weekly plan exports, rendered in the background as print
+	// HTML or, when the converter is installed, PDF
+	exportCfg := printexport.LoadConfig()
+	exportHTML, err := printexport.NewHTMLRenderer(exportCfg)
+	if err != nil {
+		log.Fatalf("❌ Export setup failed: %v", err)
+	}
+	exportRenderers := map[string]printexport.Renderer{printexport.FormatHTML: exportHTML}
+	if exportPDF, err := printexport.NewCommandRenderer(exportHTML, exportCfg); err == nil {
+		exportRenderers[printexport.FormatPDF] = exportPDF
+	} else {
+		log.Printf("⚠️ PDF exports disabled: %v", err)
+	}
+	exportStore := printexport.NewStore(db)
+	exports := printexport.NewRunner(exportStore, printexport.NewSQLSource(db), exportRenderers)
+	if opts.Jobs {
+		lifecycle.Go("exports", tenant.Background(exports.Start))
+	}
+
+	// Anonymized product analytics; opt-outs are also applied to the event bus
+	analyticsCfg := analytics.LoadConfig()
+	optOuts := analytics.NewOptOuts(bgCtx, db)
+	lifecycle.Go("analytics-optouts", func(ctx context.Context) { optOuts.Start(ctx, time.Minute) })
+	productAnalytics := analytics.NewCollector(analyticsCfg, analytics.NewSink(analyticsCfg), optOuts)
+	lifecycle.Go("analytics", productAnalytics.Start)
+	lifecycle.OnFlush("analytics", productAnalytics.Flush)
+
+	// Live updates for connected clients; with Redis every instance hears the
+	// relay's events, without it only this process's relay feeds the hub
+	realtimeHub := realtime.NewHub(redisClient, realtime.LoadConfig())
+	if opts.HTTP {
+		lifecycle.Go("realtime", realtimeHub.Start)
+	}
+
+	// Outbox relay: events written with the change's transaction are published here
+	outboxCfg := outbox.LoadConfig()
+	var publishers outbox.Multi
+	if redisClient != nil {
+		publishers = append(publishers, outbox.NewRedisPublisher(redisClient))
+	} else if opts.HTTP {
+		publishers = append(publishers, realtimeHub)
+	}
+	if len(outboxCfg.WebhookURLs) > 0 {
+		publishers = append(publishers, outbox.NewWebhookPublisher(outboxCfg.WebhookURLs, outboxCfg.WebhookSecret))
+	}
+	var relay *outbox.Relay
+	if len(publishers) > 0 {
+		relay = outbox.NewRelay(db, analytics.Filter(publishers, optOuts), outboxCfg)
+		if opts.Jobs {
+			lifecycle.Go("outbox", relay.Start)
+		}
+	} else {
+		log.Println("⚠️ Outbox relay disabled (no Redis or webhooks); events stay queued")
+	}
+
+	// White-label tenants resolved from the Host header
+	tenants := tenant.NewResolver(bgCtx, tenant.NewStore(db), tenant.LoadConfig())
+	lifecycle.Go("tenants", tenants.Start)
+
+	// Terms, privacy and disclaimer acceptance
+	consents := consent.NewService(db)
+
+	// Partner request signing; SIGNING_KEYS was checked by configcheck at startup
+	signingSecrets, _ := signing.LoadSecrets()
+	var signingNonces signing.NonceStore = signing.NewMemoryNonces()
+	if redisClient != nil {
+		signingNonces = signing.NewRedisNonces(redisClient)
+	}
+
+	// Fault injection for resilience testing; never active in production
+	faultInjector := faults.New(faults.LoadConfig(), cfg.Server.Environment)
+	if faultInjector != nil {
+		if redisClient != nil {
+			redisClient.AddHook(faults.RedisHook())
+		}
+		log.Println("🧪 Fault injection enabled")
+	}
+
+	// Maintenance/read-only toggle, shared through Redis when available
+	maintenanceMode := maintenance.NewService(redisClient)
+
+	// Circuit breakers for external APIs reached through the default transport
+	externalAPIs := extapi.NewTransport(http.DefaultTransport, extapi.LoadConfig())
+	externalAPIs.Observe(func(ctx context.Context, _ string, failed bool) {
+		statsCounters.Incr(ctx, stats.CountExternalAPICalls)
+		if failed {
+			statsCounters.Incr(ctx, stats.CountExternalAPIErrors)
+		}
+	})
+	http.DefaultTransport = externalAPIs
+
+	// Initialize services with DI; their caches get a client that keeps each
+	// tenant's keys apart
+	serviceRedis := redisClient
+	if redisClient != nil {
+		serviceRedis = tenant.CacheClient(redisClient)
+		if faultInjector != nil {
+			serviceRedis.AddHook(faults.RedisHook())
+		}
+	}
+	services := services.NewServices(db, serviceRedis, cfg)
 	log.Println("✅ Services initialized")
 
+	// Error reporting (Sentry-compatible, disabled without SENTRY_DSN)
+	reporter, err := errreport.New(errreport.LoadConfig(cfg.Server.Environment, build.Release()))
+	if err != nil {
+		log.Fatalf("❌ Error reporter init failed: %v", err)
+	}
+	lifecycle.OnFlush("errreport", func(ctx context.Context) error {
+		timeout := 2 * time.Second
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+			timeout = time.Until(deadline)
+		}
+		reporter.Flush(timeout)
+		return nil
+	})
+
+	// Native TLS (static certificate or ACME)
+	tlsCfg := server.LoadTLSConfig()
+
 	// Initialize Echo
 	e := echo.New()
 	e.HideBanner = true
+	e.HTTPErrorHandler = errreport.ErrorHandler(reporter, e.DefaultHTTPErrorHandler)
+	// Client IPs come from X-Forwarded-For only when a trusted proxy set it
+	ipExtractor, err := server.LoadProxyConfig().IPExtractor()
+	if err != nil {
+		log.Fatalf("❌ Invalid TRUSTED_PROXIES: %v", err)
+	}
+	e.IPExtractor = ipExtractor
 
 	// Setup structured logging
 	middleware.SetupLogger(cfg.Server.Environment)
 
 	// Core middleware
-	e.Use(echomiddleware.Recover())
+	e.Use(errreport.Recover(reporter))
 	e.Use(middleware.CorrelationID())
-	e.Use(middleware.StructuredLogger())
+	e.Use(dbmetrics.Middleware())
+	if logFormat == logging.JSON {
+		e.Use(logging.AccessLog(logging.Stdout))
+	} else {
+		e.Use(middleware.StructuredLogger())
+	}
 	e.Use(middleware.ErrorLogger())
 	e.Use(middleware.AuditLogger())
 
 	// Custom middleware
 	e.Use(middleware.Security())
-	e.Use(middleware.CORS(cfg.Security.CORSOrigins))
+	e.Use(limits.Middleware(limits.LoadConfig(cfg.API.Version)))
+	e.Use(signing.Middleware(signing.LoadConfig(cfg.API.Version), signingSecrets, signingNonces))
+	e.Use(faultInjector.Middleware())
+	e.Use(maintenanceMode.Middleware(cfg.API.Version))
+	e.Use(tenants.Middleware())
+	if tlsCfg.Enabled() {
+		e.Use(server.HSTS(tlsCfg))
+	}
+	e.Use(runtimeSettings.Middleware(func(s runtimecfg.Settings) echo.MiddlewareFunc {
+		return cors.Middleware(cors.LoadConfig(s.CORSOrigins, s.CORSCredentialOrigins))
+	}))
 
 	// Distributed rate limiting with Redis
 	if redisClient != nil {
-		rateLimitConfig := middleware.RateLimitConfig{
-			Client: redisClient,
-			Limit:  int64(cfg.Security.RateLimitReqs),
-			Window: cfg.Security.RateLimitWindow,
-		}
-		e.Use(middleware.DistributedRateLimiter(rateLimitConfig))
+		e.Use(runtimeSettings.Middleware(func(s runtimecfg.Settings) echo.MiddlewareFunc {
+			return tenant.RateLimiter(redisClient, tenant.RateLimit{Requests: s.RateLimitReqs, Window: s.RateLimitWindow})
+		}))
 	}
 
 	e.Use(middleware.Compression())
+	e.Use(featureflags.Inject(flags))
+	e.Use(searchgaps.Middleware(searchGaps, "/api/"+cfg.API.Version))
+	e.Use(productAnalytics.Middleware())
+	e.Use(fieldsets.Middleware(fieldsets.AllowedIncludes()...))
+	// Anonymous requests get the X-Unit-System header; signed-in users their
+	// stored preference, bound again on the authenticated group
+	unitSystems := units.NewStore(db)
+	e.Use(unitSystems.Middleware())
+
+	// Startup sequencing: the listener binds right away, /health/startup
+	// passes once the schema is verified, workers are connected and caches
+	// are warm
+	startupTracker := startup.NewTracker()
+	startupTracker.Add(startup.Step{Name: "migrations", Run: func(context.Context) error {
+		return database.VerifySchema(db)
+	}})
+	startupTracker.Add(startup.Step{Name: "workers", Run: func(ctx context.Context) error {
+		if redisClient != nil {
+			if err := redisClient.Ping(ctx).Err(); err != nil {
+				return fmt.Errorf("redis: %w", err)
+			}
+		}
+		if relay != nil {
+			if _, err := relay.Backlog(ctx); err != nil {
+				return fmt.Errorf("outbox: %w", err)
+			}
+		}
+		return nil
+	}})
+	brandedStore := branded.NewStore(db)
+	startupTracker.Add(startup.Step{Name: "cache_warmup", Optional: true, Run: func(ctx context.Context) error {
+		if err := flags.Refresh(ctx); err != nil {
+			return fmt.Errorf("feature flags: %w", err)
+		}
+		if err := tenants.Refresh(ctx); err != nil {
+			return fmt.Errorf("tenants: %w", err)
+		}
+		if err := optOuts.Refresh(ctx); err != nil {
+			return fmt.Errorf("opt-outs: %w", err)
+		}
+		if _, err := brandedStore.Chains(ctx); err != nil {
+			return fmt.Errorf("branded chains: %w", err)
+		}
+		return nil
+	}})
 
 	// Health check endpoints (Kubernetes-ready)
 	healthCheckHandler := handlers.NewHealthCheckHandler(services)
 	e.GET("/health", healthCheckHandler.Health)
 	e.GET("/health/live", healthCheckHandler.Liveness)
-	e.GET("/health/ready", healthCheckHandler.Readiness)
-	e.GET("/health/startup", healthCheckHandler.Startup)
+	components := health.NewRegistry(
+		health.DBCheck(db, envconfig.Duration("HEALTH_DB_SLOW", 100*time.Millisecond)),
+		health.RedisCheck(redisClient, envconfig.Duration("HEALTH_REDIS_SLOW", 50*time.Millisecond)),
+		health.DiskCheck("disk_database", filepath.Dir(cfg.Database.Path), true, 10, 2),
+	)
+	if uploads := envconfig.String("UPLOADS_DIR", "uploads"); uploads != "" {
+		components.Add(health.DiskCheck("disk_images", uploads, false, 10, 2))
+	}
+	if relay != nil {
+		components.Add(health.Func("outbox", false, func(ctx context.Context) health.Component {
+			backlog, err := relay.Backlog(ctx)
+			if err != nil {
+				return health.Component{Status: health.Failed, Error: err.Error()}
+			}
+			comp := health.Component{Status: health.OK, Details: map[string]interface{}{"backlog": backlog}}
+			if backlog.Dead > 0 || backlog.OldestAgeSec > 300 {
+				comp.Status = health.Degraded
+			}
+			return comp
+		}))
+	}
+	if replicator != nil {
+		// A stale backup needs attention but must not take the API out of rotation
+		components.Add(health.Func("backup", false, func(context.Context) health.Component {
+			status := replicator.Status()
+			comp := health.Component{Status: health.OK, Details: map[string]interface{}{"backup": status}}
+			if !status.Healthy {
+				comp.Status, comp.Error = health.Degraded, "no recent snapshot"
+			}
+			return comp
+		}))
+	}
+	// An open circuit means degraded results, not an unusable API
+	components.Add(health.Func("external_apis", false, func(context.Context) health.Component {
+		apis := externalAPIs.Status()
+		comp := health.Component{Status: health.OK, Details: map[string]interface{}{"apis": apis}}
+		for _, api := range apis {
+			if api.State != extapi.Closed {
+				comp.Status, comp.Error = health.Degraded, api.Host+" circuit "+string(api.State)
+				break
+			}
+		}
+		return comp
+	}))
+	readiness := components.Readiness(healthCheckHandler.Readiness)
+	e.GET("/health/ready", startupTracker.Gate(func(c echo.Context) error {
+		if lifecycle.Draining() {
+			return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+				"status": "draining",
+			})
+		}
+		return readiness(c)
+	}))
+	// Component details and metrics expose internals, so they need the admin token
+	requireAdmin := admin.RequireToken(admin.Token())
+	e.GET("/health/components", components.Components, requireAdmin)
+	e.GET("/health/startup", startupTracker.Handler)
+
+	e.GET("/version", version.Handler)
+
+	// Per-query database histograms (Prometheus text format)
+	e.GET("/metrics/db", dbmetrics.Handler, requireAdmin)
+	e.GET("/metrics/cors", cors.Handler, requireAdmin)
+	e.GET("/metrics/retention", retentionJob.MetricsHandler, requireAdmin)
 
 	e.GET("/disclaimer", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, map[string]string{
@@ -111,22 +575,83 @@ func main() {
 		})
 	})
 
+	// Operator endpoints, guarded by ADMIN_TOKEN
+	adminGroup := e.Group("/admin", requireAdmin)
+	runtimeSettings.RegisterRoutes(adminGroup)
+	flags.RegisterRoutes(adminGroup)
+	maintenanceMode.RegisterRoutes(adminGroup)
+	searchgaps.NewHandler(searchGaps, foodAdmin).RegisterRoutes(adminGroup)
+	tenants.RegisterAdminRoutes(adminGroup)
+	consents.RegisterAdminRoutes(adminGroup)
+	pricingHandler := pricing.NewHandler(pricing.NewStore(db))
+	pricingHandler.RegisterAdminRoutes(adminGroup)
+	brandedHandler := branded.NewHandler(brandedStore)
+	brandedHandler.RegisterAdminRoutes(adminGroup)
+	foodadmin.NewHandler(foodAdmin, dedupeJob).RegisterRoutes(adminGroup)
+	faultInjector.RegisterRoutes(adminGroup)
+	retentionJob.RegisterRoutes(adminGroup)
+	if adminui.Enabled() {
+		adminui.NewHandler("/api/"+cfg.API.Version, admin.Token()).RegisterRoutes(e)
+		log.Println("🖥️ Admin UI at /admin")
+	}
+	if diagnostics.Enabled() {
+		diagnostics.NewHandler(db, redisClient).RegisterRoutes(adminGroup)
+		log.Println("🩺 Diagnostics enabled at /admin/diagnostics")
+	}
+
 	// API routes
 	api := e.Group("/api/" + cfg.API.Version)
 	handlers.RegisterRoutes(api, services, cfg)
+	tenants.RegisterRoutes(api)
+	calendarHandler := calendar.NewHandler(calendar.NewFeeds(db, calendar.NewPlanMeals(db, zones), zones))
+	calendarHandler.RegisterFeedRoutes(api)
+	pricingHandler.RegisterRoutes(api)
+	brandedHandler.RegisterRoutes(api)
+	// Routes for the signed-in user, authenticated with the login handlers'
+	// JWTs; fault injection runs again here so FAULT_SCOPE=users sees the user
+	userAPI := api.Group("", auth.RequireUser(auth.Secret()), faultInjector.Middleware())
+	userAPI.Use(unitSystems.Middleware(), zones.Middleware())
+	// Users can always read and accept the terms and opt out of analytics;
+	// everything after needs the terms accepted
+	consents.RegisterRoutes(userAPI)
+	optOuts.RegisterRoutes(userAPI)
+	userAPI.Use(consents.Require())
+	unitSystems.RegisterRoutes(userAPI)
+	zones.RegisterRoutes(userAPI)
+	symptoms.NewHandler(symptomStore).RegisterRoutes(userAPI)
+	healthrecords.NewHandler(healthrecords.NewStore(db, fieldCipher)).RegisterRoutes(userAPI)
+	reminders.NewHandler(reminderStore).RegisterRoutes(userAPI)
+	insights.NewHandler(insightsService).RegisterRoutes(userAPI)
+	diarybatch.NewHandler(diarybatch.NewService(db, diaryStore)).RegisterRoutes(userAPI)
+	diaryimport.NewHandler(importStore, diaryImports).RegisterRoutes(userAPI)
+	// Offline clients pull the journal and push queued diary, weight and plan writes
+	syncStore := deltasync.NewStore(db)
+	diaryStore.RegisterSync(syncStore)
+	deltasync.NewHandler(syncStore).RegisterRoutes(userAPI)
+	portions.NewHandler(foodAdmin, diaryStore).RegisterRoutes(userAPI)
+	printexport.NewHandler(exportStore, exports).RegisterRoutes(userAPI)
+	calendarHandler.RegisterRoutes(userAPI)
+	batchcook.NewHandler(batchcook.NewStore(db)).RegisterRoutes(userAPI)
+	pricingHandler.RegisterUserRoutes(userAPI)
+	targetsHandler := targets.NewHandler(targetStore)
+	targetsHandler.RegisterUserRoutes(userAPI)
+	// Coach relationships live with the services; until they are exposed here
+	// viewers only get their own stream
+	realtime.NewHandler(realtimeHub, nil).RegisterRoutes(userAPI)
+	apiAdmin := api.Group("/admin", requireAdmin)
+	stats.NewHandler(statsStore).RegisterRoutes(apiAdmin)
+	targetsHandler.RegisterRoutes(api, apiAdmin)
 	log.Println("✅ Routes registered")
 
 	// Start server
-	go func() {
-		addr := ":" + cfg.Server.Port
-		log.Printf("🌐 Server starting on http://localhost%s", addr)
-		log.Printf("📊 Health: http://localhost%s/health", addr)
-		log.Printf("📖 API: http://localhost%s/api/%s", addr, cfg.API.Version)
-
-		if err := e.Start(addr); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("❌ Server failed: %v", err)
-		}
-	}()
+	var redirectServer *http.Server
+	if opts.HTTP {
+		redirectServer = startHTTP(e, cfg, tlsCfg)
+	} else {
+		log.Println("👷 Worker mode: HTTP listener disabled")
+	}
+	// Runs after the bind so probes can watch the progress
+	lifecycle.Go("startup", startupTracker.Run)
 
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
@@ -135,34 +660,140 @@ func main() {
 
 	log.Println("🛑 Shutting down server...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), server.ShutdownTimeout())
 	defer cancel()
 
-	// Cleanup services
-	if err := services.Cleanup(); err != nil {
-		log.Printf("⚠️ Service cleanup error: %v", err)
+	// Stop taking new background work before draining requests
+	lifecycle.StopWorkers(ctx)
+
+	if redirectServer != nil {
+		redirectServer.Shutdown(ctx)
 	}
 
+	// Drain in-flight requests; connections still open at the deadline are dropped
 	if err := e.Shutdown(ctx); err != nil {
-		log.Fatalf("❌ Forced shutdown: %v", err)
+		log.Printf("⚠️ Forced shutdown: %v", err)
 	}
 
+	// Cleanup services once no handler can still be using them
+	if err := services.Cleanup(); err != nil {
+		log.Printf("⚠️ Service cleanup error: %v", err)
+	}
+
+	// Flush buffered events last
+	lifecycle.Flush(ctx)
+
 	log.Println("✅ Server stopped gracefully")
 }
 
+// startHTTP starts the API listener (and the HTTPS redirect listener when
+// configured) in the background
+func startHTTP(e *echo.Echo, cfg *config.Config, tlsCfg server.TLSConfig) *http.Server {
+	addr := ":" + cfg.Server.Port
+	var redirectServer *http.Server
+	if tlsCfg.Enabled() && tlsCfg.RedirectAddr != "" {
+		redirectServer = server.RedirectServer(e, tlsCfg, addr)
+		go func() {
+			log.Printf("↪️ HTTP->HTTPS redirect on %s", tlsCfg.RedirectAddr)
+			if err := redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("❌ Redirect server failed: %v", err)
+			}
+		}()
+	}
+
+	go func() {
+		scheme := "http"
+		if tlsCfg.Enabled() {
+			scheme = "https"
+		}
+		log.Printf("🌐 Server starting on %s://localhost%s", scheme, addr)
+		log.Printf("📊 Health: %s://localhost%s/health", scheme, addr)
+		log.Printf("📖 API: %s://localhost%s/api/%s", scheme, addr, cfg.API.Version)
+
+		var err error
+		switch {
+		case tlsCfg.CertFile != "":
+			err = e.StartTLS(addr, tlsCfg.CertFile, tlsCfg.KeyFile)
+		case tlsCfg.Autocert():
+			server.ConfigureAutocert(e, tlsCfg)
+			err = e.StartAutoTLS(addr)
+		case server.H2CEnabled():
+			err = e.StartH2CServer(addr, &http2.Server{})
+		default:
+			err = e.Start(addr)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatalf("❌ Server failed: %v", err)
+		}
+	}()
+
+	return redirectServer
+}
+
+// openDatabase opens the SQLite database and applies PRAGMA and pool tuning
+func openDatabase(path string) *sql.DB {
+	base, err := database.Initialize(path)
+	if err != nil {
+		log.Fatalf("❌ Database init failed: %v", err)
+	}
+	base.Close()
+
+	// Reopen through the metrics connector so every query is measured, with
+	// the tuned driver so every new connection gets the PRAGMAs
+	opts := sqlitetune.OptionsFromEnv()
+	if err := opts.Validate(); err != nil {
+		log.Fatalf("❌ Database tuning failed: %v", err)
+	}
+	db := sql.OpenDB(dbmetrics.NewConnector(sqlitetune.Driver(opts), path))
+	if err := sqlitetune.Apply(db, opts); err != nil {
+		db.Close()
+		log.Fatalf("❌ Database tuning failed: %v", err)
+	}
+	return db
+}
+
+// featureMigrations create the tables owned by feature packages
+var featureMigrations = []struct {
+	name    string
+	migrate func(*sql.DB) error
+}{
+	{"Symptom journal", symptoms.Migrate},
+	{"Health records", healthrecords.Migrate},
+	{"Target templates", targets.Migrate},
+	{"Sync journal", deltasync.Migrate},
+	{"Feature flags", featureflags.Migrate},
+	{"Admin statistics", stats.Migrate},
+	{"Search gaps", searchgaps.Migrate},
+	{"Seed versions", seeds.Migrate},
+	{"Event outbox", outbox.Migrate},
+	{"Tenants", tenant.Migrate},
+	{"Consent", consent.Migrate},
+	{"Field encryption keys", fieldcrypt.Migrate},
+	{"Analytics opt-outs", analytics.Migrate},
+	{"User timezones", localtime.Migrate},
+	{"Unit preferences", units.Migrate},
+	{"Calendar feeds", calendar.Migrate},
+	{"Food prices", pricing.Migrate},
+	{"Branded foods", branded.Migrate},
+	{"USDA foods", usda.Migrate},
+	{"Batch cooking", batchcook.Migrate},
+	{"Food merges", foodadmin.Migrate},
+	{"Diary imports", diaryimport.Migrate},
+	{"Reminder rules", reminders.Migrate},
+	{"Weekly insights", insights.Migrate},
+	{"Print exports", printexport.Migrate},
+}
+
 // runMigrations runs database migrations
 func runMigrations() {
 	log.Println("🔄 Running database migrations...")
 
 	cfg := config.Load()
-	db, err := database.Initialize(cfg.Database.Path)
-	if err != nil {
-		log.Fatalf("❌ Database init failed: %v", err)
-	}
+	db := openDatabase(cfg.Database.Path)
 	defer db.Close()
 
-	if err := database.RunMigrations(db); err != nil {
-		log.Fatalf("❌ Migration failed: %v", err)
+	if err := applyMigrations(db); err != nil {
+		log.Fatalf("❌ %v", err)
 	}
 
 	if err := database.VerifySchema(db); err != nil {
@@ -172,25 +803,134 @@ func runMigrations() {
 	log.Println("✅ Migrations completed successfully")
 }
 
-// runSeeding seeds the database with initial data
-func runSeeding() {
-	log.Println("🌱 Seeding database...")
+// applyMigrations runs the database package's migrations, then the feature packages'
+func applyMigrations(db *sql.DB) error {
+	if err := database.RunMigrations(db); err != nil {
+		return fmt.Errorf("database migration failed: %w", err)
+	}
+	for _, m := range featureMigrations {
+		if err := m.migrate(db); err != nil {
+			return fmt.Errorf("%s migration failed: %w", m.name, err)
+		}
+	}
+	return nil
+}
 
-	cfg := config.Load()
-	db, err := database.Initialize(cfg.Database.Path)
+// runSchemaDump migrates a scratch database and writes its schema to path.
+// db/schema.sql is produced this way, so sqlc checks the queries against the
+// tables the migrations actually create.
+func runSchemaDump(path string) {
+	log.Printf("📐 Dumping the migrated schema to %s...", path)
+
+	dir, err := os.MkdirTemp("", "schema-dump")
 	if err != nil {
-		log.Fatalf("❌ Database init failed: %v", err)
+		log.Fatalf("❌ Scratch database failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db := openDatabase(filepath.Join(dir, "schema.db"))
+	defer db.Close()
+	if err := applyMigrations(db); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	rows, err := db.Query(`SELECT sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%'
+		ORDER BY tbl_name, type DESC, name`)
+	if err != nil {
+		log.Fatalf("❌ Reading schema failed: %v", err)
 	}
+	defer rows.Close()
+	var b strings.Builder
+	b.WriteString("-- Code generated by schema-dump from the migrations. DO NOT EDIT.\n")
+	b.WriteString("-- Regenerate with: go run . schema-dump db/schema.sql\n")
+	n := 0
+	for rows.Next() {
+		var stmt string
+		if err := rows.Scan(&stmt); err != nil {
+			log.Fatalf("❌ Reading schema failed: %v", err)
+		}
+		b.WriteString("\n" + tidySQL(stmt) + ";\n")
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatalf("❌ Reading schema failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		log.Fatalf("❌ Writing %s failed: %v", path, err)
+	}
+	log.Printf("✅ Wrote %d statements to %s", n, path)
+}
+
+// tidySQL re-indents a statement as stored by sqlite, whose text keeps the
+// indentation of the Go source it came from
+func tidySQL(stmt string) string {
+	lines := strings.Split(stmt, "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, ")") {
+			line = "    " + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runSeeding seeds the database with initial data. With dataset names only
+// those datasets from SEED_DATA_DIR are loaded; otherwise the built-in seeds
+// run followed by every dataset in the manifest.
+func runSeeding(datasets []string) {
+	log.Println("🌱 Seeding database...")
+
+	cfg := config.Load()
+	db := openDatabase(cfg.Database.Path)
 	defer db.Close()
 
-	seeder := database.NewSeeder(db)
-	if err := seeder.SeedAll(); err != nil {
+	if len(datasets) == 0 {
+		seeder := database.NewSeeder(db)
+		if err := seeder.SeedAll(); err != nil {
+			log.Fatalf("❌ Seeding failed: %v", err)
+		}
+	}
+
+	dataSeeder, err := seeds.NewSeeder(db, seeds.Dir())
+	if err != nil {
+		log.Fatalf("❌ Seed manifest invalid: %v", err)
+	}
+	results, err := dataSeeder.Apply(context.Background(), datasets...)
+	for _, res := range results {
+		if res.Skipped {
+			log.Printf("⏭️ Dataset %s %s unchanged", res.Dataset, res.Version)
+		} else {
+			log.Printf("✅ Dataset %s %s loaded (%d rows)", res.Dataset, res.Version, res.Rows)
+		}
+	}
+	if err != nil {
 		log.Fatalf("❌ Seeding failed: %v", err)
 	}
 
 	log.Println("✅ Seeding completed successfully")
 }
 
+// runSeedFake fills the database with synthetic users for load testing;
+// run migrate and seed first so the tables and foods exist
+func runSeedFake(opts fakedata.Options, tenantID string) {
+	log.Printf("🧪 Generating %d fake users with %d days of history (seed %d) for tenant %s...", opts.Users, opts.Days, opts.Seed, tenantID)
+
+	cfg := config.Load()
+	db := openDatabase(cfg.Database.Path)
+	defer db.Close()
+
+	start := time.Now()
+	ctx := tenant.WithTenant(context.Background(), tenant.Tenant{ID: tenantID})
+	sum, err := fakedata.Generate(ctx, fakedata.NewSQLWriter(db), opts)
+	if err != nil {
+		log.Fatalf("❌ Fake data generation failed: %v", err)
+	}
+	log.Printf("✅ Generated %d users (%d already present): %d diary entries, %d weigh-ins, %d plans in %s",
+		sum.Users, sum.Skipped, sum.Entries, sum.Weights, sum.Plans, time.Since(start).Round(time.Millisecond))
+}
+
 // runReset resets the database (drops and recreates)
 func runReset() {
 	log.Println("🔄 Resetting database...")
@@ -206,7 +946,204 @@ func runReset() {
 	runMigrations()
 
 	// Run seeding
-	runSeeding()
+	runSeeding(nil)
 
 	log.Println("✅ Database reset completed successfully")
 }
+
+// runBackupNow uploads a single snapshot to the configured backup replica
+func runBackupNow() {
+	log.Println("💾 Taking database backup...")
+
+	cfg := config.Load()
+	db := openDatabase(cfg.Database.Path)
+	defer db.Close()
+
+	replica, err := backup.NewReplica(backup.LoadConfig())
+	if err != nil {
+		log.Fatalf("❌ Backup init failed: %v", err)
+	}
+
+	name, size, err := backup.Upload(context.Background(), db, replica, time.Now())
+	if err != nil {
+		log.Fatalf("❌ Backup failed: %v", err)
+	}
+
+	log.Printf("✅ Backup %s uploaded (%d bytes)", name, size)
+}
+
+// runBackup writes a consistent snapshot of the live database to a local file
+func runBackup(path string) {
+	log.Printf("💾 Backing up database to %s...", path)
+
+	cfg := config.Load()
+	db := openDatabase(cfg.Database.Path)
+	defer db.Close()
+
+	if err := backup.Snapshot(context.Background(), db, path); err != nil {
+		log.Fatalf("❌ Backup failed: %v", err)
+	}
+
+	log.Printf("✅ Backup written to %s", path)
+}
+
+// runRestore restores from a snapshot file, or from the newest replica
+// snapshot taken at or before an RFC3339 timestamp. The server must be stopped.
+func runRestore(from string) {
+	cfg := config.Load()
+
+	if _, err := os.Stat(from); err == nil {
+		log.Printf("🔄 Restoring database from %s...", from)
+		if err := backup.RestoreFile(from, cfg.Database.Path); err != nil {
+			log.Fatalf("❌ Restore failed: %v", err)
+		}
+		log.Printf("✅ Database restored from %s", from)
+		return
+	}
+
+	target := time.Now()
+	if from != "latest" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			log.Fatalf("❌ %q is neither a snapshot file nor an RFC3339 timestamp", from)
+		}
+		target = t
+	}
+	log.Printf("🔄 Restoring database to %s...", target.UTC().Format(time.RFC3339))
+
+	replica, err := backup.NewReplica(backup.LoadConfig())
+	if err != nil {
+		log.Fatalf("❌ Backup init failed: %v", err)
+	}
+
+	name, err := backup.Restore(context.Background(), replica, target, cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("❌ Restore failed: %v", err)
+	}
+
+	log.Printf("✅ Database restored from %s", name)
+}
+
+// runCheck validates database integrity and schema without stopping the server
+func runCheck() {
+	log.Println("🔍 Checking database...")
+
+	cfg := config.Load()
+	db := openDatabase(cfg.Database.Path)
+	defer db.Close()
+
+	problems, err := backup.IntegrityCheck(context.Background(), db)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	for _, p := range problems {
+		log.Printf("❌ Integrity: %s", p)
+	}
+
+	if err := database.VerifySchema(db); err != nil {
+		log.Printf("❌ Schema verification failed: %v", err)
+		os.Exit(1)
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+
+	log.Println("✅ Database integrity and schema OK")
+}
+
+// runRotateKeys rewraps field-encryption data keys under the primary key
+func runRotateKeys() {
+	log.Println("🔑 Rotating field encryption keys...")
+
+	ring, err := fieldcrypt.LoadKeyRing()
+	if err != nil {
+		log.Fatalf("❌ Invalid FIELD_ENCRYPTION_KEYS: %v", err)
+	}
+	if ring == nil {
+		log.Fatal("❌ FIELD_ENCRYPTION_KEYS is not set")
+	}
+
+	cfg := config.Load()
+	db := openDatabase(cfg.Database.Path)
+	defer db.Close()
+	if err := fieldcrypt.Migrate(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	n, err := fieldcrypt.New(db, ring).Rotate(context.Background())
+	if err != nil {
+		log.Fatalf("❌ Key rotation failed after %d keys: %v", n, err)
+	}
+	log.Printf("✅ Rewrapped %d data keys under %s", n, ring.Primary())
+}
+
+// runImportBranded loads a branded/restaurant CSV export into branded_foods
+func runImportBranded(path, source string) {
+	log.Printf("🍔 Importing branded foods from %s...", path)
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("❌ Failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	cfg := config.Load()
+	db := openDatabase(cfg.Database.Path)
+	defer db.Close()
+	if err := branded.Migrate(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	result, err := branded.NewStore(db).Import(context.Background(), f, source)
+	if err != nil {
+		log.Fatalf("❌ Import failed: %v", err)
+	}
+	for _, e := range result.Errors {
+		log.Printf("⚠️ Skipped %s", e)
+	}
+	log.Printf("✅ Imported %d new, %d updated, %d skipped", result.Inserted, result.Updated, result.Skipped)
+}
+
+// runSyncUSDA imports or refreshes generic foods from FoodData Central
+func runSyncUSDA(opts usda.Options) {
+	log.Printf("🥦 Syncing %s foods from USDA FoodData Central...", strings.Join(opts.DataTypes, ", "))
+
+	cfg := config.Load()
+	db := openDatabase(cfg.Database.Path)
+	defer db.Close()
+	if err := applyMigrations(db); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	start := time.Now()
+	result, err := usda.NewSyncer(db, usda.NewClient()).Sync(context.Background(), opts)
+	if err != nil {
+		log.Fatalf("❌ USDA sync failed after %d pages (%d new, %d updated): %v", result.Pages, result.Inserted, result.Updated, err)
+	}
+	log.Printf("✅ Synced %d pages in %s: %d new, %d updated, %d skipped",
+		result.Pages, time.Since(start).Round(time.Second), result.Inserted, result.Updated, result.Skipped)
+}
+
+// runConfigCheck validates configuration and exits non-zero on problems, for CI pipelines
+func runConfigCheck() {
+	log.Println("🔍 Checking configuration...")
+
+	// An unreachable secrets backend is reported, not fatal, so the rest of
+	// the configuration can still be checked
+	secretsErr := loadSecrets()
+
+	cfg := config.Load()
+	errs := configcheck.Validate(cfg)
+	if secretsErr != nil {
+		errs = append(errs, secretsErr)
+	}
+	for _, err := range errs {
+		log.Printf("❌ Config: %v", err)
+	}
+	if len(errs) > 0 {
+		log.Printf("❌ %d configuration problem(s)", len(errs))
+		os.Exit(1)
+	}
+
+	log.Println("✅ Configuration OK")
+}
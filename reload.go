@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"nutrition-health-backend/internal/config"
+	"nutrition-health-backend/internal/cors"
+	"nutrition-health-backend/internal/envconfig"
+	"nutrition-health-backend/internal/runtimecfg"
+	"nutrition-health-backend/internal/secrets"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+)
+
+// processEnv holds the variables set before .env was loaded; they keep precedence on reload
+var processEnv map[string]bool
+
+func envKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for _, kv := range os.Environ() {
+		if k, _, ok := strings.Cut(kv, "="); ok {
+			keys[k] = true
+		}
+	}
+	return keys
+}
+
+// reloadDotEnv re-reads .env without overriding the process environment
+func reloadDotEnv() {
+	values, err := godotenv.Read()
+	if err != nil {
+		return
+	}
+	for k, v := range values {
+		if !processEnv[k] {
+			os.Setenv(k, v)
+		}
+	}
+}
+
+// loadRuntimeSettings re-reads .env, secrets and config for the reloadable subset
+func loadRuntimeSettings(provider secrets.Provider, keys []string) (runtimecfg.Settings, error) {
+	reloadDotEnv()
+	if provider != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		_, err := secrets.LoadIntoEnv(ctx, provider, keys)
+		cancel()
+		if err != nil {
+			return runtimecfg.Settings{}, err
+		}
+	}
+
+	cfg := config.Load()
+	s := runtimecfg.Settings{
+		RateLimitReqs:         cfg.Security.RateLimitReqs,
+		RateLimitWindow:       cfg.Security.RateLimitWindow,
+		CORSOrigins:           cors.Origins(cfg.Server.Environment, cfg.Security.CORSOrigins),
+		CORSCredentialOrigins: cors.CredentialOrigins(cfg.Server.Environment),
+		LogLevel:              strings.ToLower(envconfig.String("LOG_LEVEL", "info")),
+	}
+
+	if s.RateLimitReqs <= 0 || s.RateLimitWindow <= 0 {
+		return s, fmt.Errorf("rate limit requests and window must be positive")
+	}
+	if err := cors.LoadConfig(s.CORSOrigins, s.CORSCredentialOrigins).Validate(cfg.Server.Environment == "production"); err != nil {
+		return s, err
+	}
+	if _, err := zerolog.ParseLevel(s.LogLevel); err != nil {
+		return s, fmt.Errorf("invalid LOG_LEVEL %q", s.LogLevel)
+	}
+	return s, nil
+}
+
+// applyLogLevel sets the global log level from the live settings
+func applyLogLevel(s runtimecfg.Settings) {
+	if level, err := zerolog.ParseLevel(s.LogLevel); err == nil {
+		zerolog.SetGlobalLevel(level)
+	}
+}
+
+// reloadOnSIGHUP reloads runtime settings whenever the process receives SIGHUP
+func reloadOnSIGHUP(ctx context.Context, m *runtimecfg.Manager) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			m.Reload("signal:SIGHUP")
+		}
+	}
+}